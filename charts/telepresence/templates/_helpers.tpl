@@ -17,6 +17,13 @@ Expand the name of the chart.
 {{- end -}}
 {{- end -}}
 
+{{- /*
+Traffic Manager Service Name
+*/}}
+{{- define "traffic-manager.serviceName" -}}
+{{- default (include "traffic-manager.name" .) .Values.service.name }}
+{{- end -}}
+
 {{- /*
 Traffic Manager Namespace
 */}}
@@ -206,13 +206,18 @@ func sidecar(ctx context.Context, s State, info *rpc.AgentInfo) error {
 func TalkToManagerLoop(ctx context.Context, s State, info *rpc.AgentInfo) {
 	ac := s.AgentConfig()
 	gRPCAddress := fmt.Sprintf("%s:%v", ac.ManagerHost, ac.ManagerPort)
+	creds, err := managerCredentials(ac)
+	if err != nil {
+		dlog.Error(ctx, err)
+		return
+	}
 
 	// Don't reconnect more than once every five seconds
 	ticker := time.NewTicker(5 * time.Second)
 	defer ticker.Stop()
 
 	for {
-		if err := TalkToManager(ctx, gRPCAddress, info, s); err != nil {
+		if err := TalkToManager(ctx, gRPCAddress, creds, info, s); err != nil {
 			dlog.Info(ctx, err)
 		}
 
@@ -315,6 +320,7 @@ func StartServices(ctx context.Context, g *dgroup.Group, config Config, srv Stat
 		Namespace: config.AgentConfig().Namespace,
 		PodName:   config.PodName(),
 		PodIp:     config.PodIP(),
+		NodeName:  config.NodeName(),
 		ApiPort:   int32(grpcPort),
 		Product:   "telepresence",
 		Version:   version.Version,
@@ -2,6 +2,8 @@ package agent
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
@@ -14,6 +16,7 @@ import (
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 	empty "google.golang.org/protobuf/types/known/emptypb"
@@ -21,6 +24,7 @@ import (
 	"github.com/datawire/dlib/dgroup"
 	"github.com/datawire/dlib/dlog"
 	rpc "github.com/telepresenceio/telepresence/rpc/v2/manager"
+	"github.com/telepresenceio/telepresence/v2/pkg/agentconfig"
 	"github.com/telepresenceio/telepresence/v2/pkg/dnsproxy"
 	"github.com/telepresenceio/telepresence/v2/pkg/dos"
 	"github.com/telepresenceio/telepresence/v2/pkg/log"
@@ -46,12 +50,35 @@ func (is interceptsStringer) String() string {
 
 var NewExtendedManagerClient func(conn *grpc.ClientConn, ossManager rpc.ManagerClient) rpc.ManagerClient //nolint:gochecknoglobals // extension point
 
-func TalkToManager(ctx context.Context, address string, info *rpc.AgentInfo, state State) error {
+// managerCredentials returns the transport credentials to use when connecting to the traffic-manager. Mutual
+// TLS is used when the agent config contains the CA of the traffic-manager's certificate. The agent then
+// presents the client certificate from its config.
+func managerCredentials(ac *agentconfig.Sidecar) (credentials.TransportCredentials, error) {
+	if ac.ManagerTLSCA == "" {
+		return insecure.NewCredentials(), nil
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(ac.ManagerTLSCA)) {
+		return nil, errors.New("the traffic-manager CA in the agent config contains no valid certificates")
+	}
+	cert, err := tls.X509KeyPair([]byte(ac.ManagerTLSCert), []byte(ac.ManagerTLSKey))
+	if err != nil {
+		return nil, fmt.Errorf("the traffic-manager client certificate in the agent config is invalid: %w", err)
+	}
+	return credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		ServerName:   ac.ManagerHost,
+		MinVersion:   tls.VersionTLS12,
+	}), nil
+}
+
+func TalkToManager(ctx context.Context, address string, creds credentials.TransportCredentials, info *rpc.AgentInfo, state State) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	conn, err := grpc.NewClient(address,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithTransportCredentials(creds),
 		grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
 	if err != nil {
 		return err
@@ -24,12 +24,14 @@ type Config interface {
 	HasMounts(ctx context.Context) bool
 	PodName() string
 	PodIP() string
+	NodeName() string
 }
 
 type config struct {
 	sidecarExt agentconfig.SidecarExt
 	podName    string
 	podIP      string
+	nodeName   string
 }
 
 func LoadConfig(ctx context.Context) (Config, error) {
@@ -53,6 +55,7 @@ func LoadConfig(ctx context.Context) (Config, error) {
 	}
 	c.podName = dos.Getenv(ctx, "_TEL_AGENT_NAME")
 	c.podIP = dos.Getenv(ctx, "_TEL_AGENT_POD_IP")
+	c.nodeName = dos.Getenv(ctx, "_TEL_AGENT_NODE_NAME")
 	for _, cn := range sc.Containers {
 		if err := addAppMounts(ctx, cn); err != nil {
 			return nil, err
@@ -86,6 +89,10 @@ func (c *config) PodIP() string {
 	return c.podIP
 }
 
+func (c *config) NodeName() string {
+	return c.nodeName
+}
+
 func OtelResources(ctx context.Context, c Config) []attribute.KeyValue {
 	return []attribute.KeyValue{
 		attribute.Bool("tel2.has-mounts", c.HasMounts(ctx)),
@@ -0,0 +1,22 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/tunnel"
+)
+
+// HoldSpare exposes holdSpare to tests.
+func HoldSpare(ctx context.Context, s State, stream tunnel.Stream) {
+	s.(*state).holdSpare(ctx, stream)
+}
+
+// SparePoolSize returns the number of spare streams held for the given client session, and false if the
+// session has no pool.
+func SparePoolSize(s State, sessionID string) (int, bool) {
+	sc, ok := s.(*state).spareStreams.Load(sessionID)
+	if !ok {
+		return 0, false
+	}
+	return sc.Size(), true
+}
@@ -16,11 +16,24 @@ import (
 	"github.com/telepresenceio/telepresence/v2/pkg/version"
 )
 
+var closedCh = func() chan struct{} { //nolint:gochecknoglobals // constant
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}()
+
 type awaitingForward struct {
 	streamCh chan tunnel.Stream
 	doneCh   <-chan struct{}
 }
 
+// spareStream is a stream that a client has opened ahead of time, so that it can be bound to a connection
+// without the roundtrips of a DialRequest.
+type spareStream struct {
+	stream  tunnel.Stream
+	boundCh chan (<-chan struct{})
+}
+
 func (s *state) Version(context.Context, *emptypb.Empty) (*rpc.VersionInfo2, error) {
 	return &rpc.VersionInfo2{Name: DisplayName, Version: version.Version}, nil
 }
@@ -31,6 +44,10 @@ func (s *state) Tunnel(server agent.Agent_TunnelServer) error {
 	if err != nil {
 		return status.Errorf(codes.FailedPrecondition, "failed to connect stream: %v", err)
 	}
+	if tunnel.IsSpare(stream) {
+		s.holdSpare(ctx, stream)
+		return nil
+	}
 	if awc, ok := s.awaitingForwards.Load(stream.SessionID()); ok {
 		if awf, ok := awc.Load(stream.ID()); ok {
 			awf.streamCh <- stream
@@ -53,6 +70,73 @@ func (s *state) Tunnel(server agent.Agent_TunnelServer) error {
 	return nil
 }
 
+// holdSpare keeps the given spare stream available to CreateClientStream until it is bound and the bound
+// connection is done, or until it has been idle for the time given by the client.
+func (s *state) holdSpare(ctx context.Context, stream tunnel.Stream) {
+	sp := &spareStream{stream: stream, boundCh: make(chan (<-chan struct{}), 1)}
+	s.spareStreams.Compute(stream.SessionID(), func(sc *xsync.MapOf[*spareStream, struct{}], loaded bool) (*xsync.MapOf[*spareStream, struct{}], bool) {
+		if !loaded {
+			sc = xsync.NewMapOf[*spareStream, struct{}]()
+		}
+		sc.Store(sp, struct{}{})
+		return sc, false
+	})
+
+	var idleCh <-chan time.Time
+	if it := stream.DialTimeout(); it > 0 {
+		idle := time.NewTimer(it)
+		defer idle.Stop()
+		idleCh = idle.C
+	}
+	select {
+	case doneCh := <-sp.boundCh:
+		<-doneCh
+		return
+	case <-idleCh:
+	case <-ctx.Done():
+	}
+	if s.removeSpare(stream.SessionID(), sp) {
+		dlog.Tracef(ctx, "Closing idle spare stream from client %s", stream.SessionID())
+		return
+	}
+	// The spare was taken by CreateClientStream before it could be removed.
+	<-<-sp.boundCh
+}
+
+// removeSpare removes the given spare stream from the pool of the given client session, and returns true
+// if it was found. The pool of the session is deleted when its last spare stream is removed. All changes to
+// a pool are made while its entry in spareStreams is locked, so a pool cannot be deleted while a spare is
+// added to it.
+func (s *state) removeSpare(sessionID string, sp *spareStream) (found bool) {
+	s.spareStreams.Compute(sessionID, func(sc *xsync.MapOf[*spareStream, struct{}], loaded bool) (*xsync.MapOf[*spareStream, struct{}], bool) {
+		if !loaded {
+			return sc, true
+		}
+		_, found = sc.LoadAndDelete(sp)
+		return sc, sc.Size() == 0
+	})
+	return found
+}
+
+// takeSpare removes a spare stream for the given client session from the pool and returns it, or returns
+// nil when no spare stream is available.
+func (s *state) takeSpare(sessionID string) (sp *spareStream) {
+	s.spareStreams.Compute(sessionID, func(sc *xsync.MapOf[*spareStream, struct{}], loaded bool) (*xsync.MapOf[*spareStream, struct{}], bool) {
+		if !loaded {
+			return sc, true
+		}
+		sc.Range(func(k *spareStream, _ struct{}) bool {
+			sp = k
+			return false
+		})
+		if sp != nil {
+			sc.Delete(sp)
+		}
+		return sc, sc.Size() == 0
+	})
+	return sp
+}
+
 func (s *state) WatchDial(session *rpc.SessionInfo, server agent.Agent_WatchDialServer) error {
 	ctx := server.Context()
 	dlog.Debugf(ctx, "WatchDial called from client %s", session.SessionId)
@@ -81,6 +165,17 @@ func (s *state) WatchDial(session *rpc.SessionInfo, server agent.Agent_WatchDial
 
 func (s *state) CreateClientStream(ctx context.Context, sessionID string, id tunnel.ConnID, roundTripLatency, dialTimeout time.Duration) (tunnel.Stream, error) {
 	dlog.Debugf(ctx, "Creating tunnel to client %s for id %s", sessionID, id)
+	for sp := s.takeSpare(sessionID); sp != nil; sp = s.takeSpare(sessionID) {
+		stream, err := tunnel.Bind(ctx, sp.stream, id, roundTripLatency, dialTimeout)
+		if err != nil {
+			dlog.Debugf(ctx, "Unable to bind spare stream from client %s to id %s: %v", sessionID, id, err)
+			sp.boundCh <- closedCh
+			continue
+		}
+		sp.boundCh <- ctx.Done()
+		dlog.Debugf(ctx, "Bound spare tunnel from client %s to id %s", sessionID, id)
+		return stream, nil
+	}
 	drCh, ok := s.dialWatchers.Load(sessionID)
 	var stCh <-chan tunnel.Stream
 	if ok {
@@ -55,6 +55,7 @@ type state struct {
 	sftpPort         uint16
 	dialWatchers     *xsync.MapOf[string, chan *manager.DialRequest]
 	awaitingForwards *xsync.MapOf[string, *xsync.MapOf[tunnel.ConnID, *awaitingForward]]
+	spareStreams     *xsync.MapOf[string, *xsync.MapOf[*spareStream, struct{}]]
 
 	// The sessionInfo and manager client are needed when forwarders establish their
 	// tunnel to the traffic-manager.
@@ -90,6 +91,7 @@ func NewState(config Config) State {
 		containerStates:  make(map[string]ContainerState),
 		dialWatchers:     xsync.NewMapOf[string, chan *manager.DialRequest](),
 		awaitingForwards: xsync.NewMapOf[string, *xsync.MapOf[tunnel.ConnID, *awaitingForward]](),
+		spareStreams:     xsync.NewMapOf[string, *xsync.MapOf[*spareStream, struct{}]](),
 	}
 }
 
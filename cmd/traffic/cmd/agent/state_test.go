@@ -4,6 +4,7 @@ import (
 	"context"
 	"net"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -15,6 +16,8 @@ import (
 	"github.com/telepresenceio/telepresence/v2/cmd/traffic/cmd/agent"
 	"github.com/telepresenceio/telepresence/v2/pkg/agentconfig"
 	"github.com/telepresenceio/telepresence/v2/pkg/forwarder"
+	"github.com/telepresenceio/telepresence/v2/pkg/ipproto"
+	"github.com/telepresenceio/telepresence/v2/pkg/tunnel"
 )
 
 const (
@@ -150,3 +153,126 @@ func TestState_HandleIntercepts(t *testing.T) {
 	a.Len(reviews, 0)
 	a.Equal("", f.InterceptId())
 }
+
+// spareStream is a tunnel.Stream that counts the messages sent to it, which for a spare is the BIND message.
+type spareStream struct {
+	tunnel.Stream
+	sessionID   string
+	dialTimeout time.Duration
+	sends       atomic.Int32
+}
+
+func (s *spareStream) SessionID() string {
+	return s.sessionID
+}
+
+func (s *spareStream) DialTimeout() time.Duration {
+	return s.dialTimeout
+}
+
+func (s *spareStream) Send(context.Context, tunnel.Message) error {
+	s.sends.Add(1)
+	return nil
+}
+
+func TestState_spareStreams(t *testing.T) {
+	const sessionID = "client-session"
+	id := tunnel.NewConnID(ipproto.TCP, net.IP{10, 0, 0, 1}, net.IP{127, 0, 0, 1}, 4711, 8080)
+
+	t.Run("idle spare is reaped", func(t *testing.T) {
+		ctx := dlog.NewTestContext(t, false)
+		s := agent.NewState(nil)
+		sp := &spareStream{sessionID: sessionID, dialTimeout: 50 * time.Millisecond}
+		held := make(chan struct{})
+		go func() {
+			defer close(held)
+			agent.HoldSpare(ctx, s, sp)
+		}()
+		require.Eventually(t, func() bool {
+			n, _ := agent.SparePoolSize(s, sessionID)
+			return n == 1
+		}, time.Second, time.Millisecond)
+
+		select {
+		case <-held:
+		case <-time.After(time.Second):
+			require.Fail(t, "idle spare stream was not reaped")
+		}
+		_, ok := agent.SparePoolSize(s, sessionID)
+		assert.False(t, ok, "empty pool was not deleted")
+
+		// Nothing left to take, so no stream is created.
+		stream, err := s.CreateClientStream(ctx, sessionID, id, 0, 0)
+		require.NoError(t, err)
+		assert.Nil(t, stream)
+		assert.Zero(t, sp.sends.Load())
+	})
+
+	t.Run("pool is deleted when its last spare is taken", func(t *testing.T) {
+		ctx := dlog.NewTestContext(t, false)
+		s := agent.NewState(nil)
+		spares := []*spareStream{{sessionID: sessionID}, {sessionID: sessionID}}
+		for _, sp := range spares {
+			go agent.HoldSpare(ctx, s, sp)
+		}
+		require.Eventually(t, func() bool {
+			n, _ := agent.SparePoolSize(s, sessionID)
+			return n == 2
+		}, time.Second, time.Millisecond)
+
+		for i := range spares {
+			cCtx, cancel := context.WithCancel(ctx)
+			stream, err := s.CreateClientStream(cCtx, sessionID, id, 0, 0)
+			cancel()
+			require.NoError(t, err)
+			require.NotNil(t, stream)
+			n, ok := agent.SparePoolSize(s, sessionID)
+			if i < len(spares)-1 {
+				assert.Equal(t, 1, n)
+			} else {
+				assert.False(t, ok, "empty pool was not deleted")
+			}
+		}
+		assert.Equal(t, int32(1), spares[0].sends.Load())
+		assert.Equal(t, int32(1), spares[1].sends.Load())
+	})
+
+	t.Run("take racing a reap binds once", func(t *testing.T) {
+		ctx := dlog.NewTestContext(t, false)
+		s := agent.NewState(nil)
+		for i := 0; i < 200; i++ {
+			sp := &spareStream{sessionID: sessionID, dialTimeout: time.Millisecond}
+			held := make(chan struct{})
+			go func() {
+				defer close(held)
+				agent.HoldSpare(ctx, s, sp)
+			}()
+
+			// Sleep for roughly the idle time so that the take and the reap race. The take may also come before
+			// the spare is held, or after it has been reaped, and then finds nothing to bind.
+			time.Sleep(time.Duration(i%4) * 500 * time.Microsecond)
+			cCtx, cancel := context.WithCancel(ctx)
+			stream, err := s.CreateClientStream(cCtx, sessionID, id, 0, 0)
+			require.NoError(t, err)
+			if stream != nil {
+				assert.Equal(t, int32(1), sp.sends.Load())
+				// A bound spare is held until its connection is done.
+				select {
+				case <-held:
+					require.Fail(t, "bound spare stream was released before its connection ended")
+				case <-time.After(2 * time.Millisecond):
+				}
+			} else {
+				assert.Zero(t, sp.sends.Load())
+			}
+			cancel()
+			select {
+			case <-held:
+			case <-time.After(time.Second):
+				require.Fail(t, "spare stream was never released")
+			}
+			_, ok := agent.SparePoolSize(s, sessionID)
+			require.False(t, ok, "empty pool was not deleted")
+		}
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"sync"
 
 	core "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
@@ -26,6 +27,9 @@ type WatcherCallback func(watch.EventType, runtime.Object) error
 type Watcher interface {
 	Run(ctx context.Context) error
 	GetClientConfigYaml() []byte
+
+	// Ready returns a channel that is closed once the client configuration has been loaded.
+	Ready() <-chan struct{}
 }
 
 type config struct {
@@ -33,14 +37,20 @@ type config struct {
 	namespace string
 
 	clientYAML []byte
+	ready      chan struct{}
 }
 
 func NewWatcher(namespace string) Watcher {
 	return &config{
 		namespace: namespace,
+		ready:     make(chan struct{}),
 	}
 }
 
+func (c *config) Ready() <-chan struct{} {
+	return c.ready
+}
+
 func (c *config) Run(ctx context.Context) error {
 	dlog.Infof(ctx, "Started watcher for ConfigMap %s", cfgConfigMapName)
 	defer dlog.Infof(ctx, "Ended watcher for ConfigMap %s", cfgConfigMapName)
@@ -48,6 +58,20 @@ func (c *config) Run(ctx context.Context) error {
 	// The Watch will perform a http GET call to the kubernetes API server, and that connection will not remain open forever
 	// so when it closes, the watch must start over. This goes on until the context is cancelled.
 	api := k8sapi.GetK8sInterface(ctx).CoreV1()
+
+	// A watch doesn't report a ConfigMap that doesn't exist, so the initial state is loaded using a
+	// get before the watcher is considered ready.
+	cm, err := api.ConfigMaps(c.namespace).Get(ctx, cfgConfigMapName, meta.GetOptions{})
+	switch {
+	case err == nil:
+		c.refreshFile(ctx, cm.Data)
+	case k8sErrors.IsNotFound(err):
+		c.refreshFile(ctx, nil)
+	default:
+		return fmt.Errorf("unable to get configmap %s: %v", cfgConfigMapName, err)
+	}
+	close(c.ready)
+
 	for ctx.Err() == nil {
 		w, err := api.ConfigMaps(c.namespace).Watch(ctx, meta.SingleObject(meta.ObjectMeta{Name: cfgConfigMapName}))
 		if err != nil {
@@ -2,24 +2,66 @@ package manager
 
 import (
 	"context"
+	"time"
 
+	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/datawire/k8sapi/pkg/k8sapi"
+	rpc "github.com/telepresenceio/telepresence/rpc/v2/manager"
 )
 
-// Perhaps replace this health check stuff with something more normal, i.e.
-// based on HTTP, since we'll likely be running the Injector as an HTTP service
-// from this same executable anyhow.
+// HealthChecker implements the standard gRPC health protocol for the traffic-manager. It reports
+// NOT_SERVING until the manager has connected to the Kubernetes API server and finished loading its
+// state, and then SERVING until the manager starts to shut down.
+type HealthChecker struct {
+	*health.Server
+}
 
-type HealthChecker struct{}
+func NewHealthChecker() *HealthChecker {
+	hs := health.NewServer()
+	hs.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	hs.SetServingStatus(rpc.Manager_ServiceDesc.ServiceName, grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	return &HealthChecker{Server: hs}
+}
 
-func (s *HealthChecker) Check(ctx context.Context, _ *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
-	return &grpc_health_v1.HealthCheckResponse{
-		Status: grpc_health_v1.HealthCheckResponse_SERVING,
-	}, nil
+// SetServing sets the serving status of the traffic-manager.
+func (h *HealthChecker) SetServing(serving bool) {
+	st := grpc_health_v1.HealthCheckResponse_NOT_SERVING
+	if serving {
+		st = grpc_health_v1.HealthCheckResponse_SERVING
+	}
+	h.SetServingStatus("", st)
+	h.SetServingStatus(rpc.Manager_ServiceDesc.ServiceName, st)
 }
 
-func (s *HealthChecker) Watch(_ *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
-	return stream.Send(&grpc_health_v1.HealthCheckResponse{
-		Status: grpc_health_v1.HealthCheckResponse_SERVING,
-	})
+// Run waits until the Kubernetes API server responds and all the given ready channels are closed,
+// and then reports SERVING. The status changes to NOT_SERVING for good when the context is cancelled.
+func (h *HealthChecker) Run(ctx context.Context, ready ...<-chan struct{}) error {
+	defer h.Shutdown()
+	for {
+		_, err := k8sapi.GetK8sInterface(ctx).Discovery().ServerVersion()
+		if err == nil {
+			break
+		}
+		dlog.Debugf(ctx, "health check: API server not yet reachable: %v", err)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Second):
+		}
+	}
+	for _, r := range ready {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r:
+		}
+	}
+	h.SetServing(true)
+	dlog.Info(ctx, "Health status is SERVING")
+	<-ctx.Done()
+	dlog.Info(ctx, "Health status is NOT_SERVING")
+	return nil
 }
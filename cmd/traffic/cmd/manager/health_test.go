@@ -0,0 +1,54 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/datawire/k8sapi/pkg/k8sapi"
+)
+
+func TestHealthChecker(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	ctx = k8sapi.WithK8sInterface(ctx, fake.NewSimpleClientset())
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	h := NewHealthChecker()
+	check := func() grpc_health_v1.HealthCheckResponse_ServingStatus {
+		r, err := h.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+		require.NoError(t, err)
+		return r.Status
+	}
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, check())
+
+	ready := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.NoError(t, h.Run(ctx, ready))
+	}()
+
+	// The API server is reachable, but the manager hasn't finished loading its state.
+	require.Never(t, func() bool {
+		return check() == grpc_health_v1.HealthCheckResponse_SERVING
+	}, 200*time.Millisecond, 10*time.Millisecond)
+
+	close(ready)
+	require.Eventually(t, func() bool {
+		return check() == grpc_health_v1.HealthCheckResponse_SERVING
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Shutdown flips the status to NOT_SERVING, and it stays that way.
+	cancel()
+	<-done
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, check())
+	h.SetServing(true)
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, check())
+}
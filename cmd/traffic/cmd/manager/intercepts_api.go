@@ -0,0 +1,70 @@
+package manager
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+
+	rpc "github.com/telepresenceio/telepresence/rpc/v2/manager"
+)
+
+// interceptsAPIPath is the path of the read-only JSON endpoint that lists the intercepts.
+const interceptsAPIPath = "/api/v1/intercepts"
+
+// interceptJSON is the JSON representation of an intercept in the response of the intercepts API.
+type interceptJSON struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Client       string `json:"client"`
+	Workload     string `json:"workload"`
+	WorkloadKind string `json:"workloadKind,omitempty"`
+	Namespace    string `json:"namespace"`
+	Disposition  string `json:"disposition"`
+	Message      string `json:"message,omitempty"`
+}
+
+// interceptsAPIHandler returns a handler that responds to GET requests with a JSON array of all intercepts
+// that haven't been removed. Requests must carry the given token as a bearer token.
+func (s *service) interceptsAPIHandler(token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bt, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(bt), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="traffic-manager"`)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		iis := s.state.LoadMatchingIntercepts(func(_ string, ii *rpc.InterceptInfo) bool {
+			return ii.Disposition != rpc.InterceptDispositionType_REMOVED
+		})
+		ijs := make([]interceptJSON, 0, len(iis))
+		for _, ii := range iis {
+			spec := ii.Spec
+			ijs = append(ijs, interceptJSON{
+				ID:           ii.Id,
+				Name:         spec.Name,
+				Client:       spec.Client,
+				Workload:     spec.Agent,
+				WorkloadKind: spec.WorkloadKind,
+				Namespace:    spec.Namespace,
+				Disposition:  ii.Disposition.String(),
+				Message:      ii.Message,
+			})
+		}
+		slices.SortFunc(ijs, func(a, b interceptJSON) int {
+			if c := strings.Compare(a.Namespace, b.Namespace); c != 0 {
+				return c
+			}
+			return strings.Compare(a.Name, b.Name)
+		})
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = json.NewEncoder(w).Encode(ijs)
+	})
+}
@@ -0,0 +1,107 @@
+package manager
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rpc "github.com/telepresenceio/telepresence/rpc/v2/manager"
+	"github.com/telepresenceio/telepresence/v2/cmd/traffic/cmd/manager/state"
+)
+
+type interceptsState struct {
+	state.State
+	intercepts map[string]*rpc.InterceptInfo
+}
+
+func (s *interceptsState) LoadMatchingIntercepts(filter func(string, *rpc.InterceptInfo) bool) map[string]*rpc.InterceptInfo {
+	m := make(map[string]*rpc.InterceptInfo)
+	for id, ii := range s.intercepts {
+		if filter(id, ii) {
+			m[id] = ii
+		}
+	}
+	return m
+}
+
+func TestInterceptsAPI(t *testing.T) {
+	ii := func(id, name, ns string, d rpc.InterceptDispositionType) *rpc.InterceptInfo {
+		return &rpc.InterceptInfo{
+			Id: id,
+			Spec: &rpc.InterceptSpec{
+				Name:         name,
+				Client:       "alice@host",
+				Agent:        "echo",
+				WorkloadKind: "Deployment",
+				Namespace:    ns,
+			},
+			Disposition: d,
+		}
+	}
+	s := &service{state: &interceptsState{intercepts: map[string]*rpc.InterceptInfo{
+		"1": ii("1", "echo-b", "b", rpc.InterceptDispositionType_ACTIVE),
+		"2": ii("2", "echo-a", "a", rpc.InterceptDispositionType_WAITING),
+		"3": ii("3", "echo-c", "a", rpc.InterceptDispositionType_REMOVED),
+	}}}
+	h := s.interceptsAPIHandler("s3cr3t")
+
+	get := func(auth string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, interceptsAPIPath, nil)
+		if auth != "" {
+			r.Header.Set("Authorization", auth)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("no token", func(t *testing.T) {
+		w := get("")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+	})
+
+	t.Run("wrong token", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, get("Bearer wrong").Code)
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, interceptsAPIPath, nil)
+		r.Header.Set("Authorization", "Bearer s3cr3t")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		w := get("Bearer s3cr3t")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		var ijs []interceptJSON
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &ijs))
+		assert.Equal(t, []interceptJSON{
+			{
+				ID:           "2",
+				Name:         "echo-a",
+				Client:       "alice@host",
+				Workload:     "echo",
+				WorkloadKind: "Deployment",
+				Namespace:    "a",
+				Disposition:  "WAITING",
+			},
+			{
+				ID:           "1",
+				Name:         "echo-b",
+				Client:       "alice@host",
+				Workload:     "echo",
+				WorkloadKind: "Deployment",
+				Namespace:    "b",
+				Disposition:  "ACTIVE",
+			},
+		}, ijs)
+	})
+}
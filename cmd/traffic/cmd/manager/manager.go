@@ -3,6 +3,7 @@ package manager
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"slices"
@@ -142,6 +143,8 @@ func MainWithEnv(ctx context.Context) (err error) {
 
 	g.Go("cli-config", mgr.runConfigWatcher)
 
+	g.Go("health", mgr.runHealthChecker)
+
 	// Serve HTTP (including gRPC)
 	g.Go("httpd", mgr.serveHTTP)
 
@@ -175,6 +178,14 @@ func newCounterFunc[T int | uint64](n, h string, f func() T) {
 	}, func() float64 { return float64(f()) })
 }
 
+func newLabeledCounterFunc[T int | uint64](n, h string, labels prometheus.Labels, f func() T) {
+	promauto.NewCounterFunc(prometheus.CounterOpts{
+		Name:        n,
+		Help:        h,
+		ConstLabels: labels,
+	}, func() float64 { return float64(f()) })
+}
+
 func newGaugeFunc[T int | uint64](n, h string, f func() T) {
 	promauto.NewGaugeFunc(prometheus.GaugeOpts{
 		Name: n,
@@ -235,6 +246,10 @@ func (s *service) servePrometheus(ctx context.Context) error {
 	newGaugeFunc("tunnel_count", "Number of tunnels", s.state.CountTunnels)
 	newCounterFunc("tunnel_ingress_bytes", "Number of bytes tunneled from clients", s.state.CountTunnelIngress)
 	newCounterFunc("tunnel_egress_bytes", "Number bytes tunneled to clients", s.state.CountTunnelEgress)
+	newLabeledCounterFunc("tunnel_bytes_total", "Number of tunneled bytes",
+		prometheus.Labels{"direction": "ingress"}, s.state.CountTunnelIngress)
+	newLabeledCounterFunc("tunnel_bytes_total", "Number of tunneled bytes",
+		prometheus.Labels{"direction": "egress"}, s.state.CountTunnelEgress)
 
 	newGaugeFunc("active_http_request_count", "Number of currently served http requests", func() int {
 		return int(atomic.LoadInt32(&s.activeHttpRequests))
@@ -287,6 +302,18 @@ func (s *service) serveHTTP(ctx context.Context) error {
 	httpHandler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(w, "Hello World from: %s\n", r.URL.Path)
 	}))
+	switch {
+	case env.InterceptsAPIToken == "":
+	case env.GrpcTLSDir == "":
+		// The bearer token would be sent in plaintext over the cluster network.
+		dlog.Errorf(ctx, "Intercepts API is not served, because it requires that grpc.tls.enabled is true")
+	default:
+		mux := http.NewServeMux()
+		mux.Handle(interceptsAPIPath, s.interceptsAPIHandler(env.InterceptsAPIToken))
+		mux.Handle("/", httpHandler)
+		httpHandler = mux
+		dlog.Infof(ctx, "Intercepts API is served on %s", interceptsAPIPath)
+	}
 
 	lg := dlog.StdLogger(ctx, dlog.MaxLogLevel(ctx))
 	addr := iputil.JoinHostPort(host, port)
@@ -310,12 +337,25 @@ func (s *service) serveHTTP(ctx context.Context) error {
 		ErrorLog: lg,
 	}
 	s.self.RegisterServers(grpcHandler)
-	return sc.ListenAndServe(ctx, fmt.Sprintf("%s:%d", host, port))
+	if env.GrpcTLSDir == "" {
+		return sc.ListenAndServe(ctx, fmt.Sprintf("%s:%d", host, port))
+	}
+
+	tlsConfig, err := loadTLSConfig(ctx, env.GrpcTLSDir)
+	if err != nil {
+		return err
+	}
+	ln, err := net.Listen("tcp", fmt.Sprintf("%s:%d", host, port))
+	if err != nil {
+		return err
+	}
+	dlog.Infof(ctx, "gRPC API on %s is served using mutual TLS", addr)
+	return sc.Serve(ctx, newTLSListener(ctx, ln, tlsConfig))
 }
 
 func (s *service) RegisterServers(grpcHandler *grpc.Server) {
 	rpc.RegisterManagerServer(grpcHandler, s)
-	grpc_health_v1.RegisterHealthServer(grpcHandler, &HealthChecker{})
+	grpc_health_v1.RegisterHealthServer(grpcHandler, s.healthChecker)
 }
 
 func (s *service) runSessionGCLoop(ctx context.Context) error {
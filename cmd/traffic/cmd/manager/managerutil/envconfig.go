@@ -3,7 +3,10 @@ package managerutil
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
@@ -35,14 +38,22 @@ type Env struct {
 	ServerHost          string        `env:"SERVER_HOST,              parser=string,      default="`
 	ServerPort          uint16        `env:"SERVER_PORT,              parser=port-number"`
 	PrometheusPort      uint16        `env:"PROMETHEUS_PORT,          parser=port-number, default=0"`
+	InterceptsAPIToken  string        `env:"INTERCEPTS_API_TOKEN,     parser=string,      default="`
 	MutatorWebhookPort  uint16        `env:"MUTATOR_WEBHOOK_PORT,     parser=port-number, default=0"`
 	ManagerNamespace    string        `env:"MANAGER_NAMESPACE,        parser=string,      default="`
+	ManagerServiceName  string        `env:"MANAGER_SERVICE_NAME,     parser=string,      default=traffic-manager"`
 	ManagedNamespaces   []string      `env:"MANAGED_NAMESPACES,       parser=split-trim,  default="`
 	APIPort             uint16        `env:"AGENT_REST_API_PORT,      parser=port-number, default=0"`
 	AgentArrivalTimeout time.Duration `env:"AGENT_ARRIVAL_TIMEOUT,    parser=time.ParseDuration, default=0"`
 
+	InterceptMaxPerClient      int           `env:"INTERCEPT_MAX_PER_CLIENT,     parser=strconv.ParseInt,  default=0"`
+	InterceptMaxPerWorkload    int           `env:"INTERCEPT_MAX_PER_WORKLOAD,   parser=strconv.ParseInt,  default=0"`
+	InterceptMaxLifetime       time.Duration `env:"INTERCEPT_MAX_LIFETIME,       parser=time.ParseDuration, default=0"`
+	InterceptDisableAnnotation string        `env:"INTERCEPT_DISABLE_ANNOTATION, parser=string,            default=telepresence.getambassador.io/intercept"`
+
 	TracingGrpcPort uint16            `env:"TRACING_GRPC_PORT,     parser=port-number,default=0"`
 	MaxReceiveSize  resource.Quantity `env:"GRPC_MAX_RECEIVE_SIZE, parser=quantity"`
+	GrpcTLSDir      string            `env:"GRPC_TLS_DIR,          parser=string,     default="`
 
 	PodCIDRStrategy string       `env:"POD_CIDR_STRATEGY, parser=nonempty-string"`
 	PodCIDRs        []*net.IPNet `env:"POD_CIDRS,         parser=split-ipnet, default="`
@@ -53,6 +64,7 @@ type Env struct {
 	AgentImageTag            string                      `env:"AGENT_IMAGE_TAG,          parser=string,         default="`
 	AgentImagePullPolicy     string                      `env:"AGENT_IMAGE_PULL_POLICY,  parser=string,         default="`
 	AgentImagePullSecrets    []core.LocalObjectReference `env:"AGENT_IMAGE_PULL_SECRETS, parser=json-local-refs,default="`
+	AgentImageOverrides      []string                    `env:"AGENT_IMAGE_OVERRIDES,    parser=split-trim,     default="`
 	AgentInjectPolicy        agentconfig.InjectPolicy    `env:"AGENT_INJECT_POLICY,      parser=enable-policy,  default=Never"`
 	AgentAppProtocolStrategy k8sapi.AppProtocolStrategy  `env:"AGENT_APP_PROTO_STRATEGY, parser=app-proto-strategy, default=http2Probe"`
 	AgentLogLevel            string                      `env:"AGENT_LOG_LEVEL,          parser=logLevel,       defaultFrom=LogLevel"`
@@ -62,6 +74,10 @@ type Env struct {
 	AgentInjectorName        string                      `env:"AGENT_INJECTOR_NAME,      parser=string,         default="`
 	AgentInjectorSecret      string                      `env:"AGENT_INJECTOR_SECRET,    parser=string,         default="`
 	AgentSecurityContext     *core.SecurityContext       `env:"AGENT_SECURITY_CONTEXT,   parser=json-security-context, default="`
+	AgentPodLabels           map[string]string           `env:"AGENT_POD_LABELS,         parser=json-string-map, default="`
+	AgentPodAnnotations      map[string]string           `env:"AGENT_POD_ANNOTATIONS,    parser=json-string-map, default="`
+	AgentPodNodeSelector     map[string]string           `env:"AGENT_POD_NODE_SELECTOR,  parser=json-string-map, default="`
+	AgentPodAffinity         *core.Affinity              `env:"AGENT_POD_AFFINITY,       parser=json-affinity,  default="`
 
 	ClientRoutingAlsoProxySubnets        []*net.IPNet  `env:"CLIENT_ROUTING_ALSO_PROXY_SUBNETS,  		parser=split-ipnet, default="`
 	ClientRoutingNeverProxySubnets       []*net.IPNet  `env:"CLIENT_ROUTING_NEVER_PROXY_SUBNETS, 		parser=split-ipnet, default="`
@@ -74,13 +90,35 @@ type Env struct {
 }
 
 func (e *Env) GeneratorConfig(qualifiedAgentImage string) (agentmap.GeneratorConfig, error) {
+	var managerTLSCA, managerTLSCert, managerTLSKey string
+	if e.GrpcTLSDir != "" {
+		// The files are read each time, so that agents get the current ones after a rotation.
+		for _, f := range []struct {
+			name string
+			dst  *string
+		}{
+			{"ca.crt", &managerTLSCA},
+			{"client.crt", &managerTLSCert},
+			{"client.key", &managerTLSKey},
+		} {
+			data, err := os.ReadFile(filepath.Join(e.GrpcTLSDir, f.name))
+			if err != nil {
+				return nil, fmt.Errorf("unable to read the %s of the gRPC TLS certificates: %w", f.name, err)
+			}
+			*f.dst = string(data)
+		}
+	}
 	return &agentmap.BasicGeneratorConfig{
 		AgentPort:           e.AgentPort,
 		APIPort:             e.APIPort,
 		TracingPort:         e.TracingGrpcPort,
 		ManagerPort:         e.ServerPort,
+		ManagerTLSCA:        managerTLSCA,
+		ManagerTLSCert:      managerTLSCert,
+		ManagerTLSKey:       managerTLSKey,
 		QualifiedAgentImage: qualifiedAgentImage,
 		ManagerNamespace:    e.ManagerNamespace,
+		ManagerServiceName:  e.ManagerServiceName,
 		LogLevel:            e.AgentLogLevel,
 		InitResources:       e.AgentInitResources,
 		Resources:           e.AgentResources,
@@ -200,6 +238,21 @@ func fieldTypeHandlers() map[reflect.Type]envconfig.FieldTypeHandler {
 		},
 		Setter: func(dst reflect.Value, src interface{}) { dst.Set(reflect.ValueOf(src.([]core.LocalObjectReference))) },
 	}
+	fhs[reflect.TypeOf(map[string]string{})] = envconfig.FieldTypeHandler{
+		Parsers: map[string]func(string) (any, error){
+			"json-string-map": func(js string) (any, error) {
+				if js == "" {
+					return nil, nil
+				}
+				var m map[string]string
+				if err := json.Unmarshal([]byte(js), &m); err != nil {
+					return nil, err
+				}
+				return m, nil
+			},
+		},
+		Setter: func(dst reflect.Value, src interface{}) { dst.Set(reflect.ValueOf(src.(map[string]string))) },
+	}
 	fhs[reflect.TypeOf(&core.ResourceRequirements{})] = envconfig.FieldTypeHandler{
 		Parsers: map[string]func(string) (any, error){
 			"json-resources": func(js string) (any, error) {
@@ -230,6 +283,21 @@ func fieldTypeHandlers() map[reflect.Type]envconfig.FieldTypeHandler {
 		},
 		Setter: func(dst reflect.Value, src interface{}) { dst.Set(reflect.ValueOf(src.(*core.SecurityContext))) },
 	}
+	fhs[reflect.TypeOf(&core.Affinity{})] = envconfig.FieldTypeHandler{
+		Parsers: map[string]func(string) (any, error){
+			"json-affinity": func(js string) (any, error) {
+				if js == "" {
+					return nil, nil
+				}
+				var af *core.Affinity
+				if err := json.Unmarshal([]byte(js), &af); err != nil {
+					return nil, err
+				}
+				return af, nil
+			},
+		},
+		Setter: func(dst reflect.Value, src interface{}) { dst.Set(reflect.ValueOf(src.(*core.Affinity))) },
+	}
 	fhs[reflect.TypeOf(true)] = envconfig.FieldTypeHandler{
 		Parsers: map[string]func(string) (any, error){
 			"bool": func(str string) (any, error) {
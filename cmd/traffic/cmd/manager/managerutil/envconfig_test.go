@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	core "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 
 	"github.com/datawire/k8sapi/pkg/k8sapi"
@@ -49,10 +50,13 @@ func TestEnvconfig(t *testing.T) {
 		ClientConnectionTTL:      24 * time.Hour,
 		ClientDnsExcludeSuffixes: []string{".com", ".io", ".net", ".org", ".ru"},
 		LogLevel:                 "info",
+		ManagerServiceName:       "traffic-manager",
 		MaxReceiveSize:           resource.MustParse("4Mi"),
 		PodCIDRStrategy:          "auto",
 		PodIP:                    net.IP{203, 0, 113, 18},
 		ServerPort:               8081,
+
+		InterceptDisableAnnotation: "telepresence.getambassador.io/intercept",
 	}
 
 	testcases := map[string]struct {
@@ -73,6 +77,14 @@ func TestEnvconfig(t *testing.T) {
 				e.ArgoRolloutsEnabled = true
 			},
 		},
+		"service-name": {
+			Input: map[string]string{
+				"MANAGER_SERVICE_NAME": "tel-manager",
+			},
+			Output: func(e *managerutil.Env) {
+				e.ManagerServiceName = "tel-manager"
+			},
+		},
 		"complex": {
 			Input: map[string]string{
 				"CLIENT_ROUTING_NEVER_PROXY_SUBNETS": "10.20.30.0/24 10.20.40.0/24",
@@ -83,6 +95,104 @@ func TestEnvconfig(t *testing.T) {
 				e.ClientRoutingNeverProxySubnets = []*net.IPNet{a, b}
 			},
 		},
+		"pod labels and annotations": {
+			Input: map[string]string{
+				"AGENT_POD_LABELS":      `{"team":"platform"}`,
+				"AGENT_POD_ANNOTATIONS": `{"cost-center":"1234"}`,
+			},
+			Output: func(e *managerutil.Env) {
+				e.AgentPodLabels = map[string]string{"team": "platform"}
+				e.AgentPodAnnotations = map[string]string{"cost-center": "1234"}
+			},
+		},
+		"intercept limits": {
+			Input: map[string]string{
+				"INTERCEPT_MAX_PER_CLIENT":   "3",
+				"INTERCEPT_MAX_PER_WORKLOAD": "2",
+			},
+			Output: func(e *managerutil.Env) {
+				e.InterceptMaxPerClient = 3
+				e.InterceptMaxPerWorkload = 2
+			},
+		},
+		"intercept max lifetime": {
+			Input: map[string]string{
+				"INTERCEPT_MAX_LIFETIME": "8h",
+			},
+			Output: func(e *managerutil.Env) {
+				e.InterceptMaxLifetime = 8 * time.Hour
+			},
+		},
+		"intercept disable annotation": {
+			Input: map[string]string{
+				"INTERCEPT_DISABLE_ANNOTATION": "example.com/intercept",
+			},
+			Output: func(e *managerutil.Env) {
+				e.InterceptDisableAnnotation = "example.com/intercept"
+			},
+		},
+		"intercept disable annotation turned off": {
+			Input: map[string]string{
+				"INTERCEPT_DISABLE_ANNOTATION": "",
+			},
+			Output: func(e *managerutil.Env) {
+				e.InterceptDisableAnnotation = ""
+			},
+		},
+		"intercepts api": {
+			Input: map[string]string{
+				"INTERCEPTS_API_TOKEN": "s3cr3t",
+			},
+			Output: func(e *managerutil.Env) {
+				e.InterceptsAPIToken = "s3cr3t"
+			},
+		},
+		"pod scheduling": {
+			Input: map[string]string{
+				"AGENT_POD_NODE_SELECTOR": `{"kubernetes.io/hostname":"debug-node"}`,
+				"AGENT_POD_AFFINITY":      `{"nodeAffinity":{"preferredDuringSchedulingIgnoredDuringExecution":[{"weight":1,"preference":{"matchExpressions":[{"key":"pool","operator":"In","values":["debug"]}]}}]}}`,
+			},
+			Output: func(e *managerutil.Env) {
+				e.AgentPodNodeSelector = map[string]string{"kubernetes.io/hostname": "debug-node"}
+				e.AgentPodAffinity = &core.Affinity{
+					NodeAffinity: &core.NodeAffinity{
+						PreferredDuringSchedulingIgnoredDuringExecution: []core.PreferredSchedulingTerm{{
+							Weight: 1,
+							Preference: core.NodeSelectorTerm{
+								MatchExpressions: []core.NodeSelectorRequirement{{
+									Key:      "pool",
+									Operator: core.NodeSelectorOpIn,
+									Values:   []string{"debug"},
+								}},
+							},
+						}},
+					},
+				}
+			},
+		},
+		"resources": {
+			Input: map[string]string{
+				"AGENT_RESOURCES":      `{"requests":{"cpu":"50m","memory":"64Mi"},"limits":{"cpu":"200m","memory":"128Mi"}}`,
+				"AGENT_INIT_RESOURCES": `{"requests":{"cpu":"10m"}}`,
+			},
+			Output: func(e *managerutil.Env) {
+				e.AgentResources = &core.ResourceRequirements{
+					Requests: core.ResourceList{
+						core.ResourceCPU:    resource.MustParse("50m"),
+						core.ResourceMemory: resource.MustParse("64Mi"),
+					},
+					Limits: core.ResourceList{
+						core.ResourceCPU:    resource.MustParse("200m"),
+						core.ResourceMemory: resource.MustParse("128Mi"),
+					},
+				}
+				e.AgentInitResources = &core.ResourceRequirements{
+					Requests: core.ResourceList{
+						core.ResourceCPU: resource.MustParse("10m"),
+					},
+				}
+			},
+		},
 	}
 
 	for tcName, tc := range testcases {
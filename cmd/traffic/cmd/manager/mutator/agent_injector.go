@@ -195,6 +195,7 @@ func (a *agentInjector) Inject(ctx context.Context, req *admission.AdmissionRequ
 	patches = hidePorts(pod, config, patches)
 	patches = addPodAnnotations(ctx, pod, patches)
 	patches = addPodLabels(ctx, pod, config, patches)
+	patches = addPodScheduling(ctx, pod, patches)
 
 	if config.APIPort != 0 {
 		tpEnv := make(map[string]string)
@@ -456,7 +457,8 @@ func addAgentContainer(
 	})
 }
 
-// addAgentContainer creates a patch operation to add the traffic-agent container.
+// addPullSecrets creates patch operations that add the configured pull secrets to the pod's imagePullSecrets.
+// Secrets that the pod already lists are retained, so the result is the union of both lists.
 func addPullSecrets(
 	pod *core.Pod,
 	config *agentconfig.Sidecar,
@@ -611,7 +613,10 @@ func hideContainerPorts(pod *core.Pod, app *core.Container, isReplace bool, port
 	return patches
 }
 
-func addPodAnnotations(_ context.Context, pod *core.Pod, patches PatchOps) PatchOps {
+// addPodAnnotations ensures that the pod has the inject annotation and the annotations configured
+// for injected pods using AGENT_POD_ANNOTATIONS. Annotations that are already present on the pod are
+// never changed.
+func addPodAnnotations(ctx context.Context, pod *core.Pod, patches PatchOps) PatchOps {
 	op := "replace"
 	changed := false
 	am := pod.Annotations
@@ -626,6 +631,9 @@ func addPodAnnotations(_ context.Context, pod *core.Pod, patches PatchOps) Patch
 		changed = true
 		am[agentconfig.InjectAnnotation] = "enabled"
 	}
+	if mergeMissing(ctx, "annotation", am, managerutil.GetEnv(ctx).AgentPodAnnotations) {
+		changed = true
+	}
 
 	if changed {
 		patches = append(patches, PatchOperation{
@@ -637,7 +645,9 @@ func addPodAnnotations(_ context.Context, pod *core.Pod, patches PatchOps) Patch
 	return patches
 }
 
-func addPodLabels(_ context.Context, pod *core.Pod, config agentconfig.SidecarExt, patches PatchOps) PatchOps {
+// addPodLabels ensures that the pod has the workload labels and the labels configured for injected
+// pods using AGENT_POD_LABELS. Labels that are already present on the pod are never changed.
+func addPodLabels(ctx context.Context, pod *core.Pod, config agentconfig.SidecarExt, patches PatchOps) PatchOps {
 	op := "replace"
 	changed := false
 	lm := pod.Labels
@@ -659,6 +669,9 @@ func addPodLabels(_ context.Context, pod *core.Pod, config agentconfig.SidecarEx
 		changed = true
 		lm[agentconfig.WorkloadEnabledLabel] = "true"
 	}
+	if mergeMissing(ctx, "label", lm, managerutil.GetEnv(ctx).AgentPodLabels) {
+		changed = true
+	}
 	if changed {
 		patches = append(patches, PatchOperation{
 			Op:    op,
@@ -669,6 +682,60 @@ func addPodLabels(_ context.Context, pod *core.Pod, config agentconfig.SidecarEx
 	return patches
 }
 
+// addPodScheduling adds the node selector entries configured using AGENT_POD_NODE_SELECTOR and the affinity
+// configured using AGENT_POD_AFFINITY, so that injected pods are scheduled on predictable nodes. Node selector
+// entries that are already present on the pod are never changed, and the affinity is only added when the pod
+// has none.
+func addPodScheduling(ctx context.Context, pod *core.Pod, patches PatchOps) PatchOps {
+	env := managerutil.GetEnv(ctx)
+	if len(env.AgentPodNodeSelector) > 0 {
+		op := "replace"
+		ns := pod.Spec.NodeSelector
+		if ns == nil {
+			op = "add"
+			ns = make(map[string]string)
+		} else {
+			ns = maps.Copy(ns)
+		}
+		if mergeMissing(ctx, "node selector", ns, env.AgentPodNodeSelector) {
+			patches = append(patches, PatchOperation{
+				Op:    op,
+				Path:  "/spec/nodeSelector",
+				Value: ns,
+			})
+		}
+	}
+	if env.AgentPodAffinity != nil {
+		if pod.Spec.Affinity == nil {
+			patches = append(patches, PatchOperation{
+				Op:    "add",
+				Path:  "/spec/affinity",
+				Value: env.AgentPodAffinity,
+			})
+		} else {
+			dlog.Debugf(ctx, "Pod %s.%s affinity is retained instead of the configured affinity", pod.Name, pod.Namespace)
+		}
+	}
+	return patches
+}
+
+// mergeMissing adds the entries of src that are not already present in dst, and returns true if
+// at least one entry was added. Entries that exist with a different value are left untouched.
+func mergeMissing(ctx context.Context, kind string, dst, src map[string]string) bool {
+	changed := false
+	for k, v := range src {
+		if ov, ok := dst[k]; ok {
+			if ov != v {
+				dlog.Debugf(ctx, "Pod %s %s=%q is retained instead of the configured value %q", kind, k, ov, v)
+			}
+			continue
+		}
+		dst[k] = v
+		changed = true
+	}
+	return changed
+}
+
 const maxPortNameLen = 15
 
 // hiddenPortName prefixes the given name with "tm-" and truncates it to 15 characters. If
@@ -36,6 +36,10 @@ func int32P(i int32) *int32 {
 	return &i
 }
 
+func int64P(i int64) *int64 {
+	return &i
+}
+
 func boolP(b bool) *bool {
 	return &b
 }
@@ -1089,6 +1093,11 @@ func TestTrafficAgentInjector(t *testing.T) {
         fieldRef:
           apiVersion: v1
           fieldPath: metadata.name
+    - name: _TEL_AGENT_NODE_NAME
+      valueFrom:
+        fieldRef:
+          apiVersion: v1
+          fieldPath: spec.nodeName
     image: ghcr.io/telepresenceio/tel2:2.13.3
     name: traffic-agent
     ports:
@@ -1101,6 +1110,15 @@ func TestTrafficAgentInjector(t *testing.T) {
         - /bin/stat
         - /tmp/agent/ready
     resources: {}
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        add:
+        - NET_BIND_SERVICE
+        drop:
+        - ALL
+      seccompProfile:
+        type: RuntimeDefault
     volumeMounts:
     - mountPath: /tel_pod_info
       name: traffic-annotations
@@ -1181,6 +1199,11 @@ func TestTrafficAgentInjector(t *testing.T) {
         fieldRef:
           apiVersion: v1
           fieldPath: metadata.name
+    - name: _TEL_AGENT_NODE_NAME
+      valueFrom:
+        fieldRef:
+          apiVersion: v1
+          fieldPath: spec.nodeName
     image: ghcr.io/telepresenceio/tel2:2.13.3
     name: traffic-agent
     ports:
@@ -1193,6 +1216,15 @@ func TestTrafficAgentInjector(t *testing.T) {
         - /bin/stat
         - /tmp/agent/ready
     resources: {}
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        add:
+        - NET_BIND_SERVICE
+        drop:
+        - ALL
+      seccompProfile:
+        type: RuntimeDefault
     volumeMounts:
     - mountPath: /tel_pod_info
       name: traffic-annotations
@@ -1322,6 +1354,11 @@ func TestTrafficAgentInjector(t *testing.T) {
         fieldRef:
           apiVersion: v1
           fieldPath: metadata.name
+    - name: _TEL_AGENT_NODE_NAME
+      valueFrom:
+        fieldRef:
+          apiVersion: v1
+          fieldPath: spec.nodeName
     image: ghcr.io/telepresenceio/tel2:2.13.3
     name: traffic-agent
     ports:
@@ -1334,6 +1371,15 @@ func TestTrafficAgentInjector(t *testing.T) {
         - /bin/stat
         - /tmp/agent/ready
     resources: {}
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        add:
+        - NET_BIND_SERVICE
+        drop:
+        - ALL
+      seccompProfile:
+        type: RuntimeDefault
     volumeMounts:
     - mountPath: /tel_pod_info
       name: traffic-annotations
@@ -1430,6 +1476,11 @@ func TestTrafficAgentInjector(t *testing.T) {
         fieldRef:
           apiVersion: v1
           fieldPath: metadata.name
+    - name: _TEL_AGENT_NODE_NAME
+      valueFrom:
+        fieldRef:
+          apiVersion: v1
+          fieldPath: spec.nodeName
     image: ghcr.io/telepresenceio/tel2:2.13.3
     name: traffic-agent
     ports:
@@ -1441,6 +1492,15 @@ func TestTrafficAgentInjector(t *testing.T) {
         - /bin/stat
         - /tmp/agent/ready
     resources: {}
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        add:
+        - NET_BIND_SERVICE
+        drop:
+        - ALL
+      seccompProfile:
+        type: RuntimeDefault
     volumeMounts:
     - mountPath: /tel_pod_info
       name: traffic-annotations
@@ -1538,6 +1598,11 @@ func TestTrafficAgentInjector(t *testing.T) {
         fieldRef:
           apiVersion: v1
           fieldPath: metadata.name
+    - name: _TEL_AGENT_NODE_NAME
+      valueFrom:
+        fieldRef:
+          apiVersion: v1
+          fieldPath: spec.nodeName
     image: ghcr.io/telepresenceio/tel2:2.13.3
     name: traffic-agent
     ports:
@@ -1549,6 +1614,15 @@ func TestTrafficAgentInjector(t *testing.T) {
         - /bin/stat
         - /tmp/agent/ready
     resources: {}
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        add:
+        - NET_BIND_SERVICE
+        drop:
+        - ALL
+      seccompProfile:
+        type: RuntimeDefault
     volumeMounts:
     - mountPath: /tel_pod_info
       name: traffic-annotations
@@ -1643,8 +1717,18 @@ func TestTrafficAgentInjector(t *testing.T) {
 										},
 									},
 								},
+								{
+									Name: "_TEL_AGENT_NODE_NAME",
+									ValueFrom: &core.EnvVarSource{
+										FieldRef: &core.ObjectFieldSelector{
+											APIVersion: "v1",
+											FieldPath:  "spec.nodeName",
+										},
+									},
+								},
 							},
 							Resources:                core.ResourceRequirements{},
+							SecurityContext:          agentconfig.DefaultSecurityContext(),
 							TerminationMessagePath:   "/dev/termination-log",
 							TerminationMessagePolicy: "File",
 							VolumeMounts: []core.VolumeMount{
@@ -1751,6 +1835,11 @@ func TestTrafficAgentInjector(t *testing.T) {
         fieldRef:
           apiVersion: v1
           fieldPath: metadata.name
+    - name: _TEL_AGENT_NODE_NAME
+      valueFrom:
+        fieldRef:
+          apiVersion: v1
+          fieldPath: spec.nodeName
     - name: A_TELEPRESENCE_MOUNTS
       value: /var/run/secrets/kubernetes.io/serviceaccount
     image: ghcr.io/telepresenceio/tel2:2.13.3
@@ -1765,6 +1854,15 @@ func TestTrafficAgentInjector(t *testing.T) {
         - /bin/stat
         - /tmp/agent/ready
     resources: {}
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        add:
+        - NET_BIND_SERVICE
+        drop:
+        - ALL
+      seccompProfile:
+        type: RuntimeDefault
     volumeMounts:
     - mountPath: /tel_app_mounts/some-container/var/run/secrets/kubernetes.io/serviceaccount
       name: $(_TEL_APP_A_TOKEN_VOLUME)
@@ -1880,6 +1978,177 @@ func TestTrafficAgentInjector(t *testing.T) {
 	}
 }
 
+func TestAgentContainerSecurityContext(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	pod := &core.Pod{
+		ObjectMeta: meta.ObjectMeta{Name: "echo", Namespace: "some-ns"},
+		Spec: core.PodSpec{
+			Containers: []core.Container{{Name: "echo", Image: "echo-image"}},
+		},
+	}
+	config := &agentconfig.Sidecar{
+		AgentName:    "echo",
+		AgentImage:   "ghcr.io/telepresenceio/tel2:2.13.3",
+		WorkloadName: "echo",
+		WorkloadKind: "Deployment",
+		Containers: []*agentconfig.Container{{
+			Name:       "echo",
+			Intercepts: []*agentconfig.Intercept{{ContainerPort: 8080, Protocol: core.ProtocolTCP}},
+		}},
+	}
+
+	agentSecurityContext := func(patches PatchOps) *core.SecurityContext {
+		t.Helper()
+		require.Len(t, patches, 1)
+		assert.Equal(t, "/spec/containers/-", patches[0].Path)
+		cn, ok := patches[0].Value.(*core.Container)
+		require.True(t, ok)
+		return cn.SecurityContext
+	}
+
+	t.Run("default", func(t *testing.T) {
+		sc := agentSecurityContext(addAgentContainer(ctx, pod, config, nil))
+		assert.Equal(t, agentconfig.DefaultSecurityContext(), sc)
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		configured := &core.SecurityContext{
+			RunAsNonRoot:             boolP(true),
+			RunAsUser:                int64P(1000),
+			AllowPrivilegeEscalation: boolP(false),
+			Capabilities:             &core.Capabilities{Drop: []core.Capability{"ALL"}},
+			SeccompProfile:           &core.SeccompProfile{Type: core.SeccompProfileTypeRuntimeDefault},
+		}
+		cc := *config
+		cc.SecurityContext = configured
+		sc := agentSecurityContext(addAgentContainer(ctx, pod, &cc, nil))
+		assert.Equal(t, configured, sc)
+	})
+}
+
+func TestAddPullSecrets(t *testing.T) {
+	config := &agentconfig.Sidecar{
+		PullSecrets: []core.LocalObjectReference{{Name: "agent-registry"}, {Name: "shared-registry"}},
+	}
+
+	t.Run("none on pod", func(t *testing.T) {
+		pod := &core.Pod{}
+		assert.Equal(t, PatchOps{{
+			Op:    "replace",
+			Path:  "/spec/imagePullSecrets",
+			Value: config.PullSecrets,
+		}}, addPullSecrets(pod, config, nil))
+	})
+
+	t.Run("union with pod", func(t *testing.T) {
+		pod := &core.Pod{Spec: core.PodSpec{
+			ImagePullSecrets: []core.LocalObjectReference{{Name: "app-registry"}, {Name: "shared-registry"}},
+		}}
+		assert.Equal(t, PatchOps{{
+			Op:    "add",
+			Path:  "/spec/imagePullSecrets/-",
+			Value: core.LocalObjectReference{Name: "agent-registry"},
+		}}, addPullSecrets(pod, config, nil))
+	})
+
+	t.Run("none configured", func(t *testing.T) {
+		assert.Empty(t, addPullSecrets(&core.Pod{}, &agentconfig.Sidecar{}, nil))
+	})
+}
+
+func TestAddPodLabelsAndAnnotations(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	ctx = managerutil.WithEnv(ctx, &managerutil.Env{
+		AgentPodLabels: map[string]string{
+			"team":    "platform",
+			"service": "configured",
+		},
+		AgentPodAnnotations: map[string]string{
+			"cost-center":                "1234",
+			agentconfig.InjectAnnotation: "disabled",
+		},
+	})
+	pod := &core.Pod{
+		ObjectMeta: meta.ObjectMeta{
+			Name:        "echo",
+			Namespace:   "some-ns",
+			Labels:      map[string]string{"service": "echo"},
+			Annotations: map[string]string{"cost-center": "42"},
+		},
+	}
+	config := &agentconfig.Sidecar{WorkloadName: "echo", WorkloadKind: "Deployment"}
+
+	patches := addPodAnnotations(ctx, pod, nil)
+	require.Len(t, patches, 1)
+	assert.Equal(t, "replace", patches[0].Op)
+	assert.Equal(t, map[string]string{
+		"cost-center":                "42",
+		agentconfig.InjectAnnotation: "enabled",
+	}, patches[0].Value)
+
+	patches = addPodLabels(ctx, pod, config, nil)
+	require.Len(t, patches, 1)
+	assert.Equal(t, map[string]string{
+		"service":                        "echo",
+		"team":                           "platform",
+		agentconfig.WorkloadNameLabel:    "echo",
+		agentconfig.WorkloadKindLabel:    "Deployment",
+		agentconfig.WorkloadEnabledLabel: "true",
+	}, patches[0].Value)
+
+	// Nothing is patched when all configured entries are present.
+	pod.Labels = patches[0].Value.(map[string]string)
+	assert.Empty(t, addPodLabels(ctx, pod, config, nil))
+}
+
+func TestAddPodScheduling(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	affinity := &core.Affinity{
+		PodAffinity: &core.PodAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []core.PodAffinityTerm{{
+				LabelSelector: &meta.LabelSelector{MatchLabels: map[string]string{"app": "debugger"}},
+				TopologyKey:   "kubernetes.io/hostname",
+			}},
+		},
+	}
+	ctx = managerutil.WithEnv(ctx, &managerutil.Env{
+		AgentPodNodeSelector: map[string]string{
+			"kubernetes.io/hostname": "debug-node",
+			"pool":                   "debug",
+		},
+		AgentPodAffinity: affinity,
+	})
+	pod := &core.Pod{
+		ObjectMeta: meta.ObjectMeta{
+			Name:      "echo",
+			Namespace: "some-ns",
+		},
+	}
+
+	patches := addPodScheduling(ctx, pod, nil)
+	require.Len(t, patches, 2)
+	assert.Equal(t, "add", patches[0].Op)
+	assert.Equal(t, "/spec/nodeSelector", patches[0].Path)
+	assert.Equal(t, map[string]string{
+		"kubernetes.io/hostname": "debug-node",
+		"pool":                   "debug",
+	}, patches[0].Value)
+	assert.Equal(t, "add", patches[1].Op)
+	assert.Equal(t, "/spec/affinity", patches[1].Path)
+	assert.Equal(t, affinity, patches[1].Value)
+
+	// Existing node selector entries and affinity are retained.
+	pod.Spec.NodeSelector = map[string]string{"pool": "general"}
+	pod.Spec.Affinity = &core.Affinity{}
+	patches = addPodScheduling(ctx, pod, nil)
+	require.Len(t, patches, 1)
+	assert.Equal(t, "replace", patches[0].Op)
+	assert.Equal(t, map[string]string{
+		"kubernetes.io/hostname": "debug-node",
+		"pool":                   "general",
+	}, patches[0].Value)
+}
+
 func requireContains(t *testing.T, err error, expected string) {
 	if expected == "" {
 		require.NoError(t, err)
@@ -49,6 +49,7 @@ type Service interface {
 
 	// unexported methods.
 	runConfigWatcher(context.Context) error
+	runHealthChecker(context.Context) error
 	runSessionGCLoop(context.Context) error
 	serveHTTP(context.Context) error
 	servePrometheus(context.Context) error
@@ -61,6 +62,7 @@ type service struct {
 	state              state.State
 	clusterInfo        cluster.Info
 	configWatcher      config.Watcher
+	healthChecker      *HealthChecker
 	activeHttpRequests int32
 	activeGrpcRequests int32
 
@@ -92,6 +94,7 @@ func NewService(ctx context.Context) (Service, *dgroup.Group, error) {
 		}
 	}
 	ret.configWatcher = config.NewWatcher(managerutil.GetEnv(ctx).ManagerNamespace)
+	ret.healthChecker = NewHealthChecker()
 	ret.ctx = ctx
 	// These are context dependent so build them once the pool is up
 	ret.clusterInfo = cluster.NewInfo(ctx)
@@ -132,6 +135,12 @@ func (s *service) runConfigWatcher(ctx context.Context) error {
 	return s.configWatcher.Run(ctx)
 }
 
+// runHealthChecker reports SERVING once the client configuration has been loaded. The agent configurations
+// are loaded before the service is created.
+func (s *service) runHealthChecker(ctx context.Context) error {
+	return s.healthChecker.Run(ctx, s.configWatcher.Ready())
+}
+
 // Version returns the version information of the Manager.
 func (*service) Version(context.Context, *empty.Empty) (*rpc.VersionInfo2, error) {
 	return &rpc.VersionInfo2{Name: DisplayName, Version: version.Version}, nil
@@ -203,6 +212,9 @@ func (s *service) ArriveAsAgent(ctx context.Context, agent *rpc.AgentInfo) (*rpc
 
 func (s *service) ReportMetrics(ctx context.Context, metrics *rpc.TunnelMetrics) (*empty.Empty, error) {
 	s.state.AddSessionConsumptionMetrics(metrics)
+	if metrics.InterceptId != "" {
+		s.state.AddInterceptMetrics(metrics)
+	}
 	return &empty.Empty{}, nil
 }
 
@@ -627,6 +639,12 @@ func (s *service) CreateIntercept(ctx context.Context, ciReq *rpc.CreateIntercep
 			dlog.Errorf(ctx, "Failed to add finalizer for %s: %v", interceptInfo.Id, err)
 		}
 	}
+	if ciReq.AgentImage != "" {
+		err := s.state.AddInterceptFinalizer(interceptInfo.Id, s.state.RestoreAgentImage)
+		if err != nil {
+			dlog.Errorf(ctx, "Failed to add finalizer for %s: %v", interceptInfo.Id, err)
+		}
+	}
 
 	SetGauge(s.state.GetInterceptActiveStatus(), client.Name, client.InstallId, &spec.Name, 1)
 
@@ -721,6 +739,7 @@ func (s *service) ReviewIntercept(ctx context.Context, rIReq *rpc.ReviewIntercep
 			intercept.Message = rIReq.Message
 			intercept.PodIp = rIReq.PodIp
 			intercept.PodName = agent.PodName
+			intercept.NodeName = agent.NodeName
 			intercept.FtpPort = rIReq.FtpPort
 			intercept.SftpPort = rIReq.SftpPort
 			intercept.MountPoint = rIReq.MountPoint
@@ -964,8 +983,11 @@ func (s *service) WatchWorkloads(request *rpc.WorkloadEventsRequest, stream rpc.
 
 const agentSessionTTL = 15 * time.Second
 
-// expire removes stale sessions.
+// expire removes stale sessions. It also publishes the intercept metrics, so that clients see them
+// at the same interval.
 func (s *service) expire(ctx context.Context) {
 	now := s.clock.Now()
 	s.state.ExpireSessions(ctx, now.Add(-managerutil.GetEnv(ctx).ClientConnectionTTL), now.Add(-agentSessionTTL))
+	s.state.ExpireIntercepts(ctx, now)
+	s.state.PublishInterceptMetrics()
 }
@@ -87,6 +87,48 @@ func (s *state) AddSessionConsumptionMetrics(metrics *manager.TunnelMetrics) {
 	}
 }
 
+// interceptCounters are the metrics collected for an intercept since they were last published.
+type interceptCounters struct {
+	fromClientBytes uint64
+	toClientBytes   uint64
+	connections     uint64
+}
+
+// AddInterceptMetrics adds the given metrics to the metrics of the intercept that they were collected for.
+// The metrics arrive each time an intercepted connection ends, so they are kept outside the watched
+// InterceptInfo until PublishInterceptMetrics is called. Otherwise, each connection would cause an update
+// to be sent to every intercept watcher.
+func (s *state) AddInterceptMetrics(metrics *manager.TunnelMetrics) {
+	s.interceptMetrics.Compute(metrics.InterceptId, func(ic interceptCounters, _ bool) (interceptCounters, bool) {
+		ic.fromClientBytes += metrics.IngressBytes
+		ic.toClientBytes += metrics.EgressBytes
+		ic.connections++
+		return ic, false
+	})
+}
+
+// PublishInterceptMetrics adds the metrics that have been collected since the last call to the metrics
+// of the InterceptInfo of each intercept, so that watchers see them. The intercept metrics are reset when
+// the intercept is recreated, because a new InterceptInfo is created.
+func (s *state) PublishInterceptMetrics() {
+	s.interceptMetrics.Range(func(id string, _ interceptCounters) bool {
+		ic, ok := s.interceptMetrics.LoadAndDelete(id)
+		if ok {
+			s.UpdateIntercept(id, func(ii *manager.InterceptInfo) {
+				im := ii.Metrics
+				if im == nil {
+					im = &manager.InterceptMetrics{}
+					ii.Metrics = im
+				}
+				im.FromClientBytes += ic.fromClientBytes
+				im.ToClientBytes += ic.toClientBytes
+				im.Connections += ic.connections
+			})
+		}
+		return true
+	})
+}
+
 // RefreshSessionConsumptionMetrics refreshes the metrics associated to a specific session.
 func (s *state) RefreshSessionConsumptionMetrics(sessionID string) {
 	css, ok := s.GetSession(sessionID).(*clientSessionState)
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
 )
 
 func (s *suiteState) TestRefreshSessionConsumptionMetrics() {
@@ -37,3 +39,32 @@ func (s *suiteState) TestRefreshSessionConsumptionMetrics() {
 	assert.True(s.T(), ccs1.(*clientSessionState).ConsumptionMetrics().ConnectDuration() > 42*time.Second)
 	assert.Equal(s.T(), 41*time.Second, ccs3.(*clientSessionState).ConsumptionMetrics().ConnectDuration())
 }
+
+func (s *suiteState) TestAddInterceptMetrics() {
+	// given
+	s.state.intercepts.Store("session-1:echo", &manager.InterceptInfo{Id: "session-1:echo"})
+
+	// when
+	s.state.AddInterceptMetrics(&manager.TunnelMetrics{InterceptId: "session-1:echo", IngressBytes: 10, EgressBytes: 100})
+	s.state.AddInterceptMetrics(&manager.TunnelMetrics{InterceptId: "session-1:echo", IngressBytes: 5, EgressBytes: 50})
+	s.state.AddInterceptMetrics(&manager.TunnelMetrics{InterceptId: "session-1:other", IngressBytes: 5}) // doesn't exist but shouldn't fail.
+
+	// then the metrics aren't visible until they are published
+	ii, ok := s.state.GetIntercept("session-1:echo")
+	s.Require().True(ok)
+	s.Nil(ii.Metrics)
+
+	// when
+	s.state.PublishInterceptMetrics()
+	s.state.AddInterceptMetrics(&manager.TunnelMetrics{InterceptId: "session-1:echo", IngressBytes: 1, EgressBytes: 1})
+	s.state.PublishInterceptMetrics()
+
+	// then
+	ii, ok = s.state.GetIntercept("session-1:echo")
+	s.Require().True(ok)
+	s.Require().NotNil(ii.Metrics)
+	assert.Equal(s.T(), uint64(16), ii.Metrics.FromClientBytes)
+	assert.Equal(s.T(), uint64(151), ii.Metrics.ToClientBytes)
+	assert.Equal(s.T(), uint64(3), ii.Metrics.Connections)
+	assert.Equal(s.T(), 0, s.state.interceptMetrics.Size())
+}
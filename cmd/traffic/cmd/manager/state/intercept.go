@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"path"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -68,6 +70,10 @@ func (s *state) PrepareIntercept(
 	}
 
 	spec := cr.InterceptSpec
+	sessionID := cr.GetSession().GetSessionId()
+	if err = s.checkInterceptLimits(ctx, interceptID(sessionID, spec.Name), sessionID, spec); err != nil {
+		return interceptError(err)
+	}
 	wl, err := agentmap.GetWorkload(ctx, spec.Agent, spec.Namespace, spec.WorkloadKind)
 	if err != nil {
 		if k8sErrors.IsNotFound(err) {
@@ -76,8 +82,11 @@ func (s *state) PrepareIntercept(
 		dlog.Error(ctx, err)
 		return interceptError(err)
 	}
+	if err = checkInterceptAllowed(ctx, wl); err != nil {
+		return interceptError(err)
+	}
 
-	ac, err := s.ensureAgent(ctx, wl, s.isExtended(spec), spec)
+	ac, err := s.ensureAgent(ctx, wl, s.isExtended(spec), spec, cr.AgentImage)
 	if err != nil {
 		return interceptError(err)
 	}
@@ -99,6 +108,82 @@ func (s *state) PrepareIntercept(
 	}, nil
 }
 
+// checkInterceptLimits returns an error if adding the intercept with the given ID would exceed the maximum
+// number of intercepts per client session or per workload that the traffic-manager is configured with.
+func (s *state) checkInterceptLimits(ctx context.Context, interceptID, sessionID string, spec *managerrpc.InterceptSpec) error {
+	env := managerutil.GetEnv(ctx)
+	if env.InterceptMaxPerClient <= 0 && env.InterceptMaxPerWorkload <= 0 {
+		return nil
+	}
+	clientCount, workloadCount := 0, 0
+	for id, ii := range s.intercepts.LoadAll() {
+		if id == interceptID || ii.Disposition == managerrpc.InterceptDispositionType_REMOVED {
+			continue
+		}
+		if ii.ClientSession.GetSessionId() == sessionID {
+			clientCount++
+		}
+		if ii.Spec.Agent == spec.Agent && ii.Spec.Namespace == spec.Namespace {
+			workloadCount++
+		}
+	}
+	if limit := env.InterceptMaxPerClient; limit > 0 && clientCount >= limit {
+		return errcat.User.Newf(
+			"unable to intercept %s: the client already has %d intercepts, which is the maximum allowed by the traffic-manager",
+			spec.Name, clientCount)
+	}
+	if limit := env.InterceptMaxPerWorkload; limit > 0 && workloadCount >= limit {
+		return errcat.User.Newf(
+			"unable to intercept %s: workload %s.%s already has %d intercepts, which is the maximum allowed by the traffic-manager",
+			spec.Name, spec.Agent, spec.Namespace, workloadCount)
+	}
+	return nil
+}
+
+// checkInterceptAllowed returns an error if the given workload has been protected from intercepts using the
+// annotation that the traffic-manager is configured with.
+func checkInterceptAllowed(ctx context.Context, wl k8sapi.Workload) error {
+	key := managerutil.GetEnv(ctx).InterceptDisableAnnotation
+	if key == "" || wl.GetAnnotations()[key] != "disabled" {
+		return nil
+	}
+	return errcat.User.Newf(
+		"unable to intercept %s %s.%s: the workload is protected from intercepts by its %s=disabled annotation",
+		wl.GetKind(), wl.GetName(), wl.GetNamespace(), key)
+}
+
+// checkSpecAllowed is like checkInterceptAllowed, but looks up the workload of the given spec and returns a gRPC
+// status error. A workload that cannot be found is allowed, because the intercept will then wait for an agent like
+// any other intercept.
+func checkSpecAllowed(ctx context.Context, spec *managerrpc.InterceptSpec) error {
+	if managerutil.GetEnv(ctx).InterceptDisableAnnotation == "" {
+		return nil
+	}
+	wl, err := agentmap.GetWorkload(ctx, spec.Agent, spec.Namespace, spec.WorkloadKind)
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return nil
+		}
+		return status.Errorf(codes.Internal, "unable to get workload %s.%s: %v", spec.Agent, spec.Namespace, err)
+	}
+	if err = checkInterceptAllowed(ctx, wl); err != nil {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return nil
+}
+
+// checkAgentImageOverride returns a PermissionDenied error unless the given image, requested by a client in place
+// of the configured agent image, matches one of the patterns in the manager's AGENT_IMAGE_OVERRIDES.
+func checkAgentImageOverride(ctx context.Context, image string) error {
+	for _, pattern := range managerutil.GetEnv(ctx).AgentImageOverrides {
+		if ok, _ := path.Match(pattern, image); ok {
+			return nil
+		}
+	}
+	return status.Errorf(codes.PermissionDenied,
+		"the traffic-manager doesn't allow the agent image %q. Allowed images are configured using the Helm value agent.image.allowedOverrides", image)
+}
+
 func (s *state) EnsureAgent(ctx context.Context, n, ns string) error {
 	wl, err := agentmap.GetWorkload(ctx, n, ns, "")
 	if err != nil {
@@ -107,7 +192,7 @@ func (s *state) EnsureAgent(ctx context.Context, n, ns string) error {
 		}
 		return err
 	}
-	_, err = s.ensureAgent(ctx, wl, false, nil)
+	_, err = s.ensureAgent(ctx, wl, false, nil, "")
 	return err
 }
 
@@ -115,7 +200,15 @@ func (s *state) ValidateCreateAgent(context.Context, k8sapi.Workload, agentconfi
 	return nil
 }
 
-func (s *state) ensureAgent(parentCtx context.Context, wl k8sapi.Workload, extended bool, spec *managerrpc.InterceptSpec) (ac *agentconfig.Sidecar, err error) {
+// ensureAgent ensures that the workload has an agent config and waits for the agent to arrive. A non-empty
+// agentImage overrides the image configured for the traffic-manager.
+func (s *state) ensureAgent(
+	parentCtx context.Context,
+	wl k8sapi.Workload,
+	extended bool,
+	spec *managerrpc.InterceptSpec,
+	agentImage string,
+) (ac *agentconfig.Sidecar, err error) {
 	if !managerutil.AgentInjectorEnabled(parentCtx) {
 		sce, err := mutator.GetMap(parentCtx).Get(parentCtx, wl.GetName(), wl.GetNamespace())
 		if err != nil {
@@ -126,7 +219,7 @@ func (s *state) ensureAgent(parentCtx context.Context, wl k8sapi.Workload, exten
 		}
 		return sce.AgentConfig(), nil
 	}
-	ctx, cancel := context.WithTimeout(parentCtx, managerutil.GetEnv(parentCtx).AgentArrivalTimeout)
+	ctx, cancel := agentArrivalContext(parentCtx)
 	defer cancel()
 
 	failedCreateCh, err := watchFailedInjectionEvents(ctx, wl.GetName(), wl.GetNamespace())
@@ -134,7 +227,7 @@ func (s *state) ensureAgent(parentCtx context.Context, wl k8sapi.Workload, exten
 		return nil, err
 	}
 
-	sce, err := s.getOrCreateAgentConfig(ctx, wl, extended, spec)
+	sce, err := s.getOrCreateAgentConfig(ctx, wl, extended, spec, agentImage)
 	if err != nil {
 		return nil, err
 	}
@@ -150,6 +243,16 @@ func (s *state) ensureAgent(parentCtx context.Context, wl k8sapi.Workload, exten
 	return ac, nil
 }
 
+// agentArrivalContext returns a context that limits the time to wait for an agent to arrive. A deadline set by
+// the client, e.g. using intercept --wait, takes precedence over the configured agent arrival timeout, so that
+// a client can allow more time for slow pods.
+func agentArrivalContext(parentCtx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := parentCtx.Deadline(); ok {
+		return context.WithCancel(parentCtx)
+	}
+	return context.WithTimeout(parentCtx, managerutil.GetEnv(parentCtx).AgentArrivalTimeout)
+}
+
 func (s *state) isExtended(spec *managerrpc.InterceptSpec) bool {
 	return spec.Mechanism != "tcp"
 }
@@ -198,20 +301,71 @@ func (s *state) RestoreAppContainer(ctx context.Context, ii *managerrpc.Intercep
 			return false, nil
 		}
 		cn.Replace = false
+		s.evictAgents(mm, n, ns)
+		return updateSidecar(sce, cm, n)
+	})
+}
 
-		// The pods for this workload will be killed once the new updated sidecar
-		// reaches the configmap. We remove them now, so that they don't continue to
-		// review intercepts.
-		for sessionID, ai := range s.getAgentsByName(n, ns) {
-			if as, ok := s.GetSession(sessionID).(*agentSessionState); ok {
-				as.active.Store(false)
-			}
-			mm.Blacklist(ai.PodName, ns)
+// RestoreAgentImage is an intercept finalizer that restores the configured agent image of a workload that got
+// its agent image from the removed intercept's --agent-image override.
+func (s *state) RestoreAgentImage(ctx context.Context, ii *managerrpc.InterceptInfo) (err error) {
+	spec := ii.Spec
+	n := spec.Agent
+	ns := spec.Namespace
+	dlog.Debugf(ctx, "Restoring agent image for %s", ii.Id)
+	ctx, span := otel.GetTracerProvider().Tracer("").Start(ctx, "state.RestoreAgentImage", trace.WithAttributes(
+		attribute.String("tel2.name", n),
+		attribute.String("tel2.namespace", ns),
+	))
+	defer func() {
+		tracing.EndAndRecord(span, err)
+	}()
+	mm := mutator.GetMap(ctx)
+	return mm.Update(ctx, ns, func(cm *core.ConfigMap) (changed bool, err error) {
+		y, ok := cm.Data[n]
+		if !ok {
+			return false, nil
 		}
+		sce, err := unmarshalConfigMapEntry(y, n, ns)
+		if err != nil {
+			return false, err
+		}
+		if !s.restoreAgentImage(ctx, sce.AgentConfig()) {
+			return false, nil
+		}
+		s.evictAgents(mm, n, ns)
 		return updateSidecar(sce, cm, n)
 	})
 }
 
+// restoreAgentImage sets the agent image of the given config back to the configured agent image when it was
+// changed by an allowed override and no intercepts remain on the workload. It returns true if the image was changed.
+func (s *state) restoreAgentImage(ctx context.Context, ac *agentconfig.Sidecar) bool {
+	agentImage := managerutil.GetAgentImage(ctx)
+	if ac.AgentImage == agentImage || checkAgentImageOverride(ctx, ac.AgentImage) != nil {
+		return false
+	}
+	if len(s.intercepts.LoadAllMatching(func(_ string, ii *managerrpc.InterceptInfo) bool {
+		return ii.Spec.Agent == ac.AgentName && ii.Spec.Namespace == ac.Namespace
+	})) > 0 {
+		return false
+	}
+	ac.AgentImage = agentImage
+	return true
+}
+
+// evictAgents deactivates and blacklists the agents of the given workload. The pods for the workload will be
+// killed once the new updated sidecar reaches the configmap. We remove them now, so that they don't continue to
+// review intercepts.
+func (s *state) evictAgents(mm mutator.Map, n, ns string) {
+	for sessionID, ai := range s.getAgentsByName(n, ns) {
+		if as, ok := s.GetSession(sessionID).(*agentSessionState); ok {
+			as.active.Store(false)
+		}
+		mm.Blacklist(ai.PodName, ns)
+	}
+}
+
 func updateSidecar(sce agentconfig.SidecarExt, cm *core.ConfigMap, n string) (bool, error) {
 	yml, err := sce.Marshal()
 	if err != nil {
@@ -231,6 +385,7 @@ func (s *state) getOrCreateAgentConfig(
 	wl k8sapi.Workload,
 	extended bool,
 	spec *managerrpc.InterceptSpec,
+	imageOverride string,
 ) (sce agentconfig.SidecarExt, err error) {
 	enabled, err := checkInterceptAnnotations(wl)
 	if err != nil {
@@ -241,6 +396,12 @@ func (s *state) getOrCreateAgentConfig(
 	}
 
 	agentImage := managerutil.GetAgentImage(ctx)
+	if imageOverride != "" {
+		if err = checkAgentImageOverride(ctx, imageOverride); err != nil {
+			return nil, err
+		}
+		agentImage = imageOverride
+	}
 	if err = s.self.ValidateAgentImage(agentImage, extended); err != nil {
 		return nil, err
 	}
@@ -252,10 +413,13 @@ func (s *state) getOrCreateAgentConfig(
 				return false, err
 			}
 			ac := sce.AgentConfig()
-			// If the agentImage has changed, and the extended image is requested, then update
-			if ac.AgentImage != agentImage && extended {
+			// If the agentImage has changed, and the extended image or an explicit image is requested, then update.
+			// An image set by an earlier override is restored when no override is requested.
+			if ac.AgentImage != agentImage && (extended || imageOverride != "") {
 				ac.AgentImage = agentImage
 				doUpdate = true
+			} else if imageOverride == "" && s.restoreAgentImage(ctx, ac) {
+				doUpdate = true
 			}
 		} else {
 			if cm.Data == nil {
@@ -521,6 +685,7 @@ func unmarshalConfigMapEntry(y string, name, namespace string) (agentconfig.Side
 // findIntercept finds the intercept configuration that matches the given InterceptSpec's service/service port or container port.
 func findIntercept(ac *agentconfig.Sidecar, spec *managerrpc.InterceptSpec) (foundCN *agentconfig.Container, foundIC *agentconfig.Intercept, err error) {
 	pi := agentconfig.PortIdentifier(spec.PortIdentifier)
+	var matchedCN *agentconfig.Container
 	for _, cn := range ac.Containers {
 		for _, ic := range cn.Intercepts {
 			if !(spec.ServiceName == "" || spec.ServiceName == ic.ServiceName) {
@@ -536,6 +701,7 @@ func findIntercept(ac *agentconfig.Sidecar, spec *managerrpc.InterceptSpec) (fou
 				}
 			}
 			if foundIC == nil {
+				matchedCN = cn
 				foundCN = cn
 				if spec.ContainerName != "" {
 					for _, cx := range ac.Containers {
@@ -550,6 +716,10 @@ func findIntercept(ac *agentconfig.Sidecar, spec *managerrpc.InterceptSpec) (fou
 			}
 			var msg string
 			switch {
+			case pi != "" && ic.ServiceUID == "" && foundIC.ServiceUID == "" && cn != matchedCN:
+				msg = fmt.Sprintf("%s %s.%s has more than one container with a port matching %s: %s and %s.\n"+
+					"Please use a port name or number that is unique to one container.",
+					ac.WorkloadKind, ac.WorkloadName, ac.Namespace, pi, matchedCN.Name, cn.Name)
 			case spec.ServiceName == "" && pi == "":
 				msg = fmt.Sprintf("%s %s.%s has multiple interceptable ports.\n"+
 					"Please specify the service and/or port you want to intercept "+
@@ -584,9 +754,39 @@ func findIntercept(ac *agentconfig.Sidecar, spec *managerrpc.InterceptSpec) (fou
 	} else if pi != "" {
 		ss = fmt.Sprintf(" matching port %s", pi)
 	}
+	if ap := availablePorts(ac, spec.ServiceName); len(ap) > 0 {
+		ss = fmt.Sprintf("%s. Available ports are:\n  %s", ss, strings.Join(ap, "\n  "))
+	}
 	return nil, nil, errcat.User.Newf("%s %s.%s has no interceptable port%s", ac.WorkloadKind, ac.WorkloadName, ac.Namespace, ss)
 }
 
+// availablePorts returns a description of each interceptable port in the given config, optionally limited
+// to the ports of the given service.
+func availablePorts(ac *agentconfig.Sidecar, serviceName string) []string {
+	var ps []string
+	for _, cn := range ac.Containers {
+		for _, ic := range cn.Intercepts {
+			if serviceName != "" && serviceName != ic.ServiceName {
+				continue
+			}
+			cp := fmt.Sprintf("container %s port %d/%s", cn.Name, ic.ContainerPort, ic.Protocol)
+			if ic.ContainerPortName != "" {
+				cp = fmt.Sprintf("container %s port %s (%d/%s)", cn.Name, ic.ContainerPortName, ic.ContainerPort, ic.Protocol)
+			}
+			if ic.ServiceName == "" {
+				ps = append(ps, cp)
+				continue
+			}
+			sp := strconv.Itoa(int(ic.ServicePort))
+			if ic.ServicePortName != "" {
+				sp = fmt.Sprintf("%s (%d)", ic.ServicePortName, ic.ServicePort)
+			}
+			ps = append(ps, fmt.Sprintf("service %s port %s, targeting %s", ic.ServiceName, sp, cp))
+		}
+	}
+	return ps
+}
+
 type InterceptFinalizer func(ctx context.Context, interceptInfo *managerrpc.InterceptInfo) error
 
 type interceptState struct {
@@ -0,0 +1,113 @@
+package state
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	core "k8s.io/api/core/v1"
+
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+	"github.com/telepresenceio/telepresence/v2/cmd/traffic/cmd/manager/managerutil"
+	"github.com/telepresenceio/telepresence/v2/pkg/agentconfig"
+)
+
+func Test_findIntercept(t *testing.T) {
+	ac := &agentconfig.Sidecar{
+		WorkloadKind: "Deployment",
+		WorkloadName: "echo",
+		Namespace:    "default",
+		Containers: []*agentconfig.Container{{
+			Name: "echo",
+			Intercepts: []*agentconfig.Intercept{
+				{
+					ServiceName:       "echo",
+					ServiceUID:        "1234",
+					ServicePortName:   "http",
+					ServicePort:       80,
+					ContainerPortName: "http",
+					ContainerPort:     8081,
+					Protocol:          core.ProtocolTCP,
+				},
+				{
+					ServiceName:   "echo",
+					ServiceUID:    "1234",
+					ServicePort:   9000,
+					ContainerPort: 9001,
+					Protocol:      core.ProtocolTCP,
+				},
+			},
+		}},
+	}
+
+	t.Run("found", func(t *testing.T) {
+		cn, ic, err := findIntercept(ac, &manager.InterceptSpec{PortIdentifier: "http"})
+		require.NoError(t, err)
+		assert.Equal(t, "echo", cn.Name)
+		assert.Equal(t, uint16(8081), ic.ContainerPort)
+	})
+
+	t.Run("not found lists available ports", func(t *testing.T) {
+		_, _, err := findIntercept(ac, &manager.InterceptSpec{PortIdentifier: "8080"})
+		require.Error(t, err)
+		assert.Equal(t, "Deployment echo.default has no interceptable port matching port 8080. Available ports are:\n"+
+			"  service echo port http (80), targeting container echo port http (8081/TCP)\n"+
+			"  service echo port 9000, targeting container echo port 9001/TCP", err.Error())
+	})
+
+	t.Run("not found in service", func(t *testing.T) {
+		_, _, err := findIntercept(ac, &manager.InterceptSpec{ServiceName: "other", PortIdentifier: "8080"})
+		require.Error(t, err)
+		assert.Equal(t, "Deployment echo.default has no interceptable port matching service other, port 8080", err.Error())
+	})
+
+	t.Run("ambiguous container port name", func(t *testing.T) {
+		cac := &agentconfig.Sidecar{
+			WorkloadKind: "Deployment",
+			WorkloadName: "multi",
+			Namespace:    "default",
+			Containers: []*agentconfig.Container{
+				{
+					Name:       "api",
+					Intercepts: []*agentconfig.Intercept{{ContainerPortName: "http", ContainerPort: 8080, Protocol: core.ProtocolTCP}},
+				},
+				{
+					Name:       "admin",
+					Intercepts: []*agentconfig.Intercept{{ContainerPortName: "http", ContainerPort: 9090, Protocol: core.ProtocolTCP}},
+				},
+			},
+		}
+		_, _, err := findIntercept(cac, &manager.InterceptSpec{PortIdentifier: "http"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "has more than one container with a port matching http: api and admin")
+	})
+}
+
+func Test_agentArrivalContext(t *testing.T) {
+	tests := []struct {
+		name           string
+		clientDeadline time.Duration
+		want           time.Duration
+	}{
+		{"configured timeout", 0, 30 * time.Second},
+		{"longer client deadline", 2 * time.Minute, 2 * time.Minute},
+		{"shorter client deadline", 10 * time.Second, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := managerutil.WithEnv(context.Background(), &managerutil.Env{AgentArrivalTimeout: 30 * time.Second})
+			if tt.clientDeadline > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.clientDeadline)
+				defer cancel()
+			}
+			ctx, cancel := agentArrivalContext(ctx)
+			defer cancel()
+			dl, ok := ctx.Deadline()
+			require.True(t, ok)
+			assert.InDelta(t, tt.want, time.Until(dl), float64(time.Second))
+		})
+	}
+}
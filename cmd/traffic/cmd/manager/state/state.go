@@ -40,6 +40,7 @@ type State interface {
 	AddIntercept(context.Context, string, string, *rpc.CreateInterceptRequest) (*rpc.ClientInfo, *rpc.InterceptInfo, error)
 	AddInterceptFinalizer(string, InterceptFinalizer) error
 	AddSessionConsumptionMetrics(metrics *rpc.TunnelMetrics)
+	AddInterceptMetrics(metrics *rpc.TunnelMetrics)
 	AgentsLookupDNS(context.Context, string, *rpc.DNSRequest) (dnsproxy.RRs, int, error)
 	CountAgents() int
 	CountClients() int
@@ -48,7 +49,9 @@ type State interface {
 	CountTunnels() int
 	CountTunnelIngress() uint64
 	CountTunnelEgress() uint64
+	ExpireIntercepts(context.Context, time.Time)
 	ExpireSessions(context.Context, time.Time, time.Time)
+	PublishInterceptMetrics()
 	GetAgent(sessionID string) *rpc.AgentInfo
 	GetActiveAgent(sessionID string) *rpc.AgentInfo
 	GetAllClients() map[string]*rpc.ClientInfo
@@ -70,6 +73,7 @@ type State interface {
 	RemoveIntercept(context.Context, string)
 	DropIntercept(string)
 	RestoreAppContainer(context.Context, *rpc.InterceptInfo) error
+	RestoreAgentImage(context.Context, *rpc.InterceptInfo) error
 	FinalizeIntercept(ctx context.Context, intercept *rpc.InterceptInfo)
 	LoadMatchingIntercepts(filter func(string, *rpc.InterceptInfo) bool) map[string]*rpc.InterceptInfo
 	RemoveSession(context.Context, string)
@@ -133,6 +137,7 @@ type state struct {
 	sessions                   *xsync.MapOf[string, SessionState]                         // info for all sessions, keyed by session id
 	agentsByName               *xsync.MapOf[string, *xsync.MapOf[string, *rpc.AgentInfo]] // indexed copy of `agents`
 	interceptStates            *xsync.MapOf[string, *interceptState]
+	interceptMetrics           *xsync.MapOf[string, interceptCounters] // unpublished intercept metrics, keyed by intercept id
 	timedLogLevel              log.TimedLevel
 	llSubs                     *loglevelSubscribers
 	workloadWatchers           *xsync.MapOf[string, WorkloadWatcher] // workload watchers, created on demand and keyed by namespace
@@ -157,6 +162,7 @@ func NewState(ctx context.Context) State {
 		sessions:         xsync.NewMapOf[string, SessionState](),
 		agentsByName:     xsync.NewMapOf[string, *xsync.MapOf[string, *rpc.AgentInfo]](),
 		interceptStates:  xsync.NewMapOf[string, *interceptState](),
+		interceptMetrics: xsync.NewMapOf[string, interceptCounters](),
 		workloadWatchers: xsync.NewMapOf[string, WorkloadWatcher](),
 		timedLogLevel:    log.NewTimedLevel(loglevel, log.SetLevel),
 		llSubs:           newLoglevelSubscribers(),
@@ -332,6 +338,25 @@ func (s *state) ExpireSessions(ctx context.Context, clientMoment, agentMoment ti
 	})
 }
 
+// ExpireIntercepts removes the intercepts that have expired at the given moment. The client sees the
+// removal in its intercept watcher.
+func (s *state) ExpireIntercepts(ctx context.Context, moment time.Time) {
+	for interceptID, intercept := range s.intercepts.LoadAll() {
+		if intercept.Disposition == rpc.InterceptDispositionType_REMOVED || intercept.ExpiresAt == nil {
+			continue
+		}
+		if expiresAt := intercept.ExpiresAt.AsTime(); expiresAt.Before(moment) {
+			spec := intercept.Spec
+			dlog.Warnf(ctx, "Removing intercept %s of %s.%s created by %s, it reached its maximum lifetime at %s",
+				spec.Name, spec.Agent, spec.Namespace, spec.Client, expiresAt.Format(time.RFC3339))
+			if client := s.GetClient(intercept.ClientSession.SessionId); client != nil {
+				s.allInterceptsFinalizerCall(client, &spec.Name)
+			}
+			s.self.RemoveIntercept(ctx, interceptID)
+		}
+	}
+}
+
 // SessionDone returns a channel that is closed when the session with the given ID terminates.  If
 // there is no such currently-live session, then an already-closed channel is returned.
 func (s *state) SessionDone(id string) (<-chan struct{}, error) {
@@ -506,6 +531,10 @@ func (s *state) WatchWorkloads(ctx context.Context, sessionID string) (ch <-chan
 // Intercepts //////////////////////////////////////////////////////////////////////////////////////
 
 func (s *state) AddIntercept(ctx context.Context, sessionID, clusterID string, cir *rpc.CreateInterceptRequest) (client *rpc.ClientInfo, ret *rpc.InterceptInfo, err error) {
+	// The workload lookup must not be made while holding the lock.
+	if err = checkSpecAllowed(ctx, cir.InterceptSpec); err != nil {
+		return nil, nil, err
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	_, span := otel.GetTracerProvider().Tracer("").Start(ctx, "state.AddIntercept")
@@ -517,7 +546,10 @@ func (s *state) AddIntercept(ctx context.Context, sessionID, clusterID string, c
 	}
 
 	spec := cir.InterceptSpec
-	interceptID := fmt.Sprintf("%s:%s", sessionID, spec.Name)
+	interceptID := interceptID(sessionID, spec.Name)
+	if err = s.checkInterceptLimits(ctx, interceptID, sessionID, spec); err != nil {
+		return nil, nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
 	installID := client.GetInstallId()
 	clientSession := rpc.SessionInfo{
 		SessionId: sessionID,
@@ -526,6 +558,9 @@ func (s *state) AddIntercept(ctx context.Context, sessionID, clusterID string, c
 	}
 
 	cept := s.self.NewInterceptInfo(interceptID, &clientSession, cir)
+	if lt := managerutil.GetEnv(ctx).InterceptMaxLifetime; lt > 0 {
+		cept.ExpiresAt = timestamppb.New(cept.ModifiedAt.AsTime().Add(lt))
+	}
 
 	// Wrap each potential-state-change in a
 	//
@@ -551,6 +586,11 @@ func (s *state) AddIntercept(ctx context.Context, sessionID, clusterID string, c
 	return client, cept, nil
 }
 
+// interceptID returns the ID of the intercept with the given name that is created by the given client session.
+func interceptID(sessionID, name string) string {
+	return fmt.Sprintf("%s:%s", sessionID, name)
+}
+
 func (s *state) NewInterceptInfo(interceptID string, session *rpc.SessionInfo, ciReq *rpc.CreateInterceptRequest) *rpc.InterceptInfo {
 	return &rpc.InterceptInfo{
 		Spec:          ciReq.InterceptSpec,
@@ -8,10 +8,21 @@ import (
 	"github.com/puzpuzpuz/xsync/v3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
-
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	appsv1 "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	fakeargorollouts "github.com/datawire/argo-rollouts-go-client/pkg/client/clientset/versioned/fake"
 	"github.com/datawire/dlib/dlog"
+	"github.com/datawire/k8sapi/pkg/k8sapi"
 	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+	"github.com/telepresenceio/telepresence/v2/cmd/traffic/cmd/manager/managerutil"
+	"github.com/telepresenceio/telepresence/v2/cmd/traffic/cmd/manager/mutator"
 	testdata "github.com/telepresenceio/telepresence/v2/cmd/traffic/cmd/manager/test"
+	"github.com/telepresenceio/telepresence/v2/pkg/agentconfig"
 	"github.com/telepresenceio/telepresence/v2/pkg/log"
 )
 
@@ -25,12 +36,13 @@ type suiteState struct {
 func (s *suiteState) SetupTest() {
 	s.ctx = dlog.NewTestContext(s.T(), false)
 	s.state = &state{
-		backgroundCtx:   s.ctx,
-		sessions:        xsync.NewMapOf[string, SessionState](),
-		agentsByName:    xsync.NewMapOf[string, *xsync.MapOf[string, *manager.AgentInfo]](),
-		interceptStates: xsync.NewMapOf[string, *interceptState](),
-		timedLogLevel:   log.NewTimedLevel("debug", log.SetLevel),
-		llSubs:          newLoglevelSubscribers(),
+		backgroundCtx:    s.ctx,
+		sessions:         xsync.NewMapOf[string, SessionState](),
+		agentsByName:     xsync.NewMapOf[string, *xsync.MapOf[string, *manager.AgentInfo]](),
+		interceptStates:  xsync.NewMapOf[string, *interceptState](),
+		interceptMetrics: xsync.NewMapOf[string, interceptCounters](),
+		timedLogLevel:    log.NewTimedLevel("debug", log.SetLevel),
+		llSubs:           newLoglevelSubscribers(),
 	}
 }
 
@@ -185,6 +197,195 @@ func (s *suiteState) TestRemoveSession() {
 	assert.Equal(s.T(), s.state.sessions.Size(), 0)
 }
 
+func (s *suiteState) TestInterceptLimits() {
+	ctx := managerutil.WithEnv(s.ctx, &managerutil.Env{
+		InterceptMaxPerClient:   2,
+		InterceptMaxPerWorkload: 2,
+	})
+	st := NewState(ctx)
+	now := time.Now()
+	alice := st.AddClient(&manager.ClientInfo{Name: "alice"}, now)
+	bob := st.AddClient(&manager.ClientInfo{Name: "bob"}, now)
+	cameron := st.AddClient(&manager.ClientInfo{Name: "cameron"}, now)
+
+	add := func(sessionID, name, workload string) error {
+		_, _, err := st.AddIntercept(ctx, sessionID, "cluster", &manager.CreateInterceptRequest{
+			Session:       &manager.SessionInfo{SessionId: sessionID},
+			InterceptSpec: &manager.InterceptSpec{Name: name, Agent: workload, Namespace: "default"},
+		})
+		return err
+	}
+	s.Require().NoError(add(alice, "a1", "echo"))
+	s.Require().NoError(add(alice, "a2", "hello"))
+
+	// Alice has reached the per-client limit.
+	err := add(alice, "a3", "demo")
+	s.Require().Error(err)
+	s.Equal(codes.ResourceExhausted, status.Code(err))
+	s.Contains(err.Error(), "the client already has 2 intercepts")
+
+	// The echo workload has reached the per-workload limit when Bob adds his intercept.
+	s.Require().NoError(add(bob, "b1", "echo"))
+	err = add(cameron, "c1", "echo")
+	s.Require().Error(err)
+	s.Contains(err.Error(), "workload echo.default already has 2 intercepts")
+
+	// Removed intercepts don't count.
+	st.RemoveIntercept(ctx, alice+":a1")
+	s.NoError(add(cameron, "c1", "echo"))
+	s.NoError(add(alice, "a3", "demo"))
+}
+
+func (s *suiteState) TestInterceptProtectedWorkload() {
+	deployment := func(name string, annotations map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+		}
+	}
+	fakeClient := fake.NewSimpleClientset(
+		deployment("auth", map[string]string{"telepresence.getambassador.io/intercept": "disabled"}),
+		deployment("echo", map[string]string{"telepresence.getambassador.io/intercept": "enabled"}),
+	)
+	ctx := k8sapi.WithJoinedClientSetInterface(s.ctx, fakeClient, fakeargorollouts.NewSimpleClientset())
+	ctx = managerutil.WithEnv(ctx, &managerutil.Env{InterceptDisableAnnotation: "telepresence.getambassador.io/intercept"})
+	st := NewState(ctx)
+	alice := st.AddClient(&manager.ClientInfo{Name: "alice"}, time.Now())
+
+	add := func(name, workload string) error {
+		_, _, err := st.AddIntercept(ctx, alice, "cluster", &manager.CreateInterceptRequest{
+			Session:       &manager.SessionInfo{SessionId: alice},
+			InterceptSpec: &manager.InterceptSpec{Name: name, Agent: workload, Namespace: "default", WorkloadKind: "Deployment"},
+		})
+		return err
+	}
+	err := add("a1", "auth")
+	s.Require().Error(err)
+	s.Equal(codes.PermissionDenied, status.Code(err))
+	s.Contains(err.Error(), "Deployment auth.default: the workload is protected from intercepts")
+	s.NoError(add("a2", "echo"))
+	s.NoError(add("a3", "unknown"))
+
+	// The annotation is ignored when no key is configured.
+	ctx = managerutil.WithEnv(ctx, &managerutil.Env{})
+	_, _, err = st.AddIntercept(ctx, alice, "cluster", &manager.CreateInterceptRequest{
+		Session:       &manager.SessionInfo{SessionId: alice},
+		InterceptSpec: &manager.InterceptSpec{Name: "a4", Agent: "auth", Namespace: "default", WorkloadKind: "Deployment"},
+	})
+	s.NoError(err)
+}
+
+func (s *suiteState) TestAgentImageOverride() {
+	ctx := managerutil.WithEnv(s.ctx, &managerutil.Env{})
+	err := checkAgentImageOverride(ctx, "example.com/tel2:2.20.0")
+	s.Equal(codes.PermissionDenied, status.Code(err))
+
+	ctx = managerutil.WithEnv(s.ctx, &managerutil.Env{AgentImageOverrides: []string{"ghcr.io/example/tel2:*", "example.com/tel2:2.20.0"}})
+	s.NoError(checkAgentImageOverride(ctx, "ghcr.io/example/tel2:2.20.1"))
+	s.NoError(checkAgentImageOverride(ctx, "example.com/tel2:2.20.0"))
+	s.Equal(codes.PermissionDenied, status.Code(checkAgentImageOverride(ctx, "example.com/tel2:2.20.1")))
+	s.Equal(codes.PermissionDenied, status.Code(checkAgentImageOverride(ctx, "ghcr.io/other/tel2:2.20.1")))
+}
+
+// configMapMap is a mutator.Map that keeps a single ConfigMap in memory.
+type configMapMap struct {
+	mutator.Map
+	cm *core.ConfigMap
+}
+
+func (m *configMapMap) Update(_ context.Context, _ string, updater func(cm *core.ConfigMap) (bool, error)) error {
+	_, err := updater(m.cm)
+	return err
+}
+
+func (m *configMapMap) Blacklist(string, string) {}
+
+func (s *suiteState) TestRestoreAgentImage() {
+	const (
+		configured = "ghcr.io/telepresenceio/tel2:2.20.0"
+		override   = "ghcr.io/example/tel2:2.20.1"
+	)
+	ctx := managerutil.WithEnv(s.ctx, &managerutil.Env{AgentImageOverrides: []string{"ghcr.io/example/tel2:*"}})
+	ctx = managerutil.WithResolvedAgentImageRetriever(ctx, managerutil.ImageFromEnv(configured))
+
+	tests := []struct {
+		name        string
+		image       string
+		intercepted bool
+		want        string
+	}{
+		{"override is restored", override, false, configured},
+		{"override is kept while intercepted", override, true, override},
+		{"configured image is kept", configured, false, configured},
+		{"disallowed image is kept", "example.com/tel2:2.19.0", false, "example.com/tel2:2.19.0"},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			st := NewState(ctx).(*state)
+			y, err := (&agentconfig.Sidecar{AgentName: "echo", Namespace: "default", AgentImage: tt.image}).Marshal()
+			s.Require().NoError(err)
+			m := &configMapMap{cm: &core.ConfigMap{Data: map[string]string{"echo": string(y)}}}
+			ctx := mutator.WithMap(ctx, m)
+
+			spec := &manager.InterceptSpec{Name: "echo", Agent: "echo", Namespace: "default"}
+			if tt.intercepted {
+				alice := st.AddClient(&manager.ClientInfo{Name: "alice"}, time.Now())
+				_, _, err = st.AddIntercept(ctx, alice, "cluster", &manager.CreateInterceptRequest{
+					Session:       &manager.SessionInfo{SessionId: alice},
+					InterceptSpec: spec,
+				})
+				s.Require().NoError(err)
+			}
+			s.Require().NoError(st.RestoreAgentImage(ctx, &manager.InterceptInfo{Id: "x", Spec: spec}))
+
+			sce, err := agentconfig.UnmarshalYAML([]byte(m.cm.Data["echo"]))
+			s.Require().NoError(err)
+			s.Equal(tt.want, sce.AgentConfig().AgentImage)
+		})
+	}
+}
+
+func (s *suiteState) TestExpireIntercepts() {
+	ctx := managerutil.WithEnv(s.ctx, &managerutil.Env{InterceptMaxLifetime: time.Hour})
+	st := NewState(ctx)
+	now := time.Now()
+	alice := st.AddClient(&manager.ClientInfo{Name: "alice"}, now)
+
+	_, ii, err := st.AddIntercept(ctx, alice, "cluster", &manager.CreateInterceptRequest{
+		Session:       &manager.SessionInfo{SessionId: alice},
+		InterceptSpec: &manager.InterceptSpec{Name: "echo", Agent: "echo", Namespace: "default"},
+	})
+	s.Require().NoError(err)
+	s.Require().NotNil(ii.ExpiresAt)
+	s.Equal(time.Hour, ii.ExpiresAt.AsTime().Sub(ii.ModifiedAt.AsTime()))
+
+	st.ExpireIntercepts(ctx, now.Add(30*time.Minute))
+	_, ok := st.GetIntercept(ii.Id)
+	s.True(ok)
+
+	st.ExpireIntercepts(ctx, now.Add(2*time.Hour))
+	_, ok = st.GetIntercept(ii.Id)
+	s.False(ok)
+}
+
+func (s *suiteState) TestInterceptWithoutMaxLifetime() {
+	ctx := managerutil.WithEnv(s.ctx, &managerutil.Env{})
+	st := NewState(ctx)
+	now := time.Now()
+	alice := st.AddClient(&manager.ClientInfo{Name: "alice"}, now)
+
+	_, ii, err := st.AddIntercept(ctx, alice, "cluster", &manager.CreateInterceptRequest{
+		Session:       &manager.SessionInfo{SessionId: alice},
+		InterceptSpec: &manager.InterceptSpec{Name: "echo", Agent: "echo", Namespace: "default"},
+	})
+	s.Require().NoError(err)
+	s.Nil(ii.ExpiresAt)
+
+	st.ExpireIntercepts(ctx, now.Add(1000*time.Hour))
+	_, ok := st.GetIntercept(ii.Id)
+	s.True(ok)
+}
+
 func TestSuiteState(testing *testing.T) {
 	suite.Run(testing, new(suiteState))
 }
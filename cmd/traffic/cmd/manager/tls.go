@@ -0,0 +1,187 @@
+package manager
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/datawire/dlib/dlog"
+)
+
+// tlsHandshakeRecord is the first byte sent by a TLS client.
+const tlsHandshakeRecord = 0x16
+
+// sniffTimeout is the maximum time to wait for the first byte of a new connection.
+const sniffTimeout = 10 * time.Second
+
+// tlsConfigLoader creates the tls.Config of the gRPC API from the ca.crt, tls.crt, and tls.key in a
+// directory, and creates it again when any of those files change, so that rotated certificates are
+// used without a restart of the traffic-manager.
+type tlsConfigLoader struct {
+	sync.Mutex
+	dir     string
+	modTime time.Time
+	config  *tls.Config
+}
+
+// loadTLSConfig returns a tls.Config suitable for the gRPC API. The returned config requires that clients
+// present a certificate signed by the ca.crt in the given directory, and it reloads the certificates from
+// that directory when they change.
+func loadTLSConfig(ctx context.Context, dir string) (*tls.Config, error) {
+	tl := &tlsConfigLoader{dir: dir}
+	if _, err := tl.load(); err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cfg, err := tl.load()
+			if err != nil {
+				// Keep using the last config that was successfully loaded. The files might be in the
+				// middle of an update.
+				dlog.Errorf(ctx, "unable to reload the gRPC TLS certificates: %v", err)
+			}
+			return cfg, nil
+		},
+	}, nil
+}
+
+// load returns the current config, after creating it again if any of the files changed since it was
+// last created. The last successfully created config is returned together with the error when that fails.
+func (tl *tlsConfigLoader) load() (*tls.Config, error) {
+	tl.Lock()
+	defer tl.Unlock()
+	var modTime time.Time
+	for _, f := range []string{"ca.crt", "tls.crt", "tls.key"} {
+		// Stat follows the symlinks of the secret volume, so an update of the secret is detected.
+		st, err := os.Stat(filepath.Join(tl.dir, f))
+		if err != nil {
+			return tl.config, fmt.Errorf("unable to load the gRPC TLS certificate from %s: %w", tl.dir, err)
+		}
+		if mt := st.ModTime(); mt.After(modTime) {
+			modTime = mt
+		}
+	}
+	if tl.config != nil && modTime.Equal(tl.modTime) {
+		return tl.config, nil
+	}
+	cert, err := tls.LoadX509KeyPair(filepath.Join(tl.dir, "tls.crt"), filepath.Join(tl.dir, "tls.key"))
+	if err != nil {
+		return tl.config, fmt.Errorf("unable to load the gRPC TLS certificate from %s: %w", tl.dir, err)
+	}
+	ca, err := os.ReadFile(filepath.Join(tl.dir, "ca.crt"))
+	if err != nil {
+		return tl.config, fmt.Errorf("unable to load the gRPC TLS CA from %s: %w", tl.dir, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return tl.config, fmt.Errorf("the gRPC TLS CA in %s contains no valid certificates", tl.dir)
+	}
+	tl.modTime = modTime
+	tl.config = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+		NextProtos:   []string{"h2"},
+		MinVersion:   tls.VersionTLS12,
+	}
+	return tl.config, nil
+}
+
+// tlsListener is a net.Listener that serves TLS on all connections that start with a TLS handshake.
+// Plaintext connections are only accepted when they originate from a loopback address. Connections
+// made using port-forward are such connections, because they are dialed from within the pod's network
+// namespace. Traffic that crosses the cluster network, such as the traffic from the traffic-agents, is
+// therefore always encrypted, and comes from clients that present a certificate signed by the CA.
+type tlsListener struct {
+	net.Listener
+	config *tls.Config
+	conns  chan net.Conn
+	done   chan struct{}
+	err    error
+}
+
+func newTLSListener(ctx context.Context, ln net.Listener, config *tls.Config) net.Listener {
+	l := &tlsListener{
+		Listener: ln,
+		config:   config,
+		conns:    make(chan net.Conn),
+		done:     make(chan struct{}),
+	}
+	go l.acceptLoop(ctx)
+	return l
+}
+
+func (l *tlsListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, l.err
+	}
+}
+
+func (l *tlsListener) acceptLoop(ctx context.Context) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			l.err = err
+			close(l.done)
+			return
+		}
+		go l.sniff(ctx, conn)
+	}
+}
+
+// sniff peeks at the first byte of the given connection to determine if it's a TLS connection, and
+// passes it on to Accept unless it's a plaintext connection that doesn't originate from a loopback address.
+func (l *tlsListener) sniff(ctx context.Context, conn net.Conn) {
+	br := bufio.NewReader(conn)
+	_ = conn.SetReadDeadline(time.Now().Add(sniffTimeout))
+	first, err := br.Peek(1)
+	_ = conn.SetReadDeadline(time.Time{})
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+	pc := &peekedConn{Conn: conn, r: br}
+	var c net.Conn
+	switch {
+	case first[0] == tlsHandshakeRecord:
+		c = tls.Server(pc, l.config)
+	case isLoopback(conn.RemoteAddr()):
+		c = pc
+	default:
+		dlog.Errorf(ctx, "rejecting plaintext gRPC connection from %s. Only TLS is allowed", conn.RemoteAddr())
+		_ = conn.Close()
+		return
+	}
+	select {
+	case l.conns <- c:
+	case <-l.done:
+		_ = c.Close()
+	}
+}
+
+func isLoopback(addr net.Addr) bool {
+	ta, ok := addr.(*net.TCPAddr)
+	return ok && ta.IP.IsLoopback()
+}
+
+// peekedConn is a net.Conn that reads from a bufio.Reader that might contain bytes that have
+// been peeked at.
+type peekedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *peekedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
@@ -0,0 +1,269 @@
+package manager
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/datawire/dlib/dhttp"
+	"github.com/datawire/dlib/dlog"
+)
+
+// writeTestCert writes a self-signed CA that is also the traffic-manager's certificate to the ca.crt, tls.crt,
+// and tls.key of the given directory, and returns it together with a client certificate that it has signed.
+func writeTestCert(t *testing.T, dir string) (*x509.Certificate, tls.Certificate) {
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "traffic-manager"},
+		DNSNames:              []string{"traffic-manager.ambassador"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ca.crt"), certPEM, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tls.crt"), certPEM, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tls.key"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600))
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	clientTmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(0).Add(serial, big.NewInt(1)),
+		Subject:      pkix.Name{CommonName: "traffic-agent"},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	clientDer, err := x509.CreateCertificate(rand.Reader, clientTmpl, cert, &clientKey.PublicKey, key)
+	require.NoError(t, err)
+	return cert, tls.Certificate{Certificate: [][]byte{clientDer}, PrivateKey: clientKey}
+}
+
+func TestTLSListener(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	dir := t.TempDir()
+	cert, clientCert := writeTestCert(t, dir)
+	cfg, err := loadTLSConfig(ctx, dir)
+	require.NoError(t, err)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	tl := newTLSListener(ctx, ln, cfg)
+	defer tl.Close()
+
+	readAll := func(conn net.Conn) string {
+		defer conn.Close()
+		data, err := io.ReadAll(conn)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("tls", func(t *testing.T) {
+		pool := x509.NewCertPool()
+		pool.AddCert(cert)
+		go func() {
+			conn, err := tls.Dial("tcp", ln.Addr().String(), &tls.Config{
+				Certificates: []tls.Certificate{clientCert},
+				RootCAs:      pool,
+				ServerName:   "traffic-manager.ambassador",
+			})
+			if !assert.NoError(t, err) {
+				return
+			}
+			_, _ = conn.Write([]byte("hello"))
+			_ = conn.Close()
+		}()
+		conn, err := tl.Accept()
+		require.NoError(t, err)
+		assert.IsType(t, &tls.Conn{}, conn)
+		assert.Equal(t, "hello", readAll(conn))
+	})
+
+	t.Run("plaintext from loopback", func(t *testing.T) {
+		go func() {
+			conn, err := net.Dial("tcp", ln.Addr().String())
+			if !assert.NoError(t, err) {
+				return
+			}
+			_, _ = conn.Write([]byte("hello"))
+			_ = conn.Close()
+		}()
+		conn, err := tl.Accept()
+		require.NoError(t, err)
+		assert.IsType(t, &peekedConn{}, conn)
+		assert.Equal(t, "hello", readAll(conn))
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		require.NoError(t, tl.Close())
+		_, err := tl.Accept()
+		assert.Error(t, err)
+	})
+}
+
+func Test_isLoopback(t *testing.T) {
+	assert.True(t, isLoopback(&net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 1234}))
+	assert.True(t, isLoopback(&net.TCPAddr{IP: net.ParseIP("::1"), Port: 1234}))
+	assert.False(t, isLoopback(&net.TCPAddr{IP: net.ParseIP("10.1.2.3"), Port: 1234}))
+}
+
+// TestTLSListener_grpc makes gRPC calls to a dhttp server that serves on a tlsListener, the same way as
+// the traffic-manager's gRPC API, using both mutual TLS and plaintext from loopback, before and after a
+// rotation of the certificates.
+func TestTLSListener_grpc(t *testing.T) {
+	ctx, cancel := context.WithCancel(dlog.NewTestContext(t, false))
+	defer cancel()
+	dir := t.TempDir()
+	cert, clientCert := writeTestCert(t, dir)
+	cfg, err := loadTLSConfig(ctx, dir)
+	require.NoError(t, err)
+
+	gs := grpc.NewServer()
+	healthpb.RegisterHealthServer(gs, health.NewServer())
+	sc := &dhttp.ServerConfig{Handler: gs}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	served := make(chan error, 1)
+	go func() {
+		served <- sc.Serve(ctx, newTLSListener(ctx, ln, cfg))
+	}()
+
+	check := func(t *testing.T, creds credentials.TransportCredentials) error {
+		conn, err := grpc.NewClient(ln.Addr().String(), grpc.WithTransportCredentials(creds))
+		require.NoError(t, err)
+		defer conn.Close()
+		tc, tCancel := context.WithTimeout(ctx, 5*time.Second)
+		defer tCancel()
+		rsp, err := healthpb.NewHealthClient(conn).Check(tc, &healthpb.HealthCheckRequest{})
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, rsp.Status)
+		return nil
+	}
+	tlsCreds := func(cert *x509.Certificate, clientCerts ...tls.Certificate) credentials.TransportCredentials {
+		pool := x509.NewCertPool()
+		pool.AddCert(cert)
+		return credentials.NewTLS(&tls.Config{Certificates: clientCerts, RootCAs: pool, ServerName: "traffic-manager.ambassador"})
+	}
+
+	tests := []struct {
+		name    string
+		creds   func() credentials.TransportCredentials
+		wantErr bool
+	}{
+		{
+			name:  "tls",
+			creds: func() credentials.TransportCredentials { return tlsCreds(cert, clientCert) },
+		},
+		{
+			name:    "tls without client certificate",
+			creds:   func() credentials.TransportCredentials { return tlsCreds(cert) },
+			wantErr: true,
+		},
+		{
+			name:  "plaintext from loopback",
+			creds: insecure.NewCredentials,
+		},
+		{
+			name: "rotated certificates",
+			creds: func() credentials.TransportCredentials {
+				newCert, newClientCert := writeTestCert(t, dir)
+				touchTLSFiles(t, dir, time.Now().Add(time.Minute))
+				return tlsCreds(newCert, newClientCert)
+			},
+		},
+		{
+			name:    "client certificate signed by the rotated CA",
+			creds:   func() credentials.TransportCredentials { return tlsCreds(cert, clientCert) },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := check(t, tt.creds())
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	cancel()
+	assert.ErrorIs(t, <-served, context.Canceled)
+}
+
+// touchTLSFiles sets the modification time of the files that the tls.Config is loaded from, so that
+// a change is detected even when the file system's timestamps are coarse.
+func touchTLSFiles(t *testing.T, dir string, mt time.Time) {
+	for _, f := range []string{"ca.crt", "tls.crt", "tls.key"} {
+		require.NoError(t, os.Chtimes(filepath.Join(dir, f), mt, mt))
+	}
+}
+
+func Test_loadTLSConfig(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	dir := t.TempDir()
+	_, _ = writeTestCert(t, dir)
+	cfg, err := loadTLSConfig(ctx, dir)
+	require.NoError(t, err)
+
+	first, err := cfg.GetConfigForClient(nil)
+	require.NoError(t, err)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, first.ClientAuth)
+	assert.NotNil(t, first.ClientCAs)
+
+	again, err := cfg.GetConfigForClient(nil)
+	require.NoError(t, err)
+	assert.Same(t, first, again, "unchanged files are not loaded again")
+
+	_, _ = writeTestCert(t, dir)
+	touchTLSFiles(t, dir, time.Now().Add(time.Minute))
+	rotated, err := cfg.GetConfigForClient(nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.Certificates[0].Certificate, rotated.Certificates[0].Certificate)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tls.key"), []byte("garbage"), 0o600))
+	touchTLSFiles(t, dir, time.Now().Add(2*time.Minute))
+	broken, err := cfg.GetConfigForClient(nil)
+	require.NoError(t, err)
+	assert.Same(t, rotated, broken, "the last valid config is kept")
+
+	_, err = loadTLSConfig(ctx, t.TempDir())
+	assert.Error(t, err)
+}
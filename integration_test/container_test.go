@@ -36,7 +36,6 @@ func (s *connectedSuite) Test_InterceptsContainer() {
 	envFile := filepath.Join(dir, "env.json")
 	stdout := itest.TelepresenceOk(ctx, "intercept", svc,
 		"--output", "json",
-		"--detailed-output",
 		"--container", "echo",
 		"--env-json", envFile,
 		"--port", strconv.Itoa(svcPort))
@@ -73,7 +72,6 @@ func (s *connectedSuite) Test_InterceptsContainer() {
 	itest.TelepresenceOk(ctx, "leave", svc)
 	stdout = itest.TelepresenceOk(ctx, "intercept", svc,
 		"--output", "json",
-		"--detailed-output",
 		"--env-json", envFile,
 		"--port", strconv.Itoa(svcPort))
 
@@ -108,7 +106,6 @@ func (s *connectedSuite) Test_InterceptsContainerAndReplace() {
 	envFile := filepath.Join(dir, "env.json")
 	stdout := itest.TelepresenceOk(ctx, "intercept", svc,
 		"--output", "json",
-		"--detailed-output",
 		"--container", "echo",
 		"--replace",
 		"--env-json", envFile,
@@ -160,7 +157,6 @@ func (s *connectedSuite) Test_InterceptsContainerAndReplace() {
 	itest.TelepresenceOk(ctx, "leave", svc)
 	stdout = itest.TelepresenceOk(ctx, "intercept", svc,
 		"--output", "json",
-		"--detailed-output",
 		"--env-json", envFile,
 		"--port", strconv.Itoa(svcPort))
 
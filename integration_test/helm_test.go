@@ -135,6 +135,55 @@ func (s *helmSuite) Test_HelmMultipleInstalls() {
 	})
 }
 
+func (s *helmSuite) Test_HelmCustomServiceName() {
+	svc := s.ServiceName()
+	defer func() {
+		ctx := s.Context()
+		itest.TelepresenceDisconnectOk(ctx)
+		s.TelepresenceConnect(ctx)
+	}()
+
+	s.Run("Installs Successfully", func() {
+		ctx := itest.WithNamespaces(s.Context(), &itest.Namespaces{
+			Namespace:         s.mgrSpace2,
+			ManagedNamespaces: []string{s.appSpace2},
+		})
+		s.NoError(itest.Kubectl(ctx, s.mgrSpace2, "apply", "-f", filepath.Join("testdata", "k8s", "client_sa.yaml")))
+		itest.TelepresenceDisconnectOk(ctx)
+		s.TelepresenceHelmInstallOK(ctx, false, "--set", "service.name=tel-manager")
+	})
+
+	s.Run("Cannot be connected to using the default service name", func() {
+		ctx := itest.WithUser(s.Context(), s.mgrSpace2+":"+itest.TestUser)
+		_, stderr, err := itest.Telepresence(ctx, "connect", "--namespace", s.appSpace2, "--manager-namespace", s.mgrSpace2)
+		s.Error(err)
+		s.Contains(stderr, "unable to get service traffic-manager")
+		itest.TelepresenceQuitOk(ctx)
+	})
+
+	s.Run("Can be connected to using the custom service name", func() {
+		ctx := itest.WithUser(s.Context(), s.mgrSpace2+":"+itest.TestUser)
+		stdout := itest.TelepresenceOk(ctx, "connect", "--namespace", s.appSpace2,
+			"--manager-namespace", s.mgrSpace2, "--manager-service-name", "tel-manager")
+		s.Contains(stdout, "Connected to context")
+		s.Eventually(func() bool {
+			return itest.Run(ctx, "curl", "--silent", "--connect-timeout", "1", fmt.Sprintf("%s.%s", svc, s.appSpace2)) == nil
+		}, 30*time.Second, 3*time.Second)
+	})
+
+	s.Run("Can intercept", func() {
+		ctx := s.Context()
+		defer itest.TelepresenceOk(ctx, "leave", svc)
+		stdout := itest.TelepresenceOk(ctx, "intercept", "--mount", "false", svc, "--port", "9090")
+		s.Contains(stdout, "Using Deployment "+svc)
+	})
+
+	s.Run("Uninstalls Successfully", func() {
+		defer itest.TelepresenceQuitOk(s.Context())
+		s.UninstallTrafficManager(s.Context(), s.mgrSpace2)
+	})
+}
+
 func (s *helmSuite) Test_CollidingInstalls() {
 	defer func() {
 		ctx := s.Context()
@@ -141,7 +141,6 @@ func (s *singleServiceSuite) Test_InterceptDetailedOutput() {
 	stdout := itest.TelepresenceOk(ctx, "intercept",
 		"--mount", "false",
 		"--port", strconv.Itoa(port),
-		"--detailed-output",
 		"--output", "json",
 		s.ServiceName())
 	defer func() {
@@ -263,6 +263,23 @@ func (s *notConnectedSuite) Test_AlsoNeverProxyDocker() {
 	s.True(slice.ContainsAll(cidrsToStrings(st.ContainerizedDaemon.NeverProxy), neverProxy))
 }
 
+func (s *notConnectedSuite) Test_AlsoProxyFlagOverridesExtension() {
+	extAlsoProxy := []string{"10.128.0.0/16"}
+	flagAlsoProxy := []string{"10.129.0.0/16"}
+	ctx := itest.WithKubeConfigExtension(s.Context(), func(cluster *api.Cluster) map[string]any {
+		return map[string]any{"also-proxy": extAlsoProxy}
+	})
+	s.TelepresenceConnect(ctx, "--context", "extra", "--also-proxy", flagAlsoProxy[0])
+	defer itest.TelepresenceQuitOk(ctx)
+	st := itest.TelepresenceStatusOk(ctx)
+	var aps []string
+	for _, ap := range st.RootDaemon.AlsoProxy {
+		aps = append(aps, ap.String())
+	}
+	s.True(slice.ContainsAll(aps, flagAlsoProxy))
+	s.False(slice.ContainsAny(aps, extAlsoProxy))
+}
+
 func (s *notConnectedSuite) Test_DNSSuffixRules() {
 	if s.IsCI() && runtime.GOOS == "linux" && runtime.GOARCH == "arm64" {
 		s.T().Skip("The DNS on the linux-arm64 GitHub runner is not configured correctly")
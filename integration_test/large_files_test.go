@@ -106,7 +106,6 @@ func (s *largeFilesSuite) createIntercepts(ctx context.Context) {
 			defer wg.Done()
 			svc := fmt.Sprintf("%s-%d", s.Name(), i)
 			stdout := itest.TelepresenceOk(ctx, "intercept",
-				"--detailed-output",
 				"--output", "json",
 				"--port", strconv.Itoa(8080+i),
 				svc,
@@ -42,7 +42,7 @@ func (m *managerGRPCSuite) SetupSuite() {
 
 	pfDialer, err := dnet.NewK8sPortForwardDialer(ctx, k8sCluster.RestConfig, k8sapi.GetK8sInterface(ctx))
 	m.Require().NoError(err)
-	m.conn, m.client, _, err = k8sclient.ConnectToManager(ctx, m.ManagerNamespace(), pfDialer.Dial)
+	m.conn, m.client, _, err = k8sclient.ConnectToManager(ctx, m.ManagerNamespace(), k8sCluster.GetManagerServiceName(), pfDialer.Dial)
 	m.Require().NoError(err)
 
 	_, err = m.client.Version(ctx, &empty.Empty{})
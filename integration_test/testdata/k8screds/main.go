@@ -2,12 +2,14 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/client-go/pkg/apis/clientauthentication"
 	"k8s.io/client-go/pkg/apis/clientauthentication/install"
@@ -74,7 +76,27 @@ func run(args []string) error {
 	return err
 }
 
+// requestedAPIVersion returns the ExecCredential API version that the caller requested in the
+// KUBERNETES_EXEC_INFO environment, falling back to v1beta1 when unspecified.
+func requestedAPIVersion() (schema.GroupVersion, error) {
+	gv := schema.GroupVersion{Group: clientauthentication.GroupName, Version: "v1beta1"}
+	if ei := os.Getenv("KUBERNETES_EXEC_INFO"); ei != "" {
+		var tm meta.TypeMeta
+		if err := json.Unmarshal([]byte(ei), &tm); err != nil {
+			return gv, fmt.Errorf("unable to parse KUBERNETES_EXEC_INFO: %w", err)
+		}
+		if tm.APIVersion != "" {
+			return schema.ParseGroupVersion(tm.APIVersion)
+		}
+	}
+	return gv, nil
+}
+
 func resolveCreds(ai *api.AuthInfo, cl *api.Cluster) ([]byte, error) {
+	gv, err := requestedAPIVersion()
+	if err != nil {
+		return nil, err
+	}
 	st := clientauthentication.ExecCredentialStatus{
 		Token: ai.Token,
 	}
@@ -87,7 +109,7 @@ func resolveCreds(ai *api.AuthInfo, cl *api.Cluster) ([]byte, error) {
 	creds := clientauthentication.ExecCredential{
 		TypeMeta: meta.TypeMeta{
 			Kind:       "ExecCredential",
-			APIVersion: "client.authentication.k8s.io/v1beta1",
+			APIVersion: gv.String(),
 		},
 		Spec: clientauthentication.ExecCredentialSpec{
 			Interactive: false,
@@ -107,7 +129,7 @@ func resolveCreds(ai *api.AuthInfo, cl *api.Cluster) ([]byte, error) {
 	scheme := runtime.NewScheme()
 	install.Install(scheme)
 	codecs := serializer.NewCodecFactory(scheme)
-	return runtime.Encode(codecs.LegacyCodec(creds.GroupVersionKind().GroupVersion()), &creds)
+	return runtime.Encode(codecs.LegacyCodec(gv), &creds)
 }
 
 func resolveExec(execConfig *api.ExecConfig) ([]byte, error) {
@@ -64,6 +64,15 @@ func AgentContainer(
 					FieldPath:  "metadata.name",
 				},
 			},
+		},
+		core.EnvVar{
+			Name: EnvPrefixAgent + "NODE_NAME",
+			ValueFrom: &core.EnvVarSource{
+				FieldRef: &core.ObjectFieldSelector{
+					APIVersion: "v1",
+					FieldPath:  "spec.nodeName",
+				},
+			},
 		})
 
 	mounts := make([]core.VolumeMount, 0, len(config.Containers)*3)
@@ -163,12 +172,34 @@ func AgentContainer(
 			dlog.Error(ctx, err)
 			return nil
 		}
+		if appSc == nil {
+			appSc = DefaultSecurityContext()
+		}
 	}
 	ac.SecurityContext = appSc
 
 	return ac
 }
 
+// DefaultSecurityContext returns the security context that is assigned to the traffic-agent when no
+// security context has been configured and no intercepted app container has one. It satisfies the
+// container level requirements of the "restricted" Pod Security Standard, except for runAsNonRoot,
+// which is inherited from the pod's security context. NET_BIND_SERVICE, which the standard allows, is
+// retained so that the agent can listen to ports below 1024.
+func DefaultSecurityContext() *core.SecurityContext {
+	allowPrivilegeEscalation := false
+	return &core.SecurityContext{
+		AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+		Capabilities: &core.Capabilities{
+			Add:  []core.Capability{"NET_BIND_SERVICE"},
+			Drop: []core.Capability{"ALL"},
+		},
+		SeccompProfile: &core.SeccompProfile{
+			Type: core.SeccompProfileTypeRuntimeDefault,
+		},
+	}
+}
+
 // Find security context of the first container (with both intercepts and a set security context) and ensure
 // that any env interpolations in it are prefixed with the env-prefix of the corresponding config container.
 func firstAppSecurityContext(pod *core.Pod, config *Sidecar) (*core.SecurityContext, error) {
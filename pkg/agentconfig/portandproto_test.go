@@ -0,0 +1,51 @@
+package agentconfig
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	core "k8s.io/api/core/v1"
+)
+
+func TestNewPortAndProto(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     string
+		want    PortAndProto
+		wantErr bool
+	}{
+		{"default tcp", "8080", PortAndProto{Port: 8080, Proto: core.ProtocolTCP}, false},
+		{"explicit tcp", "8080/TCP", PortAndProto{Port: 8080, Proto: core.ProtocolTCP}, false},
+		{"udp", "8125/UDP", PortAndProto{Port: 8125, Proto: core.ProtocolUDP}, false},
+		{"lowercase udp", "8125/udp", PortAndProto{Port: 8125, Proto: core.ProtocolUDP}, false},
+		{"sctp", "8125/sctp", PortAndProto{}, true},
+		{"bad port", "http/udp", PortAndProto{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewPortAndProto(tt.arg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPortAndProto_Addr(t *testing.T) {
+	pp := PortAndProto{Port: 8125, Proto: core.ProtocolUDP}
+	addr, err := pp.Addr()
+	require.NoError(t, err)
+	assert.IsType(t, &net.UDPAddr{}, addr)
+	assert.Equal(t, "8125/UDP", pp.String())
+
+	pp = PortAndProto{Port: 8080, Proto: core.ProtocolTCP}
+	addr, err = pp.Addr()
+	require.NoError(t, err)
+	assert.IsType(t, &net.TCPAddr{}, addr)
+	assert.Equal(t, "8080", pp.String())
+}
@@ -178,6 +178,15 @@ type Sidecar struct {
 	// The port used when connecting to the traffic manager
 	ManagerPort uint16 `json:"managerPort,omitempty"`
 
+	// PEM encoded CA certificate used to verify the traffic-manager when its gRPC API is served
+	// using TLS. Empty when TLS isn't used.
+	ManagerTLSCA string `json:"managerTLSCA,omitempty"`
+
+	// PEM encoded client certificate and key that the traffic-agent presents to the traffic-manager
+	// when its gRPC API is served using TLS. Empty when TLS isn't used.
+	ManagerTLSCert string `json:"managerTLSCert,omitempty"`
+	ManagerTLSKey  string `json:"managerTLSKey,omitempty"`
+
 	// The port used by the agents restFUL API server
 	APIPort uint16 `json:"apiPort,omitempty"`
 
@@ -0,0 +1,87 @@
+package agentconfig
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	core "k8s.io/api/core/v1"
+)
+
+// ToPodForward is a port that is forwarded from an intercepted pod to a local address. Its string form is
+// [<bind address>:[<local port>:]]<pod port>[/<protocol>], where an IPv6 bind address must be enclosed in
+// brackets.
+type ToPodForward struct {
+	// PortAndProto is the port and protocol in the pod.
+	PortAndProto
+
+	// BindAddr is the local address to bind to. The zero value means all local addresses.
+	BindAddr netip.Addr
+
+	// LocalPort is the local port to bind to.
+	LocalPort uint16
+}
+
+// NewToPodForward parses the given string into a ToPodForward.
+func NewToPodForward(s string) (f ToPodForward, err error) {
+	ix := strings.LastIndexByte(s, ':')
+	if f.PortAndProto, err = NewPortAndProto(s[ix+1:]); err != nil {
+		return f, err
+	}
+	f.LocalPort = f.Port
+	if ix < 0 {
+		return f, nil
+	}
+	addr := s[:ix]
+	if host, port, err := net.SplitHostPort(addr); err == nil {
+		if f.LocalPort, err = ParseNumericPort(port); err != nil {
+			return f, fmt.Errorf("invalid local port in %q: %w", s, err)
+		}
+		addr = host
+	} else {
+		addr = strings.TrimSuffix(strings.TrimPrefix(addr, "["), "]")
+	}
+	if f.BindAddr, err = netip.ParseAddr(addr); err != nil {
+		return f, fmt.Errorf("invalid bind address in %q: %w", s, err)
+	}
+	return f, nil
+}
+
+// Addr returns the local address to bind to.
+func (f *ToPodForward) Addr() (net.Addr, error) {
+	lp := PortAndProto{Port: f.LocalPort, Proto: f.Proto}
+	if !f.BindAddr.IsValid() {
+		return lp.Addr()
+	}
+	ap := netip.AddrPortFrom(f.BindAddr, f.LocalPort)
+	if f.Proto == core.ProtocolTCP {
+		return net.TCPAddrFromAddrPort(ap), nil
+	}
+	return net.UDPAddrFromAddrPort(ap), nil
+}
+
+// Conflicts returns true if the local address of this forward overlaps with the local address of the given forward.
+func (f *ToPodForward) Conflicts(o *ToPodForward) bool {
+	return f.Proto == o.Proto && f.LocalPort == o.LocalPort &&
+		(!f.BindAddr.IsValid() || !o.BindAddr.IsValid() || f.BindAddr == o.BindAddr)
+}
+
+// LocalAddrString returns the local address of this forward in host:port form.
+func (f *ToPodForward) LocalAddrString() string {
+	host := "0.0.0.0"
+	if f.BindAddr.IsValid() {
+		host = f.BindAddr.String()
+	}
+	return net.JoinHostPort(host, strconv.Itoa(int(f.LocalPort)))
+}
+
+// String returns the string form of this forward. The form is identical to that of the PortAndProto when
+// no bind address has been given.
+func (f *ToPodForward) String() string {
+	if !f.BindAddr.IsValid() {
+		return f.PortAndProto.String()
+	}
+	return net.JoinHostPort(f.BindAddr.String(), strconv.Itoa(int(f.LocalPort))) + ":" + f.PortAndProto.String()
+}
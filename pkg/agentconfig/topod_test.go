@@ -0,0 +1,95 @@
+package agentconfig
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	core "k8s.io/api/core/v1"
+)
+
+func TestNewToPodForward(t *testing.T) {
+	tests := []struct {
+		name     string
+		arg      string
+		want     ToPodForward
+		wantAddr string
+		wantErr  bool
+	}{
+		{
+			name:     "port",
+			arg:      "8081",
+			want:     ToPodForward{PortAndProto: PortAndProto{Port: 8081, Proto: core.ProtocolTCP}, LocalPort: 8081},
+			wantAddr: "0.0.0.0:8081",
+		},
+		{
+			name: "address",
+			arg:  "127.0.0.2:8081",
+			want: ToPodForward{
+				PortAndProto: PortAndProto{Port: 8081, Proto: core.ProtocolTCP},
+				BindAddr:     netip.MustParseAddr("127.0.0.2"),
+				LocalPort:    8081,
+			},
+			wantAddr: "127.0.0.2:8081",
+		},
+		{
+			name: "address and local port",
+			arg:  "127.0.0.2:9081:8081/udp",
+			want: ToPodForward{
+				PortAndProto: PortAndProto{Port: 8081, Proto: core.ProtocolUDP},
+				BindAddr:     netip.MustParseAddr("127.0.0.2"),
+				LocalPort:    9081,
+			},
+			wantAddr: "127.0.0.2:9081",
+		},
+		{
+			name: "ipv6 address",
+			arg:  "[::1]:8081:8081",
+			want: ToPodForward{
+				PortAndProto: PortAndProto{Port: 8081, Proto: core.ProtocolTCP},
+				BindAddr:     netip.MustParseAddr("::1"),
+				LocalPort:    8081,
+			},
+			wantAddr: "[::1]:8081",
+		},
+		{
+			name:    "bad address",
+			arg:     "localhost:8081",
+			wantErr: true,
+		},
+		{
+			name:    "bad local port",
+			arg:     "127.0.0.2:0:8081",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewToPodForward(tt.arg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantAddr, got.LocalAddrString())
+			again, err := NewToPodForward(got.String())
+			require.NoError(t, err)
+			assert.Equal(t, got, again)
+		})
+	}
+}
+
+func TestToPodForward_Conflicts(t *testing.T) {
+	parse := func(s string) *ToPodForward {
+		tp, err := NewToPodForward(s)
+		require.NoError(t, err)
+		return &tp
+	}
+	assert.True(t, parse("8081").Conflicts(parse("127.0.0.2:8081")))
+	assert.False(t, parse("127.0.0.2:8081").Conflicts(parse("127.0.0.2:9081:8082")))
+	assert.True(t, parse("127.0.0.2:8081:8082").Conflicts(parse("127.0.0.2:8081")))
+	assert.False(t, parse("127.0.0.1:8081").Conflicts(parse("127.0.0.2:8081")))
+	assert.False(t, parse("8081").Conflicts(parse("8081/udp")))
+}
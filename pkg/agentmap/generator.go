@@ -40,11 +40,15 @@ var GeneratorConfigFunc func(qualifiedAgentImage string) (GeneratorConfig, error
 
 type BasicGeneratorConfig struct {
 	ManagerPort         uint16
+	ManagerTLSCA        string
+	ManagerTLSCert      string
+	ManagerTLSKey       string
 	AgentPort           uint16
 	APIPort             uint16
 	TracingPort         uint16
 	QualifiedAgentImage string
 	ManagerNamespace    string
+	ManagerServiceName  string
 	LogLevel            string
 	InitResources       *core.ResourceRequirements
 	Resources           *core.ResourceRequirements
@@ -54,6 +58,13 @@ type BasicGeneratorConfig struct {
 	SecurityContext     *core.SecurityContext
 }
 
+func (cfg *BasicGeneratorConfig) managerServiceName() string {
+	if cfg.ManagerServiceName != "" {
+		return cfg.ManagerServiceName
+	}
+	return ManagerAppName
+}
+
 func portsFromAnnotation(wl k8sapi.Workload, annotation string) (ports []agentconfig.PortIdentifier, err error) {
 	if cpa := wl.GetPodTemplate().GetAnnotations()[annotation]; cpa != "" {
 		cps := strings.Split(cpa, ",")
@@ -165,8 +176,11 @@ func (cfg *BasicGeneratorConfig) Generate(
 		Namespace:       wl.GetNamespace(),
 		WorkloadName:    wl.GetName(),
 		WorkloadKind:    wl.GetKind(),
-		ManagerHost:     ManagerAppName + "." + cfg.ManagerNamespace,
+		ManagerHost:     cfg.managerServiceName() + "." + cfg.ManagerNamespace,
 		ManagerPort:     cfg.ManagerPort,
+		ManagerTLSCA:    cfg.ManagerTLSCA,
+		ManagerTLSCert:  cfg.ManagerTLSCert,
+		ManagerTLSKey:   cfg.ManagerTLSKey,
 		APIPort:         cfg.APIPort,
 		TracingPort:     cfg.TracingPort,
 		Containers:      ccs,
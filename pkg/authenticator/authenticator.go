@@ -2,18 +2,25 @@ package authenticator
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"sync"
+	"time"
 
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmd_api "k8s.io/client-go/tools/clientcmd/api"
 )
 
+// NewService returns a Service that resolves exec credentials using the given kubeconfig. An exec
+// credential plugin that doesn't complete within the given timeout is killed.
 func NewService(
 	kubeClientConfig clientcmd.ClientConfig,
+	timeout time.Duration,
 ) *Service {
 	return &Service{
 		kubeClientConfig:        kubeClientConfig,
-		execCredentialsResolver: execCredentialBinary{},
+		execCredentialsResolver: execCredentialBinary{timeout: timeout},
 	}
 }
 
@@ -29,23 +36,92 @@ type ExecCredentialsResolver interface {
 type Service struct {
 	kubeClientConfig        clientcmd.ClientConfig
 	execCredentialsResolver ExecCredentialsResolver
+
+	cacheLock sync.Mutex
+	cache     map[string]*cachedCredentials
+}
+
+// cachedCredentials are the raw credentials produced by an exec config, valid until they expire.
+type cachedCredentials struct {
+	execConfig     *clientcmd_api.ExecConfig
+	rawCredentials []byte
+	expiry         time.Time
 }
 
-func (a Service) GetExecCredentials(ctx context.Context, contextName string) ([]byte, error) {
+// expirySkew is subtracted from the expiration timestamp of cached credentials, so that credentials
+// aren't handed out when they are about to expire.
+const expirySkew = 10 * time.Second
+
+func (a *Service) GetExecCredentials(ctx context.Context, contextName string) ([]byte, error) {
 	execConfig, err := a.getExecConfigFromContext(contextName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get exec config from context %s, %w", contextName, err)
 	}
 
+	if rawCredentials, ok := a.cachedCredentials(contextName, execConfig); ok {
+		return rawCredentials, nil
+	}
+
+	// The plugin runs without holding the lock, so that a slow or interactive plugin doesn't block other callers.
 	rawExecCredentials, err := a.execCredentialsResolver.Resolve(ctx, execConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve credentials: %w", err)
 	}
 
+	// Credentials without an expiration timestamp are never cached, because there's no way
+	// to tell when they become invalid.
+	if expiry, ok := credentialsExpiry(rawExecCredentials); ok {
+		a.storeCredentials(contextName, &cachedCredentials{
+			execConfig:     execConfig,
+			rawCredentials: rawExecCredentials,
+			expiry:         expiry,
+		})
+	}
 	return rawExecCredentials, nil
 }
 
-func (a Service) getExecConfigFromContext(contextName string) (*clientcmd_api.ExecConfig, error) {
+// cachedCredentials returns the cached credentials for the given context, provided that they were produced by
+// the given exec config and don't expire within the expirySkew.
+func (a *Service) cachedCredentials(contextName string, execConfig *clientcmd_api.ExecConfig) ([]byte, bool) {
+	a.cacheLock.Lock()
+	defer a.cacheLock.Unlock()
+	if cc, ok := a.cache[contextName]; ok {
+		if time.Now().Add(expirySkew).Before(cc.expiry) && reflect.DeepEqual(cc.execConfig, execConfig) {
+			return cc.rawCredentials, true
+		}
+		delete(a.cache, contextName)
+	}
+	return nil, false
+}
+
+// storeCredentials caches the given credentials for the given context. A concurrent caller may have stored
+// credentials for the same context while the plugin ran, and those are kept if they're valid longer.
+func (a *Service) storeCredentials(contextName string, cc *cachedCredentials) {
+	a.cacheLock.Lock()
+	defer a.cacheLock.Unlock()
+	if oc, ok := a.cache[contextName]; ok && oc.expiry.After(cc.expiry) && reflect.DeepEqual(oc.execConfig, cc.execConfig) {
+		return
+	}
+	if a.cache == nil {
+		a.cache = make(map[string]*cachedCredentials)
+	}
+	a.cache[contextName] = cc
+}
+
+// credentialsExpiry returns the expirationTimestamp of the status in the given ExecCredential JSON.
+func credentialsExpiry(rawCredentials []byte) (time.Time, bool) {
+	var ec struct {
+		Status *struct {
+			ExpirationTimestamp *time.Time `json:"expirationTimestamp"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(rawCredentials, &ec); err != nil || ec.Status == nil || ec.Status.ExpirationTimestamp == nil {
+		return time.Time{}, false
+	}
+	return *ec.Status.ExpirationTimestamp, true
+}
+
+func (a *Service) getExecConfigFromContext(contextName string) (*clientcmd_api.ExecConfig, error) {
 	rawConfig, err := a.kubeClientConfig.RawConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get kubeconfig: %w", err)
@@ -66,8 +142,9 @@ func (a Service) getExecConfigFromContext(contextName string) (*clientcmd_api.Ex
 	}
 
 	return &clientcmd_api.ExecConfig{
-		Command: authInfo.Exec.Command,
-		Args:    authInfo.Exec.Args,
-		Env:     authInfo.Exec.Env,
+		Command:    authInfo.Exec.Command,
+		Args:       authInfo.Exec.Args,
+		Env:        authInfo.Exec.Env,
+		APIVersion: authInfo.Exec.APIVersion,
 	}, nil
 }
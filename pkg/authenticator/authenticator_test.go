@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -208,6 +209,86 @@ func (s *SuiteService) TestGetExecCredentialsSuccess() {
 	assert.Equal(s.T(), []byte(mockExecCredentials), rawCredentials)
 }
 
+func (s *SuiteService) TestGetExecCredentialsCached() {
+	// given
+	ctx := context.Background()
+	execCredentials := fmt.Sprintf(`{
+    "kind": "ExecCredential",
+    "apiVersion": "client.authentication.k8s.io/v1beta1",
+    "status": {
+        "expirationTimestamp": %q,
+        "token": "xxxx"
+    }
+}`, time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+	s.kubeClientConfig.EXPECT().RawConfig().Return(mockKubeConfig, nil).Times(2)
+	s.execCredentialsResolver.EXPECT().Resolve(ctx, gomock.Any()).Return([]byte(execCredentials), nil).Times(1)
+
+	// when
+	rawCredentials1, err1 := s.service.GetExecCredentials(ctx, "my-context")
+	rawCredentials2, err2 := s.service.GetExecCredentials(ctx, "my-context")
+
+	// then
+	assert.NoError(s.T(), err1)
+	assert.NoError(s.T(), err2)
+	assert.Equal(s.T(), []byte(execCredentials), rawCredentials1)
+	assert.Equal(s.T(), rawCredentials1, rawCredentials2)
+}
+
+func (s *SuiteService) TestGetExecCredentialsExpired() {
+	// given
+	ctx := context.Background()
+	s.kubeClientConfig.EXPECT().RawConfig().Return(mockKubeConfig, nil).Times(2)
+	s.execCredentialsResolver.EXPECT().Resolve(ctx, gomock.Any()).Return([]byte(mockExecCredentials), nil).Times(2)
+
+	// when
+	_, err1 := s.service.GetExecCredentials(ctx, "my-context")
+	_, err2 := s.service.GetExecCredentials(ctx, "my-context")
+
+	// then
+	assert.NoError(s.T(), err1)
+	assert.NoError(s.T(), err2)
+}
+
+func (s *SuiteService) TestGetExecCredentialsNotBlockedBySlowPlugin() {
+	// given
+	ctx := context.Background()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s.kubeClientConfig.EXPECT().RawConfig().Return(mockKubeConfig, nil).Times(2)
+	gomock.InOrder(
+		s.execCredentialsResolver.EXPECT().Resolve(ctx, gomock.Any()).DoAndReturn(
+			func(context.Context, *clientcmd_api.ExecConfig) ([]byte, error) {
+				close(started)
+				<-release
+				return []byte(mockExecCredentials), nil
+			}),
+		s.execCredentialsResolver.EXPECT().Resolve(ctx, gomock.Any()).Return([]byte(mockExecCredentials), nil),
+	)
+	slowDone := make(chan error, 1)
+	go func() {
+		_, err := s.service.GetExecCredentials(ctx, "my-context")
+		slowDone <- err
+	}()
+	<-started
+
+	// when
+	fastDone := make(chan error, 1)
+	go func() {
+		_, err := s.service.GetExecCredentials(ctx, "my-context")
+		fastDone <- err
+	}()
+
+	// then
+	select {
+	case err := <-fastDone:
+		assert.NoError(s.T(), err)
+	case <-time.After(5 * time.Second):
+		s.Fail("GetExecCredentials was blocked by a running plugin")
+	}
+	close(release)
+	assert.NoError(s.T(), <-slowDone)
+}
+
 func TestSuiteService(t *testing.T) {
 	suite.Run(t, new(SuiteService))
 }
@@ -3,7 +3,12 @@ package authenticator
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
+	"time"
 
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 
@@ -11,29 +16,75 @@ import (
 	"github.com/telepresenceio/telepresence/v2/pkg/proc"
 )
 
-type execCredentialBinary struct{}
+type execCredentialBinary struct {
+	// timeout is the maximum time that the credential plugin is allowed to run. Zero means no timeout.
+	timeout time.Duration
+}
+
+// defaultExecAPIVersion is the ExecCredential API version that is requested from a plugin when the
+// exec config doesn't declare one.
+const defaultExecAPIVersion = "client.authentication.k8s.io/v1beta1"
+
+// execInfoEnvVar is the environment variable that a credential plugin reads to learn what
+// ExecCredential API version it is expected to produce.
+const execInfoEnvVar = "KUBERNETES_EXEC_INFO"
+
+// execInfo returns the ExecCredential that is passed to the credential plugin in the execInfoEnvVar.
+func execInfo(apiVersion string) (string, error) {
+	if apiVersion == "" {
+		apiVersion = defaultExecAPIVersion
+	}
+	data, err := json.Marshal(map[string]any{
+		"kind":       "ExecCredential",
+		"apiVersion": apiVersion,
+		"spec":       map[string]any{"interactive": false},
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
 
 func (e execCredentialBinary) Resolve(
 	ctx context.Context,
 	execConfig *clientcmdapi.ExecConfig,
 ) ([]byte, error) {
-	var buf bytes.Buffer
+	var buf, errBuf bytes.Buffer
 
+	if e.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.timeout)
+		defer cancel()
+	}
 	cmd := proc.CommandContext(ctx, execConfig.Command, execConfig.Args...)
 	cmd.Stdout = &buf
-	cmd.Stderr = dos.Stderr(ctx)
+	cmd.Stderr = io.MultiWriter(dos.Stderr(ctx), &errBuf)
 	cmd.DisableLogging = true
-	cmd.Env = dos.Environ(ctx)
-	if len(execConfig.Env) > 0 {
-		em := dos.FromEnvPairs(cmd.Env)
-		for _, ev := range execConfig.Env {
-			em[ev.Name] = ev.Value
-		}
-		cmd.Env = em.Environ()
+	if e.timeout > 0 {
+		// Don't wait for output from child processes of a killed plugin.
+		cmd.WaitDelay = time.Second
+	}
+	info, err := execInfo(execConfig.APIVersion)
+	if err != nil {
+		return nil, err
 	}
+	em := dos.FromEnvPairs(dos.Environ(ctx))
+	em[execInfoEnvVar] = info
+	for _, ev := range execConfig.Env {
+		em[ev.Name] = ev.Value
+	}
+	cmd.Env = em.Environ()
 
 	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("failed to run host command: %w", err)
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("credential plugin %q timed out after %s", execConfig.Command, e.timeout)
+		} else {
+			err = fmt.Errorf("failed to run host command: %w", err)
+		}
+		if stderr := strings.TrimSpace(errBuf.String()); stderr != "" {
+			err = fmt.Errorf("%w: %s", err, stderr)
+		}
+		return nil, err
 	}
 
 	return buf.Bytes(), nil
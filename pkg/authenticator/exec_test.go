@@ -3,8 +3,14 @@ package authenticator
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/client-go/pkg/apis/clientauthentication"
+	"k8s.io/client-go/pkg/apis/clientauthentication/install"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 )
 
@@ -32,3 +38,49 @@ func TestExecCredentialsYesLocalEnv(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, string(result), "global-val/local-val\n")
 }
+
+func TestExecCredentialsAPIVersion(t *testing.T) {
+	scheme := runtime.NewScheme()
+	install.Install(scheme)
+	decoder := serializer.NewCodecFactory(scheme).UniversalDecoder()
+
+	tests := []struct {
+		name       string
+		apiVersion string
+		want       string
+	}{
+		{"unspecified", "", "client.authentication.k8s.io/v1beta1"},
+		{"v1beta1", "client.authentication.k8s.io/v1beta1", "client.authentication.k8s.io/v1beta1"},
+		{"v1", "client.authentication.k8s.io/v1", "client.authentication.k8s.io/v1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &clientcmdapi.ExecConfig{
+				Command:    "sh",
+				Args:       []string{"-c", "echo $KUBERNETES_EXEC_INFO"},
+				APIVersion: tt.apiVersion,
+			}
+			result, err := execCredentialBinary{}.Resolve(context.Background(), config)
+			require.NoError(t, err)
+
+			var ec clientauthentication.ExecCredential
+			_, gvk, err := decoder.Decode(result, nil, &ec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, gvk.GroupVersion().String())
+			assert.False(t, ec.Spec.Interactive)
+		})
+	}
+}
+
+func TestExecCredentialsTimeout(t *testing.T) {
+	config := &clientcmdapi.ExecConfig{
+		Command: "sh",
+		Args:    []string{"-c", "echo waiting for token >&2; exec sleep 10"},
+	}
+	start := time.Now()
+	_, err := execCredentialBinary{timeout: 200 * time.Millisecond}.Resolve(context.Background(), config)
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.ErrorContains(t, err, `credential plugin "sh" timed out after 200ms`)
+	assert.ErrorContains(t, err, "waiting for token")
+}
@@ -3,6 +3,7 @@ package grpc
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"google.golang.org/grpc"
 	"k8s.io/client-go/tools/clientcmd"
@@ -12,9 +13,9 @@ import (
 	"github.com/telepresenceio/telepresence/v2/pkg/authenticator"
 )
 
-func RegisterAuthenticatorServer(srv *grpc.Server, kubeClientConfig clientcmd.ClientConfig) {
+func RegisterAuthenticatorServer(srv *grpc.Server, kubeClientConfig clientcmd.ClientConfig, timeout time.Duration) {
 	rpc.RegisterAuthenticatorServer(srv, &AuthenticatorServer{
-		authenticator: authenticator.NewService(kubeClientConfig),
+		authenticator: authenticator.NewService(kubeClientConfig, timeout),
 	})
 }
 
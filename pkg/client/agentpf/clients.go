@@ -20,6 +20,7 @@ import (
 	"github.com/datawire/dlib/dtime"
 	"github.com/telepresenceio/telepresence/rpc/v2/agent"
 	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+	clientcfg "github.com/telepresenceio/telepresence/v2/pkg/client"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/k8sclient"
 	"github.com/telepresenceio/telepresence/v2/pkg/dnet"
 	"github.com/telepresenceio/telepresence/v2/pkg/iputil"
@@ -200,8 +201,15 @@ func (ac *client) startDialWatcherReady(ctx context.Context) error {
 	}
 	ac.Unlock()
 
+	provider := tunnel.AgentProvider(ac.cli)
+	if tc := clientcfg.GetConfig(ctx).Tunnel(); tc.PoolSize > 0 {
+		pool := tunnel.NewSparePool(provider, ac.session.SessionId, tc.PoolSize, tc.GetPoolIdleTimeout())
+		go pool.Run(ctx)
+		watcher = &refillingDialWatcher{Agent_WatchDialClient: watcher, pool: pool}
+	}
+
 	go func() {
-		err := tunnel.DialWaitLoop(ctx, tunnel.AgentProvider(ac.cli), watcher, ac.session.SessionId)
+		err := tunnel.DialWaitLoop(ctx, provider, watcher, ac.session.SessionId)
 		if err != nil {
 			dlog.Error(ctx, err)
 		}
@@ -209,6 +217,21 @@ func (ac *client) startDialWatcherReady(ctx context.Context) error {
 	return nil
 }
 
+// refillingDialWatcher refills a pool of spare streams each time a dial request arrives, so that the pool
+// is kept warm while the agent receives connections that it cannot bind to a spare stream.
+type refillingDialWatcher struct {
+	agent.Agent_WatchDialClient
+	pool *tunnel.SparePool
+}
+
+func (w *refillingDialWatcher) Recv() (*manager.DialRequest, error) {
+	dr, err := w.Agent_WatchDialClient.Recv()
+	if err == nil {
+		w.pool.Refill()
+	}
+	return dr, err
+}
+
 type Clients interface {
 	GetClient(net.IP) tunnel.Provider
 	WatchAgentPods(ctx context.Context, rmc manager.ManagerClient) error
@@ -0,0 +1,142 @@
+// Package api is a Go client for the Telepresence user daemon. It lets programs connect to a cluster and
+// create, list, and remove intercepts without running the telepresence CLI. The CLI itself uses the same
+// daemon API, so the two can be used interchangeably against the same user daemon.
+//
+// The user daemon must be running on the host. It is started by "telepresence connect" and keeps running
+// until "telepresence quit" is used or Client.Quit is called.
+package api
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+
+	"google.golang.org/grpc"
+	empty "google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/telepresenceio/telepresence/rpc/v2/connector"
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/socket"
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+)
+
+// ErrNotRunning is returned by NewClient when no user daemon is running on the host.
+var ErrNotRunning = errors.New(`the telepresence user daemon is not running; use "telepresence connect" to start it`)
+
+// Client is a client of the user daemon's connector API. It is safe for concurrent use.
+type Client struct {
+	conn *grpc.ClientConn
+	cc   connector.ConnectorClient
+}
+
+// ConnectError is the error returned by Client.Connect when the user daemon is unable to connect.
+type ConnectError struct {
+	error
+	code connector.ConnectInfo_ErrType
+}
+
+// Code returns the error type reported by the user daemon.
+func (ce *ConnectError) Code() connector.ConnectInfo_ErrType {
+	return ce.code
+}
+
+func (ce *ConnectError) Unwrap() error {
+	return ce.error
+}
+
+// NewClient returns a client of the user daemon that runs on the host. The returned client must be
+// closed when it is no longer needed.
+func NewClient(ctx context.Context) (*Client, error) {
+	return dial(ctx, socket.UserDaemonPath(ctx))
+}
+
+func dial(ctx context.Context, socketName string) (*Client, error) {
+	conn, err := socket.Dial(ctx, socketName, false)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			err = errcat.User.New(ErrNotRunning)
+		}
+		return nil, err
+	}
+	return &Client{conn: conn, cc: connector.NewConnectorClient(conn)}, nil
+}
+
+// Close closes the connection to the user daemon. The daemon, and its connection to the cluster, remain.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Connector returns the underlying gRPC client, for calls that have no counterpart in this package.
+func (c *Client) Connector() connector.ConnectorClient {
+	return c.cc
+}
+
+// Connect connects the user daemon to the cluster described by the request, or returns the current
+// connection when it is already connected with the same parameters.
+func (c *Client) Connect(ctx context.Context, cr *connector.ConnectRequest) (*connector.ConnectInfo, error) {
+	ci, err := c.cc.Connect(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	if err = ConnectInfoError(ci); err != nil {
+		return nil, err
+	}
+	return ci, nil
+}
+
+// ConnectInfoError returns a *ConnectError that describes the failure reported by the given connect info.
+// A nil error is returned when the connect info indicates success.
+func ConnectInfoError(ci *connector.ConnectInfo) error {
+	var msg string
+	switch ci.Error {
+	case connector.ConnectInfo_UNSPECIFIED, connector.ConnectInfo_ALREADY_CONNECTED:
+		return nil
+	case connector.ConnectInfo_MUST_RESTART:
+		msg = "Cluster configuration changed, please quit telepresence and reconnect"
+	default:
+		msg = ci.ErrorText
+	}
+	cat := errcat.Unknown
+	if ci.ErrorCategory != 0 {
+		cat = errcat.Category(ci.ErrorCategory)
+	}
+	return &ConnectError{error: cat.Newf("connector.Connect: %s", msg), code: ci.Error}
+}
+
+// Disconnect disconnects the user daemon from the cluster. The daemon keeps running.
+func (c *Client) Disconnect(ctx context.Context) error {
+	_, err := c.cc.Disconnect(ctx, &empty.Empty{})
+	return err
+}
+
+// CreateIntercept creates an intercept and returns its info once it is active. A failure is returned as
+// a *ResultError.
+func (c *Client) CreateIntercept(ctx context.Context, ir *connector.CreateInterceptRequest) (*manager.InterceptInfo, error) {
+	r, err := c.cc.CreateIntercept(ctx, ir)
+	if err = InterceptResultError(r, err); err != nil {
+		return nil, err
+	}
+	return r.InterceptInfo, nil
+}
+
+// RemoveIntercept removes the intercept with the given name. A failure is returned as a *ResultError.
+func (c *Client) RemoveIntercept(ctx context.Context, name string) error {
+	return InterceptResultError(c.cc.RemoveIntercept(ctx, &manager.RemoveInterceptRequest2{Name: name}))
+}
+
+// ListIntercepts returns the intercepts of the current connection. The list is empty when the user
+// daemon isn't connected.
+func (c *Client) ListIntercepts(ctx context.Context) ([]*manager.InterceptInfo, error) {
+	ci, err := c.cc.Status(ctx, &empty.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	return ci.GetIntercepts().GetIntercepts(), nil
+}
+
+// Quit terminates the user daemon, and the root daemon that it controls. The client cannot be used
+// after Quit, but it must still be closed.
+func (c *Client) Quit(ctx context.Context) error {
+	_, err := c.cc.Quit(ctx, &empty.Empty{})
+	return err
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	empty "google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/telepresence/rpc/v2/common"
+	"github.com/telepresenceio/telepresence/rpc/v2/connector"
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+)
+
+type fakeConnector struct {
+	connector.UnimplementedConnectorServer
+	connectInfo *connector.ConnectInfo
+	intercepts  map[string]*manager.InterceptInfo
+	quit        bool
+}
+
+func (f *fakeConnector) Connect(context.Context, *connector.ConnectRequest) (*connector.ConnectInfo, error) {
+	return f.connectInfo, nil
+}
+
+func (f *fakeConnector) Status(context.Context, *empty.Empty) (*connector.ConnectInfo, error) {
+	ci := &connector.ConnectInfo{Intercepts: &manager.InterceptInfoSnapshot{}}
+	for _, ii := range f.intercepts {
+		ci.Intercepts.Intercepts = append(ci.Intercepts.Intercepts, ii)
+	}
+	return ci, nil
+}
+
+func (f *fakeConnector) CreateIntercept(_ context.Context, ir *connector.CreateInterceptRequest) (*connector.InterceptResult, error) {
+	name := ir.Spec.Name
+	if _, ok := f.intercepts[name]; ok {
+		return &connector.InterceptResult{Error: common.InterceptError_ALREADY_EXISTS, ErrorText: name}, nil
+	}
+	ii := &manager.InterceptInfo{Id: "id-" + name, Spec: ir.Spec, Disposition: manager.InterceptDispositionType_ACTIVE}
+	f.intercepts[name] = ii
+	return &connector.InterceptResult{InterceptInfo: ii}, nil
+}
+
+func (f *fakeConnector) RemoveIntercept(_ context.Context, rr *manager.RemoveInterceptRequest2) (*connector.InterceptResult, error) {
+	if _, ok := f.intercepts[rr.Name]; !ok {
+		return &connector.InterceptResult{Error: common.InterceptError_NOT_FOUND, ErrorText: rr.Name}, nil
+	}
+	delete(f.intercepts, rr.Name)
+	return &connector.InterceptResult{}, nil
+}
+
+func (f *fakeConnector) Quit(context.Context, *empty.Empty) (*empty.Empty, error) {
+	f.quit = true
+	return &empty.Empty{}, nil
+}
+
+func startFakeConnector(t *testing.T, f *fakeConnector) string {
+	sockName := filepath.Join(t.TempDir(), "connector.sock")
+	l, err := net.Listen("unix", sockName)
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	connector.RegisterConnectorServer(srv, f)
+	go func() {
+		_ = srv.Serve(l)
+	}()
+	t.Cleanup(srv.Stop)
+	return sockName
+}
+
+func TestClient(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	f := &fakeConnector{
+		connectInfo: &connector.ConnectInfo{Error: connector.ConnectInfo_ALREADY_CONNECTED, ClusterContext: "default"},
+		intercepts:  make(map[string]*manager.InterceptInfo),
+	}
+	c, err := dial(ctx, startFakeConnector(t, f))
+	require.NoError(t, err)
+	defer c.Close()
+
+	ci, err := c.Connect(ctx, &connector.ConnectRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "default", ci.ClusterContext)
+
+	ii, err := c.CreateIntercept(ctx, &connector.CreateInterceptRequest{Spec: &manager.InterceptSpec{Name: "echo"}})
+	require.NoError(t, err)
+	assert.Equal(t, "id-echo", ii.Id)
+
+	_, err = c.CreateIntercept(ctx, &connector.CreateInterceptRequest{Spec: &manager.InterceptSpec{Name: "echo"}})
+	var re *ResultError
+	require.ErrorAs(t, err, &re)
+	assert.Equal(t, common.InterceptError_ALREADY_EXISTS, re.Code())
+
+	iis, err := c.ListIntercepts(ctx)
+	require.NoError(t, err)
+	require.Len(t, iis, 1)
+	assert.Equal(t, "echo", iis[0].Spec.Name)
+
+	require.NoError(t, c.RemoveIntercept(ctx, "echo"))
+	require.ErrorAs(t, c.RemoveIntercept(ctx, "echo"), &re)
+	assert.Equal(t, common.InterceptError_NOT_FOUND, re.Code())
+
+	require.NoError(t, c.Quit(ctx))
+	assert.True(t, f.quit)
+}
+
+func TestClient_connectError(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	f := &fakeConnector{connectInfo: &connector.ConnectInfo{
+		Error:         connector.ConnectInfo_CLUSTER_FAILED,
+		ErrorText:     "unable to reach the cluster",
+		ErrorCategory: int32(errcat.Config),
+	}}
+	c, err := dial(ctx, startFakeConnector(t, f))
+	require.NoError(t, err)
+	defer c.Close()
+
+	_, err = c.Connect(ctx, &connector.ConnectRequest{})
+	var ce *ConnectError
+	require.ErrorAs(t, err, &ce)
+	assert.Equal(t, connector.ConnectInfo_CLUSTER_FAILED, ce.Code())
+	assert.Equal(t, errcat.Config, errcat.GetCategory(err))
+	assert.Equal(t, "connector.Connect: unable to reach the cluster", err.Error())
+}
+
+func TestNewClient_notRunning(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	_, err := dial(ctx, filepath.Join(t.TempDir(), "not-running.sock"))
+	require.ErrorIs(t, err, ErrNotRunning)
+	assert.Equal(t, errcat.User, errcat.GetCategory(err))
+}
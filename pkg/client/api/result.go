@@ -1,8 +1,10 @@
-package intercept
+package api
 
 import (
 	"encoding/json"
 	"fmt"
+	"net"
+	"strconv"
 	"strings"
 
 	"github.com/telepresenceio/telepresence/rpc/v2/common"
@@ -11,7 +13,32 @@ import (
 	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
 )
 
-func Result(r *connector.InterceptResult, err error) error {
+// ResultError is the error returned by InterceptResultError. Its Code tells what kind of failure that caused it.
+type ResultError struct {
+	error
+	code common.InterceptError
+}
+
+func (re *ResultError) Code() common.InterceptError {
+	return re.code
+}
+
+func (re *ResultError) Unwrap() error {
+	return re.error
+}
+
+// DispositionMessage returns the disposition of the given intercept, followed by its message when
+// present, e.g. "AGENT_ERROR: port already bound".
+func DispositionMessage(ii *manager.InterceptInfo) string {
+	if ii.Message == "" {
+		return ii.Disposition.String()
+	}
+	return ii.Disposition.String() + ": " + ii.Message
+}
+
+// InterceptResultError returns the given error, or an error that describes the failure reported by
+// the given result. A nil error is returned when the result indicates success.
+func InterceptResultError(r *connector.InterceptResult, err error) error {
 	if r == nil || err != nil {
 		return err
 	}
@@ -39,8 +66,12 @@ func Result(r *connector.InterceptResult, err error) error {
 			nss[1], nss[0])
 	case common.InterceptError_LOCAL_TARGET_IN_USE:
 		spec := r.InterceptInfo.Spec
-		msg = fmt.Sprintf("Port %s:%d is already in use by intercept %s",
-			spec.TargetHost, spec.TargetPort, spec.Name)
+		// Older daemons send the name of the intercept rather than the conflicting address.
+		addr := r.ErrorText
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			addr = net.JoinHostPort(spec.TargetHost, strconv.Itoa(int(spec.TargetPort)))
+		}
+		msg = fmt.Sprintf("Port %s is already in use by intercept %s", addr, spec.Name)
 	case common.InterceptError_NO_ACCEPTABLE_WORKLOAD:
 		msg = fmt.Sprintf("No interceptable deployment, replicaset, or statefulset matching %s found", r.ErrorText)
 	case common.InterceptError_AMBIGUOUS_MATCH:
@@ -58,7 +89,12 @@ func Result(r *connector.InterceptResult, err error) error {
 		}
 		msg = st.String()
 	case common.InterceptError_FAILED_TO_ESTABLISH:
-		msg = fmt.Sprintf("Failed to establish intercept: %s", r.ErrorText)
+		if ii := r.InterceptInfo; ii != nil && ii.Disposition > manager.InterceptDispositionType_WAITING {
+			// The intercept entered an error state. Its disposition and message tell why.
+			msg = fmt.Sprintf("Failed to establish intercept: %s", DispositionMessage(ii))
+		} else {
+			msg = fmt.Sprintf("Failed to establish intercept: %s", r.ErrorText)
+		}
 	case common.InterceptError_UNSUPPORTED_WORKLOAD:
 		msg = fmt.Sprintf("Unsupported workload type: %s", r.ErrorText)
 	case common.InterceptError_NOT_FOUND:
@@ -79,5 +115,5 @@ func Result(r *connector.InterceptResult, err error) error {
 	if id := r.GetInterceptInfo().GetId(); id != "" {
 		msg = fmt.Sprintf("%s: id = %q", msg, id)
 	}
-	return errCat.New(msg)
+	return &ResultError{error: errCat.New(msg), code: r.Error}
 }
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/telepresenceio/telepresence/rpc/v2/common"
+	"github.com/telepresenceio/telepresence/rpc/v2/connector"
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+)
+
+func TestInterceptResultError_failedToEstablish(t *testing.T) {
+	r := &connector.InterceptResult{
+		Error:     common.InterceptError_FAILED_TO_ESTABLISH,
+		ErrorText: "AGENT_ERROR: port already bound",
+		InterceptInfo: &manager.InterceptInfo{
+			Id:          "abc",
+			Disposition: manager.InterceptDispositionType_AGENT_ERROR,
+			Message:     "port already bound",
+		},
+	}
+	err := InterceptResultError(r, nil)
+	require.Error(t, err)
+	assert.Equal(t, `Failed to establish intercept: AGENT_ERROR: port already bound: id = "abc"`, err.Error())
+
+	var re *ResultError
+	require.ErrorAs(t, err, &re)
+	assert.Equal(t, common.InterceptError_FAILED_TO_ESTABLISH, re.Code())
+
+	// Without an intercept in an error state, the error text is used.
+	r = &connector.InterceptResult{
+		Error:     common.InterceptError_FAILED_TO_ESTABLISH,
+		ErrorText: "the intercept did not become active within --wait 5s",
+	}
+	assert.Equal(t, "Failed to establish intercept: the intercept did not become active within --wait 5s", InterceptResultError(r, nil).Error())
+}
+
+func TestDispositionMessage(t *testing.T) {
+	assert.Equal(t, "NO_AGENT", DispositionMessage(&manager.InterceptInfo{Disposition: manager.InterceptDispositionType_NO_AGENT}))
+	assert.Equal(t, "BAD_ARGS: invalid header", DispositionMessage(&manager.InterceptInfo{
+		Disposition: manager.InterceptDispositionType_BAD_ARGS,
+		Message:     "invalid header",
+	}))
+}
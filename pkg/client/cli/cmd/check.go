@@ -0,0 +1,387 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sVersion "k8s.io/apimachinery/pkg/version"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/datawire/dlib/dexec"
+	"github.com/datawire/k8sapi/pkg/k8sapi"
+	"github.com/telepresenceio/telepresence/v2/pkg/agentmap"
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/output"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/socket"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/userd/k8s"
+	"github.com/telepresenceio/telepresence/v2/pkg/dnsproxy"
+	"github.com/telepresenceio/telepresence/v2/pkg/dos"
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+	"github.com/telepresenceio/telepresence/v2/pkg/ioutil"
+	"github.com/telepresenceio/telepresence/v2/pkg/proc"
+	"github.com/telepresenceio/telepresence/v2/pkg/slice"
+)
+
+type checkStatus string
+
+const (
+	checkPass checkStatus = "pass"
+	checkWarn checkStatus = "warn"
+	checkFail checkStatus = "fail"
+	checkSkip checkStatus = "skip"
+)
+
+// checkResult is the outcome of one of the diagnostics performed by the check command.
+type checkResult struct {
+	Name   string      `json:"name"             yaml:"name"`
+	Status checkStatus `json:"status"           yaml:"status"`
+	Detail string      `json:"detail,omitempty" yaml:"detail,omitempty"`
+	Hint   string      `json:"hint,omitempty"   yaml:"hint,omitempty"`
+}
+
+type checkCommand struct {
+	kubeConfig         *genericclioptions.ConfigFlags
+	kubeFlagSet        *pflag.FlagSet
+	managerNamespace   string
+	dnsResolverAddress string
+}
+
+func checkCmd() *cobra.Command {
+	cc := &checkCommand{kubeConfig: genericclioptions.NewConfigFlags(false)}
+	cmd := &cobra.Command{
+		Use:   "check",
+		Args:  cobra.NoArgs,
+		Short: "Check that this workstation and the cluster are ready for telepresence connect",
+		Long: `Run a series of diagnostics that verify that telepresence connect can succeed, and report the result of each
+one together with a hint on how to remedy a failure. The command neither starts the daemons nor installs anything in
+the cluster.
+
+The checks are:
+  kubeconfig       the kubeconfig can be loaded and has a valid context
+  cluster API      the cluster's API server is reachable using the kubeconfig
+  traffic-manager  the traffic-manager is installed and its version is compatible with the client
+  cluster DNS      the cluster's DNS service has ready endpoints
+  DNS resolver     the address given with --dns-resolver-address is available
+  privileges       the root daemon can be started`,
+		RunE: cc.run,
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&cc.managerNamespace, "manager-namespace", "", `The namespace where the traffic manager is to be found. `+
+		`Overrides any other manager namespace set in config`)
+	flags.StringVar(&cc.dnsResolverAddress, "dns-resolver-address", "", ``+
+		`Local address and port that the DNS resolver of the root daemon will listen to. Defaults to 127.0.0.1 and a random port`)
+	cc.kubeFlagSet = pflag.NewFlagSet("Kubernetes flags", 0)
+	cc.kubeConfig.AddFlags(cc.kubeFlagSet)
+	flags.AddFlagSet(cc.kubeFlagSet)
+	return cmd
+}
+
+func (cc *checkCommand) run(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	results := cc.runChecks(ctx)
+	if output.WantsFormatted(cmd) {
+		output.Object(ctx, results, false)
+	} else {
+		writeCheckReport(output.Out(ctx), results)
+	}
+	failed := 0
+	for _, r := range results {
+		if r.Status == checkFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return errcat.User.Newf("%d of %d checks failed", failed, len(results))
+	}
+	return nil
+}
+
+func (cc *checkCommand) runChecks(ctx context.Context) []checkResult {
+	kc, kr := cc.checkKubeconfig(ctx)
+	results := []checkResult{kr}
+	if kc == nil {
+		results = append(results,
+			checkResult{Name: "cluster API", Status: checkSkip, Detail: "no valid kubeconfig"},
+			checkResult{Name: "traffic-manager", Status: checkSkip, Detail: "no valid kubeconfig"},
+			checkResult{Name: "cluster DNS", Status: checkSkip, Detail: "no valid kubeconfig"})
+	} else {
+		ki, ar := checkClusterAPI(ctx, kc)
+		results = append(results, ar)
+		if ki == nil {
+			results = append(results,
+				checkResult{Name: "traffic-manager", Status: checkSkip, Detail: "the cluster API is not reachable"},
+				checkResult{Name: "cluster DNS", Status: checkSkip, Detail: "the cluster API is not reachable"})
+		} else {
+			results = append(results, checkTrafficManager(ctx, ki, kc), checkClusterDNS(ctx, ki, kc))
+		}
+	}
+	return append(results, checkDNSResolver(cc.dnsResolverAddress), checkPrivileges(ctx))
+}
+
+func (cc *checkCommand) checkKubeconfig(ctx context.Context) (*client.Kubeconfig, checkResult) {
+	r := checkResult{Name: "kubeconfig"}
+	flagMap := make(map[string]string)
+	cc.kubeFlagSet.VisitAll(func(flag *pflag.Flag) {
+		if flag.Changed {
+			if sv, ok := flag.Value.(pflag.SliceValue); ok {
+				flagMap[flag.Name] = slice.AsCSV(sv.GetSlice())
+			} else {
+				flagMap[flag.Name] = flag.Value.String()
+			}
+		}
+	})
+	kc, err := client.NewKubeconfig(ctx, flagMap, cc.managerNamespace)
+	if err != nil {
+		r.Status = checkFail
+		r.Detail = err.Error()
+		r.Hint = "Verify that the KUBECONFIG environment variable, or the --kubeconfig flag, refers to a valid kubeconfig, " +
+			"and that its current context, or the one given with --context, exists. Use telepresence list-contexts to list the contexts"
+		return nil, r
+	}
+	r.Status = checkPass
+	r.Detail = fmt.Sprintf("context %s, server %s", kc.Context, kc.Server)
+	return kc, r
+}
+
+func checkClusterAPI(ctx context.Context, kc *client.Kubeconfig) (kubernetes.Interface, checkResult) {
+	r := checkResult{Name: "cluster API"}
+	timeout := client.GetConfig(ctx).Timeouts().Get(client.TimeoutClusterConnect)
+	rc := *kc.RestConfig
+	rc.Timeout = timeout
+	ki, err := kubernetes.NewForConfig(&rc)
+	if err == nil {
+		var sv *k8sVersion.Info
+		if sv, err = ki.Discovery().ServerVersion(); err == nil {
+			r.Status = checkPass
+			r.Detail = "Kubernetes " + sv.String()
+			return ki, r
+		}
+	}
+	r.Status = checkFail
+	r.Detail = err.Error()
+	r.Hint = fmt.Sprintf("Verify that the cluster is running and that kubectl version succeeds using the same context. "+
+		"When the kubeconfig uses an exec credentials plugin, it must complete within the %s given by timeouts.clusterConnect "+
+		"in the telepresence config.yml", timeout)
+	return nil, r
+}
+
+func checkTrafficManager(ctx context.Context, ki kubernetes.Interface, kc *client.Kubeconfig) checkResult {
+	r := checkResult{Name: "traffic-manager"}
+	ctx = k8sapi.WithK8sInterface(ctx, ki)
+	ns := kc.GetManagerNamespace()
+	if ns == "" {
+		var err error
+		if ns, err = k8s.DiscoverTrafficManagerNamespace(ctx, kc.GetManagerServiceName(), kc.Namespace, candidateNamespaces(ctx, ki, kc)); err != nil {
+			r.Status = checkFail
+			r.Detail = err.Error()
+			r.Hint = "Use --manager-namespace to specify the namespace of the traffic-manager"
+			return r
+		}
+	}
+	name := agentmap.ManagerAppName
+	dep, err := ki.AppsV1().Deployments(ns).Get(ctx, name, meta.GetOptions{})
+	switch {
+	case err == nil:
+		return managerVersionResult(r, ns, dep.Labels["app.kubernetes.io/version"], client.Semver())
+	case k8sErrors.IsNotFound(err):
+		r.Status = checkFail
+		r.Detail = fmt.Sprintf("no %s found in namespace %s", name, ns)
+		r.Hint = "Use telepresence helm install to install the traffic-manager, or --manager-namespace if it is installed in another namespace"
+	case k8sErrors.IsForbidden(err):
+		r.Status = checkWarn
+		r.Detail = fmt.Sprintf("not permitted to get the %s deployment in namespace %s", name, ns)
+		r.Hint = "The traffic-manager may still be reachable. Ask your cluster administrator to verify that it is installed"
+	default:
+		r.Status = checkFail
+		r.Detail = err.Error()
+	}
+	return r
+}
+
+// candidateNamespaces returns the namespaces that telepresence connect would search for the traffic-manager
+// service: the mapped namespaces when they are configured, otherwise all namespaces, or only the namespace of
+// the kubeconfig context when the client isn't permitted to list namespaces.
+func candidateNamespaces(ctx context.Context, ki kubernetes.Interface, kc *client.Kubeconfig) []string {
+	if nss := client.GetConfig(ctx).Cluster().MappedNamespaces; len(nss) > 0 {
+		return nss
+	}
+	nl, err := ki.CoreV1().Namespaces().List(ctx, meta.ListOptions{})
+	if err != nil {
+		return []string{kc.Namespace}
+	}
+	nss := make([]string, len(nl.Items))
+	for i := range nl.Items {
+		nss[i] = nl.Items[i].Name
+	}
+	return nss
+}
+
+// managerVersionResult completes the given result using the traffic-manager version, which is compared to
+// the client version in the same way as telepresence connect does.
+func managerVersionResult(r checkResult, ns, mv string, cv semver.Version) checkResult {
+	if mv == "" {
+		r.Status = checkWarn
+		r.Detail = fmt.Sprintf("found in namespace %s, but its version is unknown", ns)
+		return r
+	}
+	ms, err := semver.Parse(strings.TrimPrefix(mv, "v"))
+	if err != nil {
+		r.Status = checkWarn
+		r.Detail = fmt.Sprintf("found in namespace %s, but its version %q is invalid", ns, mv)
+		return r
+	}
+	r.Detail = fmt.Sprintf("version %s in namespace %s", mv, ns)
+	if cv.Major != ms.Major || cv.Minor != ms.Minor {
+		r.Status = checkWarn
+		r.Detail += fmt.Sprintf(" differs from the client version v%s by more than a patch release", cv)
+		if cv.GT(ms) {
+			r.Hint = "Use telepresence helm upgrade to upgrade the traffic-manager"
+		} else {
+			r.Hint = "Upgrade the client"
+		}
+		return r
+	}
+	r.Status = checkPass
+	return r
+}
+
+// clusterDNSServices are the services that provide the cluster's DNS, in the order that they are looked up.
+var clusterDNSServices = []struct{ namespace, name string }{ //nolint:gochecknoglobals // constant
+	{"kube-system", "kube-dns"},
+	{"openshift-dns", "dns-default"},
+}
+
+// checkClusterDNS finds the cluster's DNS service and checks that it has endpoints that are ready to answer.
+// The address isn't queried directly, because it isn't routed to the workstation until telepresence connect
+// has been run.
+func checkClusterDNS(ctx context.Context, ki kubernetes.Interface, kc *client.Kubeconfig) checkResult {
+	r := checkResult{Name: "cluster DNS"}
+	if dc := kc.DNS; dc != nil && dc.RemoteIP != "" {
+		r.Status = checkPass
+		r.Detail = fmt.Sprintf("using %s, configured with dns.remote-ip in the kubeconfig extension", dc.RemoteIP)
+		return r
+	}
+	for _, ds := range clusterDNSServices {
+		svc, err := ki.CoreV1().Services(ds.namespace).Get(ctx, ds.name, meta.GetOptions{})
+		if err != nil {
+			if k8sErrors.IsNotFound(err) {
+				continue
+			}
+			r.Status = checkWarn
+			r.Detail = fmt.Sprintf("unable to get service %s.%s: %v", ds.name, ds.namespace, err)
+			r.Hint = "Ask your cluster administrator to verify that the cluster DNS is running"
+			return r
+		}
+		ready := 0
+		if eps, err := ki.CoreV1().Endpoints(ds.namespace).Get(ctx, ds.name, meta.GetOptions{}); err == nil {
+			for _, ss := range eps.Subsets {
+				ready += len(ss.Addresses)
+			}
+		}
+		addr := fmt.Sprintf("%s (service %s.%s)", svc.Spec.ClusterIP, ds.name, ds.namespace)
+		if ready == 0 {
+			r.Status = checkFail
+			r.Detail = addr + " has no ready endpoints"
+			r.Hint = fmt.Sprintf("Verify that the DNS pods in namespace %s are running", ds.namespace)
+			return r
+		}
+		r.Status = checkPass
+		r.Detail = fmt.Sprintf("%s has %d ready endpoints", addr, ready)
+		return r
+	}
+	r.Status = checkWarn
+	r.Detail = "no cluster DNS service found"
+	r.Hint = "Use dns.remote-ip in the telepresence.io kubeconfig extension to declare the address of the cluster DNS"
+	return r
+}
+
+func checkDNSResolver(addr string) checkResult {
+	r := checkResult{Name: "DNS resolver"}
+	if addr == "" {
+		r.Status = checkSkip
+		r.Detail = "no --dns-resolver-address given, so connect will pick a free port"
+		return r
+	}
+	if err := dnsproxy.CheckListenAddress(addr); err != nil {
+		r.Status = checkFail
+		r.Detail = err.Error()
+		r.Hint = "Use --dns-resolver-address with connect to choose an address and port that is free, " +
+			"or stop the process that listens to it"
+		return r
+	}
+	r.Status = checkPass
+	r.Detail = "able to listen to " + addr
+	return r
+}
+
+func checkPrivileges(ctx context.Context) checkResult {
+	r := checkResult{Name: "privileges", Status: checkPass}
+	if running, _ := socket.IsRunning(ctx, socket.RootDaemonPath(ctx)); running {
+		r.Detail = "the root daemon is already running"
+		return r
+	}
+	switch {
+	case proc.IsAdmin():
+		r.Detail = "running with administrator privileges"
+	case runtime.GOOS == "windows":
+		r.Detail = "the root daemon will be started after an elevation prompt"
+	default:
+		if _, err := dexec.LookPath("sudo"); err != nil {
+			r.Status = checkFail
+			r.Detail = "sudo is not available, so the root daemon cannot be started"
+			r.Hint = "Install sudo, run telepresence connect as root, or use telepresence connect --docker to run the daemons in a container"
+		} else {
+			var errBuf bytes.Buffer
+			cmd := proc.CommandContext(ctx, "sudo", "-n", "true")
+			cmd.Stderr = &errBuf
+			// The stderr message is matched by sudoResult, so it must not be localized.
+			cmd.Env = append(dos.Environ(ctx), "LC_ALL=C")
+			cmd.DisableLogging = true
+			r = sudoResult(r, cmd.Run(), errBuf.String())
+		}
+	}
+	return r
+}
+
+// sudoResult completes the given result using the outcome of running "sudo -n true", which fails rather than
+// prompting when a password is required.
+func sudoResult(r checkResult, err error, stderr string) checkResult {
+	switch {
+	case err == nil:
+		r.Status = checkPass
+		r.Detail = "the root daemon will be started using sudo"
+	case strings.Contains(stderr, "password is required"):
+		r.Status = checkPass
+		r.Detail = "the root daemon will be started using sudo, which will prompt for a password"
+	default:
+		r.Status = checkFail
+		msg := strings.TrimSpace(stderr)
+		if msg == "" {
+			msg = err.Error()
+		}
+		r.Detail = "unable to use sudo: " + msg
+		r.Hint = "Make sure that you are permitted to use sudo, run telepresence connect as root, or use " +
+			"telepresence connect --docker to run the daemons in a container"
+	}
+	return r
+}
+
+// writeCheckReport writes one line per result, followed by the hint of each result that didn't pass.
+func writeCheckReport(w io.Writer, results []checkResult) {
+	for _, r := range results {
+		ioutil.Printf(w, "%-4s  %-15s  %s\n", strings.ToUpper(string(r.Status)), r.Name, r.Detail)
+		if r.Hint != "" && r.Status != checkPass {
+			ioutil.Printf(w, "%-4s  %-15s  hint: %s\n", "", "", r.Hint)
+		}
+	}
+}
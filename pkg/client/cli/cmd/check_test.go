@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
+	"github.com/telepresenceio/telepresence/v2/pkg/iputil"
+)
+
+func Test_managerVersionResult(t *testing.T) {
+	cv := semver.MustParse("2.20.1")
+	tests := []struct {
+		name   string
+		mv     string
+		status checkStatus
+		hint   string
+	}{
+		{name: "same", mv: "2.20.1", status: checkPass},
+		{name: "patch", mv: "v2.20.3", status: checkPass},
+		{name: "older", mv: "2.19.0", status: checkWarn, hint: "Use telepresence helm upgrade to upgrade the traffic-manager"},
+		{name: "newer", mv: "2.21.0", status: checkWarn, hint: "Upgrade the client"},
+		{name: "unknown", mv: "", status: checkWarn},
+		{name: "invalid", mv: "latest", status: checkWarn},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := managerVersionResult(checkResult{Name: "traffic-manager"}, "ambassador", tt.mv, cv)
+			assert.Equal(t, tt.status, r.Status)
+			assert.Equal(t, tt.hint, r.Hint)
+			assert.Contains(t, r.Detail, "namespace ambassador")
+		})
+	}
+}
+
+func Test_checkTrafficManager(t *testing.T) {
+	ctx := client.WithConfig(context.Background(), client.GetDefaultConfig())
+	kc := &client.Kubeconfig{KubeconfigExtension: client.KubeconfigExtension{Manager: &client.ManagerConfig{Namespace: "tel"}}}
+
+	r := checkTrafficManager(ctx, fake.NewSimpleClientset(), kc)
+	assert.Equal(t, checkFail, r.Status)
+	assert.Equal(t, "no traffic-manager found in namespace tel", r.Detail)
+
+	ki := fake.NewSimpleClientset(&apps.Deployment{ObjectMeta: meta.ObjectMeta{
+		Name:      "traffic-manager",
+		Namespace: "tel",
+		Labels:    map[string]string{"app.kubernetes.io/version": client.Semver().String()},
+	}})
+	r = checkTrafficManager(ctx, ki, kc)
+	assert.Equal(t, checkPass, r.Status)
+
+	// The deployment is always named traffic-manager, even when the service has another name.
+	kc = &client.Kubeconfig{KubeconfigExtension: client.KubeconfigExtension{Manager: &client.ManagerConfig{ServiceName: "tm"}}}
+	ki = fake.NewSimpleClientset(
+		&core.Namespace{ObjectMeta: meta.ObjectMeta{Name: "default"}},
+		&core.Namespace{ObjectMeta: meta.ObjectMeta{Name: "tel"}},
+		&core.Service{ObjectMeta: meta.ObjectMeta{Name: "tm", Namespace: "tel"}},
+		&apps.Deployment{ObjectMeta: meta.ObjectMeta{
+			Name:      "traffic-manager",
+			Namespace: "tel",
+			Labels:    map[string]string{"app.kubernetes.io/version": client.Semver().String()},
+		}})
+	r = checkTrafficManager(ctx, ki, kc)
+	assert.Equal(t, checkPass, r.Status)
+	assert.Contains(t, r.Detail, "in namespace tel")
+}
+
+func Test_checkClusterDNS(t *testing.T) {
+	ctx := context.Background()
+	kubeDNS := &core.Service{
+		ObjectMeta: meta.ObjectMeta{Name: "kube-dns", Namespace: "kube-system"},
+		Spec:       core.ServiceSpec{ClusterIP: "10.96.0.10"},
+	}
+	endpoints := func(addrs ...string) *core.Endpoints {
+		ss := core.EndpointSubset{}
+		for _, a := range addrs {
+			ss.Addresses = append(ss.Addresses, core.EndpointAddress{IP: a})
+		}
+		return &core.Endpoints{
+			ObjectMeta: meta.ObjectMeta{Name: "kube-dns", Namespace: "kube-system"},
+			Subsets:    []core.EndpointSubset{ss},
+		}
+	}
+	kc := &client.Kubeconfig{}
+
+	tests := []struct {
+		name   string
+		kc     *client.Kubeconfig
+		ki     *fake.Clientset
+		status checkStatus
+		detail string
+	}{
+		{
+			name:   "ready",
+			kc:     kc,
+			ki:     fake.NewSimpleClientset(kubeDNS, endpoints("10.244.0.2", "10.244.0.3")),
+			status: checkPass,
+			detail: "10.96.0.10 (service kube-dns.kube-system) has 2 ready endpoints",
+		},
+		{
+			name:   "not ready",
+			kc:     kc,
+			ki:     fake.NewSimpleClientset(kubeDNS, endpoints()),
+			status: checkFail,
+			detail: "10.96.0.10 (service kube-dns.kube-system) has no ready endpoints",
+		},
+		{
+			name:   "not found",
+			kc:     kc,
+			ki:     fake.NewSimpleClientset(),
+			status: checkWarn,
+			detail: "no cluster DNS service found",
+		},
+		{
+			name: "configured",
+			kc: &client.Kubeconfig{KubeconfigExtension: client.KubeconfigExtension{
+				DNS: &client.DnsConfig{RemoteIP: iputil.IPKey(net.ParseIP("10.0.0.10"))},
+			}},
+			ki:     fake.NewSimpleClientset(),
+			status: checkPass,
+			detail: "using 10.0.0.10, configured with dns.remote-ip in the kubeconfig extension",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := checkClusterDNS(ctx, tt.ki, tt.kc)
+			assert.Equal(t, tt.status, r.Status)
+			assert.Equal(t, tt.detail, r.Detail)
+		})
+	}
+}
+
+func Test_sudoResult(t *testing.T) {
+	r := sudoResult(checkResult{}, nil, "")
+	assert.Equal(t, checkPass, r.Status)
+
+	r = sudoResult(checkResult{}, errors.New("exit status 1"), "sudo: a password is required\n")
+	assert.Equal(t, checkPass, r.Status)
+	assert.Contains(t, r.Detail, "prompt for a password")
+
+	r = sudoResult(checkResult{}, errors.New("exit status 1"), "alice is not in the sudoers file.\n")
+	assert.Equal(t, checkFail, r.Status)
+	assert.Equal(t, "unable to use sudo: alice is not in the sudoers file.", r.Detail)
+	assert.NotEmpty(t, r.Hint)
+
+	r = sudoResult(checkResult{}, errors.New("exit status 1"), "")
+	assert.Equal(t, "unable to use sudo: exit status 1", r.Detail)
+}
+
+func Test_checkDNSResolver(t *testing.T) {
+	assert.Equal(t, checkSkip, checkDNSResolver("").Status)
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer pc.Close()
+	r := checkDNSResolver(pc.LocalAddr().String())
+	assert.Equal(t, checkFail, r.Status)
+	assert.NotEmpty(t, r.Hint)
+}
+
+func Test_writeCheckReport(t *testing.T) {
+	out := &bytes.Buffer{}
+	writeCheckReport(out, []checkResult{
+		{Name: "kubeconfig", Status: checkPass, Detail: "context kind", Hint: "not shown"},
+		{Name: "cluster API", Status: checkFail, Detail: "connection refused", Hint: "start the cluster"},
+		{Name: "traffic-manager", Status: checkSkip, Detail: "the cluster API is not reachable"},
+	})
+	assert.Equal(t, ""+
+		"PASS  kubeconfig       context kind\n"+
+		"FAIL  cluster API      connection refused\n"+
+		"                       hint: start the cluster\n"+
+		"SKIP  traffic-manager  the cluster API is not reachable\n",
+		out.String())
+}
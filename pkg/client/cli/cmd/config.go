@@ -98,6 +98,8 @@ func runConfigView(cmd *cobra.Command, _ []string) error {
 			cfg.DNS.ExcludeSuffixes = dns.ExcludeSuffixes
 			cfg.DNS.IncludeSuffixes = dns.IncludeSuffixes
 			cfg.DNS.LookupTimeout = dns.LookupTimeout.Duration
+			cfg.DNS.CacheTTL = dns.CacheTTL.Duration
+			cfg.DNS.NegativeTTL = dns.NegativeTTL.Duration
 			cfg.DNS.LocalIP = dns.LocalIP.IP()
 			cfg.DNS.RemoteIP = dns.RemoteIP.IP()
 		}
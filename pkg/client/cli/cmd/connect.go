@@ -10,6 +10,7 @@ import (
 
 func connectCmd() *cobra.Command {
 	var request *daemon.CobraRequest
+	var waitForReady bool
 
 	cmd := &cobra.Command{
 		Use:   "connect [flags] [-- <command to run while connected>]",
@@ -22,9 +23,12 @@ func connectCmd() *cobra.Command {
 			if err := request.CommitFlags(cmd); err != nil {
 				return err
 			}
-			return connect.RunConnect(cmd, args)
+			return connect.RunConnect(cmd, args, waitForReady)
 		},
 	}
 	request = daemon.InitRequest(cmd)
+	cmd.Flags().BoolVar(&waitForReady, "wait-for-ready", false, ``+
+		`Wait until the cluster subnets are routed and the DNS resolver answers before returning, or before running the `+
+		`command. The wait is limited by the timeouts.connectReady setting`)
 	return cmd
 }
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
+	"github.com/telepresenceio/telepresence/v2/pkg/proc"
+)
+
+func credentialPluginCmd() *cobra.Command {
+	var timeout time.Duration
+	cmd := &cobra.Command{
+		Use:    client.CredentialPluginCommand + " --timeout <duration> -- <plugin> [args...]",
+		Args:   cobra.MinimumNArgs(1),
+		Short:  "Run a kubeconfig exec credential plugin and kill it if it doesn't complete in time",
+		Hidden: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCredentialPlugin(cmd, timeout, args)
+		},
+	}
+	cmd.Flags().DurationVar(&timeout, "timeout", time.Minute, "The maximum time that the plugin is allowed to run")
+	return cmd
+}
+
+// runCredentialPlugin runs the given plugin with the environment, standard input, and standard output of this
+// process, so that it receives the KUBERNETES_EXEC_INFO set by client-go and returns its ExecCredential.
+func runCredentialPlugin(cmd *cobra.Command, timeout time.Duration, args []string) error {
+	ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+	defer cancel()
+	pc := proc.CommandContext(ctx, args[0], args[1:]...)
+	pc.Stdin = cmd.InOrStdin()
+	pc.Stdout = cmd.OutOrStdout()
+	pc.Stderr = cmd.ErrOrStderr()
+	pc.DisableLogging = true
+	// Don't wait for output from child processes of a killed plugin.
+	pc.WaitDelay = time.Second
+	if err := pc.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("credential plugin %q timed out after %s", args[0], timeout)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_runCredentialPlugin(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantOut string
+		wantErr string
+	}{
+		{"passes output", []string{"sh", "-c", "echo token"}, "token\n", ""},
+		{"passes exit status", []string{"sh", "-c", "exit 3"}, "", "exit status 3"},
+		{"kills hanging plugin", []string{"sh", "-c", "exec sleep 10"}, "", `credential plugin "sh" timed out after 200ms`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			cmd := &cobra.Command{}
+			cmd.SetContext(context.Background())
+			cmd.SetIn(&bytes.Buffer{})
+			cmd.SetOut(&out)
+			cmd.SetErr(&bytes.Buffer{})
+			start := time.Now()
+			err := runCredentialPlugin(cmd, 200*time.Millisecond, tt.args)
+			assert.Less(t, time.Since(start), 5*time.Second)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOut, out.String())
+		})
+	}
+}
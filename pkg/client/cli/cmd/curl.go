@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/spf13/cobra"
+	empty "google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/telepresenceio/telepresence/rpc/v2/connector"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/ann"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/connect"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/daemon"
+	"github.com/telepresenceio/telepresence/v2/pkg/dnsproxy"
+	"github.com/telepresenceio/telepresence/v2/pkg/dos"
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+	"github.com/telepresenceio/telepresence/v2/pkg/proc"
+)
+
+const curlDNSPollInterval = 200 * time.Millisecond
+
+func curlCmd() *cobra.Command {
+	var dnsTimeout time.Duration
+	cmd := &cobra.Command{
+		Use:   "curl [flags] [--] <curl arguments>",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "Run curl once the cluster DNS is available",
+		Long: `Run curl with the given arguments once the cluster DNS is available on this workstation.
+
+The command waits until the root daemon's DNS resolver is configured for the current connection and answers
+lookups made using the system's resolver, and then runs curl. It will not connect to the cluster. Use "--" to
+separate curl flags from the flags of this command, e.g.
+
+  telepresence curl -- --silent http://hello.default`,
+		Annotations: map[string]string{
+			ann.UserDaemon: ann.Optional,
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := connect.InitCommand(cmd); err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+			if err := waitForClusterDNS(ctx, daemon.GetUserClient(ctx), dnsTimeout, net.DefaultResolver.LookupHost); err != nil {
+				return err
+			}
+			return proc.Run(dos.WithStdio(ctx, cmd), nil, "curl", args...)
+		},
+	}
+	flags := cmd.Flags()
+	flags.SetInterspersed(false)
+	flags.DurationVar(&dnsTimeout, "dns-timeout", 10*time.Second, "How long to wait for the cluster DNS to become available")
+	return cmd
+}
+
+// waitForClusterDNS waits until the user daemon reports a connection with a root daemon that has its
+// DNS configured, and then until the DNS server answers lookups made using the given lookup function,
+// which normally is the system's resolver. The resolver isn't probed in routing-only mode, because the
+// cluster names are then resolved by other means.
+func waitForClusterDNS(
+	ctx context.Context,
+	userD daemon.UserClient,
+	timeout time.Duration,
+	lookupHost func(context.Context, string) ([]string, error),
+) error {
+	if userD == nil {
+		return errcat.User.New("Not connected. Use telepresence connect to connect to a cluster")
+	}
+	if userD.Containerized() {
+		return errcat.User.New("The daemon runs in a container, so the cluster DNS is not available on this workstation")
+	}
+	tc, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	configured := false
+	for {
+		if !configured {
+			st, err := userD.Status(tc, &empty.Empty{})
+			if err != nil {
+				if tc.Err() == nil {
+					return err
+				}
+			} else {
+				switch st.Error {
+				case connector.ConnectInfo_UNSPECIFIED, connector.ConnectInfo_ALREADY_CONNECTED:
+					if obc := st.DaemonStatus.GetOutboundConfig(); obc.GetDns() != nil {
+						if obc.RouteOnly {
+							return nil
+						}
+						configured = true
+					}
+				case connector.ConnectInfo_DISCONNECTED:
+					return errcat.User.New("Not connected. Use telepresence connect to connect to a cluster")
+				}
+			}
+		}
+		if configured {
+			if _, err := lookupHost(tc, dnsproxy.ProbeName); err == nil {
+				return nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-tc.Done():
+			return errcat.User.Newf(
+				"The cluster DNS did not become available within %s. Use telepresence status to check that the root daemon is running", timeout)
+		case <-time.After(curlDNSPollInterval):
+		}
+	}
+}
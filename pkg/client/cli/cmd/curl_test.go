@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/telepresenceio/telepresence/rpc/v2/connector"
+	rpc "github.com/telepresenceio/telepresence/rpc/v2/daemon"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/daemon"
+	"github.com/telepresenceio/telepresence/v2/pkg/dnsproxy"
+)
+
+// statusUserClient is a user daemon that reports the given status.
+type statusUserClient struct {
+	daemon.UserClient
+	status *connector.ConnectInfo
+}
+
+func (c *statusUserClient) Containerized() bool {
+	return false
+}
+
+func (c *statusUserClient) Status(context.Context, *emptypb.Empty, ...grpc.CallOption) (*connector.ConnectInfo, error) {
+	return c.status, nil
+}
+
+// probeResolver fails the given number of lookups of the DNS probe name before it succeeds.
+type probeResolver struct {
+	failures int32
+	lookups  atomic.Int32
+}
+
+func (r *probeResolver) lookupHost(_ context.Context, host string) ([]string, error) {
+	if host != dnsproxy.ProbeName {
+		return nil, errors.New("unexpected host " + host)
+	}
+	if r.lookups.Add(1) <= r.failures {
+		return nil, errors.New("no such host")
+	}
+	return []string{"127.0.0.1"}, nil
+}
+
+func Test_waitForClusterDNS(t *testing.T) {
+	connected := func(obc *rpc.OutboundInfo) *statusUserClient {
+		return &statusUserClient{status: &connector.ConnectInfo{
+			Error:        connector.ConnectInfo_ALREADY_CONNECTED,
+			DaemonStatus: &rpc.DaemonStatus{OutboundConfig: obc},
+		}}
+	}
+	ctx := context.Background()
+
+	t.Run("waits for the resolver", func(t *testing.T) {
+		r := &probeResolver{failures: 2}
+		err := waitForClusterDNS(ctx, connected(&rpc.OutboundInfo{Dns: &rpc.DNSConfig{}}), 5*time.Second, r.lookupHost)
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), r.lookups.Load())
+	})
+
+	t.Run("times out when the resolver never answers", func(t *testing.T) {
+		r := &probeResolver{failures: 1000}
+		err := waitForClusterDNS(ctx, connected(&rpc.OutboundInfo{Dns: &rpc.DNSConfig{}}), 500*time.Millisecond, r.lookupHost)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "did not become available within 500ms")
+		assert.Positive(t, r.lookups.Load())
+	})
+
+	t.Run("resolver is not probed before DNS is configured", func(t *testing.T) {
+		r := &probeResolver{}
+		err := waitForClusterDNS(ctx, connected(&rpc.OutboundInfo{}), 300*time.Millisecond, r.lookupHost)
+		require.Error(t, err)
+		assert.Zero(t, r.lookups.Load())
+	})
+
+	t.Run("resolver is not probed in routing-only mode", func(t *testing.T) {
+		r := &probeResolver{failures: 1000}
+		err := waitForClusterDNS(ctx, connected(&rpc.OutboundInfo{Dns: &rpc.DNSConfig{}, RouteOnly: true}), time.Second, r.lookupHost)
+		require.NoError(t, err)
+		assert.Zero(t, r.lookups.Load())
+	})
+
+	t.Run("disconnected", func(t *testing.T) {
+		userD := &statusUserClient{status: &connector.ConnectInfo{Error: connector.ConnectInfo_DISCONNECTED}}
+		err := waitForClusterDNS(ctx, userD, time.Second, (&probeResolver{}).lookupHost)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Not connected")
+	})
+
+	t.Run("no user daemon", func(t *testing.T) {
+		err := waitForClusterDNS(ctx, nil, time.Second, (&probeResolver{}).lookupHost)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Not connected")
+	})
+}
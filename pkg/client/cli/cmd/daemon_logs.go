@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/output"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/logging"
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+	"github.com/telepresenceio/telepresence/v2/pkg/filelocation"
+)
+
+const (
+	// logTimestampLen is the length of the timestamp that starts each log entry, e.g. "2024-10-21 12:34:56.1234".
+	logTimestampLen = len("2006-01-02 15:04:05.0000")
+
+	// logPollInterval is the interval between checks for new lines when following the logs.
+	logPollInterval = 250 * time.Millisecond
+)
+
+func daemonCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Inspect the local telepresence daemons",
+		Args:  OnlySubcommands,
+		RunE:  RunSubcommands,
+	}
+	cmd.AddCommand(daemonLogs())
+	return cmd
+}
+
+func daemonLogs() *cobra.Command {
+	var follow bool
+	var tail int
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Args:  cobra.NoArgs,
+		Short: "Show the logs of the root and user daemons",
+		Long: "Show the logs of the root and user daemons. The lines are interleaved in chronological order, " +
+			"and each line is prefixed with the daemon that produced it.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			logDir := filelocation.AppUserLogDir(ctx)
+			sources := []*logSource{
+				{name: "root", path: filepath.Join(logDir, "daemon.log")},
+				{name: "user", path: filepath.Join(logDir, "connector.log")},
+			}
+			return tailLogs(ctx, output.Out(ctx), sources, tail, follow)
+		},
+	}
+	flags := cmd.Flags()
+	flags.BoolVarP(&follow, "follow", "f", false, "Keep printing lines as they are added to the logs")
+	flags.IntVar(&tail, "tail", 10, "Number of lines to show from the end of the logs. Use -1 to show all lines")
+	return cmd
+}
+
+// logSource is a log file that is being tailed.
+type logSource struct {
+	name    string
+	path    string
+	info    os.FileInfo
+	offset  int64
+	partial []byte
+	lastTS  string
+}
+
+// logLine is a line from a logSource, together with the timestamp of the log entry that it belongs to.
+type logLine struct {
+	ts   string
+	text string
+}
+
+// tailLogs prints the last tail lines of the given sources, interleaved in chronological order, and then
+// continues to print new lines as they are added when follow is true.
+func tailLogs(ctx context.Context, out io.Writer, sources []*logSource, tail int, follow bool) error {
+	var lines []logLine
+	found := false
+	for _, src := range sources {
+		sl, err := src.read()
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+		found = true
+		lines = append(lines, sl...)
+	}
+	if !found && !follow {
+		return errcat.User.Newf("no daemon logs found in %s", filepath.Dir(sources[0].path))
+	}
+	// Lines from the same source are already in order, so a stable sort on the timestamp interleaves them.
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].ts < lines[j].ts })
+	if tail >= 0 && len(lines) > tail {
+		lines = lines[len(lines)-tail:]
+	}
+	for _, l := range lines {
+		fmt.Fprintln(out, l.text)
+	}
+	if !follow {
+		return nil
+	}
+
+	ticker := time.NewTicker(logPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		for _, src := range sources {
+			sl, err := src.read()
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			for _, l := range sl {
+				fmt.Fprintln(out, l.text)
+			}
+		}
+	}
+}
+
+// read returns the complete lines that have been added to the log file since the last read. Reading starts
+// over from the beginning of the file when the file has been rotated.
+func (s *logSource) read() ([]logLine, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if s.info != nil && (!os.SameFile(s.info, info) || info.Size() < s.offset) {
+		s.offset = 0
+		s.partial = nil
+	}
+	s.info = info
+	if info.Size() == s.offset {
+		return nil, nil
+	}
+	if _, err = f.Seek(s.offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	s.offset += int64(len(data))
+	return s.parse(data), nil
+}
+
+// parse splits the given data into lines. An incomplete last line is retained until the rest of it is read.
+// Lines that don't start with a timestamp, such as the lines of a stack trace, get the timestamp of the
+// preceding line.
+func (s *logSource) parse(data []byte) []logLine {
+	if len(s.partial) > 0 {
+		data = append(s.partial, data...)
+		s.partial = nil
+	}
+	var lines []logLine
+	for len(data) > 0 {
+		eol := bytes.IndexByte(data, '\n')
+		if eol < 0 {
+			s.partial = bytes.Clone(data)
+			break
+		}
+		line := string(bytes.TrimRight(data[:eol], "\r"))
+		data = data[eol+1:]
+		if ts, ok := logTimestamp(line); ok {
+			s.lastTS = ts
+		}
+		lines = append(lines, logLine{ts: s.lastTS, text: fmt.Sprintf("[%s] %s", s.name, line)})
+	}
+	return lines
+}
+
+// logTimestamp returns the timestamp of the given line in the format "2006-01-02 15:04:05.0000", or false if the
+// line doesn't start a log entry. Lines in JSON format are recognized by their "time" field.
+func logTimestamp(line string) (string, bool) {
+	const layout = "2006-01-02 15:04:05.0000"
+	if strings.HasPrefix(line, "{") {
+		var entry struct {
+			Time string `json:"time"`
+		}
+		if json.Unmarshal([]byte(line), &entry) != nil {
+			return "", false
+		}
+		t, err := time.Parse(logging.JSONTimestampFormat, entry.Time)
+		if err != nil {
+			return "", false
+		}
+		return t.Local().Format(layout), true
+	}
+	if len(line) < logTimestampLen {
+		return "", false
+	}
+	ts := line[:logTimestampLen]
+	if _, err := time.Parse(layout, ts); err != nil {
+		return "", false
+	}
+	return ts, true
+}
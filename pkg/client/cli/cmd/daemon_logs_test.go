@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/client/logging"
+)
+
+func Test_tailLogs(t *testing.T) {
+	dir := t.TempDir()
+	rootLog := filepath.Join(dir, "daemon.log")
+	userLog := filepath.Join(dir, "connector.log")
+	require.NoError(t, os.WriteFile(rootLog, []byte(
+		"2024-10-21 12:00:00.0000 info    root 1\n"+
+			"2024-10-21 12:00:02.0000 error   root 2\n"+
+			"goroutine 1 [running]:\n"+
+			"2024-10-21 12:00:04.0000 info    root 3\n"), 0o600))
+	require.NoError(t, os.WriteFile(userLog, []byte(
+		"2024-10-21 12:00:01.0000 info    user 1\n"+
+			"2024-10-21 12:00:03.0000 info    user 2\n"+
+			"2024-10-21 12:00:05.0000 info    partial"), 0o600))
+
+	sources := func() []*logSource {
+		return []*logSource{{name: "root", path: rootLog}, {name: "user", path: userLog}}
+	}
+
+	t.Run("all", func(t *testing.T) {
+		var out strings.Builder
+		require.NoError(t, tailLogs(context.Background(), &out, sources(), -1, false))
+		assert.Equal(t,
+			"[root] 2024-10-21 12:00:00.0000 info    root 1\n"+
+				"[user] 2024-10-21 12:00:01.0000 info    user 1\n"+
+				"[root] 2024-10-21 12:00:02.0000 error   root 2\n"+
+				"[root] goroutine 1 [running]:\n"+
+				"[user] 2024-10-21 12:00:03.0000 info    user 2\n"+
+				"[root] 2024-10-21 12:00:04.0000 info    root 3\n",
+			out.String())
+	})
+
+	t.Run("tail", func(t *testing.T) {
+		var out strings.Builder
+		require.NoError(t, tailLogs(context.Background(), &out, sources(), 2, false))
+		assert.Equal(t,
+			"[user] 2024-10-21 12:00:03.0000 info    user 2\n"+
+				"[root] 2024-10-21 12:00:04.0000 info    root 3\n",
+			out.String())
+	})
+
+	t.Run("no logs", func(t *testing.T) {
+		var out strings.Builder
+		err := tailLogs(context.Background(), &out, []*logSource{{name: "root", path: filepath.Join(dir, "missing.log")}}, 10, false)
+		assert.Error(t, err)
+	})
+}
+
+func Test_logSource_read(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "connector.log")
+	require.NoError(t, os.WriteFile(path, []byte("2024-10-21 12:00:00.0000 info    one\n2024-10-21 12:00:01.0000 info    tw"), 0o600))
+	src := &logSource{name: "user", path: path}
+	lines, err := src.read()
+	require.NoError(t, err)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "[user] 2024-10-21 12:00:00.0000 info    one", lines[0].text)
+
+	// Complete the partial line.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString("o\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	lines, err = src.read()
+	require.NoError(t, err)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "[user] 2024-10-21 12:00:01.0000 info    two", lines[0].text)
+
+	// Truncation, as done when the log is rotated, starts over from the beginning.
+	require.NoError(t, os.WriteFile(path, []byte("2024-10-21 12:00:02.0000 info    new\n"), 0o600))
+	lines, err = src.read()
+	require.NoError(t, err)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "[user] 2024-10-21 12:00:02.0000 info    new", lines[0].text)
+}
+
+func Test_logTimestamp(t *testing.T) {
+	ts, ok := logTimestamp("2024-10-21 12:00:00.1234 info    text")
+	assert.True(t, ok)
+	assert.Equal(t, "2024-10-21 12:00:00.1234", ts)
+
+	jt := time.Date(2024, 10, 21, 12, 0, 0, 123400000, time.Local)
+	ts, ok = logTimestamp(`{"level":"info","msg":"json","time":"` + jt.Format(logging.JSONTimestampFormat) + `"}`)
+	assert.True(t, ok)
+	assert.Equal(t, "2024-10-21 12:00:00.1234", ts)
+
+	_, ok = logTimestamp("goroutine 1 [running]:")
+	assert.False(t, ok)
+	_, ok = logTimestamp(`{"msg":"no time"}`)
+	assert.False(t, ok)
+}
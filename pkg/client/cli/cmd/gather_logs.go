@@ -3,6 +3,7 @@ package cmd
 import (
 	"archive/zip"
 	"bufio"
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -35,6 +36,8 @@ type gatherLogsCommand struct {
 	trafficManager bool
 	anon           bool
 	podYaml        bool
+	redact         bool
+	since          time.Duration
 }
 
 func gatherLogs() *cobra.Command {
@@ -64,6 +67,9 @@ telepresence gather-logs --traffic-manager=False --traffic-agents=echo-easy-6848
 
 # Get logs from everything except the daemons
 telepresence gather-logs --daemons=None
+
+# Get all logs, but only entries from the last 30 minutes
+telepresence gather-logs --since 30m
 `,
 
 		RunE: gl.gatherLogs,
@@ -78,6 +84,8 @@ telepresence gather-logs --daemons=None
 	flags.StringVar(&gl.trafficAgents, "traffic-agents", "all", "Traffic-agents to collect logs from: all, name substring, None")
 	flags.BoolVarP(&gl.anon, "anonymize", "a", false, "To anonymize pod names + namespaces from the logs")
 	flags.BoolVarP(&gl.podYaml, "get-pod-yaml", "y", false, "Get the yaml of any pods you are getting logs for")
+	flags.BoolVar(&gl.redact, "redact", true, "Replace bearer tokens, API keys, and similar secrets in the logs with REDACTED")
+	flags.DurationVar(&gl.since, "since", 0, "Only include log entries newer than this relative duration, e.g. 30m or 2h")
 	return cmd
 }
 
@@ -95,6 +103,9 @@ func (gl *gatherLogsCommand) gatherLogs(cmd *cobra.Command, _ []string) error {
 	if err := connect.InitCommand(cmd); err != nil {
 		return err
 	}
+	if gl.since < 0 {
+		return errcat.User.New("--since must be a positive duration")
+	}
 	ctx := cmd.Context()
 	ctx = dos.WithStdio(ctx, cmd)
 	ctx = scout.NewReporter(ctx, "cli")
@@ -154,6 +165,8 @@ func (gl *gatherLogsCommand) gatherLogs(cmd *cobra.Command, _ []string) error {
 	scout.SetMetadatum(ctx, "traffic_agent_logs", gl.trafficAgents)
 	scout.SetMetadatum(ctx, "get_pod_yaml", gl.podYaml)
 	scout.SetMetadatum(ctx, "anonymized_logs", gl.anon)
+	scout.SetMetadatum(ctx, "redacted_logs", gl.redact)
+	scout.SetMetadatum(ctx, "since", gl.since.String())
 	scout.Report(ctx, "used_gather_logs")
 
 	var az *anonymizer
@@ -177,6 +190,12 @@ func (gl *gatherLogsCommand) gatherLogs(cmd *cobra.Command, _ []string) error {
 		}
 	}
 
+	// The files that are present at this point originate from the cluster.
+	clusterFiles, err := getLogFiles(exportDir)
+	if err != nil {
+		return errcat.User.New(err)
+	}
+
 	err = retrieveLocalLogs(ctx, daemonLogs, exportDir)
 	if err != nil {
 		return errcat.User.New(err)
@@ -187,10 +206,13 @@ func (gl *gatherLogsCommand) gatherLogs(cmd *cobra.Command, _ []string) error {
 	if err != nil {
 		return errcat.User.New(err)
 	}
+	if gl.since > 0 {
+		trimLogs(ctx, files, clusterFiles, time.Now().Add(-gl.since))
+	}
 	if az != nil {
 		anonymizeLogs(ctx, files, az)
 	}
-	if err = zipFiles(files, gl.outputFile); err != nil {
+	if err = zipFiles(files, gl.outputFile, gl.redact); err != nil {
 		return err
 	}
 
@@ -259,6 +281,72 @@ func anonymizeLogs(ctx context.Context, files []string, az *anonymizer) {
 	}
 }
 
+// trimLogs removes all entries older than the given cutoff from the given files. Timestamps in the logs
+// that originate from the cluster are considered to be in UTC, whereas the timestamps in the local logs
+// are considered to be in the local timezone.
+func trimLogs(ctx context.Context, files, clusterFiles []string, cutoff time.Time) {
+	for _, fullFileName := range files {
+		loc := time.Local
+		for _, cf := range clusterFiles {
+			if cf == fullFileName {
+				loc = time.UTC
+				break
+			}
+		}
+		if err := trimLog(fullFileName, cutoff.In(loc), loc); err != nil {
+			ioutil.Printf(dos.Stderr(ctx), "error trimming %s: %s\n", fullFileName, err)
+		}
+	}
+}
+
+// trimLog overwrites the given file with a version that only contains the lines that have a leading
+// timestamp that isn't before the cutoff. Lines without a parseable timestamp, such as the continuation
+// lines of a stack trace, are kept or dropped together with the last line that had a timestamp.
+func trimLog(logFile string, cutoff time.Time, loc *time.Location) error {
+	content, err := os.ReadFile(logFile)
+	if err != nil {
+		return err
+	}
+	var sb strings.Builder
+	keep := true
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if ts, ok := parseLogTimestamp(line, loc); ok {
+			keep = !ts.Before(cutoff)
+		}
+		if keep {
+			sb.WriteString(line)
+			sb.WriteByte('\n')
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+	return os.WriteFile(logFile, []byte(sb.String()), 0o666)
+}
+
+const logTimestampLayout = "2006-01-02 15:04:05.0000"
+
+// parseLogTimestamp parses the timestamp that leads the given log line. Both the format used by
+// the Telepresence formatter and the one used by the logrus text formatter (time="...") are
+// recognized, and so are the RFC3339 timestamps that kubectl logs --timestamps produces.
+func parseLogTimestamp(line string, loc *time.Location) (time.Time, bool) {
+	line = strings.TrimPrefix(line, `time="`)
+	if len(line) >= len(logTimestampLayout) {
+		if ts, err := time.ParseInLocation(logTimestampLayout, line[:len(logTimestampLayout)], loc); err == nil {
+			return ts, true
+		}
+	}
+	if sp := strings.IndexByte(line, ' '); sp > 0 {
+		if ts, err := time.Parse(time.RFC3339Nano, line[:sp]); err == nil {
+			return ts, true
+		}
+	}
+	return time.Time{}, false
+}
+
 func (gl *gatherLogsCommand) gatherClusterLogs(ctx context.Context, exportDir string, az *anonymizer) error {
 	// To get logs from the components in the kubernetes cluster, we ask the
 	// traffic-manager.
@@ -316,10 +404,37 @@ func copyFiles(dstFile, srcFile string) error {
 	return nil
 }
 
+// secretPatterns are the patterns of secrets that are replaced with REDACTED when the logs are redacted. Each
+// pattern has at most one capture group, and the content of that group is retained.
+var secretPatterns = []*regexp.Regexp{ //nolint:gochecknoglobals // constant
+	// Authorization: Bearer <token>
+	regexp.MustCompile(`(?i)(\bbearer\s+)[a-z0-9\-._~+/]+=*`),
+	// apikey=<key>, api-key: <key>, AMBASSADOR_CLOUD_APIKEY=<key>, "apiKey":"<key>"
+	regexp.MustCompile(`(?i)(api[-_]?key["']?\s*[=:]\s*["']?)[^\s"'&,;]+`),
+	// token: <token>, "token":"<token>", client-key-data: <base64>
+	regexp.MustCompile(`(?i)(\b(?:token|client-key-data|client-certificate-data)["']?\s*[=:]\s*["']?)[a-z0-9\-._~+/]{8,}=*`),
+	// JSON Web Tokens, as used by service account tokens and many exec credential plugins.
+	regexp.MustCompile(`\beyJ[a-zA-Z0-9_-]+\.[a-zA-Z0-9_-]+\.[a-zA-Z0-9_-]*`),
+}
+
+// redactSecrets replaces all occurrences of secretPatterns in the given content with REDACTED.
+func redactSecrets(content []byte) []byte {
+	for _, rx := range secretPatterns {
+		if rx.NumSubexp() > 0 {
+			content = rx.ReplaceAll(content, []byte("${1}REDACTED"))
+		} else {
+			content = rx.ReplaceAll(content, []byte("REDACTED"))
+		}
+	}
+	return content
+}
+
 // zipFiles creates a zip file with the contents of all the files passed in.
 // If some files do not exist, it will include that in the error message,
-// but it will still create a zip file with as many files as it can.
-func zipFiles(files []string, zipFileName string) error {
+// but it will still create a zip file with as many files as it can. Secrets
+// found in the content are redacted in the zip when redact is true. The
+// original files are never modified.
+func zipFiles(files []string, zipFileName string, redact bool) error {
 	zipFile, err := os.Create(zipFileName)
 	if err != nil {
 		return err
@@ -359,6 +474,14 @@ func zipFiles(files []string, zipFileName string) error {
 		if err != nil {
 			return err
 		}
+		if redact {
+			content, err := io.ReadAll(fd)
+			if err != nil {
+				return err
+			}
+			_, err = zfd.Write(redactSecrets(content))
+			return err
+		}
 		if _, err := io.Copy(zfd, fd); err != nil {
 			return err
 		}
@@ -55,6 +55,7 @@ func helmInstall() *cobra.Command {
 	flags.BoolVarP(&ha.NoHooks, "no-hooks", "", false, "prevent hooks from running during install")
 	flags.BoolVarP(&upgrade, "upgrade", "u", false, "replace the traffic manager if it already exists")
 	flags.BoolVar(&ha.CreateNamespace, "create-namespace", true, "create a namespace for the traffic-manager if not present")
+	flags.BoolVar(&ha.DryRun, "dry-run", false, "print the manifests that would be installed without installing them")
 	ha.addValueSettingFlags(flags)
 	ha.addCRDsFlags(flags)
 	uf := flags.Lookup("upgrade")
@@ -85,6 +86,7 @@ func helmUpgrade() *cobra.Command {
 	flags.BoolVarP(&ha.ReuseValues, "reuse-values", "", false,
 		"when upgrading, reuse the last release's values and merge in any overrides from the command line via --set and -f")
 	flags.BoolVarP(&ha.CreateNamespace, "create-namespace", "", true, "create the release namespace if not present")
+	flags.BoolVar(&ha.DryRun, "dry-run", false, "print the manifests that would be applied without upgrading")
 	ha.rq = daemon.InitRequest(cmd)
 	return cmd
 }
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -12,18 +13,26 @@ import (
 	"github.com/datawire/dlib/dlog"
 	"github.com/telepresenceio/telepresence/rpc/v2/connector"
 	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/api"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/ann"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/connect"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/daemon"
-	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/intercept"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/output"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/docker"
 	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+	"github.com/telepresenceio/telepresence/v2/pkg/ioutil"
 )
 
 func leave() *cobra.Command {
-	return &cobra.Command{
-		Use:  "leave [flags] <intercept_name>",
-		Args: cobra.ExactArgs(1),
+	var all bool
+	cmd := &cobra.Command{
+		Use: "leave [flags] <intercept_name>",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if all {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
 
 		Short: "Remove existing intercept",
 		Annotations: map[string]string{
@@ -33,6 +42,9 @@ func leave() *cobra.Command {
 			if err := connect.InitCommand(cmd); err != nil {
 				return err
 			}
+			if all {
+				return removeAllIntercepts(cmd.Context())
+			}
 			return removeIntercept(cmd.Context(), strings.TrimSpace(args[0]))
 		},
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -65,6 +77,29 @@ func leave() *cobra.Command {
 			return completions, shellCompDir
 		},
 	}
+	cmd.Flags().BoolVar(&all, "all", false, "Remove all intercepts of the current session")
+	return cmd
+}
+
+// removeAllIntercepts removes all intercepts of the current session. It continues when the removal of an
+// intercept fails, and returns the joined errors of all failed removals.
+func removeAllIntercepts(ctx context.Context) error {
+	resp, err := daemon.GetUserClient(ctx).List(ctx, &connector.ListRequest{Filter: connector.ListRequest_INTERCEPTS})
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, wl := range resp.Workloads {
+		for _, ii := range wl.InterceptInfos {
+			name := ii.Spec.Name
+			if err := removeIntercept(ctx, name); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+				continue
+			}
+			ioutil.Printf(output.Out(ctx), "Left intercept %s\n", name)
+		}
+	}
+	return errors.Join(errs...)
 }
 
 func removeIntercept(ctx context.Context, name string) error {
@@ -86,7 +121,7 @@ func removeIntercept(ctx context.Context, name string) error {
 			dlog.Error(ctx, err)
 		}
 	}
-	if err := intercept.Result(userD.RemoveIntercept(ctx, &manager.RemoveInterceptRequest2{Name: name})); err != nil {
+	if err := api.InterceptResultError(userD.RemoveIntercept(ctx, &manager.RemoveInterceptRequest2{Name: name})); err != nil {
 		if stopContainer && strings.Contains(err.Error(), fmt.Sprintf("%q not found", name)) {
 			// race condition between stopping the intercept handler, which causes the intercept to leave, and this call
 			err = nil
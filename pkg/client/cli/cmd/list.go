@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
@@ -27,6 +29,7 @@ type listCommand struct {
 	onlyInterceptable bool
 	debug             bool
 	namespace         string
+	allNamespaces     bool
 	watch             bool
 }
 
@@ -53,6 +56,8 @@ func list() *cobra.Command {
 	flags.BoolVarP(&s.onlyInterceptable, "only-interceptable", "o", true, "interceptable workloads only")
 	flags.BoolVar(&s.debug, "debug", false, "include debugging information")
 	flags.StringVarP(&s.namespace, "namespace", "n", "", "If present, the namespace scope for this CLI request")
+	flags.BoolVarP(&s.allNamespaces, "all-namespaces", "A", false, "list workloads in all namespaces that the client can access")
+	cmd.MarkFlagsMutuallyExclusive("namespace", "all-namespaces")
 
 	flags.BoolVarP(&s.watch, "watch", "w", false, "watch a namespace. --agents and --intercepts are disabled if this flag is set")
 	wf := flags.Lookup("watch")
@@ -94,6 +99,9 @@ func (s *listCommand) list(cmd *cobra.Command, _ []string) error {
 	stdout := cmd.OutOrStdout()
 	ctx := cmd.Context()
 	userD := daemon.GetUserClient(ctx)
+	if s.allNamespaces {
+		s.namespace = client.AllNamespaces
+	}
 	var filter connector.ListRequest_Filter
 	switch {
 	case s.onlyIntercepts:
@@ -121,6 +129,7 @@ func (s *listCommand) list(cmd *cobra.Command, _ []string) error {
 			return err
 		}
 		s.printList(ctx, r.Workloads, stdout, formattedOutput)
+		printInaccessibleNamespaces(cmd.ErrOrStderr(), r.InaccessibleNamespaces)
 		return nil
 	}
 
@@ -144,6 +153,7 @@ func (s *listCommand) list(cmd *cobra.Command, _ []string) error {
 		}
 	}()
 
+	var inaccessible []string
 	for {
 		select {
 		case r, ok := <-ch:
@@ -154,12 +164,24 @@ func (s *listCommand) list(cmd *cobra.Command, _ []string) error {
 				return errcat.NoDaemonLogs.Newf("%v", r.err)
 			}
 			s.printList(ctx, r.workloadInfoSnapshot.Workloads, stdout, formattedOutput)
+			if nss := r.workloadInfoSnapshot.InaccessibleNamespaces; !slices.Equal(nss, inaccessible) {
+				printInaccessibleNamespaces(cmd.ErrOrStderr(), nss)
+				inaccessible = nss
+			}
 		case <-ctx.Done():
 			return nil
 		}
 	}
 }
 
+// printInaccessibleNamespaces notes the namespaces that were left out of a listing of all namespaces because the
+// client isn't permitted to watch their workloads and services.
+func printInaccessibleNamespaces(w io.Writer, nss []string) {
+	if len(nss) > 0 {
+		fmt.Fprintf(w, "Namespaces not listed due to insufficient permissions: %s\n", strings.Join(nss, ", "))
+	}
+}
+
 func (s *listCommand) printList(ctx context.Context, workloads []*connector.WorkloadInfo, stdout io.Writer, formattedOut bool) {
 	if len(workloads) == 0 {
 		if formattedOut {
@@ -195,7 +217,6 @@ func (s *listCommand) printList(ctx context.Context, workloads []*connector.Work
 				_ = json.Unmarshal(v.Sidecar.Json, &sidecar)
 				l.Sidecar = &sidecar
 			}
-
 			o[i] = &l
 		}
 
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/telepresenceio/telepresence/rpc/v2/connector"
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
+)
+
+func Test_printListAllNamespaces(t *testing.T) {
+	s := &listCommand{namespace: client.AllNamespaces}
+	workloads := []*connector.WorkloadInfo{
+		{Name: "echo", Namespace: "alpha", WorkloadResourceType: "Deployment"},
+		{Name: "echo-long", Namespace: "alpha", WorkloadResourceType: "Deployment"},
+		{Name: "echo", Namespace: "beta", WorkloadResourceType: "Deployment"},
+	}
+	out := &bytes.Buffer{}
+	s.printList(context.Background(), workloads, out, false)
+	assert.Equal(t, ""+
+		"echo.alpha     : ready to intercept (traffic-agent not yet installed)\n"+
+		"echo-long.alpha: ready to intercept (traffic-agent not yet installed)\n"+
+		"echo.beta      : ready to intercept (traffic-agent not yet installed)\n",
+		out.String())
+
+	out.Reset()
+	printInaccessibleNamespaces(out, nil)
+	assert.Empty(t, out.String())
+	printInaccessibleNamespaces(out, []string{"gamma", "delta"})
+	assert.Equal(t, "Namespaces not listed due to insufficient permissions: gamma, delta\n", out.String())
+}
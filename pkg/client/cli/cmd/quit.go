@@ -9,14 +9,18 @@ import (
 
 func quit() *cobra.Command {
 	quitDaemons := false
+	force := false
 	cmd := &cobra.Command{
 		Use:   "quit",
 		Args:  cobra.NoArgs,
 		Short: "Tell telepresence daemons to quit",
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			if quitDaemons {
+			switch {
+			case force:
+				connect.ForceQuit(cmd.Context())
+			case quitDaemons:
 				connect.Quit(cmd.Context())
-			} else {
+			default:
 				cmd.Annotations = map[string]string{ann.UserDaemon: ann.Optional}
 				if err := connect.InitCommand(cmd); err != nil {
 					return err
@@ -28,5 +32,7 @@ func quit() *cobra.Command {
 	}
 	flags := cmd.Flags()
 	flags.BoolVarP(&quitDaemons, "stop-daemons", "s", false, "stop all local telepresence daemons")
+	flags.BoolVar(&force, "force", false,
+		"stop all local telepresence daemons, and kill the ones that don't quit gracefully within a short timeout. Implies --stop-daemons")
 	return cmd
 }
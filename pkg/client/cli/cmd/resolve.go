@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/telepresenceio/telepresence/rpc/v2/daemon"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/ann"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/connect"
+	cliDaemon "github.com/telepresenceio/telepresence/v2/pkg/client/cli/daemon"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/output"
+	"github.com/telepresenceio/telepresence/v2/pkg/ioutil"
+)
+
+// ResolveInfo is the result of a telepresence resolve.
+type ResolveInfo struct {
+	Name    string   `json:"name"            yaml:"name"`
+	Cluster bool     `json:"cluster"         yaml:"cluster"`
+	Rule    string   `json:"rule,omitempty"  yaml:"rule,omitempty"`
+	Match   string   `json:"match,omitempty" yaml:"match,omitempty"`
+	IPs     []string `json:"ips,omitempty"   yaml:"ips,omitempty"`
+}
+
+func resolveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:  "resolve <host>",
+		Args: cobra.ExactArgs(1),
+
+		Short: "Resolve a host name using the Telepresence DNS resolver",
+		Long: `Resolve a host name using the root daemon's DNS resolver and show the rule that decides if the name
+is resolved in the cluster. Names that aren't resolved by Telepresence fall through to the system's resolver.`,
+		Annotations: map[string]string{
+			ann.Session: ann.Required,
+		},
+		PersistentPreRunE: fixFlag,
+		RunE:              resolve,
+	}
+	cmd.Flags().BoolP(jsonFlag, "j", false, "output as json object")
+	return cmd
+}
+
+func resolve(cmd *cobra.Command, args []string) error {
+	if err := connect.InitCommand(cmd); err != nil {
+		return err
+	}
+	ctx := cmd.Context()
+	rsp, err := cliDaemon.GetUserClient(ctx).ResolveDNS(ctx, &daemon.ResolveDNSRequest{Name: args[0]})
+	if err != nil {
+		return err
+	}
+	ri := &ResolveInfo{
+		Name:    rsp.Name,
+		Cluster: rsp.Cluster,
+		Rule:    rsp.Rule,
+		Match:   rsp.Match,
+	}
+	for _, ip := range rsp.Ips {
+		ri.IPs = append(ri.IPs, net.IP(ip).String())
+	}
+	if output.WantsFormatted(cmd) {
+		output.Object(ctx, ri, true)
+	} else {
+		_, _ = ri.WriteTo(cmd.OutOrStdout())
+	}
+	return nil
+}
+
+func (ri *ResolveInfo) WriteTo(out io.Writer) (int64, error) {
+	if !ri.Cluster {
+		if ri.Rule == "" {
+			return int64(ioutil.Printf(out,
+				"%s is not resolvable by Telepresence because no rule matched. It would fall through to the system resolver\n", ri.Name)), nil
+		}
+		return int64(ioutil.Printf(out,
+			"%s is not resolvable by Telepresence because of %s %q. It would fall through to the system resolver\n", ri.Name, ri.Rule, ri.Match)), nil
+	}
+	kvf := ioutil.DefaultKeyValueFormatter()
+	kvf.Add("Name", ri.Name)
+	kvf.Add("Rule", fmt.Sprintf("%s %q", ri.Rule, ri.Match))
+	if len(ri.IPs) == 0 {
+		kvf.Add("Addresses", "none found in the cluster")
+	} else {
+		kvf.Add("Addresses", strings.Join(ri.IPs, ", "))
+	}
+	return int64(kvf.Println(out)), nil
+}
@@ -91,6 +91,7 @@ type ConnectStatusIntercept struct {
 const (
 	multiDaemonFlag = "multi-daemon"
 	jsonFlag        = "json"
+	watchFlag       = "watch"
 )
 
 func statusCmd() *cobra.Command {
@@ -109,6 +110,8 @@ func statusCmd() *cobra.Command {
 	flags.Bool(multiDaemonFlag, false, "always use multi-daemon output format, even if there's only one daemon connected")
 	flags.BoolP(jsonFlag, "j", false, "output as json object")
 	flags.Lookup(jsonFlag).Hidden = true
+	flags.BoolP(watchFlag, "w", false,
+		"stream changes to the connection and its intercepts as JSON objects, one per event. Same as --output json-stream")
 	return cmd
 }
 
@@ -118,8 +121,17 @@ func fixFlag(cmd *cobra.Command, _ []string) error {
 	if err != nil {
 		return err
 	}
+	watch, err := flags.GetBool(watchFlag)
+	if err != nil {
+		return err
+	}
 	rootCmd := cmd.Parent()
-	if json {
+	switch {
+	case watch:
+		if err = rootCmd.PersistentFlags().Set(global.FlagOutput, "json-stream"); err != nil {
+			return err
+		}
+	case json:
 		if err = rootCmd.PersistentFlags().Set(global.FlagOutput, "json"); err != nil {
 			return err
 		}
@@ -137,6 +149,12 @@ func run(cmd *cobra.Command, _ []string) error {
 		}
 	}
 	ctx := cmd.Context()
+	if output.WantsStream(cmd) {
+		if len(mdErr) > 0 {
+			return mdErr
+		}
+		return watchStatus(cmd)
+	}
 
 	var sis []ioutil.WriterTos
 	if len(mdErr) > 0 {
@@ -282,37 +300,7 @@ func getStatusInfo(ctx context.Context, di *daemon.Info) (*StatusInfo, error) {
 	if err != nil {
 		return nil, err
 	}
-	switch status.Error {
-	case connector.ConnectInfo_UNSPECIFIED, connector.ConnectInfo_ALREADY_CONNECTED:
-		us.Status = "Connected"
-		us.KubernetesServer = status.ClusterServer
-		us.KubernetesContext = status.ClusterContext
-		for _, icept := range status.GetIntercepts().GetIntercepts() {
-			us.Intercepts = append(us.Intercepts, ConnectStatusIntercept{
-				Name:   icept.Spec.Name,
-				Client: icept.Spec.Client,
-			})
-		}
-		us.Namespace = status.Namespace
-		us.ManagerNamespace = status.ManagerNamespace
-		us.MappedNamespaces = status.MappedNamespaces
-	case connector.ConnectInfo_UNAUTHORIZED:
-		us.Status = "Not authorized to connect"
-		us.Error = status.ErrorText
-	case connector.ConnectInfo_UNAUTHENTICATED:
-		us.Status = "Not logged in"
-		us.Error = status.ErrorText
-	case connector.ConnectInfo_MUST_RESTART:
-		us.Status = "Connected, but must restart"
-	case connector.ConnectInfo_DISCONNECTED:
-		us.Status = "Not connected"
-	case connector.ConnectInfo_CLUSTER_FAILED:
-		us.Status = "Not connected, error talking to cluster"
-		us.Error = status.ErrorText
-	case connector.ConnectInfo_TRAFFIC_MANAGER_FAILED:
-		us.Status = "Not connected, error talking to in-cluster Telepresence traffic-manager"
-		us.Error = status.ErrorText
-	}
+	us.setConnectInfo(status)
 
 	rStatus := status.DaemonStatus
 	if rStatus != nil {
@@ -325,20 +313,30 @@ func getStatusInfo(ctx context.Context, di *daemon.Info) (*StatusInfo, error) {
 		rs.Version = rStatus.Version.Version
 		rs.APIVersion = rStatus.Version.ApiVersion
 		if obc := rStatus.OutboundConfig; obc != nil {
-			rs.DNS = &client.DNSSnake{}
-			dns := obc.Dns
-			if dns.LocalIp != nil {
-				// Local IP is only set when the overriding resolver is used
-				rs.DNS.LocalIP = dns.LocalIp
+			if !obc.RouteOnly {
+				// The DNS server isn't used in routing-only mode.
+				rs.DNS = &client.DNSSnake{}
+				dns := obc.Dns
+				if dns.LocalIp != nil {
+					// Local IP is only set when the overriding resolver is used
+					rs.DNS.LocalIP = dns.LocalIp
+				}
+				rs.DNS.Error = dns.Error
+				rs.DNS.RemoteIP = dns.RemoteIp
+				rs.DNS.ExcludeSuffixes = dns.ExcludeSuffixes
+				rs.DNS.IncludeSuffixes = dns.IncludeSuffixes
+				rs.DNS.Excludes = dns.Excludes
+				rs.DNS.Mappings.FromRPC(dns.Mappings)
+				rs.DNS.LookupTimeout = dns.LookupTimeout.AsDuration()
+				rs.DNS.CacheTTL = dns.CacheTtl.AsDuration()
+				rs.DNS.NegativeTTL = dns.NegativeTtl.AsDuration()
+				rs.DNS.ListenAddress = dns.ListenAddress
+			}
+			rs.RoutingSnake = &client.RoutingSnake{
+				Protocols: rStatus.RoutedProtocols,
+				DNSOnly:   obc.DnsOnly,
+				RouteOnly: obc.RouteOnly,
 			}
-			rs.DNS.Error = dns.Error
-			rs.DNS.RemoteIP = dns.RemoteIp
-			rs.DNS.ExcludeSuffixes = dns.ExcludeSuffixes
-			rs.DNS.IncludeSuffixes = dns.IncludeSuffixes
-			rs.DNS.Excludes = dns.Excludes
-			rs.DNS.Mappings.FromRPC(dns.Mappings)
-			rs.DNS.LookupTimeout = dns.LookupTimeout.AsDuration()
-			rs.RoutingSnake = &client.RoutingSnake{}
 			for _, subnet := range rStatus.Subnets {
 				rs.RoutingSnake.Subnets = append(rs.RoutingSnake.Subnets, (*iputil.Subnet)(iputil.IPNetFromRPC(subnet)))
 			}
@@ -367,6 +365,45 @@ func getStatusInfo(ctx context.Context, di *daemon.Info) (*StatusInfo, error) {
 	return wt, nil
 }
 
+// setConnectInfo sets the connection status and the properties of the connection from the given ConnectInfo.
+func (us *UserDaemonStatus) setConnectInfo(status *connector.ConnectInfo) {
+	switch status.Error {
+	case connector.ConnectInfo_UNSPECIFIED, connector.ConnectInfo_ALREADY_CONNECTED:
+		if status.Reconnecting {
+			us.Status = "Connected, reconnecting to the traffic-manager"
+		} else {
+			us.Status = "Connected"
+		}
+		us.KubernetesServer = status.ClusterServer
+		us.KubernetesContext = status.ClusterContext
+		for _, icept := range status.GetIntercepts().GetIntercepts() {
+			us.Intercepts = append(us.Intercepts, ConnectStatusIntercept{
+				Name:   icept.Spec.Name,
+				Client: icept.Spec.Client,
+			})
+		}
+		us.Namespace = status.Namespace
+		us.ManagerNamespace = status.ManagerNamespace
+		us.MappedNamespaces = status.MappedNamespaces
+	case connector.ConnectInfo_UNAUTHORIZED:
+		us.Status = "Not authorized to connect"
+		us.Error = status.ErrorText
+	case connector.ConnectInfo_UNAUTHENTICATED:
+		us.Status = "Not logged in"
+		us.Error = status.ErrorText
+	case connector.ConnectInfo_MUST_RESTART:
+		us.Status = "Connected, but must restart"
+	case connector.ConnectInfo_DISCONNECTED:
+		us.Status = "Not connected"
+	case connector.ConnectInfo_CLUSTER_FAILED:
+		us.Status = "Not connected, error talking to cluster"
+		us.Error = status.ErrorText
+	case connector.ConnectInfo_TRAFFIC_MANAGER_FAILED:
+		us.Status = "Not connected, error talking to in-cluster Telepresence traffic-manager"
+		us.Error = status.ErrorText
+	}
+}
+
 func (s *SingleConnectStatusInfo) WriterTos() []io.WriterTo {
 	var wts []io.WriterTo
 	if s.extendedInfo != nil {
@@ -514,9 +551,30 @@ func printDNS(kvf *ioutil.KeyValueFormatter, d *client.DNSSnake) {
 		dnsKvf.Add("Mappings", "\n"+mappingsKvf.String())
 	}
 	dnsKvf.Add("Timeout", fmt.Sprintf("%v", d.LookupTimeout))
+	if d.CacheTTL > 0 {
+		dnsKvf.Add("Cache TTL", fmt.Sprintf("%v", d.CacheTTL))
+	}
+	if d.NegativeTTL > 0 {
+		dnsKvf.Add("Negative TTL", fmt.Sprintf("%v", d.NegativeTTL))
+	}
+	if d.ListenAddress != "" {
+		dnsKvf.Add("Listen address", d.ListenAddress)
+	}
 	kvf.Add("DNS", "\n"+dnsKvf.String())
 }
 
+// proxyMode returns a description of the subsystems that the daemon uses to proxy traffic to the cluster.
+func proxyMode(r *client.RoutingSnake) string {
+	switch {
+	case r.DNSOnly:
+		return "DNS-only"
+	case r.RouteOnly:
+		return "routing-only (system DNS untouched)"
+	default:
+		return "DNS and routing"
+	}
+}
+
 func printRouting(kvf *ioutil.KeyValueFormatter, r *client.RoutingSnake) {
 	printSubnets := func(title string, subnets []*iputil.Subnet) {
 		if len(subnets) == 0 {
@@ -529,10 +587,14 @@ func printRouting(kvf *ioutil.KeyValueFormatter, r *client.RoutingSnake) {
 		}
 		kvf.Add(title, out.String())
 	}
+	kvf.Add("Proxy", proxyMode(r))
 	printSubnets("Subnets", r.Subnets)
 	printSubnets("Also Proxy", r.AlsoProxy)
 	printSubnets("Never Proxy", r.NeverProxy)
 	printSubnets("Allow conflicts for", r.AllowConflicting)
+	if len(r.Protocols) > 0 {
+		kvf.Add("Routed protocols", strings.Join(r.Protocols, ", "))
+	}
 }
 
 func (cs *UserDaemonStatus) WriteTo(out io.Writer) (int64, error) {
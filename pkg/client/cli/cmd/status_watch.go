@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"errors"
+	"io"
+	"os/signal"
+	"reflect"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	empty "google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/telepresenceio/telepresence/rpc/v2/connector"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/daemon"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/output"
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+	"github.com/telepresenceio/telepresence/v2/pkg/proc"
+)
+
+const (
+	statusEventStatus           = "status"
+	statusEventConnected        = "connected"
+	statusEventDisconnected     = "disconnected"
+	statusEventInterceptAdded   = "intercept_added"
+	statusEventInterceptRemoved = "intercept_removed"
+)
+
+// statusEvent is printed by "status --watch" for each change in the status of the user daemon. The Status
+// is the status after the change.
+type statusEvent struct {
+	Event     string                  `json:"event"`
+	Intercept *ConnectStatusIntercept `json:"intercept,omitempty"`
+	Status    *UserDaemonStatus       `json:"status"`
+}
+
+// watchStatus streams status changes from the user daemon and prints them as JSON objects, one per event,
+// until the daemon ends the stream or the command is interrupted.
+func watchStatus(cmd *cobra.Command) error {
+	ctx, cancel := signal.NotifyContext(cmd.Context(), proc.SignalsToForward...)
+	defer cancel()
+	userD := daemon.GetUserClient(ctx)
+	if userD == nil {
+		return errcat.User.New("the user daemon is not running")
+	}
+	stream, err := userD.WatchStatus(ctx, &empty.Empty{})
+	if err != nil {
+		return err
+	}
+	var prev *connector.ConnectInfo
+	for {
+		ci, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, io.EOF) {
+				return nil
+			}
+			if status.Code(err) == codes.Unimplemented {
+				return errcat.User.New("the user daemon doesn't support status --watch. Please run \"telepresence quit -s\" and reconnect")
+			}
+			return errcat.NoDaemonLogs.Newf("%v", err)
+		}
+		for _, ev := range statusEvents(prev, ci) {
+			output.Object(ctx, ev, true)
+		}
+		prev = ci
+	}
+}
+
+func isConnected(ci *connector.ConnectInfo) bool {
+	return ci.Error == connector.ConnectInfo_UNSPECIFIED || ci.Error == connector.ConnectInfo_ALREADY_CONNECTED
+}
+
+// statusEvents returns the events that describe the change from prev to next. A "status" event is
+// returned when prev is nil, or when the change isn't a connect, disconnect, or intercept change. No
+// events are returned when nothing that is included in the status has changed.
+func statusEvents(prev, next *connector.ConnectInfo) []*statusEvent {
+	us := &UserDaemonStatus{}
+	us.setConnectInfo(next)
+	if prev == nil {
+		return []*statusEvent{{Event: statusEventStatus, Status: us}}
+	}
+
+	intercepts := func(ci *connector.ConnectInfo) map[string]*ConnectStatusIntercept {
+		m := make(map[string]*ConnectStatusIntercept)
+		for _, ii := range ci.GetIntercepts().GetIntercepts() {
+			m[ii.Spec.Name] = &ConnectStatusIntercept{Name: ii.Spec.Name, Client: ii.Spec.Client}
+		}
+		return m
+	}
+	diff := func(event string, a, b map[string]*ConnectStatusIntercept) []*statusEvent {
+		var evs []*statusEvent
+		for name, ic := range a {
+			if _, ok := b[name]; !ok {
+				evs = append(evs, &statusEvent{Event: event, Intercept: ic, Status: us})
+			}
+		}
+		sort.Slice(evs, func(i, j int) bool { return evs[i].Intercept.Name < evs[j].Intercept.Name })
+		return evs
+	}
+	prevIcepts, nextIcepts := intercepts(prev), intercepts(next)
+
+	// Intercepts are removed before a disconnect and added after a connect.
+	evs := diff(statusEventInterceptRemoved, prevIcepts, nextIcepts)
+	switch wasConnected, connected := isConnected(prev), isConnected(next); {
+	case connected && !wasConnected:
+		evs = append(evs, &statusEvent{Event: statusEventConnected, Status: us})
+	case wasConnected && !connected:
+		evs = append(evs, &statusEvent{Event: statusEventDisconnected, Status: us})
+	}
+	evs = append(evs, diff(statusEventInterceptAdded, nextIcepts, prevIcepts)...)
+	if len(evs) == 0 {
+		prevUs := &UserDaemonStatus{}
+		prevUs.setConnectInfo(prev)
+		if !reflect.DeepEqual(prevUs, us) {
+			evs = []*statusEvent{{Event: statusEventStatus, Status: us}}
+		}
+	}
+	return evs
+}
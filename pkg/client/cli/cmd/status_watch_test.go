@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/telepresenceio/telepresence/rpc/v2/connector"
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+)
+
+func Test_statusEvents(t *testing.T) {
+	connected := func(intercepts ...string) *connector.ConnectInfo {
+		iis := make([]*manager.InterceptInfo, len(intercepts))
+		for i, name := range intercepts {
+			iis[i] = &manager.InterceptInfo{Spec: &manager.InterceptSpec{Name: name, Client: "user@host"}}
+		}
+		return &connector.ConnectInfo{
+			Error:          connector.ConnectInfo_ALREADY_CONNECTED,
+			ClusterContext: "ctx",
+			Namespace:      "default",
+			Intercepts:     &manager.InterceptInfoSnapshot{Intercepts: iis},
+		}
+	}
+	disconnected := &connector.ConnectInfo{Error: connector.ConnectInfo_DISCONNECTED}
+	events := func(evs []*statusEvent) []string {
+		names := make([]string, len(evs))
+		for i, ev := range evs {
+			names[i] = ev.Event
+			if ev.Intercept != nil {
+				names[i] += " " + ev.Intercept.Name
+			}
+		}
+		return names
+	}
+
+	evs := statusEvents(nil, connected("a"))
+	require.Len(t, evs, 1)
+	assert.Equal(t, statusEventStatus, evs[0].Event)
+	assert.Equal(t, "Connected", evs[0].Status.Status)
+	assert.Equal(t, []ConnectStatusIntercept{{Name: "a", Client: "user@host"}}, evs[0].Status.Intercepts)
+
+	assert.Equal(t, []string{"connected", "intercept_added a", "intercept_added b"},
+		events(statusEvents(disconnected, connected("b", "a"))))
+	assert.Equal(t, []string{"intercept_removed a", "intercept_added c"},
+		events(statusEvents(connected("a", "b"), connected("b", "c"))))
+	assert.Equal(t, []string{"intercept_removed a", "disconnected"},
+		events(statusEvents(connected("a"), disconnected)))
+
+	changedNs := connected("a")
+	changedNs.Namespace = "other"
+	assert.Equal(t, []string{"status"}, events(statusEvents(connected("a"), changedNs)))
+	assert.Empty(t, statusEvents(connected("a"), connected("a")))
+
+	reconnecting := connected("a")
+	reconnecting.Reconnecting = true
+	evs = statusEvents(connected("a"), reconnecting)
+	require.Len(t, evs, 1)
+	assert.Equal(t, statusEventStatus, evs[0].Event)
+	assert.Equal(t, "Connected, reconnecting to the traffic-manager", evs[0].Status.Status)
+	assert.Equal(t, []string{"status"}, events(statusEvents(reconnecting, connected("a"))))
+}
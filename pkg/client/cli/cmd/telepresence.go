@@ -83,6 +83,7 @@ func AddSubCommands(cmd *cobra.Command) {
 	}
 	cmd.AddCommand(commands...)
 	cmd.PersistentFlags().AddFlagSet(global.Flags(false))
+	cmd.SetGlobalNormalizationFunc(global.NormalizeFlagName)
 	addCompletion(cmd)
 	cmd.InitDefaultHelpCmd()
 	addUsageTemplate(cmd)
@@ -134,8 +135,8 @@ func OnlySubcommands(cmd *cobra.Command, args []string) error {
 
 func WithSubCommands(ctx context.Context) context.Context {
 	return MergeSubCommands(ctx,
-		configCmd(), connectCmd(), currentClusterId(), gatherLogs(), gatherTraces(), genYAML(), helmCmd(),
-		interceptCmd(), kubeauthCmd(), leave(), list(), listContexts(), listNamespaces(), loglevel(), quit(), statusCmd(),
+		checkCmd(), configCmd(), connectCmd(), credentialPluginCmd(), curlCmd(), currentClusterId(), daemonCmd(), gatherLogs(), gatherTraces(), genYAML(), helmCmd(),
+		interceptCmd(), kubeauthCmd(), leave(), list(), listContexts(), listNamespaces(), loglevel(), quit(), resolveCmd(), statusCmd(),
 		testVPN(), uninstall(), uploadTraces(), version(), listNamespaces(), listContexts(),
 	)
 }
@@ -17,25 +17,48 @@ import (
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/ann"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/connect"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/daemon"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/output"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/socket"
 	"github.com/telepresenceio/telepresence/v2/pkg/ioutil"
 )
 
+// VersionInfo is the result of a telepresence version. Versions of daemons and the traffic-manager
+// are omitted when they aren't available.
+type VersionInfo struct {
+	Client             string `json:"client" yaml:"client"`
+	*DaemonVersionInfo `yaml:",inline"`
+
+	// Connections contains the versions of each connection, keyed by connection name, when more
+	// than one connection is active.
+	Connections map[string]*DaemonVersionInfo `json:"connections,omitempty" yaml:"connections,omitempty"`
+}
+
+// DaemonVersionInfo contains the versions reported by the daemons of one connection.
+type DaemonVersionInfo struct {
+	RootDaemon     string `json:"root_daemon,omitempty" yaml:"root_daemon,omitempty"`
+	UserDaemon     string `json:"user_daemon,omitempty" yaml:"user_daemon,omitempty"`
+	TrafficManager string `json:"traffic_manager,omitempty" yaml:"traffic_manager,omitempty"`
+	TrafficAgent   string `json:"traffic_agent,omitempty" yaml:"traffic_agent,omitempty"`
+}
+
 func version() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:  "version",
 		Args: cobra.NoArgs,
 
-		Short: "Show version",
-		RunE:  printVersion,
+		Short:             "Show version",
+		RunE:              printVersion,
+		PersistentPreRunE: fixFlag,
 		Annotations: map[string]string{
 			ann.UserDaemon:        ann.Optional,
 			ann.UpdateCheckFormat: ann.Tel2,
 		},
 	}
+	cmd.Flags().BoolP(jsonFlag, "j", false, "output as json object")
+	return cmd
 }
 
-func addDaemonVersions(ctx context.Context, kvf *ioutil.KeyValueFormatter) {
+func addDaemonVersions(ctx context.Context, kvf *ioutil.KeyValueFormatter, dvi *DaemonVersionInfo) {
 	remote := false
 	userD := daemon.GetUserClient(ctx)
 	if userD != nil {
@@ -47,6 +70,7 @@ func addDaemonVersions(ctx context.Context, kvf *ioutil.KeyValueFormatter) {
 		switch {
 		case err == nil:
 			kvf.Add(version.Name, version.Version)
+			dvi.RootDaemon = version.Version
 		case err == connect.ErrNoRootDaemon:
 			kvf.Add("Root Daemon", "not running")
 		default:
@@ -56,14 +80,17 @@ func addDaemonVersions(ctx context.Context, kvf *ioutil.KeyValueFormatter) {
 
 	if userD != nil {
 		kvf.Add(userD.Name(), "v"+userD.Semver().String())
+		dvi.UserDaemon = "v" + userD.Semver().String()
 		vi, err := managerVersion(ctx)
 		switch {
 		case err == nil:
 			kvf.Add(vi.Name, vi.Version)
+			dvi.TrafficManager = vi.Version
 			af, err := trafficAgentFQN(ctx)
 			switch status.Code(err) {
 			case codes.OK:
 				kvf.Add("Traffic Agent", af.FQN)
+				dvi.TrafficAgent = af.FQN
 			case codes.Unimplemented:
 				kvf.Add("Traffic Agent", "not reported by traffic-manager")
 			case codes.Unavailable:
@@ -84,6 +111,7 @@ func addDaemonVersions(ctx context.Context, kvf *ioutil.KeyValueFormatter) {
 func printVersion(cmd *cobra.Command, _ []string) error {
 	kvf := ioutil.DefaultKeyValueFormatter()
 	kvf.Add(client.DisplayName, client.Version())
+	vi := &VersionInfo{Client: client.Version()}
 
 	var mdErr daemon.MultipleDaemonsError
 	err := connect.InitCommand(cmd)
@@ -95,6 +123,7 @@ func printVersion(cmd *cobra.Command, _ []string) error {
 	ctx := cmd.Context()
 
 	if len(mdErr) > 0 {
+		vi.Connections = make(map[string]*DaemonVersionInfo, len(mdErr))
 		for _, info := range mdErr {
 			subKvf := &ioutil.KeyValueFormatter{
 				Indent:    kvf.Indent,
@@ -104,16 +133,23 @@ func printVersion(cmd *cobra.Command, _ []string) error {
 			if err != nil {
 				subKvf.Add("User Daemon", fmt.Sprintf("error: %v", err))
 			}
-			addDaemonVersions(udCtx, subKvf)
+			dvi := &DaemonVersionInfo{}
+			addDaemonVersions(udCtx, subKvf, dvi)
 			ud := daemon.GetUserClient(udCtx)
 			kvf.Add("Connection "+ud.DaemonID().Name, "\n"+subKvf.String())
+			vi.Connections[ud.DaemonID().Name] = dvi
 			_ = ud.Close()
 		}
 	} else {
-		addDaemonVersions(ctx, kvf)
+		vi.DaemonVersionInfo = &DaemonVersionInfo{}
+		addDaemonVersions(ctx, kvf, vi.DaemonVersionInfo)
 	}
 
-	kvf.Println(cmd.OutOrStdout())
+	if output.WantsFormatted(cmd) {
+		output.Object(ctx, vi, true)
+	} else {
+		kvf.Println(cmd.OutOrStdout())
+	}
 	return nil
 }
 
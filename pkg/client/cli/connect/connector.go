@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -26,6 +27,7 @@ import (
 	"github.com/telepresenceio/telepresence/rpc/v2/connector"
 	"github.com/telepresenceio/telepresence/v2/pkg/authenticator/patcher"
 	"github.com/telepresenceio/telepresence/v2/pkg/client"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/api"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/daemon"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/output"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/docker"
@@ -42,19 +44,6 @@ var (
 	ErrNoRootDaemon = errors.New("telepresence root daemon is not running")
 )
 
-type ConnectError struct {
-	error
-	code connector.ConnectInfo_ErrType
-}
-
-func (ce *ConnectError) Code() connector.ConnectInfo_ErrType {
-	return ce.code
-}
-
-func (ce *ConnectError) Unwrap() error {
-	return ce.error
-}
-
 //nolint:gochecknoglobals // extension point
 var QuitDaemonFuncs = []func(context.Context){
 	quitHostConnector, quitDockerDaemons,
@@ -124,7 +113,7 @@ func ExistingHostDaemon(ctx context.Context, id *daemon.Identifier) (context.Con
 		ctx, err = newUserDaemon(ctx, conn, id)
 		if err != nil {
 			// User daemon is not responding. Make an attempt to delete the lingering socket.
-			if rmErr := os.Remove(socketName); rmErr != nil {
+			if rmErr := socket.RemovePath(socketName); rmErr != nil {
 				err = fmt.Errorf("%v; remove of unresponsive socket failed: %v", err, rmErr)
 			}
 		}
@@ -159,10 +148,15 @@ func Disconnect(ctx context.Context) {
 	}
 }
 
-func RunConnect(cmd *cobra.Command, args []string) error {
+func RunConnect(cmd *cobra.Command, args []string, waitForReady bool) error {
 	if err := InitCommand(cmd); err != nil {
 		return err
 	}
+	if waitForReady {
+		if err := WaitForReady(cmd.Context()); err != nil {
+			return err
+		}
+	}
 	if len(args) == 0 {
 		return nil
 	}
@@ -393,29 +387,32 @@ func connectSession(ctx context.Context, useLine string, userD daemon.UserClient
 	}
 
 	// warn if the version diff between cli and manager is > 3 or if there's an OSS/Enterprise mismatch.
-	warnMngrVersion := func(ci *connector.ConnectInfo) error {
+	checkMngrVersion := func(ci *connector.ConnectInfo) error {
 		mv := ci.ManagerVersion
 
 		// remove leading v from semver
 		mSemver, err := semver.Parse(strings.TrimPrefix(mv.Version, "v"))
 		if err != nil {
-			return err
+			dlog.Error(ctx, err)
+			return nil
 		}
 
 		cliSemver := client.Semver()
-
-		var diff uint64
-		if cliSemver.Minor > mSemver.Minor {
-			diff = cliSemver.Minor - mSemver.Minor
-		} else {
-			diff = mSemver.Minor - cliSemver.Minor
-		}
-
-		maxDiff := uint64(3)
-		if diff > maxDiff {
-			ioutil.Printf(output.Info(ctx),
-				"The Traffic Manager version (%s) is more than %v minor versions diff from client version (%s), please consider upgrading.\n",
-				mv.Version, maxDiff, client.Version())
+		skew := client.GetConfig(ctx).Cluster().ManagerVersionSkew
+		if skew != client.VersionSkewIgnore && (cliSemver.Major != mSemver.Major || cliSemver.Minor != mSemver.Minor) {
+			var advice string
+			if cliSemver.GT(mSemver) {
+				advice = `please use "telepresence helm upgrade" to upgrade the traffic-manager`
+			} else {
+				advice = "please upgrade the client"
+			}
+			if skew == client.VersionSkewError {
+				return errcat.User.Newf("the traffic-manager version (%s) differs from the client version (%s) by more than a patch release, %s",
+					mv.Version, client.Version(), advice)
+			}
+			ioutil.Printf(output.Err(ctx),
+				"Warning: The traffic-manager version (%s) differs from the client version (%s) by more than a patch release, %s.\n",
+				mv.Version, client.Version(), advice)
 		}
 
 		cv := ci.Version
@@ -430,27 +427,22 @@ func connectSession(ctx context.Context, useLine string, userD daemon.UserClient
 	}
 
 	connectResult := func(ci *connector.ConnectInfo) (*daemon.Session, error) {
-		var msg string
-		cat := errcat.Unknown
 		switch ci.Error {
 		case connector.ConnectInfo_UNSPECIFIED:
-			ioutil.Printf(output.Info(ctx), "Connected to context %s, namespace %s (%s)\n", ci.ClusterContext, ci.Namespace, ci.ClusterServer)
-			err := warnMngrVersion(ci)
-			if err != nil {
-				dlog.Error(ctx, err)
+			if err := checkMngrVersion(ci); err != nil {
+				if _, derr := userD.Disconnect(ctx, &emptypb.Empty{}); derr != nil {
+					dlog.Error(ctx, derr)
+				}
+				return nil, err
 			}
+			ioutil.Printf(output.Info(ctx), "Connected to context %s, namespace %s (%s)\n", ci.ClusterContext, ci.Namespace, ci.ClusterServer)
+			printRestrictedAccess(output.Info(ctx), ci)
 			return session(ci, true), nil
 		case connector.ConnectInfo_ALREADY_CONNECTED:
 			return session(ci, false), nil
-		case connector.ConnectInfo_MUST_RESTART:
-			msg = "Cluster configuration changed, please quit telepresence and reconnect"
 		default:
-			msg = ci.ErrorText
-			if ci.ErrorCategory != 0 {
-				cat = errcat.Category(ci.ErrorCategory)
-			}
+			return nil, api.ConnectInfoError(ci)
 		}
-		return nil, &ConnectError{error: cat.Newf("connector.Connect: %s", msg), code: ci.Error}
 	}
 
 	if request.Implicit {
@@ -488,7 +480,10 @@ func connectSession(ctx context.Context, useLine string, userD daemon.UserClient
 			return nil, errcat.NoDaemonLogs.New(err)
 		}
 	}
-	if ci, err = userD.Connect(ctx, &request.ConnectRequest); err != nil {
+	stopProgress := reportProgress(ctx, "Still connecting, waiting for the traffic-manager")
+	ci, err = connectOrSwitch(ctx, userD, request)
+	stopProgress()
+	if err != nil {
 		if !userD.Containerized() {
 			_ = daemon.DeleteInfo(ctx, userD.DaemonID().InfoFileName())
 		}
@@ -496,3 +491,77 @@ func connectSession(ctx context.Context, useLine string, userD daemon.UserClient
 	}
 	return connectResult(ci)
 }
+
+// connectProgressInterval is the interval between the progress lines printed by reportProgress.
+const connectProgressInterval = 10 * time.Second
+
+// reportProgress prints the given message, together with the elapsed time, at regular intervals until the
+// returned function is called. It ensures that a connect that waits for a traffic-manager that is still
+// rolling out isn't mistaken for a hang.
+func reportProgress(ctx context.Context, msg string) func() {
+	start := time.Now()
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(connectProgressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				ioutil.Printf(output.Info(ctx), "%s (%s elapsed)...\n", msg, time.Since(start).Truncate(time.Second))
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// connectOrSwitch connects the user daemon using the given request. A session that the daemon already has with
+// another kubernetes context is ended first, so that the daemons don't have to be restarted.
+func connectOrSwitch(ctx context.Context, userD daemon.UserClient, request *daemon.Request) (*connector.ConnectInfo, error) {
+	ci, err := userD.Connect(ctx, &request.ConnectRequest)
+	if err == nil && isContextSwitch(userD, ci) {
+		ci, err = switchContext(ctx, userD, request, ci)
+	}
+	return ci, err
+}
+
+// isContextSwitch returns true if the given connect info is the result of a connect to a host daemon
+// that has a session with a kubernetes context other than the one requested.
+func isContextSwitch(userD daemon.UserClient, ci *connector.ConnectInfo) bool {
+	return ci.Error == connector.ConnectInfo_MUST_RESTART && !userD.Containerized() && ci.ClusterContext != userD.DaemonID().KubeContext
+}
+
+// switchContext ends the current session of the host daemon and then connects again using the given request.
+// The daemons keep running, so there's no need to elevate privileges again for the root daemon. The Disconnect
+// call doesn't return until the previous session's routes and DNS configuration have been removed.
+func switchContext(ctx context.Context, userD daemon.UserClient, request *daemon.Request, ci *connector.ConnectInfo) (*connector.ConnectInfo, error) {
+	ioutil.Printf(output.Info(ctx), "Switching from context %s to %s\n", ci.ClusterContext, userD.DaemonID().KubeContext)
+	if _, err := userD.Disconnect(ctx, &emptypb.Empty{}); err != nil {
+		return nil, err
+	}
+	if ci.ConnectionName != userD.DaemonID().Name {
+		_ = daemon.DeleteInfo(ctx, (&daemon.Identifier{Name: ci.ConnectionName}).InfoFileName())
+	}
+	return userD.Connect(ctx, &request.ConnectRequest)
+}
+
+// printRestrictedAccess notes the namespaces that the connection cannot use, because the RBAC permissions of the
+// user don't permit it.
+func printRestrictedAccess(w io.Writer, ci *connector.ConnectInfo) {
+	if ci.NamespacesRestricted {
+		ioutil.Printf(w, "Note: not permitted to watch namespaces, so only namespace %s is used. "+
+			"Use --mapped-namespaces to add other namespaces.\n", ci.Namespace)
+	}
+	if nss := ci.InaccessibleNamespaces; len(nss) > 0 {
+		ioutil.Printf(w, "Note: not permitted to watch workloads and services in namespaces %s. "+
+			"Workloads in those namespaces cannot be listed or intercepted.\n", strings.Join(nss, ", "))
+	}
+}
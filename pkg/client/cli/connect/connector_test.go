@@ -0,0 +1,118 @@
+package connect
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/telepresenceio/telepresence/rpc/v2/connector"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/daemon"
+	"github.com/telepresenceio/telepresence/v2/pkg/dos"
+	"github.com/telepresenceio/telepresence/v2/pkg/filelocation"
+)
+
+func Test_printRestrictedAccess(t *testing.T) {
+	var sb strings.Builder
+	printRestrictedAccess(&sb, &connector.ConnectInfo{Namespace: "dev"})
+	assert.Empty(t, sb.String())
+
+	sb.Reset()
+	printRestrictedAccess(&sb, &connector.ConnectInfo{
+		Namespace:              "dev",
+		NamespacesRestricted:   true,
+		InaccessibleNamespaces: []string{"kube-system", "prod"},
+	})
+	assert.Equal(t,
+		"Note: not permitted to watch namespaces, so only namespace dev is used. Use --mapped-namespaces to add other namespaces.\n"+
+			"Note: not permitted to watch workloads and services in namespaces kube-system, prod. "+
+			"Workloads in those namespaces cannot be listed or intercepted.\n",
+		sb.String())
+}
+
+// fakeUserClient is a host user daemon that answers Connect with the given connect infos, in order, and records
+// the calls that it receives.
+type fakeUserClient struct {
+	daemon.UserClient
+	id    *daemon.Identifier
+	infos []*connector.ConnectInfo
+	calls []string
+}
+
+func (c *fakeUserClient) Containerized() bool {
+	return false
+}
+
+func (c *fakeUserClient) DaemonID() *daemon.Identifier {
+	return c.id
+}
+
+func (c *fakeUserClient) Connect(context.Context, *connector.ConnectRequest, ...grpc.CallOption) (*connector.ConnectInfo, error) {
+	c.calls = append(c.calls, "connect")
+	ci := c.infos[0]
+	c.infos = c.infos[1:]
+	return ci, nil
+}
+
+func (c *fakeUserClient) Disconnect(context.Context, *emptypb.Empty, ...grpc.CallOption) (*emptypb.Empty, error) {
+	c.calls = append(c.calls, "disconnect")
+	return &emptypb.Empty{}, nil
+}
+
+func Test_connectOrSwitch(t *testing.T) {
+	newCtx := func(t *testing.T) (context.Context, *bytes.Buffer) {
+		out := &bytes.Buffer{}
+		ctx := filelocation.WithAppUserCacheDir(context.Background(), t.TempDir())
+		return dos.WithStdout(ctx, out), out
+	}
+	id := &daemon.Identifier{Name: "new-default", KubeContext: "new", Namespace: "default"}
+
+	t.Run("switch to another context", func(t *testing.T) {
+		ctx, out := newCtx(t)
+		oldID := &daemon.Identifier{Name: "old-default", KubeContext: "old", Namespace: "default"}
+		require.NoError(t, daemon.SaveInfo(ctx, &daemon.Info{Name: oldID.Name, KubeContext: oldID.KubeContext}, oldID.InfoFileName()))
+
+		userD := &fakeUserClient{id: id, infos: []*connector.ConnectInfo{
+			{Error: connector.ConnectInfo_MUST_RESTART, ClusterContext: "old", ConnectionName: oldID.Name},
+			{Error: connector.ConnectInfo_UNSPECIFIED, ClusterContext: "new", ConnectionName: id.Name},
+		}}
+		ci, err := connectOrSwitch(ctx, userD, &daemon.Request{})
+		require.NoError(t, err)
+		assert.Equal(t, connector.ConnectInfo_UNSPECIFIED, ci.Error)
+		assert.Equal(t, "new", ci.ClusterContext)
+		assert.Equal(t, []string{"connect", "disconnect", "connect"}, userD.calls)
+		assert.Equal(t, "Switching from context old to new\n", out.String())
+
+		exists, err := daemon.InfoExists(ctx, oldID.InfoFileName())
+		require.NoError(t, err)
+		assert.False(t, exists, "info of the previous connection was not deleted")
+	})
+
+	t.Run("reconnect to the same context", func(t *testing.T) {
+		ctx, out := newCtx(t)
+		userD := &fakeUserClient{id: id, infos: []*connector.ConnectInfo{
+			{Error: connector.ConnectInfo_ALREADY_CONNECTED, ClusterContext: "new", ConnectionName: id.Name},
+		}}
+		ci, err := connectOrSwitch(ctx, userD, &daemon.Request{})
+		require.NoError(t, err)
+		assert.Equal(t, connector.ConnectInfo_ALREADY_CONNECTED, ci.Error)
+		assert.Equal(t, []string{"connect"}, userD.calls)
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("same context with other flags", func(t *testing.T) {
+		ctx, _ := newCtx(t)
+		userD := &fakeUserClient{id: id, infos: []*connector.ConnectInfo{
+			{Error: connector.ConnectInfo_MUST_RESTART, ClusterContext: "new", ConnectionName: id.Name},
+		}}
+		ci, err := connectOrSwitch(ctx, userD, &daemon.Request{})
+		require.NoError(t, err)
+		assert.Equal(t, connector.ConnectInfo_MUST_RESTART, ci.Error)
+		assert.Equal(t, []string{"connect"}, userD.calls)
+	})
+}
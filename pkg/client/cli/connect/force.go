@@ -0,0 +1,76 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/output"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/socket"
+	"github.com/telepresenceio/telepresence/v2/pkg/ioutil"
+)
+
+// gracefulQuitTimeout is the time that ForceQuit waits for the daemons to quit gracefully.
+const gracefulQuitTimeout = 10 * time.Second
+
+// ForceQuit makes an attempt to quit the daemons gracefully, and if that doesn't succeed within a short
+// timeout, kills the daemon processes and removes their sockets.
+func ForceQuit(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Quit(ctx)
+	}()
+	select {
+	case <-done:
+	case <-time.After(gracefulQuitTimeout):
+		ioutil.Println(output.Out(ctx), "timed out")
+	}
+	forceKill(ctx, "user daemon", "connector-foreground", socket.UserDaemonPath(ctx), false)
+	forceKill(ctx, "root daemon", "daemon-foreground", socket.RootDaemonPath(ctx), true)
+}
+
+// forceKill kills the process that listens to the given socket, if that socket still exists, and removes the socket.
+// The process is only killed when its command line contains the given daemon sub-command, because the PID file might
+// be a stale leftover from a crashed daemon, and the PID may since have been reused by an unrelated process. A PID
+// file without a socket is just removed.
+func forceKill(ctx context.Context, name, subCommand, socketPath string, root bool) {
+	pidPath := socket.PidPath(socketPath)
+	if exists, err := socket.Exists(socketPath); err != nil || !exists {
+		if _, err = os.Stat(pidPath); err == nil {
+			dlog.Infof(ctx, "Removing stale PID file for %s", name)
+			if err = removeFiles(ctx, root, pidPath); err != nil {
+				dlog.Errorf(ctx, "unable to remove the %s PID file: %v", name, err)
+			}
+		}
+		return
+	}
+	pid, err := socket.ReadPid(socketPath)
+	switch {
+	case err == nil:
+		var ok bool
+		if ok, err = isDaemonProcess(ctx, pid, subCommand); err != nil || !ok {
+			if err != nil {
+				dlog.Errorf(ctx, "unable to verify that PID %d is the %s: %v", pid, name, err)
+			}
+			dlog.Infof(ctx, "PID %d is not the %s, so it will not be killed", pid, name)
+			break
+		}
+		if err = killProcess(ctx, pid, root); err != nil {
+			dlog.Errorf(ctx, "unable to kill %s with PID %d: %v", name, pid, err)
+		} else {
+			dlog.Infof(ctx, "Force-killed %s with PID %d", name, pid)
+			ioutil.Printf(output.Out(ctx), "Force-killed %s (PID %d)\n", name, pid)
+		}
+	case errors.Is(err, fs.ErrNotExist):
+		dlog.Infof(ctx, "No PID found for %s", name)
+	default:
+		dlog.Error(ctx, err)
+	}
+	if err = removeFiles(ctx, root, socketPath, pidPath); err != nil {
+		dlog.Errorf(ctx, "unable to remove the %s socket: %v", name, err)
+	}
+}
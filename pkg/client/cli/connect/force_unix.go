@@ -0,0 +1,77 @@
+//go:build !windows
+
+package connect
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/proc"
+)
+
+// isDaemonProcess returns true if the process with the given pid is running and has the given sub-command among
+// its arguments.
+func isDaemonProcess(ctx context.Context, pid int, subCommand string) (bool, error) {
+	var args []string
+	if runtime.GOOS == "linux" {
+		data, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/cmdline")
+		if err != nil {
+			if os.IsNotExist(err) {
+				err = nil
+			}
+			return false, err
+		}
+		args = strings.Split(string(bytes.TrimRight(data, "\x00")), "\x00")
+	} else {
+		cmd := proc.CommandContext(ctx, "ps", "-o", "command=", "-p", strconv.Itoa(pid))
+		cmd.DisableLogging = true
+		data, err := cmd.Output()
+		if err != nil {
+			// ps exits with status 1 when no process is found.
+			var ee *exec.ExitError
+			if errors.As(err, &ee) {
+				err = nil
+			}
+			return false, err
+		}
+		args = strings.Fields(string(data))
+	}
+	return slices.Contains(args, subCommand), nil
+}
+
+// killProcess sends a SIGKILL to the process with the given pid. Sudo is used when the process is owned by root
+// and the current process isn't.
+func killProcess(ctx context.Context, pid int, root bool) error {
+	if root && !proc.IsAdmin() {
+		return proc.StdCommand(ctx, "sudo", "-p", "Need root privileges to kill the root daemon.\nPassword:",
+			"kill", "-KILL", strconv.Itoa(pid)).Run()
+	}
+	err := unix.Kill(pid, unix.SIGKILL)
+	if errors.Is(err, unix.ESRCH) {
+		// Already gone.
+		err = nil
+	}
+	return err
+}
+
+// removeFiles removes the given files. Sudo is used when the files are owned by root and the current process isn't.
+func removeFiles(ctx context.Context, root bool, files ...string) error {
+	if root && !proc.IsAdmin() {
+		return proc.StdCommand(ctx, "sudo", append([]string{"rm", "-f"}, files...)...).Run()
+	}
+	for _, file := range files {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+//go:build !windows
+
+package connect
+
+import (
+	"context"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/client/socket"
+)
+
+// startFakeDaemon starts a process that has the given sub-command among its arguments.
+func startFakeDaemon(t *testing.T, subCommand string) *exec.Cmd {
+	cmd := exec.Command("sh", "-c", "sleep 30", subCommand)
+	require.NoError(t, cmd.Start())
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+	return cmd
+}
+
+// listenAt creates a socket at a new path in a temporary directory and writes the given pid to its PID file.
+func listenAt(t *testing.T, pid int, withSocket bool) string {
+	// Use a short path, because socket paths are limited to roughly 100 characters.
+	dir, err := os.MkdirTemp("", "tp")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	path := filepath.Join(dir, "d.socket")
+	if withSocket {
+		l, err := net.Listen("unix", path)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = l.Close() })
+	}
+	require.NoError(t, os.WriteFile(socket.PidPath(path), []byte(strconv.Itoa(pid)), 0o600))
+	return path
+}
+
+func Test_isDaemonProcess(t *testing.T) {
+	ctx := context.Background()
+	cmd := startFakeDaemon(t, "daemon-foreground")
+
+	ok, err := isDaemonProcess(ctx, cmd.Process.Pid, "daemon-foreground")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = isDaemonProcess(ctx, cmd.Process.Pid, "connector-foreground")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = isDaemonProcess(ctx, os.Getpid(), "daemon-foreground")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func Test_forceKill(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("kills daemon", func(t *testing.T) {
+		cmd := startFakeDaemon(t, "connector-foreground")
+		path := listenAt(t, cmd.Process.Pid, true)
+		forceKill(ctx, "user daemon", "connector-foreground", path, false)
+
+		exited := make(chan error, 1)
+		go func() { exited <- cmd.Wait() }()
+		select {
+		case <-exited:
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "daemon was not killed")
+		}
+		assert.NoFileExists(t, path)
+		assert.NoFileExists(t, socket.PidPath(path))
+	})
+
+	t.Run("does not kill reused PID", func(t *testing.T) {
+		cmd := startFakeDaemon(t, "something-else")
+		path := listenAt(t, cmd.Process.Pid, true)
+		forceKill(ctx, "user daemon", "connector-foreground", path, false)
+
+		ok, err := isDaemonProcess(ctx, cmd.Process.Pid, "something-else")
+		require.NoError(t, err)
+		assert.True(t, ok, "unrelated process was killed")
+		assert.NoFileExists(t, path)
+		assert.NoFileExists(t, socket.PidPath(path))
+	})
+
+	t.Run("removes stale PID file", func(t *testing.T) {
+		cmd := startFakeDaemon(t, "connector-foreground")
+		path := listenAt(t, cmd.Process.Pid, false)
+		forceKill(ctx, "user daemon", "connector-foreground", path, false)
+
+		ok, err := isDaemonProcess(ctx, cmd.Process.Pid, "connector-foreground")
+		require.NoError(t, err)
+		assert.True(t, ok, "process was killed although the socket was gone")
+		assert.NoFileExists(t, socket.PidPath(path))
+	})
+}
@@ -0,0 +1,48 @@
+package connect
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/proc"
+)
+
+// isDaemonProcess returns true if the process with the given pid is running and uses the same executable as the
+// current process. The command line of another process isn't readable here, so the sub-command isn't checked.
+func isDaemonProcess(ctx context.Context, pid int, _ string) (bool, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return false, err
+	}
+	cmd := proc.CommandContext(ctx, "tasklist", "/FI", "PID eq "+strconv.Itoa(pid), "/FO", "CSV", "/NH")
+	cmd.DisableLogging = true
+	data, err := cmd.Output()
+	if err != nil {
+		return false, err
+	}
+	// The image name is the first quoted field of the CSV output.
+	return strings.HasPrefix(strings.ToLower(string(data)), strings.ToLower(`"`+filepath.Base(exe)+`"`)), nil
+}
+
+// killProcess kills the process with the given pid. The root daemon can only be killed when the current process
+// runs with administrator privileges.
+func killProcess(_ context.Context, pid int, _ bool) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return p.Kill()
+}
+
+// removeFiles removes the given files.
+func removeFiles(_ context.Context, _ bool, files ...string) error {
+	for _, file := range files {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package connect
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	empty "google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/telepresenceio/telepresence/rpc/v2/connector"
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/daemon"
+	"github.com/telepresenceio/telepresence/v2/pkg/dnsproxy"
+)
+
+const (
+	readyPollInterval = 200 * time.Millisecond
+	dnsProbeTimeout   = 500 * time.Millisecond
+)
+
+// WaitForReady waits until the root daemon of the current session routes the cluster subnets and has a DNS
+// resolver that answers queries. The subsystems that aren't used, e.g. routes when connected with --dns-only,
+// are not waited for. The wait is limited by the connectReady timeout, and an error that lists the subsystems
+// that aren't ready is returned when it expires.
+func WaitForReady(ctx context.Context) error {
+	userD := daemon.GetUserClient(ctx)
+	tc, cancel := client.GetConfig(ctx).Timeouts().TimeoutContext(ctx, client.TimeoutConnectReady)
+	defer cancel()
+	var pending []string
+	for {
+		ci, err := userD.Status(tc, &empty.Empty{})
+		if err != nil {
+			if tc.Err() == nil {
+				return err
+			}
+		} else {
+			var probe func(string) error
+			if !userD.Containerized() {
+				probe = func(addr string) error {
+					return dnsproxy.Probe(tc, addr, dnsProbeTimeout)
+				}
+			}
+			if pending = notReady(ci, probe); len(pending) == 0 {
+				return nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-tc.Done():
+			if len(pending) == 0 {
+				return tc.Err()
+			}
+			return fmt.Errorf("%w. Not ready:\n  %s", tc.Err(), strings.Join(pending, "\n  "))
+		case <-time.After(readyPollInterval):
+		}
+	}
+}
+
+// notReady returns a description of each subsystem of the given connection that isn't ready. The given
+// probe is used to verify that the DNS resolver answers. It is nil when the resolver isn't reachable from
+// this host, e.g. when the daemon runs in a container.
+func notReady(ci *connector.ConnectInfo, probe func(string) error) []string {
+	switch ci.Error {
+	case connector.ConnectInfo_UNSPECIFIED, connector.ConnectInfo_ALREADY_CONNECTED:
+	default:
+		return []string{"session: not connected"}
+	}
+	ds := ci.DaemonStatus
+	if ds == nil || ds.OutboundConfig == nil {
+		return []string{"root daemon: not connected"}
+	}
+	var pending []string
+	obc := ds.OutboundConfig
+	if !obc.DnsOnly && len(ds.Subnets) == 0 {
+		pending = append(pending, "routes: no cluster subnets are routed")
+	}
+	if !obc.RouteOnly {
+		dc := obc.Dns
+		switch {
+		case dc.GetError() != "":
+			pending = append(pending, "DNS: "+dc.Error)
+		case dc.GetListenAddress() == "":
+			pending = append(pending, "DNS: the resolver isn't listening")
+		case probe != nil:
+			if err := probe(dc.ListenAddress); err != nil {
+				pending = append(pending, fmt.Sprintf("DNS: the resolver at %s doesn't answer: %v", dc.ListenAddress, err))
+			}
+		}
+	}
+	return pending
+}
@@ -0,0 +1,77 @@
+package connect
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/telepresenceio/telepresence/rpc/v2/connector"
+	"github.com/telepresenceio/telepresence/rpc/v2/daemon"
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+)
+
+func Test_notReady(t *testing.T) {
+	subnets := []*manager.IPNet{{Ip: []byte{10, 0, 0, 0}, Mask: 16}}
+	dnsConfig := &daemon.DNSConfig{ListenAddress: "127.0.0.1:5353"}
+	answers := func(string) error { return nil }
+	silent := func(string) error { return errors.New("i/o timeout") }
+
+	tests := []struct {
+		name  string
+		ci    *connector.ConnectInfo
+		probe func(string) error
+		want  []string
+	}{
+		{
+			name: "disconnected",
+			ci:   &connector.ConnectInfo{Error: connector.ConnectInfo_DISCONNECTED},
+			want: []string{"session: not connected"},
+		},
+		{
+			name: "no root daemon",
+			ci:   &connector.ConnectInfo{},
+			want: []string{"root daemon: not connected"},
+		},
+		{
+			name:  "ready",
+			ci:    &connector.ConnectInfo{DaemonStatus: &daemon.DaemonStatus{Subnets: subnets, OutboundConfig: &daemon.OutboundInfo{Dns: dnsConfig}}},
+			probe: answers,
+		},
+		{
+			name:  "no routes",
+			ci:    &connector.ConnectInfo{DaemonStatus: &daemon.DaemonStatus{OutboundConfig: &daemon.OutboundInfo{Dns: dnsConfig}}},
+			probe: answers,
+			want:  []string{"routes: no cluster subnets are routed"},
+		},
+		{
+			name:  "dns-only",
+			ci:    &connector.ConnectInfo{DaemonStatus: &daemon.DaemonStatus{OutboundConfig: &daemon.OutboundInfo{Dns: dnsConfig, DnsOnly: true}}},
+			probe: answers,
+		},
+		{
+			name: "route-only",
+			ci:   &connector.ConnectInfo{DaemonStatus: &daemon.DaemonStatus{Subnets: subnets, OutboundConfig: &daemon.OutboundInfo{RouteOnly: true}}},
+		},
+		{
+			name: "dns not listening",
+			ci:   &connector.ConnectInfo{DaemonStatus: &daemon.DaemonStatus{Subnets: subnets, OutboundConfig: &daemon.OutboundInfo{Dns: &daemon.DNSConfig{}}}},
+			want: []string{"DNS: the resolver isn't listening"},
+		},
+		{
+			name:  "dns not answering",
+			ci:    &connector.ConnectInfo{DaemonStatus: &daemon.DaemonStatus{Subnets: subnets, OutboundConfig: &daemon.OutboundInfo{Dns: dnsConfig}}},
+			probe: silent,
+			want:  []string{"DNS: the resolver at 127.0.0.1:5353 doesn't answer: i/o timeout"},
+		},
+		{
+			name: "containerized",
+			ci:   &connector.ConnectInfo{DaemonStatus: &daemon.DaemonStatus{Subnets: subnets, OutboundConfig: &daemon.OutboundInfo{Dns: dnsConfig}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, notReady(tt.ci, tt.probe))
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -23,6 +24,7 @@ import (
 	"github.com/datawire/dlib/dlog"
 	"github.com/telepresenceio/telepresence/rpc/v2/connector"
 	"github.com/telepresenceio/telepresence/rpc/v2/daemon"
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/global"
 	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
 	"github.com/telepresenceio/telepresence/v2/pkg/slice"
@@ -52,6 +54,9 @@ type Request struct {
 
 	// proxyVia holds the string version for the --proxy-via flag values.
 	proxyVia []string
+
+	// tokenFile is the path to a file containing a bearer token, used together with --server in place of a kubeconfig.
+	tokenFile string
 }
 
 type CobraRequest struct {
@@ -75,16 +80,38 @@ func InitRequest(cmd *cobra.Command) *CobraRequest {
 			`Defaults to all namespaces`)
 	nwFlags.StringVar(&cr.ManagerNamespace, "manager-namespace", "", `The namespace where the traffic manager is to be found. `+
 		`Overrides any other manager namespace set in config`)
+	nwFlags.StringVar(&cr.ManagerServiceName, "manager-service-name", "", `The name of the traffic manager service. `+
+		`Overrides any other manager service name set in config`)
 	nwFlags.StringSliceVar(&cr.AlsoProxy,
 		"also-proxy", nil, ``+
-			`Additional comma separated list of CIDR to proxy`)
+			`Additional comma separated list of CIDR to proxy. Can be repeated`)
 	nwFlags.StringSliceVar(&cr.NeverProxy,
 		"never-proxy", nil, ``+
-			`Comma separated list of CIDR to never proxy`)
+			`Comma separated list of CIDR to never proxy. Can be repeated`)
 	nwFlags.StringSliceVar(&cr.proxyVia,
 		"proxy-via", nil, ``+
 			`Locally translate cluster DNS responses matching CIDR to virtual IPs that are routed (with reverse `+
 			`translation) via WORKLOAD. Must be in the form CIDR=WORKLOAD. CIDR can be substituted for the symblic name "service", "pods", "also", or "all".`)
+	nwFlags.StringVar(&cr.ProxyUrl,
+		"proxy-url", "", ``+
+			`URL of a proxy, e.g. socks5://host:port, to use for all traffic to the cluster's API server, including the `+
+			`tunnel to the traffic manager. Overrides the proxy-url of the cluster in the kubeconfig`)
+	nwFlags.StringVar(&cr.tokenFile,
+		"token-file", "", ``+
+			`File containing a bearer token, e.g. a mounted service account token, to use for authentication. `+
+			`Requires --server and is used in place of a kubeconfig`)
+	nwFlags.StringVar(&cr.DnsResolverAddress,
+		"dns-resolver-address", "", ``+
+			`Local address and port, e.g. 127.0.0.1:5353, that the DNS resolver of the root daemon listens to. `+
+			`Defaults to 127.0.0.1 and a random port`)
+	nwFlags.BoolVar(&cr.DnsOnly,
+		"dns-only", false, ``+
+			`Only resolve cluster names using the cluster's DNS. No cluster subnets are routed, which is useful when `+
+			`a VPN already provides access to them. Cannot be combined with --also-proxy or --proxy-via`)
+	nwFlags.BoolVar(&cr.RouteOnly,
+		"route-only", false, ``+
+			`Only route the cluster subnets. The system's DNS configuration is left untouched, so cluster names must be `+
+			`resolved by other means. Cannot be combined with --dns-only, --dns-resolver-address, or --proxy-via`)
 	nwFlags.StringSliceVar(&cr.AllowConflictingSubnets,
 		"allow-conflicting-subnets", nil, ``+
 			`Comma separated list of CIDR that will be allowed to conflict with local subnets`)
@@ -146,6 +173,25 @@ func (cr *CobraRequest) CommitFlags(cmd *cobra.Command) error {
 	if err != nil {
 		return errcat.User.New(err)
 	}
+	if cr.tokenFile != "" {
+		if len(cr.KubeconfigData) > 0 {
+			return errcat.User.New("--token-file cannot be combined with --kubeconfig -")
+		}
+		if cr.KubeconfigData, err = tokenKubeconfig(cr.tokenFile, cr.KubeFlags); err != nil {
+			return err
+		}
+		if err = checkTokenAccess(cmd.Context(), cr.KubeconfigData); err != nil {
+			return err
+		}
+	}
+	if len(cr.KubeconfigData) == 0 {
+		if cr.KubeconfigData, err = inClusterKubeconfig(cr.KubeFlags); err != nil {
+			return errcat.Config.Newf("unable to use the in-cluster service account: %w", err)
+		}
+		if len(cr.KubeconfigData) > 0 {
+			dlog.Debug(cmd.Context(), "no kubeconfig found, using the in-cluster service account")
+		}
+	}
 	ctx, err := cr.Commit(cmd.Context())
 	if err != nil {
 		return err
@@ -157,10 +203,47 @@ func (cr *CobraRequest) CommitFlags(cmd *cobra.Command) error {
 func (cr *Request) Commit(ctx context.Context) (context.Context, error) {
 	cr.addKubeconfigEnv()
 	var err error
+	if err = validateCIDRs("also-proxy", cr.AlsoProxy); err != nil {
+		return ctx, errcat.User.New(err)
+	}
+	if err = validateCIDRs("never-proxy", cr.NeverProxy); err != nil {
+		return ctx, errcat.User.New(err)
+	}
+	if err = validateCIDRs("allow-conflicting-subnets", cr.AllowConflictingSubnets); err != nil {
+		return ctx, errcat.User.New(err)
+	}
 	cr.SubnetViaWorkloads, err = parseProxyVias(cr.proxyVia)
 	if err != nil {
 		return ctx, errcat.User.New(err)
 	}
+	if cr.DnsOnly {
+		if len(cr.AlsoProxy) > 0 {
+			return ctx, errcat.User.New("--dns-only cannot be combined with --also-proxy")
+		}
+		if len(cr.SubnetViaWorkloads) > 0 {
+			return ctx, errcat.User.New("--dns-only cannot be combined with --proxy-via")
+		}
+	}
+	if cr.RouteOnly {
+		switch {
+		case cr.DnsOnly:
+			return ctx, errcat.User.New("--route-only cannot be combined with --dns-only")
+		case cr.DnsResolverAddress != "":
+			return ctx, errcat.User.New("--route-only cannot be combined with --dns-resolver-address")
+		case len(cr.SubnetViaWorkloads) > 0:
+			return ctx, errcat.User.New("--route-only cannot be combined with --proxy-via")
+		}
+	}
+	if cr.ProxyUrl != "" {
+		if _, err = client.ParseProxyURL(cr.ProxyUrl); err != nil {
+			return ctx, err
+		}
+	}
+	if cr.DnsResolverAddress != "" {
+		if _, err = netip.ParseAddrPort(cr.DnsResolverAddress); err != nil {
+			return ctx, errcat.User.Newf("invalid --dns-resolver-address: %v", err)
+		}
+	}
 	if len(cr.KubeconfigData) > 0 {
 		kc, err := clientcmd.Load(cr.KubeconfigData)
 		if err != nil {
@@ -183,6 +266,17 @@ func (cr *Request) Commit(ctx context.Context) (context.Context, error) {
 	return context.WithValue(ctx, requestKey{}, cr), nil
 }
 
+// validateCIDRs ensures that all given strings are valid CIDR notations, so that an error is
+// reported before any attempt is made to connect.
+func validateCIDRs(flagName string, cidrs []string) error {
+	for _, cidr := range cidrs {
+		if _, err := netip.ParsePrefix(cidr); err != nil {
+			return fmt.Errorf("--%s %q is not a valid CIDR: %w", flagName, cidr, err)
+		}
+	}
+	return nil
+}
+
 type prefixViaWL struct {
 	subnet   netip.Prefix
 	symbolic string
@@ -373,6 +467,12 @@ func (cr *CobraRequest) GetAllNamespaces(cmd *cobra.Command) ([]string, error) {
 	}
 	nsl, err := cs.CoreV1().Namespaces().List(cmd.Context(), v1.ListOptions{})
 	if err != nil {
+		if k8sErrors.IsForbidden(err) {
+			// Restricted users can still use the namespace of their kubeconfig context.
+			if ns, _, nsErr := cr.kubeConfig.ToRawKubeConfigLoader().Namespace(); nsErr == nil {
+				return []string{ns}, nil
+			}
+		}
 		return nil, errcat.NoDaemonLogs.Newf("Namespaces.List: %v", err)
 	}
 	itms := nsl.Items
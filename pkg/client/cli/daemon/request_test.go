@@ -1,13 +1,58 @@
 package daemon
 
 import (
+	"context"
 	"net/netip"
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/telepresenceio/telepresence/rpc/v2/daemon"
 )
 
+func Test_validateCIDRs(t *testing.T) {
+	tests := []struct {
+		name    string
+		cidrs   []string
+		wantErr bool
+	}{
+		{
+			"empty",
+			nil,
+			false,
+		},
+		{
+			"ok",
+			[]string{"10.0.0.0/16", "192.168.1.0/24", "fd00::/8"},
+			false,
+		},
+		{
+			"missing mask",
+			[]string{"10.0.0.0/16", "192.168.1.0"},
+			true,
+		},
+		{
+			"bad address",
+			[]string{"10.0.0.256/16"},
+			true,
+		},
+		{
+			"name",
+			[]string{"service"},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCIDRs("never-proxy", tt.cidrs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateCIDRs(%q) error = %v, wantErr %v", tt.cidrs, err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func Test_parseSubnetViaWorkload(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -200,3 +245,66 @@ func Test_parseProxyVias(t *testing.T) {
 		})
 	}
 }
+
+func TestRequest_Commit_proxyModes(t *testing.T) {
+	dnsOnly := func(cr *Request) { cr.DnsOnly = true }
+	routeOnly := func(cr *Request) { cr.RouteOnly = true }
+	tests := []struct {
+		name    string
+		req     func(*Request)
+		wantErr string
+	}{
+		{
+			"dns-only",
+			dnsOnly,
+			"",
+		},
+		{
+			"dns-only with also-proxy",
+			func(cr *Request) { dnsOnly(cr); cr.AlsoProxy = []string{"10.0.0.0/16"} },
+			"--dns-only cannot be combined with --also-proxy",
+		},
+		{
+			"dns-only with proxy-via",
+			func(cr *Request) { dnsOnly(cr); cr.proxyVia = []string{"all=echo"} },
+			"--dns-only cannot be combined with --proxy-via",
+		},
+		{
+			"route-only",
+			routeOnly,
+			"",
+		},
+		{
+			"route-only with also-proxy",
+			func(cr *Request) { routeOnly(cr); cr.AlsoProxy = []string{"10.0.0.0/16"} },
+			"",
+		},
+		{
+			"route-only with dns-only",
+			func(cr *Request) { routeOnly(cr); dnsOnly(cr) },
+			"--route-only cannot be combined with --dns-only",
+		},
+		{
+			"route-only with dns-resolver-address",
+			func(cr *Request) { routeOnly(cr); cr.DnsResolverAddress = "127.0.0.1:5353" },
+			"--route-only cannot be combined with --dns-resolver-address",
+		},
+		{
+			"route-only with proxy-via",
+			func(cr *Request) { routeOnly(cr); cr.proxyVia = []string{"all=echo"} },
+			"--route-only cannot be combined with --proxy-via",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &Request{}
+			tt.req(cr)
+			_, err := cr.Commit(context.Background())
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
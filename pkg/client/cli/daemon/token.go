@@ -0,0 +1,150 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+)
+
+const (
+	// tokenContextName is the name of the context in the kubeconfig that is created from a --token-file.
+	tokenContextName = "token"
+
+	// inClusterContextName is the name of the context in the kubeconfig that is created from the service
+	// account of the pod that the CLI is running in.
+	inClusterContextName = "in-cluster"
+
+	// serviceAccountDir is the directory where Kubernetes mounts the service account token of a pod.
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount" //nolint:gosec // not a credential
+)
+
+// tokenKubeconfig creates a kubeconfig from the given token file and the --server, --certificate-authority,
+// --insecure-skip-tls-verify, and --namespace kubernetes flags. The token and the certificate authority
+// are embedded in the kubeconfig, so the daemon doesn't need access to the files. The flags that have been
+// consumed are removed from the given flag map.
+func tokenKubeconfig(tokenFile string, kubeFlags map[string]string) ([]byte, error) {
+	server := kubeFlags["server"]
+	if server == "" {
+		return nil, errcat.User.New("--token-file requires --server")
+	}
+	if _, ok := kubeFlags["context"]; ok {
+		return nil, errcat.User.New("--token-file cannot be combined with --context")
+	}
+	token, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return nil, errcat.User.Newf("unable to read --token-file: %w", err)
+	}
+	cluster := &api.Cluster{
+		Server:                server,
+		InsecureSkipTLSVerify: kubeFlags["insecure-skip-tls-verify"] == "true",
+	}
+	if caFile := kubeFlags["certificate-authority"]; caFile != "" {
+		if cluster.CertificateAuthorityData, err = os.ReadFile(caFile); err != nil {
+			return nil, errcat.User.Newf("unable to read --certificate-authority: %w", err)
+		}
+		delete(kubeFlags, "certificate-authority")
+	}
+	ns := kubeFlags["namespace"]
+	if ns == "" {
+		ns = "default"
+	}
+	return newTokenKubeconfig(tokenContextName, cluster, &api.AuthInfo{Token: strings.TrimSpace(string(token))}, ns)
+}
+
+// inClusterKubeconfig creates a kubeconfig from the service account of the pod that the CLI is running in. It returns
+// nil when a kubeconfig is available, or when the CLI isn't running in a pod.
+func inClusterKubeconfig(kubeFlags map[string]string) ([]byte, error) {
+	if _, ok := kubeFlags["kubeconfig"]; ok {
+		return nil, nil
+	}
+	if _, ok := os.LookupEnv("KUBECONFIG"); ok {
+		return nil, nil
+	}
+	if _, err := os.Stat(clientcmd.RecommendedHomeFile); err == nil {
+		return nil, nil
+	}
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, nil
+	}
+	tokenFile := filepath.Join(serviceAccountDir, "token")
+	if _, err := os.Stat(tokenFile); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	ca, err := os.ReadFile(filepath.Join(serviceAccountDir, "ca.crt"))
+	if err != nil {
+		return nil, err
+	}
+	ns := kubeFlags["namespace"]
+	if ns == "" {
+		ns = podNamespace()
+	}
+	// The token is referenced rather than embedded, because it's rotated by the kubelet.
+	return newTokenKubeconfig(inClusterContextName,
+		&api.Cluster{Server: "https://" + net.JoinHostPort(host, port), CertificateAuthorityData: ca},
+		&api.AuthInfo{TokenFile: tokenFile}, ns)
+}
+
+// podNamespace returns the namespace of the service account of the pod that the CLI is running in, or
+// "default" when it cannot be determined.
+func podNamespace() string {
+	if ns, err := os.ReadFile(filepath.Join(serviceAccountDir, "namespace")); err == nil {
+		if ns := strings.TrimSpace(string(ns)); ns != "" {
+			return ns
+		}
+	}
+	return "default"
+}
+
+func newTokenKubeconfig(name string, cluster *api.Cluster, authInfo *api.AuthInfo, ns string) ([]byte, error) {
+	cfg := api.NewConfig()
+	cfg.Clusters[name] = cluster
+	cfg.AuthInfos[name] = authInfo
+	cfg.Contexts[name] = &api.Context{Cluster: name, AuthInfo: name, Namespace: ns}
+	cfg.CurrentContext = name
+	return clientcmd.Write(*cfg)
+}
+
+// checkTokenAccess verifies that the credentials in the given kubeconfig can be used to list pods in the
+// namespace of its current context. The check is bounded by the cluster connect timeout.
+func checkTokenAccess(ctx context.Context, kubeconfigData []byte) error {
+	cc, err := clientcmd.NewClientConfigFromBytes(kubeconfigData)
+	if err != nil {
+		return err
+	}
+	rc, err := cc.ClientConfig()
+	if err != nil {
+		return err
+	}
+	ns, _, err := cc.Namespace()
+	if err != nil {
+		return err
+	}
+	ki, err := kubernetes.NewForConfig(rc)
+	if err != nil {
+		return err
+	}
+	tc, cancel := client.GetConfig(ctx).Timeouts().TimeoutContext(ctx, client.TimeoutClusterConnect)
+	defer cancel()
+	if _, err = ki.CoreV1().Pods(ns).List(tc, v1.ListOptions{Limit: 1}); err != nil {
+		if tc.Err() != nil {
+			return client.CheckTimeout(tc, err)
+		}
+		return errcat.User.New(fmt.Errorf("the token in --token-file cannot be used to list pods in namespace %s: %w", ns, err))
+	}
+	return nil
+}
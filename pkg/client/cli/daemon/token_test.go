@@ -0,0 +1,92 @@
+package daemon
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
+)
+
+func Test_tokenKubeconfig(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	caFile := filepath.Join(dir, "ca.crt")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("secret-token\n"), 0o600))
+	require.NoError(t, os.WriteFile(caFile, []byte("ca-data"), 0o600))
+
+	t.Run("ok", func(t *testing.T) {
+		flags := map[string]string{
+			"server":                "https://example.com:6443",
+			"certificate-authority": caFile,
+			"namespace":             "ci",
+		}
+		data, err := tokenKubeconfig(tokenFile, flags)
+		require.NoError(t, err)
+		cfg, err := clientcmd.Load(data)
+		require.NoError(t, err)
+		assert.Equal(t, tokenContextName, cfg.CurrentContext)
+		assert.Equal(t, "ci", cfg.Contexts[tokenContextName].Namespace)
+		assert.Equal(t, "https://example.com:6443", cfg.Clusters[tokenContextName].Server)
+		assert.Equal(t, []byte("ca-data"), cfg.Clusters[tokenContextName].CertificateAuthorityData)
+		assert.Equal(t, "secret-token", cfg.AuthInfos[tokenContextName].Token)
+		assert.NotContains(t, flags, "certificate-authority")
+	})
+
+	t.Run("default namespace", func(t *testing.T) {
+		data, err := tokenKubeconfig(tokenFile, map[string]string{"server": "https://example.com"})
+		require.NoError(t, err)
+		cfg, err := clientcmd.Load(data)
+		require.NoError(t, err)
+		assert.Equal(t, "default", cfg.Contexts[tokenContextName].Namespace)
+	})
+
+	t.Run("no server", func(t *testing.T) {
+		_, err := tokenKubeconfig(tokenFile, map[string]string{})
+		assert.ErrorContains(t, err, "requires --server")
+	})
+
+	t.Run("with context", func(t *testing.T) {
+		_, err := tokenKubeconfig(tokenFile, map[string]string{"server": "https://example.com", "context": "other"})
+		assert.ErrorContains(t, err, "cannot be combined with --context")
+	})
+
+	t.Run("missing token file", func(t *testing.T) {
+		_, err := tokenKubeconfig(filepath.Join(dir, "nope"), map[string]string{"server": "https://example.com"})
+		assert.Error(t, err)
+	})
+}
+
+func Test_checkTokenAccessTimeout(t *testing.T) {
+	unblock := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-unblock:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(unblock)
+
+	data, err := newTokenKubeconfig(tokenContextName, &api.Cluster{Server: srv.URL}, &api.AuthInfo{Token: "secret-token"}, "default")
+	require.NoError(t, err)
+
+	cfg := client.GetDefaultConfig()
+	cfg.Timeouts().PrivateClusterConnect = 200 * time.Millisecond
+	ctx := client.WithConfig(context.Background(), cfg)
+
+	start := time.Now()
+	err = checkTokenAccess(ctx, data)
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.ErrorContains(t, err, "timeout")
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"errors"
+
+	"github.com/telepresenceio/telepresence/rpc/v2/common"
+	"github.com/telepresenceio/telepresence/rpc/v2/connector"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/api"
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+)
+
+// Exit codes used by the CLI. Scripts may depend on them, so existing values must never change.
+const (
+	ExitGeneral           = 1  // Unknown failure. Consult the logs.
+	ExitUser              = 2  // Invalid command, flag, or argument.
+	ExitConfig            = 10 // Error in config.yml, extensions, or kubeconfig.
+	ExitCluster           = 11 // Unable to connect to the cluster.
+	ExitTrafficManager    = 12 // Unable to talk to, or install, the traffic-manager.
+	ExitInterceptConflict = 13 // The intercept conflicts with an existing intercept or local resource.
+)
+
+// ExitCode returns the process exit code for the given error. Errors from the connect and intercept calls
+// are mapped by their error codes, and all other errors by their errcat.Category.
+func ExitCode(err error) int {
+	var ce *api.ConnectError
+	if errors.As(err, &ce) {
+		switch ce.Code() {
+		case connector.ConnectInfo_CLUSTER_FAILED, connector.ConnectInfo_UNAUTHORIZED, connector.ConnectInfo_UNAUTHENTICATED:
+			return ExitCluster
+		case connector.ConnectInfo_TRAFFIC_MANAGER_FAILED:
+			return ExitTrafficManager
+		}
+	}
+	var re *api.ResultError
+	if errors.As(err, &re) {
+		switch re.Code() {
+		case common.InterceptError_NO_TRAFFIC_MANAGER, common.InterceptError_TRAFFIC_MANAGER_CONNECTING, common.InterceptError_TRAFFIC_MANAGER_ERROR:
+			return ExitTrafficManager
+		case common.InterceptError_ALREADY_EXISTS, common.InterceptError_LOCAL_TARGET_IN_USE,
+			common.InterceptError_MOUNT_POINT_BUSY, common.InterceptError_NAMESPACE_AMBIGUITY:
+			return ExitInterceptConflict
+		}
+	}
+	switch errcat.GetCategory(err) {
+	case errcat.User:
+		return ExitUser
+	case errcat.Config:
+		return ExitConfig
+	default:
+		return ExitGeneral
+	}
+}
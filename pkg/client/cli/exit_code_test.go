@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/telepresenceio/telepresence/rpc/v2/common"
+	"github.com/telepresenceio/telepresence/rpc/v2/connector"
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/api"
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+)
+
+func TestExitCode(t *testing.T) {
+	interceptErr := func(code common.InterceptError) error {
+		return api.InterceptResultError(&connector.InterceptResult{
+			Error:         code,
+			ErrorText:     "hello",
+			InterceptInfo: &manager.InterceptInfo{Spec: &manager.InterceptSpec{Name: "hello", TargetHost: "127.0.0.1", TargetPort: 8080}},
+		}, nil)
+	}
+	connectErr := func(code connector.ConnectInfo_ErrType) error {
+		return api.ConnectInfoError(&connector.ConnectInfo{Error: code, ErrorText: "hello"})
+	}
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"unknown", errors.New("boom"), ExitGeneral},
+		{"no daemon logs", errcat.NoDaemonLogs.New("boom"), ExitGeneral},
+		{"user", errcat.User.New("bad flag"), ExitUser},
+		{"config", errcat.Config.New("bad config"), ExitConfig},
+		{"wrapped config", fmt.Errorf("loading: %w", errcat.Config.New("bad config")), ExitConfig},
+		{"intercept exists", interceptErr(common.InterceptError_ALREADY_EXISTS), ExitInterceptConflict},
+		{"port busy", interceptErr(common.InterceptError_LOCAL_TARGET_IN_USE), ExitInterceptConflict},
+		{"no traffic manager", interceptErr(common.InterceptError_NO_TRAFFIC_MANAGER), ExitTrafficManager},
+		{"intercept not found", interceptErr(common.InterceptError_NOT_FOUND), ExitGeneral},
+		{"cluster failed", connectErr(connector.ConnectInfo_CLUSTER_FAILED), ExitCluster},
+		{"unauthorized", connectErr(connector.ConnectInfo_UNAUTHORIZED), ExitCluster},
+		{"traffic manager failed", connectErr(connector.ConnectInfo_TRAFFIC_MANAGER_FAILED), ExitTrafficManager},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExitCode(tt.err))
+		})
+	}
+}
@@ -4,6 +4,11 @@ import (
 	"github.com/spf13/pflag"
 )
 
+// flagAliases maps alternative flag names to the name of the global flag that they represent.
+var flagAliases = map[string]string{ //nolint:gochecknoglobals // constant
+	"connection": FlagUse,
+}
+
 const (
 	FlagDocker   = "docker"
 	FlagContext  = "context"
@@ -22,7 +27,16 @@ func Flags(hasKubeFlags bool) *pflag.FlagSet {
 		flags.Lookup(FlagDocker).Hidden = true
 	}
 	flags.Bool(FlagNoReport, false, "Turn off anonymous crash reports and log submission on failure")
-	flags.String(FlagUse, "", "Match expression that uniquely identifies the daemon container")
+	flags.String(FlagUse, "", "Match expression that uniquely identifies the daemon container, i.e. the name of the connection. "+
+		"Can also be given as --connection")
 	flags.String(FlagOutput, "default", "Set the output format, supported values are 'json', 'yaml', and 'default'")
 	return flags
 }
+
+// NormalizeFlagName translates aliases of global flags into the flag's name.
+func NormalizeFlagName(_ *pflag.FlagSet, name string) pflag.NormalizedName {
+	if n, ok := flagAliases[name]; ok {
+		name = n
+	}
+	return pflag.NormalizedName(name)
+}
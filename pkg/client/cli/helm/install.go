@@ -53,6 +53,7 @@ type Request struct {
 	CreateNamespace bool
 	Crds            bool
 	NoHooks         bool
+	DryRun          bool
 }
 
 func (hr *Request) Run(ctx context.Context, cr *connector.ConnectRequest) error {
@@ -116,6 +117,9 @@ func (hr *Request) Run(ctx context.Context, cr *connector.ConnectRequest) error
 		updatedResource = "Telepresence CRDs"
 	}
 
+	if hr.DryRun {
+		msg += " (dry run)"
+	}
 	ioutil.Printf(dos.Stdout(ctx), "\n%s %s successfully\n", updatedResource, msg)
 	return nil
 }
@@ -222,9 +226,14 @@ func installNew(
 	install.Atomic = true
 	install.CreateNamespace = req.CreateNamespace
 	install.DisableHooks = req.NoHooks
+	install.DryRun = req.DryRun
+	install.HideSecret = req.DryRun
 	return timedRun(ctx, func(timeout time.Duration) error {
 		install.Timeout = timeout
-		_, err := install.Run(chrt, values)
+		rel, err := install.Run(chrt, values)
+		if err == nil && req.DryRun {
+			printManifest(ctx, rel)
+		}
 		return err
 	})
 }
@@ -245,13 +254,25 @@ func upgradeExisting(
 	upgrade.ResetValues = req.ResetValues
 	upgrade.ReuseValues = req.ReuseValues
 	upgrade.DisableHooks = req.NoHooks
+	upgrade.DryRun = req.DryRun
+	upgrade.HideSecret = req.DryRun
 	return timedRun(ctx, func(timeout time.Duration) error {
 		upgrade.Timeout = timeout
-		_, err := upgrade.Run(releaseName, chrt, values)
+		rel, err := upgrade.Run(releaseName, chrt, values)
+		if err == nil && req.DryRun {
+			printManifest(ctx, rel)
+		}
 		return err
 	})
 }
 
+// printManifest prints the manifest of a release that was rendered but not applied because of a dry run.
+func printManifest(ctx context.Context, rel *release.Release) {
+	if rel != nil {
+		ioutil.Println(dos.Stdout(ctx), rel.Manifest)
+	}
+}
+
 func uninstallExisting(ctx context.Context, helmConfig *action.Configuration, releaseName, namespace string, req *Request) error {
 	dlog.Infof(ctx, "Uninstalling %s in namespace %s", releaseName, namespace)
 	uninstall := action.NewUninstall(helmConfig)
@@ -321,23 +342,29 @@ func EnsureTrafficManager(ctx context.Context, clientGetter genericclioptions.RE
 	return err
 }
 
+// cleanFailedState uninstalls a release that is stuck or failed so that it can be installed again. Nothing is
+// removed during a dry run. The release that would have been removed is reported instead.
+func cleanFailedState(ctx context.Context, helmConfig *action.Configuration, releaseName, namespace string, req *Request) error {
+	if req.DryRun {
+		ioutil.Printf(dos.Stdout(ctx), "Would remove leftover release %s in namespace %s (dry run)\n", releaseName, namespace)
+		return nil
+	}
+	urq := Request{
+		Type:    Uninstall,
+		NoHooks: true,
+	}
+	err := uninstallExisting(ctx, helmConfig, releaseName, namespace, &urq)
+	if err != nil {
+		err = fmt.Errorf("failed to clean up leftover release history: %w", err)
+	}
+	return err
+}
+
 // EnsureTrafficManager ensures the traffic manager is installed.
 func ensureIsInstalled(
 	ctx context.Context, clientGetter genericclioptions.RESTClientGetter, crd bool,
 	releaseName, namespace string, req *Request,
 ) error {
-	cleanFailedState := func(helmConfig *action.Configuration) error {
-		urq := Request{
-			Type:    Uninstall,
-			NoHooks: true,
-		}
-		err := uninstallExisting(ctx, helmConfig, releaseName, namespace, &urq)
-		if err != nil {
-			err = fmt.Errorf("failed to clean up leftover release history: %w", err)
-		}
-		return err
-	}
-
 	timeout := client.GetConfig(ctx).Timeouts().Get(client.TimeoutHelm)
 	existing, helmConfig, err := isInstalled(ctx, timeout, clientGetter, releaseName, namespace)
 	if err != nil {
@@ -346,7 +373,7 @@ func ensureIsInstalled(
 		}
 		dlog.Infof(ctx, "ensureIsInstalled(namespace=%q): current install is has been in a pending state for longer than `timeouts.helm` (%v); "+
 			"assuming it's stuck and will attempt uninstall", namespace, timeout)
-		err = cleanFailedState(helmConfig)
+		err = cleanFailedState(ctx, helmConfig, releaseName, namespace, req)
 		if err != nil {
 			return err
 		}
@@ -358,7 +385,7 @@ func ensureIsInstalled(
 	if existing != nil && (existing.Info.Status != release.StatusDeployed) {
 		dlog.Infof(ctx, "ensureIsInstalled(namespace=%q): current status (status=%q, desc=%q) is not %q, so assuming it's corrupt or stuck; removing it...",
 			namespace, existing.Info.Status, existing.Info.Description, release.StatusDeployed)
-		err = cleanFailedState(helmConfig)
+		err = cleanFailedState(ctx, helmConfig, releaseName, namespace, req)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,61 @@
+package helm
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
+	"github.com/telepresenceio/telepresence/v2/pkg/dos"
+)
+
+func failedReleaseConfig(t *testing.T) *action.Configuration {
+	cfg := &action.Configuration{
+		Releases:     storage.Init(driver.NewMemory()),
+		KubeClient:   &kubefake.PrintingKubeClient{Out: &bytes.Buffer{}},
+		Capabilities: chartutil.DefaultCapabilities,
+		Log:          t.Logf,
+	}
+	require.NoError(t, cfg.Releases.Create(&release.Release{
+		Name:      trafficManagerReleaseName,
+		Namespace: "ambassador",
+		Version:   1,
+		Info:      &release.Info{Status: release.StatusFailed},
+		Chart:     &chart.Chart{Metadata: &chart.Metadata{Name: trafficManagerReleaseName, Version: "2.20.0"}},
+	}))
+	return cfg
+}
+
+func Test_cleanFailedState(t *testing.T) {
+	ctx := client.WithConfig(context.Background(), client.GetDefaultConfig())
+
+	t.Run("dry run", func(t *testing.T) {
+		cfg := failedReleaseConfig(t)
+		out := &bytes.Buffer{}
+		err := cleanFailedState(dos.WithStdout(ctx, out), cfg, trafficManagerReleaseName, "ambassador", &Request{DryRun: true})
+		require.NoError(t, err)
+		assert.Equal(t, "Would remove leftover release traffic-manager in namespace ambassador (dry run)\n", out.String())
+
+		rel, err := cfg.Releases.Last(trafficManagerReleaseName)
+		require.NoError(t, err)
+		assert.Equal(t, release.StatusFailed, rel.Info.Status)
+	})
+
+	t.Run("uninstall", func(t *testing.T) {
+		cfg := failedReleaseConfig(t)
+		require.NoError(t, cleanFailedState(ctx, cfg, trafficManagerReleaseName, "ambassador", &Request{}))
+
+		_, err := cfg.Releases.Last(trafficManagerReleaseName)
+		assert.ErrorIs(t, err, driver.ErrReleaseNotFound)
+	})
+}
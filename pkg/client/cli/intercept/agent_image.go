@@ -0,0 +1,61 @@
+package intercept
+
+import (
+	"context"
+
+	"github.com/distribution/reference"
+	empty "google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/daemon"
+	"github.com/telepresenceio/telepresence/v2/pkg/dos"
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+	"github.com/telepresenceio/telepresence/v2/pkg/ioutil"
+)
+
+// validateAgentImage checks that the given --agent-image is a valid image reference that
+// includes a tag or a digest.
+func validateAgentImage(image string) error {
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return errcat.User.Newf("invalid --agent-image %q: %v", image, err)
+	}
+	switch ref.(type) {
+	case reference.Tagged, reference.Digested:
+		return nil
+	default:
+		return errcat.User.Newf("invalid --agent-image %q: a tag or a digest is required", image)
+	}
+}
+
+// sameImage returns true if the two image references are equal after normalization.
+func sameImage(a, b string) bool {
+	if a == b {
+		return true
+	}
+	ra, err := reference.ParseNormalizedNamed(a)
+	if err != nil {
+		return false
+	}
+	rb, err := reference.ParseNormalizedNamed(b)
+	if err != nil {
+		return false
+	}
+	return ra.String() == rb.String()
+}
+
+// warnAgentImageOverride prints a warning when the given image differs from the agent image
+// that the traffic-manager is configured to use.
+func warnAgentImageOverride(ctx context.Context, image string) {
+	af, err := daemon.GetUserClient(ctx).AgentImageFQN(ctx, &empty.Empty{})
+	if err != nil {
+		dlog.Debugf(ctx, "unable to retrieve the traffic-manager's agent image: %v", err)
+		return
+	}
+	if def := af.FQN; def != "" && !sameImage(def, image) {
+		ioutil.Printf(dos.Stderr(ctx),
+			"Warning: the agent image %s differs from the traffic-manager's default %s. "+
+				"The workload keeps that agent until it is uninstalled or intercepted with another --agent-image\n",
+			image, def)
+	}
+}
@@ -0,0 +1,39 @@
+package intercept
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_validateAgentImage(t *testing.T) {
+	tests := []struct {
+		image   string
+		wantErr bool
+	}{
+		{"ghcr.io/telepresenceio/tel2:2.20.0", false},
+		{"docker.io/datawire/tel2:2.20.0", false},
+		{"tel2:2.20.0", false},
+		{"localhost:5000/tel2@sha256:" + "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", false},
+		{"ghcr.io/telepresenceio/tel2", true},
+		{"ghcr.io/Telepresenceio/tel2:2.20.0", true},
+		{"ghcr.io/telepresenceio/tel2:bad tag", true},
+		{"", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			err := validateAgentImage(tt.image)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_sameImage(t *testing.T) {
+	assert.True(t, sameImage("tel2:2.20.0", "docker.io/library/tel2:2.20.0"))
+	assert.True(t, sameImage("ghcr.io/telepresenceio/tel2:2.20.0", "ghcr.io/telepresenceio/tel2:2.20.0"))
+	assert.False(t, sameImage("ghcr.io/telepresenceio/tel2:2.20.0", "ghcr.io/telepresenceio/tel2:2.20.1"))
+}
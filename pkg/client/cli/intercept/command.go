@@ -1,10 +1,15 @@
 package intercept
 
 import (
+	"context"
+	"crypto/x509"
+	"path"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/datawire/dlib/dlog"
 	"github.com/telepresenceio/telepresence/rpc/v2/connector"
@@ -12,13 +17,16 @@ import (
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/connect"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/daemon"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/output"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/docker"
 	"github.com/telepresenceio/telepresence/v2/pkg/dos"
 	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+	"github.com/telepresenceio/telepresence/v2/pkg/tunnel"
 )
 
 type Command struct {
 	Name           string // Command[0] || `${Command[0]}-${--namespace}` // which depends on a combinationof --workload and --namespace
-	AgentName      string // --workload || Command[0] // only valid if !localOnly
+	AgentName      string // --workload || Command[0]
+	Selector       string // --selector // resolved to AgentName by the user daemon
 	Port           string // --port
 	ServiceName    string // --service
 	ContainerName  string // --container
@@ -27,18 +35,39 @@ type Command struct {
 
 	Replace bool // whether --replace was passed
 
-	EnvFile   string // --env-file
-	EnvSyntax EnvironmentSyntax
-	EnvJSON   string   // --env-json
-	Mount     string   // --mount // "true", "false", or desired mount point // only valid if !localOnly
-	MountSet  bool     // whether --mount was passed
-	ToPod     []string // --to-pod
+	AgentImage string // --agent-image
+
+	FromFile string // --from-file
+
+	Wait time.Duration // --wait
+
+	TargetTLS         bool   // --target-tls
+	TargetTLSInsecure bool   // --target-tls-insecure
+	TargetTLSCA       string // --target-tls-ca
+	TargetTLSCAPEM    string // the contents of the --target-tls-ca file
+
+	Throttle     string // --throttle
+	ThrottleRate int64  // --throttle in bytes per second
+
+	Delay time.Duration // --delay
+
+	EnvFile     string // --env-file
+	EnvSyntax   EnvironmentSyntax
+	EnvJSON     string            // --env-json
+	DetailedEnv bool              // --detailed-env
+	Mount       string            // --mount // "true", "false", or desired mount point
+	MountSet    bool              // whether --mount was passed
+	MountMap    map[string]string // --mount <remote path>=<local dir>[,...]
+	MountRO     bool              // --mount-ro
+	NoMount     bool              // --no-volume-mount
+	ToPod       []string          // --to-pod
 
 	DockerRun          bool     // --docker-run
 	DockerBuild        string   // --docker-build DIR | URL
 	DockerBuildOptions []string // --docker-build-opt key=value, // Optional flag to docker build can be repeated (but not comma separated)
 	DockerDebug        string   // --docker-debug DIR | URL
 	DockerMount        string   // --docker-mount // where to mount in a docker container. Defaults to mount unless mount is "true" or "false".
+	ContainerRuntime   string   // --container-runtime docker|podman
 	Cmdline            []string // Command[1:]
 
 	Mechanism       string // --mechanism tcp
@@ -46,16 +75,19 @@ type Command struct {
 	ExtendedInfo    []byte
 	WaitMessage     string // Message printed when a containerized intercept handler is started and waiting for an interrupt
 	FormattedOutput bool
-	DetailedOutput  bool
 	Silent          bool
 }
 
 func (a *Command) AddFlags(cmd *cobra.Command) {
 	flagSet := cmd.Flags()
 	flagSet.StringVarP(&a.AgentName, "workload", "w", "", "Name of workload (Deployment, ReplicaSet) to intercept, if different from <name>")
+	flagSet.StringVarP(&a.Selector, "selector", "l", "", ``+
+		`Label selector (e.g. app=checkout) that matches the pod template of the workload to intercept. `+
+		`Must match exactly one workload. Cannot be combined with --workload`)
 	flagSet.StringVarP(&a.Port, "port", "p", "", ``+
 		`Local port to forward to. If intercepting a service with multiple ports, `+
 		`use <local port>:<svcPortIdentifier>, where the identifier is the port name or port number. `+
+		`A port name alone, e.g. --port http, uses the number of the container port that the name resolves to as the local port. `+
 		`With --docker-run and a daemon that doesn't run in docker', use <local port>:<container port> or `+
 		`<local port>:<container port>:<svcPortIdentifier>.`,
 	)
@@ -76,15 +108,26 @@ func (a *Command) AddFlags(cmd *cobra.Command) {
 	flagSet.Var(&a.EnvSyntax, "env-syntax", `Syntax used for env-file. One of `+EnvSyntaxUsage())
 
 	flagSet.StringVarP(&a.EnvJSON, "env-json", "j", "", `Also emit the remote environment to a file as a JSON blob.`)
+	flagSet.BoolVar(&a.DetailedEnv, "detailed-env", false, ``+
+		`Add TELEPRESENCE_POD, TELEPRESENCE_POD_IP, TELEPRESENCE_NODE, and TELEPRESENCE_NAMESPACE to the environment, `+
+		`describing the intercepted pod`)
 
 	flagSet.StringVar(&a.Mount, "mount", "true", ``+
 		`The absolute path for the root directory where volumes will be mounted, $TELEPRESENCE_ROOT. Use "true" to `+
-		`have Telepresence pick a random mount point (default). Use "false" to disable filesystem mounting entirely.`)
+		`have Telepresence pick a random mount point (default). Use "false" to disable filesystem mounting entirely. `+
+		`Use <remote path>=<local dir>, separated by comma, to mount remote paths on distinct local directories, `+
+		`e.g. '--mount /var/run/secrets=/tmp/secrets'`)
+
+	flagSet.BoolVar(&a.NoMount, "no-volume-mount", false, ``+
+		`Do not mount remote volumes. Same as --mount=false`)
+
+	flagSet.BoolVar(&a.MountRO, "mount-ro", false, ``+
+		`Mount the remote volumes read-only. Writes to the mounted volumes will fail with EROFS`)
 
 	flagSet.StringSliceVar(&a.ToPod, "to-pod", []string{}, ``+
 		`An additional port to forward from the intercepted pod, will be made available at localhost:PORT `+
 		`Use this to, for example, access proxy/helper sidecars in the intercepted pod. The default protocol is TCP. `+
-		`Use <port>/UDP for UDP ports`)
+		`Use <port>/UDP for UDP ports. Use <address>:[<local port>:]<port> to bind to a specific local address`)
 
 	flagSet.BoolVar(&a.DockerRun, "docker-run", false, ``+
 		`Run a Docker container with intercepted environment, volume mount, by passing arguments after -- to 'docker run', `+
@@ -103,43 +146,162 @@ func (a *Command) AddFlags(cmd *cobra.Command) {
 	flagSet.StringVar(&a.DockerMount, "docker-mount", "", ``+
 		`The volume mount point in docker. Defaults to same as "--mount"`)
 
+	flagSet.StringVar(&a.ContainerRuntime, "container-runtime", "", ``+
+		`The container runtime CLI used by --docker-run, --docker-build, and --docker-debug. One of "docker" or "podman". `+
+		`Detected from the executables in the path when not given`)
+
 	flagSet.StringP("namespace", "n", "", "If present, the namespace scope for this CLI request")
 
 	flagSet.StringVar(&a.Mechanism, "mechanism", "tcp", "Which extension `mechanism` to use")
 
 	flagSet.StringVar(&a.WaitMessage, "wait-message", "", "Message to print when intercept handler has started")
 
-	flagSet.BoolVar(&a.DetailedOutput, "detailed-output", false,
+	// The details are always included when the output is formatted, so the flag is only kept for backward compatibility.
+	flagSet.Bool("detailed-output", false,
 		`Provide very detailed info about the intercept when used together with --output=json or --output=yaml'`)
+	_ = flagSet.MarkDeprecated("detailed-output", "the details are always included when --output is json or yaml")
 
 	flagSet.Uint16Var(&a.LocalMountPort, "local-mount-port", 0,
 		`Do not mount remote directories. Instead, expose this port on localhost to an external mounter`)
 
+	flagSet.BoolVar(&a.TargetTLS, "target-tls", false, ``+
+		`Let the traffic-agent originate TLS to the local target. Use this when the local process only accepts HTTPS. `+
+		`The target's certificate is verified using the name "localhost" when --address is a loopback address, and must `+
+		`be issued by a publicly trusted certificate authority, unless --target-tls-ca is used`)
+
+	flagSet.BoolVar(&a.TargetTLSInsecure, "target-tls-insecure", false, ``+
+		`Like --target-tls, but skip verification of the certificate presented by the local target, e.g. a self-signed one`)
+
+	flagSet.StringVar(&a.TargetTLSCA, "target-tls-ca", "", ``+
+		`Like --target-tls, but verify the certificate presented by the local target using the certificate authorities `+
+		`in this PEM file, e.g. the rootCA.pem of mkcert. The file is read on this machine and sent to the traffic-agent`)
+
+	flagSet.StringVar(&a.Throttle, "throttle", "", ``+
+		`Limit the rate of the intercepted traffic in each direction, e.g. 1mbit or 500kB, to simulate a slow network. `+
+		`Units are bit, kbit, mbit, gbit, B, kB, MB, and GB per second`)
+
+	flagSet.DurationVar(&a.Delay, "delay", 0, ``+
+		`Add latency, e.g. 200ms, to the intercepted traffic before it's forwarded to the workstation, to reproduce timeouts`)
+
+	flagSet.DurationVar(&a.Wait, "wait", 0, ``+
+		`Maximum time to wait for the traffic-agent to become ready and the intercept to become active. `+
+		`Defaults to the timeouts.intercept of the client configuration`)
+
+	flagSet.StringVar(&a.FromFile, "from-file", "", ``+
+		`YAML file with the intercept definition. The keys are the names of the intercept flags, plus "name" for the `+
+		`name of the intercept. Flags given on the command line override the values in the file`)
+
 	flagSet.BoolVarP(&a.Replace, "replace", "", false,
 		`Indicates if the traffic-agent should replace application containers in workload pods. `+
 			`The default behavior is for the agent sidecar to be installed alongside existing containers.`)
+
+	flagSet.StringVar(&a.AgentImage, "agent-image", "", ``+
+		`Fully qualified name of the traffic-agent image to use for this intercept, overriding the image `+
+		`configured in the traffic-manager. The agent remains until the workload is uninstalled`)
 }
 
 func (a *Command) Validate(cmd *cobra.Command, positional []string) error {
-	if len(positional) > 1 && cmd.Flags().ArgsLenAtDash() != 1 {
-		return errcat.User.New("commands to be run with intercept must come after options")
+	var fileName string
+	if a.FromFile != "" {
+		var err error
+		if fileName, err = loadFromFile(cmd.Flags(), a.FromFile); err != nil {
+			return err
+		}
+	}
+	if len(positional) == 0 || cmd.Flags().ArgsLenAtDash() == 0 {
+		// No name argument, so the name must come from the file.
+		if fileName == "" {
+			return errcat.User.New("the name of the intercept must be given as an argument or in the --from-file")
+		}
+		a.Name = fileName
+		a.Cmdline = positional
+	} else {
+		if len(positional) > 1 && cmd.Flags().ArgsLenAtDash() != 1 {
+			return errcat.User.New("commands to be run with intercept must come after options")
+		}
+		a.Name = positional[0]
+		a.Cmdline = positional[1:]
 	}
-	a.Name = positional[0]
-	a.Cmdline = positional[1:]
 	a.FormattedOutput = output.WantsFormatted(cmd)
 
 	if a.LocalMountPort > 0 && client.GetConfig(cmd.Context()).Intercept().UseFtp {
 		return errcat.User.New("only SFTP can be used with --local-mount-port. Client is configured to perform remote mounts using FTP")
 	}
+	if a.MountRO && client.GetConfig(cmd.Context()).Intercept().UseFtp {
+		return errcat.User.New("only SFTP can be used with --mount-ro. Client is configured to perform remote mounts using FTP")
+	}
+	if a.MountRO && a.LocalMountPort > 0 {
+		return errcat.User.New("--mount-ro cannot be used with --local-mount-port, because the volumes are then mounted by " +
+			"something other than Telepresence")
+	}
 
-	// Actually intercepting something
-	if a.AgentName == "" {
+	if a.Selector != "" {
+		if a.AgentName != "" {
+			return errcat.User.New("--selector and --workload are mutually exclusive")
+		}
+		if _, err := labels.Parse(a.Selector); err != nil {
+			return errcat.User.Newf("invalid --selector %q: %v", a.Selector, err)
+		}
+	} else if a.AgentName == "" {
+		// Actually intercepting something
 		a.AgentName = a.Name
 	}
+	if a.Wait < 0 {
+		return errcat.User.New("--wait cannot be negative")
+	}
+	if a.Delay < 0 {
+		return errcat.User.New("--delay cannot be negative")
+	}
+	if a.AgentImage != "" {
+		if err := validateAgentImage(a.AgentImage); err != nil {
+			return err
+		}
+	}
+	if a.TargetTLSCA != "" {
+		if a.TargetTLSInsecure {
+			return errcat.User.New("--target-tls-ca cannot be combined with --target-tls-insecure")
+		}
+		var err error
+		if a.TargetTLSCAPEM, err = readTargetTLSCA(cmd.Context(), a.TargetTLSCA); err != nil {
+			return err
+		}
+	}
+	if a.TargetTLSInsecure || a.TargetTLSCA != "" {
+		a.TargetTLS = true
+	}
+	if a.Throttle != "" {
+		var err error
+		if a.ThrottleRate, err = tunnel.ParseRate(a.Throttle); err != nil {
+			return errcat.User.New(err)
+		}
+	}
 	if a.Port == "" {
 		a.Port = strconv.Itoa(client.GetConfig(cmd.Context()).Intercept().DefaultPort)
 	}
 	a.MountSet = cmd.Flag("mount").Changed
+	if a.NoMount {
+		if a.MountSet {
+			if doMount, err := strconv.ParseBool(a.Mount); err != nil || doMount {
+				return errcat.User.New("--no-volume-mount cannot be combined with --mount")
+			}
+		}
+		if a.MountRO || a.DockerMount != "" || a.LocalMountPort > 0 {
+			return errcat.User.New("--no-volume-mount cannot be combined with --mount-ro, --docker-mount, or --local-mount-port")
+		}
+	}
+	if a.MountSet && strings.ContainsRune(a.Mount, '=') {
+		mm, err := parseMountMap(a.Mount)
+		if err != nil {
+			return err
+		}
+		if a.LocalMountPort > 0 {
+			return errcat.User.New("--local-mount-port cannot be used with a --mount mapping")
+		}
+		if a.DockerMount != "" {
+			return errcat.User.New("--docker-mount cannot be used with a --mount mapping")
+		}
+		a.MountMap = mm
+	}
 	drCount := 0
 	if a.DockerRun {
 		drCount++
@@ -158,6 +320,13 @@ func (a *Command) Validate(cmd *cobra.Command, positional []string) error {
 		if err := a.ValidateDockerArgs(); err != nil {
 			return err
 		}
+		rt, err := docker.ResolveRuntime(a.ContainerRuntime)
+		if err != nil {
+			return errcat.User.New(err)
+		}
+		a.ContainerRuntime = rt
+	} else if a.ContainerRuntime != "" {
+		return errcat.User.New("--container-runtime can only be used with --docker-run, --docker-build, or --docker-debug")
 	}
 	return nil
 }
@@ -223,20 +392,78 @@ func (a *Command) ValidArgs(cmd *cobra.Command, args []string, toComplete string
 	return list, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
 }
 
-// GetMountPoint returns a boolean indicating if mounts are enabled or not, and path
-// indicating a mount point.
-func (a *Command) GetMountPoint() (bool, string) {
-	if !a.MountSet {
+// parseMountMap parses a comma separated list of <remote path>=<local dir> mappings.
+// readTargetTLSCA reads the PEM file given by --target-tls-ca, and verifies that it contains certificates.
+func readTargetTLSCA(ctx context.Context, path string) (string, error) {
+	data, err := dos.ReadFile(ctx, path)
+	if err != nil {
+		return "", errcat.User.Newf("unable to read --target-tls-ca: %v", err)
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM(data) {
+		return "", errcat.User.Newf("--target-tls-ca %s contains no valid PEM encoded certificates", path)
+	}
+	return string(data), nil
+}
+
+func parseMountMap(s string) (map[string]string, error) {
+	mm := make(map[string]string)
+	for _, m := range strings.Split(s, ",") {
+		remote, local, ok := strings.Cut(m, "=")
+		if !(ok && path.IsAbs(remote) && local != "") {
+			return nil, errcat.User.Newf("invalid --mount mapping %q, must be <remote path>=<local dir>", m)
+		}
+		remote = path.Clean(remote)
+		if _, dup := mm[remote]; dup {
+			return nil, errcat.User.Newf("remote path %s is mapped more than once", remote)
+		}
+		mm[remote] = local
+	}
+	return mm, nil
+}
+
+// MountMode controls if and how remote volumes are mounted.
+type MountMode int
+
+const (
+	// MountAuto mounts the remote volumes when possible. A warning is logged when it isn't.
+	MountAuto MountMode = iota
+
+	// MountOn mounts the remote volumes. The intercept fails when that isn't possible.
+	MountOn
+
+	// MountOff disables mounting of remote volumes.
+	MountOff
+)
+
+// GetMountMode returns the MountMode, and a path indicating the mount point when one was given.
+func (a *Command) GetMountMode() (MountMode, string) {
+	switch {
+	case a.NoMount:
+		return MountOff, ""
+	case len(a.MountMap) > 0:
+		// Mount points are given by the mapping
+		return MountOn, ""
+	case !a.MountSet:
 		// Default is that mount is enabled and the path is unspecified
-		return true, ""
+		return MountAuto, ""
 	}
 	if doMount, err := strconv.ParseBool(a.Mount); err == nil {
 		// Boolean flag, path unspecified
-		return doMount, ""
+		if doMount {
+			return MountOn, ""
+		}
+		return MountOff, ""
 	}
 	if len(a.Mount) == 0 {
 		// Let explicit --mount= have the same meaning as --mount=false
-		return false, ""
+		return MountOff, ""
 	}
-	return true, a.Mount
+	return MountOn, a.Mount
+}
+
+// GetMountPoint returns a boolean indicating if mounts are enabled or not, and path
+// indicating a mount point.
+func (a *Command) GetMountPoint() (bool, string) {
+	mode, mountPoint := a.GetMountMode()
+	return mode != MountOff, mountPoint
 }
@@ -0,0 +1,167 @@
+package intercept
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseMountMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		mount   string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			"single",
+			"/var/run/secrets=/tmp/secrets",
+			map[string]string{"/var/run/secrets": "/tmp/secrets"},
+			false,
+		},
+		{
+			"multiple",
+			"/var/run/secrets=/tmp/secrets,/etc/config/=config",
+			map[string]string{"/var/run/secrets": "/tmp/secrets", "/etc/config": "config"},
+			false,
+		},
+		{
+			"relative remote",
+			"var/run/secrets=/tmp/secrets",
+			nil,
+			true,
+		},
+		{
+			"missing local",
+			"/var/run/secrets=",
+			nil,
+			true,
+		},
+		{
+			"missing mapping",
+			"/var/run/secrets=/tmp/secrets,/tmp/other",
+			nil,
+			true,
+		},
+		{
+			"duplicate",
+			"/var/run/secrets=/tmp/a,/var/run/secrets/=/tmp/b",
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseMountMap(tt.mount)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCommand_GetMountMode(t *testing.T) {
+	tests := []struct {
+		name      string
+		cmd       Command
+		wantMode  MountMode
+		wantPoint string
+	}{
+		{
+			"default",
+			Command{Mount: "true"},
+			MountAuto,
+			"",
+		},
+		{
+			"explicit true",
+			Command{Mount: "true", MountSet: true},
+			MountOn,
+			"",
+		},
+		{
+			"explicit false",
+			Command{Mount: "false", MountSet: true},
+			MountOff,
+			"",
+		},
+		{
+			"empty",
+			Command{Mount: "", MountSet: true},
+			MountOff,
+			"",
+		},
+		{
+			"path",
+			Command{Mount: "/tmp/mnt", MountSet: true},
+			MountOn,
+			"/tmp/mnt",
+		},
+		{
+			"mapping",
+			Command{Mount: "/var/run/secrets=/tmp/secrets", MountSet: true, MountMap: map[string]string{"/var/run/secrets": "/tmp/secrets"}},
+			MountOn,
+			"",
+		},
+		{
+			"no volume mount",
+			Command{Mount: "true", NoMount: true},
+			MountOff,
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mode, mountPoint := tt.cmd.GetMountMode()
+			assert.Equal(t, tt.wantMode, mode)
+			assert.Equal(t, tt.wantPoint, mountPoint)
+		})
+	}
+}
+
+func Test_readTargetTLSCA(t *testing.T) {
+	const caPEM = `-----BEGIN CERTIFICATE-----
+MIIBUzCB+6ADAgECAgEBMAoGCCqGSM49BAMCMBIxEDAOBgNVBAMTB3Rlc3QtY2Ew
+HhcNMjYwMTAxMDAwMDAwWhcNMzYwMTAxMDAwMDAwWjASMRAwDgYDVQQDEwd0ZXN0
+LWNhMFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEXUUENloAC8/tG4awaEMddy8J
+hT7kGG+cgCLpfE0OQeR2ibxjky1irXaInAorcoF55O9uyf3Kdy8CMVMQNqCmVaNC
+MEAwDgYDVR0PAQH/BAQDAgIEMA8GA1UdEwEB/wQFMAMBAf8wHQYDVR0OBBYEFPGR
+hn4gNRI+AAXoHttwQI3UoQ1aMAoGCCqGSM49BAMCA0cAMEQCIDDUr5YBbd39OJCh
+x9xSN7om5atSIaIU+QXQj2rLB5aPAiBtGiCg0WbeCUXDNgM7wRgNUuezUlD512dI
+V1DbhcVaMw==
+-----END CERTIFICATE-----
+`
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{"valid", write("ca.pem", caPEM), false},
+		{"not PEM", write("garbage.pem", "garbage"), true},
+		{"missing", filepath.Join(dir, "missing.pem"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readTargetTLSCA(context.Background(), tt.path)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, caPEM, got)
+			}
+		})
+	}
+}
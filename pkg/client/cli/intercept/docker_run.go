@@ -1,21 +1,26 @@
 package intercept
 
 import (
+	"bufio"
 	"context"
 	"fmt"
+	"io"
 	"math"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"sort"
 	"strings"
 	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/datawire/dlib/dexec"
 	"github.com/datawire/dlib/dlog"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/daemon"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/flags"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/output"
-	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/spinner"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/docker"
 	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
 	"github.com/telepresenceio/telepresence/v2/pkg/ioutil"
@@ -48,24 +53,59 @@ func (s *state) prepareDockerRun(ctx context.Context) error {
 			`the string "IMAGE", acting as a placeholder for image ID, must be included after "--" when using "--docker-build", so ` +
 			`that flags intended for docker run can be distinguished from the command and arguments intended for the container.`)
 	}
-	opts := make([]string, len(s.DockerBuildOptions))
+	opts := make([]string, len(s.DockerBuildOptions), len(s.DockerBuildOptions)+2)
 	for i, opt := range s.DockerBuildOptions {
 		opts[i] = "--" + opt
 	}
-	spin := spinner.New(ctx, "building docker image")
-	imageID, err := docker.BuildImage(ctx, buildContext, opts)
+	var tmpTag string
+	if !hasTagOption(s.DockerBuildOptions) {
+		// Nobody will be able to refer to the image once the container ends, so it will be removed. The build
+		// might be a cache hit that yields an image that the user already has, so a temporary tag is added and
+		// only that tag is removed. The image itself is then only deleted when no other tag refers to it.
+		tmpTag = temporaryImageTag()
+		opts = append(opts, "--tag", tmpTag)
+	}
+	// The build output is streamed to stderr, so that it doesn't interfere with formatted output.
+	imageID, err := docker.BuildImage(ctx, buildContext, opts, output.Err(ctx))
 	if err != nil {
-		return spin.Error(err)
+		return errcat.User.Newf("docker build failed: %w", err)
 	}
 	if idx < 0 {
 		s.Cmdline = []string{imageID}
 	} else {
 		s.Cmdline[idx] = imageID
 	}
-	spin.DoneMsg("image built successfully")
+	s.builtImage = tmpTag
 	return nil
 }
 
+// temporaryImageTag returns a unique tag for an image built using --docker-build.
+func temporaryImageTag() string {
+	return "telepresence-build:" + uuid.NewString()
+}
+
+// removeBuiltImage removes the temporary tag of the image that was built using --docker-build, unless the user
+// tagged it.
+func (s *state) removeBuiltImage(ctx context.Context) {
+	if s.builtImage == "" {
+		return
+	}
+	if err := docker.RemoveImage(ctx, s.builtImage); err != nil {
+		ioutil.Printf(output.Err(ctx), "unable to remove image %s: %v\n", s.builtImage, err)
+	}
+	s.builtImage = ""
+}
+
+// hasTagOption returns true if the given --docker-build-opt options include a tag.
+func hasTagOption(opts []string) bool {
+	for _, opt := range opts {
+		if strings.HasPrefix(opt, "tag=") || strings.HasPrefix(opt, "t=") {
+			return true
+		}
+	}
+	return false
+}
+
 var dockerBoolFlags = map[string]bool{ //nolint:gochecknoglobals // this is a constant
 	"--detach":           true,
 	"--init":             true,
@@ -148,7 +188,10 @@ func (dr *dockerRun) wait(ctx context.Context) error {
 		killTimer.Reset(2 * time.Second)
 		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 2*time.Second)
 		defer cancel()
-		if err := docker.StopContainer(docker.EnableClient(ctx), dr.name); err != nil {
+		// The runtime CLI is used, because the docker Engine API isn't available for other runtimes.
+		cmd := proc.StdCommand(ctx, docker.Runtime(ctx), "stop", dr.name)
+		cmd.Stdout = io.Discard
+		if err := cmd.Run(); err != nil {
 			dlog.Error(ctx, err)
 		}
 	}()
@@ -177,11 +220,61 @@ func (s *state) getContainerName(args []string) (string, []string, error) {
 	return name, args, nil
 }
 
-func (s *state) startInDocker(ctx context.Context, name, envFile string, args []string) *dockerRun {
-	ourArgs := []string{
-		"run",
-		"--env-file", envFile,
+// volumeArg returns the argument for the docker run -v flag that mounts src at dst, adding the
+// read-only option when --mount-ro is in effect.
+func (s *state) volumeArg(src, dst string) string {
+	if s.MountRO {
+		return fmt.Sprintf("%s:%s:ro", src, dst)
 	}
+	return fmt.Sprintf("%s:%s", src, dst)
+}
+
+// podVolumeArgs returns docker run -v arguments that mount each of the remotely mounted directories at the
+// same path in the container as in the intercepted pod, so that code that reads files such as the service
+// account token finds them where it expects them. Nothing is returned unless the remote volumes are mounted,
+// and directories that are already mapped using --mount are skipped.
+func (s *state) podVolumeArgs() []string {
+	if s.mountDisabled || s.mountPoint == "" || s.info == nil || s.info.Mount == nil {
+		return nil
+	}
+	var args []string
+	for _, remote := range s.info.Mount.Mounts {
+		if _, ok := s.mountPoints[remote]; ok {
+			continue
+		}
+		args = append(args, "-v", s.volumeArg(filepath.Join(s.mountPoint, filepath.FromSlash(remote)), remote))
+	}
+	return args
+}
+
+// writeDockerEnvFileAndClose writes the intercept environment to the given file using the syntax of a docker run
+// --env-file, which takes each value verbatim. It returns docker run -e arguments for the variables that the file
+// can't express because their values span multiple lines.
+func (s *state) writeDockerEnvFileAndClose(file *os.File) (args []string, err error) {
+	defer file.Close()
+	w := bufio.NewWriter(file)
+
+	keys := make([]string, 0, len(s.env))
+	for k := range s.env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := s.env[k]
+		if strings.IndexByte(v, '\n') >= 0 {
+			args = append(args, "-e", k+"="+v)
+			continue
+		}
+		if _, err = fmt.Fprintf(w, "%s=%s\n", k, v); err != nil {
+			return nil, err
+		}
+	}
+	return args, w.Flush()
+}
+
+func (s *state) startInDocker(ctx context.Context, name string, envArgs, args []string) *dockerRun {
+	ourArgs := append([]string{"run"}, envArgs...)
 	dr := &dockerRun{name: name}
 
 	if s.DockerDebug != "" {
@@ -212,9 +305,18 @@ func (s *state) startInDocker(ctx context.Context, name, envFile string, args []
 			}
 		}
 		if dockerMount != "" {
-			ourArgs = append(ourArgs, "-v", fmt.Sprintf("%s:%s", s.mountPoint, dockerMount))
+			ourArgs = append(ourArgs, "-v", s.volumeArg(s.mountPoint, dockerMount))
+		}
+		for remote, local := range s.mountPoints {
+			// Mapped mounts appear at their remote paths in the container
+			ourArgs = append(ourArgs, "-v", s.volumeArg(local, remote))
 		}
+		ourArgs = append(ourArgs, s.podVolumeArgs()...)
 	} else {
+		if rt := docker.Runtime(ctx); rt != docker.RuntimeDocker {
+			dr.err = errcat.User.Newf("the %s container runtime cannot be used with a daemon that runs in a docker container", rt)
+			return dr
+		}
 		daemonName := ud.DaemonID().ContainerName()
 		ourArgs = append(ourArgs, "--network", "container:"+daemonName)
 
@@ -232,13 +334,13 @@ func (s *state) startInDocker(ctx context.Context, name, envFile string, args []
 					return dr
 				}
 				for i, vol := range dr.volumes {
-					ourArgs = append(ourArgs, "-v", fmt.Sprintf("%s:%s", vol, m.Mounts[i]))
+					ourArgs = append(ourArgs, "-v", s.volumeArg(vol, m.Mounts[i]))
 				}
 			}
 		}
 	}
 
 	args = append(ourArgs, args...)
-	dr.cmd, dr.err = proc.Start(context.WithoutCancel(ctx), nil, "docker", args...)
+	dr.cmd, dr.err = proc.Start(context.WithoutCancel(ctx), nil, docker.Runtime(ctx), args...)
 	return dr
 }
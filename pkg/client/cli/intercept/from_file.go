@@ -0,0 +1,71 @@
+package intercept
+
+import (
+	"os"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+)
+
+// nameKey is the key in an intercept file that holds the name of the intercept.
+const nameKey = "name"
+
+// loadFromFile reads an intercept definition from the YAML file at the given path and applies it to the given flags.
+// The keys in the file are flag names, except for "name", which is the name of the intercept. Flags that were given
+// on the command line take precedence over values in the file. The name of the intercept is returned.
+func loadFromFile(flags *pflag.FlagSet, path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", errcat.User.Newf("unable to read intercept file: %w", err)
+	}
+	var doc yaml.Node
+	if err = yaml.Unmarshal(data, &doc); err != nil {
+		return "", errcat.User.Newf("%s: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		return "", errcat.User.Newf("%s: file is empty", path)
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return "", errcat.User.Newf("%s:%d: expected a mapping of intercept fields", path, root.Line)
+	}
+	var name string
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		k, v := root.Content[i], root.Content[i+1]
+		if k.Value == nameKey {
+			if v.Kind != yaml.ScalarNode || v.Value == "" {
+				return "", errcat.User.Newf("%s:%d: %s must be a non-empty string", path, v.Line, nameKey)
+			}
+			name = v.Value
+			continue
+		}
+		f := flags.Lookup(k.Value)
+		if f == nil || k.Value == "from-file" {
+			return "", errcat.User.Newf("%s:%d: unknown intercept field %q", path, k.Line, k.Value)
+		}
+		if f.Changed {
+			// Flags given on the command line take precedence.
+			continue
+		}
+		var vs []*yaml.Node
+		switch v.Kind {
+		case yaml.ScalarNode:
+			vs = []*yaml.Node{v}
+		case yaml.SequenceNode:
+			vs = v.Content
+		default:
+			return "", errcat.User.Newf("%s:%d: the value of %s must be a scalar or a sequence", path, v.Line, k.Value)
+		}
+		for _, sv := range vs {
+			if sv.Kind != yaml.ScalarNode {
+				return "", errcat.User.Newf("%s:%d: the value of %s must be a scalar or a sequence", path, sv.Line, k.Value)
+			}
+			if err = flags.Set(k.Value, sv.Value); err != nil {
+				return "", errcat.User.Newf("%s:%d: invalid value for %s: %v", path, sv.Line, k.Value, err)
+			}
+		}
+	}
+	return name, nil
+}
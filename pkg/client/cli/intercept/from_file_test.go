@@ -0,0 +1,68 @@
+package intercept
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_loadFromFile(t *testing.T) {
+	writeFile := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "intercept.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+	newCommand := func(args ...string) (*Command, *cobra.Command) {
+		a := &Command{}
+		cmd := &cobra.Command{}
+		a.AddFlags(cmd)
+		require.NoError(t, cmd.Flags().Parse(args))
+		return a, cmd
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		path := writeFile(t, `
+name: echo
+workload: echo-easy
+port: "8080:http"
+mount: "false"
+to-pod:
+  - 8081
+  - 8082/UDP
+`)
+		a, cmd := newCommand("--port", "9090")
+		name, err := loadFromFile(cmd.Flags(), path)
+		require.NoError(t, err)
+		assert.Equal(t, "echo", name)
+		assert.Equal(t, "echo-easy", a.AgentName)
+		assert.Equal(t, "9090", a.Port) // the flag takes precedence
+		assert.Equal(t, "false", a.Mount)
+		assert.True(t, cmd.Flag("mount").Changed)
+		assert.Equal(t, []string{"8081", "8082/UDP"}, a.ToPod)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		path := writeFile(t, "name: echo\nports: 8080\n")
+		_, cmd := newCommand()
+		_, err := loadFromFile(cmd.Flags(), path)
+		assert.ErrorContains(t, err, `:2: unknown intercept field "ports"`)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		path := writeFile(t, "name: echo\n\nmount-ro: maybe\n")
+		_, cmd := newCommand()
+		_, err := loadFromFile(cmd.Flags(), path)
+		assert.ErrorContains(t, err, ":3: invalid value for mount-ro")
+	})
+
+	t.Run("not a mapping", func(t *testing.T) {
+		path := writeFile(t, "- echo\n")
+		_, cmd := newCommand()
+		_, err := loadFromFile(cmd.Flags(), path)
+		assert.ErrorContains(t, err, ":1: expected a mapping")
+	})
+}
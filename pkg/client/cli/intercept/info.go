@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/telepresenceio/telepresence/rpc/v2/manager"
 	"github.com/telepresenceio/telepresence/v2/pkg/client"
@@ -27,6 +29,9 @@ type Mount struct {
 	PodIP     string   `json:"pod_ip,omitempty"        yaml:"pod_ip,omitempty"`
 	Port      int32    `json:"port,omitempty"          yaml:"port,omitempty"`
 	Mounts    []string `json:"mounts,omitempty"        yaml:"mounts,omitempty"`
+
+	// LocalDirs maps remote paths to local directories when the mount point was given as a mapping
+	LocalDirs map[string]string `json:"local_dirs,omitempty"    yaml:"local_dirs,omitempty"`
 }
 
 type Info struct {
@@ -50,9 +55,29 @@ type Info struct {
 	PreviewURL    string            `json:"preview_url,omitempty"     yaml:"preview_url,omitempty"`
 	Ingress       *Ingress          `json:"ingress,omitempty"         yaml:"ingress,omitempty"`
 	PodIP         string            `json:"pod_ip,omitempty"          yaml:"pod_ip,omitempty"`
+	Traffic       *Traffic          `json:"traffic,omitempty"         yaml:"traffic,omitempty"`
+	ExpiresAt     *time.Time        `json:"expires_at,omitempty"      yaml:"expires_at,omitempty"`
 	debug         bool
 }
 
+// Traffic contains the statistics for the intercepted traffic.
+type Traffic struct {
+	ToClientBytes   uint64 `json:"to_client_bytes"   yaml:"to_client_bytes"`
+	FromClientBytes uint64 `json:"from_client_bytes" yaml:"from_client_bytes"`
+	Connections     uint64 `json:"connections"       yaml:"connections"`
+}
+
+func NewTraffic(im *manager.InterceptMetrics) *Traffic {
+	if im == nil {
+		return nil
+	}
+	return &Traffic{
+		ToClientBytes:   im.ToClientBytes,
+		FromClientBytes: im.FromClientBytes,
+		Connections:     im.Connections,
+	}
+}
+
 func NewIngress(ps *manager.PreviewSpec) *Ingress {
 	if ps == nil {
 		return nil
@@ -125,6 +150,11 @@ func NewInfo(ctx context.Context, ii *manager.InterceptInfo, mountError string)
 		Global:        spec.Mechanism == "tcp",
 		PreviewURL:    PreviewURL(ii.PreviewDomain),
 		Ingress:       NewIngress(ii.PreviewSpec),
+		Traffic:       NewTraffic(ii.Metrics),
+	}
+	if ea := ii.ExpiresAt; ea != nil {
+		t := ea.AsTime()
+		info.ExpiresAt = &t
 	}
 	if spec.ServiceUid != "" {
 		// For backward compatibility in JSON output
@@ -177,9 +207,17 @@ func (ii *Info) WriteTo(w io.Writer) (int64, error) {
 	}
 
 	if m := ii.Mount; m != nil {
-		if m.LocalDir != "" {
+		switch {
+		case m.LocalDir != "":
 			kvf.Add("Volume Mount Point", m.LocalDir)
-		} else if m.Error != "" {
+		case len(m.LocalDirs) > 0:
+			ps := make([]string, 0, len(m.LocalDirs))
+			for remote, local := range m.LocalDirs {
+				ps = append(ps, remote+"="+local)
+			}
+			sort.Strings(ps)
+			kvf.Add("Volume Mount Points", strings.Join(ps, ", "))
+		case m.Error != "":
 			kvf.Add("Volume Mount Error", m.Error)
 		}
 	}
@@ -196,6 +234,12 @@ func (ii *Info) WriteTo(w io.Writer) (int64, error) {
 	if ii.ServiceUID == "" {
 		kvf.Add("Address", iputil.JoinHostPort(ii.PodIP, uint16(ii.ContainerPort)))
 	}
+	if t := ii.Traffic; t != nil {
+		kvf.Add("Traffic", fmt.Sprintf("%d connections, %d bytes received, %d bytes sent", t.Connections, t.ToClientBytes, t.FromClientBytes))
+	}
+	if ea := ii.ExpiresAt; ea != nil {
+		kvf.Add("Expires at", ea.Local().Format(time.RFC3339))
+	}
 
 	if ii.PreviewURL != "" {
 		previewURL := ii.PreviewURL
@@ -0,0 +1,32 @@
+package intercept
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net"
+	"strconv"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+	"github.com/telepresenceio/telepresence/v2/pkg/proc"
+)
+
+// checkLocalPortFree returns an error that identifies the process that holds the given local TCP port, or nil
+// when the port can be bound.
+func checkLocalPortFree(ctx context.Context, host string, port uint16) error {
+	addr := net.JoinHostPort(host, strconv.Itoa(int(port)))
+	l, err := net.Listen("tcp", addr)
+	if err == nil {
+		_ = l.Close()
+		return nil
+	}
+	po, oErr := proc.TCPPortOwner(ctx, port)
+	switch {
+	case oErr == nil:
+		return errcat.User.Newf("Port %s is already in use by %s", addr, po)
+	case errors.Is(oErr, fs.ErrPermission):
+		return errcat.User.Newf("Port %s is already in use by a process that belongs to another user (permission to inspect it was denied)", addr)
+	default:
+		return errcat.User.Newf("Port %s cannot be used: %w", addr, err)
+	}
+}
@@ -0,0 +1,28 @@
+package intercept
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_checkLocalPortFree(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := uint16(l.Addr().(*net.TCPAddr).Port)
+
+	err = checkLocalPortFree(context.Background(), "127.0.0.1", port)
+	require.Error(t, err)
+	if runtime.GOOS == "linux" {
+		assert.Contains(t, err.Error(), fmt.Sprintf("Port 127.0.0.1:%d is already in use by process %d", port, os.Getpid()))
+	}
+
+	require.NoError(t, l.Close())
+	assert.NoError(t, checkLocalPortFree(context.Background(), "127.0.0.1", port))
+}
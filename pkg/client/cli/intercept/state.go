@@ -10,11 +10,11 @@ import (
 	"os"
 	"runtime"
 	"sort"
-	"strconv"
 	"strings"
 
 	grpcCodes "google.golang.org/grpc/codes"
 	grpcStatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 	empty "google.golang.org/protobuf/types/known/emptypb"
 	core "k8s.io/api/core/v1"
 
@@ -24,6 +24,7 @@ import (
 	"github.com/telepresenceio/telepresence/rpc/v2/manager"
 	"github.com/telepresenceio/telepresence/v2/pkg/agentconfig"
 	"github.com/telepresenceio/telepresence/v2/pkg/client"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/api"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/daemon"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/output"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/cli/spinner"
@@ -48,8 +49,10 @@ type state struct {
 	*Command
 	env           map[string]string
 	mountDisabled bool
-	mountPoint    string // if non-empty, this the final mount point of a successful mount
-	localPort     uint16 // the parsed <local port>
+	mountPoint    string            // if non-empty, this the final mount point of a successful mount
+	mountPoints   map[string]string // remote path to local dir, when the mount was given as a mapping
+	builtImage    string            // temporary tag of an image built using --docker-build that must be removed when done
+	localPort     uint16            // the parsed <local port>
 	dockerPort    uint16
 	status        *connector.ConnectInfo
 	info          *Info // Info from the created intercept
@@ -78,15 +81,14 @@ func (s *state) CreateRequest(ctx context.Context) (*connector.CreateInterceptRe
 		Replace: s.Replace,
 	}
 	ir := &connector.CreateInterceptRequest{
-		Spec:         spec,
-		ExtendedInfo: s.ExtendedInfo,
+		Spec:             spec,
+		ExtendedInfo:     s.ExtendedInfo,
+		WorkloadSelector: s.Selector,
 	}
-
-	if s.AgentName == "" {
-		// local-only
-		s.mountDisabled = true
-		return ir, nil
+	if s.Wait > 0 {
+		ir.Wait = durationpb.New(s.Wait)
 	}
+	ir.AgentImage = s.AgentImage
 
 	spec.ServiceName = s.ServiceName
 	spec.ContainerName = s.ContainerName
@@ -108,11 +110,25 @@ func (s *state) CreateRequest(ctx context.Context) (*connector.CreateInterceptRe
 		return nil, fmt.Errorf("--address %s is not a valid IP address", s.Address)
 	}
 	spec.TargetHost = s.Address
+	if s.DockerRun && !ud.Containerized() && s.localPort != 0 {
+		// The container publishes the local port, so it must not be held by another process.
+		if err = checkLocalPortFree(ctx, s.Address, s.localPort); err != nil {
+			return nil, err
+		}
+	}
+	spec.TargetTls = s.TargetTLS
+	spec.TargetTlsInsecure = s.TargetTLSInsecure
+	spec.TargetTlsCa = s.TargetTLSCAPEM
+	spec.ThrottleRate = s.ThrottleRate
+	spec.Delay = int64(s.Delay)
 
-	mountEnabled, mountPoint := s.GetMountPoint()
-	if !mountEnabled {
+	mountMode, mountPoint := s.GetMountMode()
+	if mountMode == MountOff {
 		s.mountDisabled = true
 	} else {
+		if ud.Containerized() && len(s.MountMap) > 0 {
+			return nil, errors.New("a --mount mapping cannot be used when the daemon runs in a container")
+		}
 		if ud.Containerized() && ir.LocalMountPort == 0 {
 			// No use having the remote container actually mount, so let's have it create a bridge
 			// to the remote sftp server instead.
@@ -126,7 +142,7 @@ func (s *state) CreateRequest(ctx context.Context) (*connector.CreateInterceptRe
 
 		if err = s.checkMountCapability(ctx); err != nil {
 			err = fmt.Errorf("remote volume mounts are disabled: %w", err)
-			if mountPoint != "" {
+			if mountMode == MountOn {
 				return nil, err
 			}
 			// Log a warning and disable, but continue
@@ -135,13 +151,21 @@ func (s *state) CreateRequest(ctx context.Context) (*connector.CreateInterceptRe
 		}
 
 		if !s.mountDisabled {
+			ir.MountReadOnly = s.MountRO
 			ir.LocalMountPort = int32(s.LocalMountPort)
 			if ir.LocalMountPort == 0 {
 				var cwd string
 				if cwd, err = os.Getwd(); err != nil {
 					return nil, err
 				}
-				if ir.MountPoint, err = PrepareMount(cwd, mountPoint); err != nil {
+				if len(s.MountMap) > 0 {
+					ir.MountPoints = make(map[string]string, len(s.MountMap))
+					for remote, local := range s.MountMap {
+						if ir.MountPoints[remote], err = PrepareMount(cwd, local); err != nil {
+							return nil, err
+						}
+					}
+				} else if ir.MountPoint, err = PrepareMount(cwd, mountPoint); err != nil {
 					return nil, err
 				}
 			}
@@ -149,17 +173,21 @@ func (s *state) CreateRequest(ctx context.Context) (*connector.CreateInterceptRe
 	}
 
 	for _, toPod := range s.ToPod {
-		pp, err := agentconfig.NewPortAndProto(toPod)
+		tp, err := agentconfig.NewToPodForward(toPod)
 		if err != nil {
 			return nil, err
 		}
-		spec.LocalPorts = append(spec.LocalPorts, pp.String())
-		if pp.Proto == core.ProtocolTCP {
+		spec.LocalPorts = append(spec.LocalPorts, tp.String())
+		if tp.Proto == core.ProtocolTCP {
 			// For backward compatibility
-			spec.ExtraPorts = append(spec.ExtraPorts, int32(pp.Port))
+			spec.ExtraPorts = append(spec.ExtraPorts, int32(tp.Port))
 		}
 	}
 
+	if s.MountRO && s.mountDisabled {
+		return nil, errors.New("--mount-ro cannot be used with --mount=false")
+	}
+
 	if s.DockerMount != "" {
 		if !s.DockerRun {
 			return nil, errors.New("--docker-mount must be used together with --docker-run")
@@ -194,9 +222,12 @@ func (s *state) Run(ctx context.Context) (*Info, error) {
 
 	// start intercept, run command, then leave the intercept
 	if s.DockerRun {
-		if err := s.prepareDockerRun(docker.EnableClient(ctx)); err != nil {
+		ctx = docker.WithRuntime(ctx, s.ContainerRuntime)
+		dctx := docker.EnableClient(ctx)
+		if err := s.prepareDockerRun(dctx); err != nil {
 			return nil, err
 		}
+		defer s.removeBuiltImage(dctx)
 	}
 	err := client.WithEnsuredState(ctx, s.create, s.runCommand, s.leave)
 	if err != nil {
@@ -224,6 +255,9 @@ func (s *state) create(ctx context.Context) (acquired bool, err error) {
 		scout.Report(ctx, "intercept_validation_fail", scout.Entry{Key: "error", Value: err.Error()})
 		return false, errcat.NoDaemonLogs.New(err)
 	}
+	if ir.AgentImage != "" {
+		warnAgentImageOverride(ctx, ir.AgentImage)
+	}
 
 	if ir.MountPoint != "" {
 		defer func() {
@@ -234,6 +268,17 @@ func (s *state) create(ctx context.Context) (acquired bool, err error) {
 		}()
 		s.mountPoint = ir.MountPoint
 	}
+	if len(ir.MountPoints) > 0 {
+		defer func() {
+			if !acquired && runtime.GOOS != "windows" {
+				for _, mp := range ir.MountPoints {
+					// remove if empty
+					_ = os.Remove(mp)
+				}
+			}
+		}()
+		s.mountPoints = ir.MountPoints
+	}
 
 	defer func() {
 		if err != nil {
@@ -245,16 +290,15 @@ func (s *state) create(ctx context.Context) (acquired bool, err error) {
 
 	// Submit the request
 	r, err := ud.CreateIntercept(ctx, ir)
-	if err = Result(r, err); err != nil {
+	if err = api.InterceptResultError(r, err); err != nil {
 		return false, fmt.Errorf("connector.CreateIntercept: %w", err)
 	}
 
 	if s.AgentName == "" {
-		// local-only
-		return true, nil
+		// The workload was found using the selector.
+		s.AgentName = r.GetInterceptInfo().GetSpec().GetAgent()
 	}
-	detailedOutput := s.DetailedOutput && s.FormattedOutput
-	if !s.Silent && !detailedOutput {
+	if !s.Silent && !s.FormattedOutput {
 		fmt.Fprintf(dos.Stdout(ctx), "Using %s %s\n", r.WorkloadKind, s.AgentName)
 	}
 	var intercept *manager.InterceptInfo
@@ -276,6 +320,15 @@ func (s *state) create(ctx context.Context) (acquired bool, err error) {
 	}
 	s.env["TELEPRESENCE_INTERCEPT_ID"] = intercept.Id
 	s.env["TELEPRESENCE_ROOT"] = intercept.ClientMountPoint
+	if s.DetailedEnv {
+		s.env["TELEPRESENCE_POD"] = intercept.PodName
+		s.env["TELEPRESENCE_POD_IP"] = intercept.PodIp
+		s.env["TELEPRESENCE_NAMESPACE"] = intercept.Spec.Namespace
+		if intercept.NodeName != "" {
+			// Older traffic-agents don't report the node name.
+			s.env["TELEPRESENCE_NODE"] = intercept.NodeName
+		}
+	}
 	if s.EnvFile != "" {
 		if err = s.writeEnvFile(); err != nil {
 			return true, err
@@ -291,19 +344,23 @@ func (s *state) create(ctx context.Context) (acquired bool, err error) {
 	if ir.LocalMountPort != 0 {
 		intercept.PodIp = "127.0.0.1"
 		intercept.SftpPort = ir.LocalMountPort
-	} else {
-		doMount, err := strconv.ParseBool(s.Mount)
-		if doMount || err != nil {
-			volumeMountProblem = s.checkMountCapability(ctx)
-		}
+	} else if mountMode, _ := s.GetMountMode(); mountMode != MountOff {
+		volumeMountProblem = s.checkMountCapability(ctx)
 	}
 	mountError := ""
 	if volumeMountProblem != nil {
 		mountError = volumeMountProblem.Error()
 	}
 	s.info = NewInfo(ctx, intercept, mountError)
+	if s.mountDisabled && mountError == "" {
+		// Never report a mount when mounting is off
+		s.info.Mount = nil
+	}
+	if m := s.info.Mount; m != nil && len(ir.MountPoints) > 0 {
+		m.LocalDirs = ir.MountPoints
+	}
 	if !s.Silent {
-		if detailedOutput {
+		if s.FormattedOutput {
 			output.Object(ctx, s.info, true)
 		} else {
 			out := dos.Stdout(ctx)
@@ -325,7 +382,7 @@ func (s *state) leave(ctx context.Context) error {
 	if err != nil {
 		dlog.Errorf(ctx, "Leaving intercept ended with error %v", err)
 	}
-	return Result(r, err)
+	return api.InterceptResultError(r, err)
 }
 
 func (s *state) runCommand(ctx context.Context) error {
@@ -349,19 +406,18 @@ func (s *state) runCommand(ctx context.Context) error {
 		return errcat.NoDaemonLogs.New(proc.Wait(ctx, func() {}, cmd))
 	}
 
-	envFile := s.EnvFile
-	if envFile == "" {
-		file, err := os.CreateTemp("", "tel-*.env")
-		if err != nil {
-			return fmt.Errorf("failed to create temporary environment file. %w", err)
-		}
-		defer os.Remove(file.Name())
-
-		if err = s.writeEnvToFileAndClose(file); err != nil {
-			return err
-		}
-		envFile = file.Name()
+	// The environment is passed in a file, because the environment of the docker command must not be
+	// changed. It depends on variables such as HOME, PATH, and DOCKER_HOST that the pod might also set.
+	file, err := os.CreateTemp("", "tel-*.env")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary environment file. %w", err)
 	}
+	defer os.Remove(file.Name())
+	envArgs, err := s.writeDockerEnvFileAndClose(file)
+	if err != nil {
+		return err
+	}
+	envArgs = append([]string{"--env-file", file.Name()}, envArgs...)
 
 	// Ensure that the intercept handler is stopped properly if the daemon quits
 	procCtx, cancel := context.WithCancel(ctx)
@@ -383,7 +439,7 @@ func (s *state) runCommand(ctx context.Context) error {
 
 	spin := spinner.New(ctx, "container "+name)
 	spin.Message("starting")
-	dr := s.startInDocker(procCtx, name, envFile, args)
+	dr := s.startInDocker(procCtx, name, envArgs, args)
 	if dr.err == nil {
 		dr.err = s.addInterceptorToDaemon(ctx, dr.cmd, dr.name)
 		spin.Message("started")
@@ -487,11 +543,19 @@ func parsePort(portSpec string, dockerRun, remote bool) (local uint16, docker ui
 		if dockerRun && !remote {
 			return 0, 0, "", errcat.User.New("port must be of the format --port <local-port>:<container-port>[:<svcPortIdentifier>]")
 		}
-		return 0, 0, "", errcat.User.New("port must be of the format --port <local-port>[:<svcPortIdentifier>]")
+		return 0, 0, "", errcat.User.New("port must be of the format --port <local-port>[:<svcPortIdentifier>] or --port <svcPortName>")
 	}
 
 	if local, err = agentconfig.ParseNumericPort(portMapping[0]); err != nil {
-		return portError()
+		if err != agentconfig.ErrNotInteger || len(portMapping) != 1 || dockerRun {
+			return portError()
+		}
+		// A lone port name identifies the service port. The local port will be the number of the container port
+		// that the name resolves to.
+		if err = agentconfig.ValidatePort(portMapping[0]); err != nil {
+			return portError()
+		}
+		return 0, 0, portMapping[0], nil
 	}
 
 	switch len(portMapping) {
@@ -0,0 +1,96 @@
+package intercept
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parsePort(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      string
+		dockerRun bool
+		local     uint16
+		docker    uint16
+		svcPortID string
+		wantErr   bool
+	}{
+		{name: "local", spec: "8080", local: 8080},
+		{name: "local and number", spec: "8080:80", local: 8080, svcPortID: "80"},
+		{name: "local and name", spec: "8080:http", local: 8080, svcPortID: "http"},
+		{name: "name only", spec: "http", svcPortID: "http"},
+		{name: "invalid name", spec: "Not_A_Name", wantErr: true},
+		{name: "name only with docker run", spec: "http", dockerRun: true, wantErr: true},
+		{name: "docker run", spec: "8080:80", dockerRun: true, local: 8080, docker: 80},
+		{name: "docker run with name", spec: "8080:80:http", dockerRun: true, local: 8080, docker: 80, svcPortID: "http"},
+		{name: "name and local", spec: "http:8080", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			local, docker, svcPortID, err := parsePort(tt.spec, tt.dockerRun, false)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.local, local)
+			assert.Equal(t, tt.docker, docker)
+			assert.Equal(t, tt.svcPortID, svcPortID)
+		})
+	}
+}
+
+func Test_podVolumeArgs(t *testing.T) {
+	mountPoint := filepath.Join(string(filepath.Separator)+"tmp", "telfs-123")
+	s := &state{
+		Command: &Command{},
+		info: &Info{Mount: &Mount{
+			Mounts: []string{"/var/run/secrets/kubernetes.io/serviceaccount", "/etc/config"},
+		}},
+		mountPoint: mountPoint,
+	}
+	assert.Equal(t, []string{
+		"-v", filepath.Join(mountPoint, "var", "run", "secrets", "kubernetes.io", "serviceaccount") + ":/var/run/secrets/kubernetes.io/serviceaccount",
+		"-v", filepath.Join(mountPoint, "etc", "config") + ":/etc/config",
+	}, s.podVolumeArgs())
+
+	s.mountPoints = map[string]string{"/etc/config": "/home/me/config"}
+	s.MountRO = true
+	assert.Equal(t, []string{
+		"-v", filepath.Join(mountPoint, "var", "run", "secrets", "kubernetes.io", "serviceaccount") + ":/var/run/secrets/kubernetes.io/serviceaccount:ro",
+	}, s.podVolumeArgs())
+
+	s.mountDisabled = true
+	assert.Empty(t, s.podVolumeArgs())
+}
+
+func Test_volumeArg(t *testing.T) {
+	s := &state{Command: &Command{}}
+	assert.Equal(t, "/tmp/telfs-123:/var/run/secrets", s.volumeArg("/tmp/telfs-123", "/var/run/secrets"))
+	s.MountRO = true
+	assert.Equal(t, "/tmp/telfs-123:/var/run/secrets:ro", s.volumeArg("/tmp/telfs-123", "/var/run/secrets"))
+}
+
+func Test_writeDockerEnvFileAndClose(t *testing.T) {
+	s := &state{
+		Command: &Command{},
+		env: map[string]string{
+			"TELEPRESENCE_INTERCEPT_ID": "abc:hello",
+			"MULTI_LINE":                "first\nsecond",
+			"QUOTED":                    `"it's" $HOME`,
+		},
+	}
+	file, err := os.Create(filepath.Join(t.TempDir(), "tel.env"))
+	require.NoError(t, err)
+	args, err := s.writeDockerEnvFileAndClose(file)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-e", "MULTI_LINE=first\nsecond"}, args)
+
+	data, err := os.ReadFile(file.Name())
+	require.NoError(t, err)
+	assert.Equal(t, "QUOTED=\"it's\" $HOME\nTELEPRESENCE_INTERCEPT_ID=abc:hello\n", string(data))
+}
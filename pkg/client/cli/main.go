@@ -72,7 +72,7 @@ func Main(ctx context.Context) {
 	} else {
 		if cmd, fmtOutput, err := output.Execute(cmd.Telepresence(ctx)); err != nil {
 			if fmtOutput {
-				os.Exit(1)
+				os.Exit(ExitCode(err))
 			}
 			fmt.Fprintf(cmd.ErrOrStderr(), "%s: error: %v\n", cmd.CommandPath(), err)
 			if errcat.GetCategory(err) > errcat.NoDaemonLogs {
@@ -86,7 +86,7 @@ func Main(ctx context.Context) {
 						"https://github.com/telepresenceio/telepresence/issues/new?template=Bug_report.md .")
 				}
 			}
-			os.Exit(1)
+			os.Exit(ExitCode(err))
 		}
 	}
 }
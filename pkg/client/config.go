@@ -34,11 +34,14 @@ type Config interface {
 	Base() *BaseConfig
 	Timeouts() *Timeouts
 	LogLevels() *LogLevels
+	LogFormat() LogFormat
+	LogRotation() *LogRotation
 	Images() *Images
 	Grpc() *Grpc
 	TelepresenceAPI() *TelepresenceAPI
 	Intercept() *Intercept
 	Cluster() *Cluster
+	Tunnel() *Tunnel
 	Merge(Config)
 }
 
@@ -47,11 +50,14 @@ type BaseConfig struct {
 	OSSpecificConfig `yaml:",inline"`
 	TimeoutsV        Timeouts        `json:"timeouts,omitempty" yaml:"timeouts,omitempty"`
 	LogLevelsV       LogLevels       `json:"logLevels,omitempty" yaml:"logLevels,omitempty"`
+	LogFormatV       LogFormat       `json:"logFormat,omitempty" yaml:"logFormat,omitempty"`
+	LogRotationV     LogRotation     `json:"logRotation,omitempty" yaml:"logRotation,omitempty"`
 	ImagesV          Images          `json:"images,omitempty" yaml:"images,omitempty"`
 	GrpcV            Grpc            `json:"grpc,omitempty" yaml:"grpc,omitempty"`
 	TelepresenceAPIV TelepresenceAPI `json:"telepresenceAPI,omitempty" yaml:"telepresenceAPI,omitempty"`
 	InterceptV       Intercept       `json:"intercept,omitempty" yaml:"intercept,omitempty"`
 	ClusterV         Cluster         `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+	TunnelV          Tunnel          `json:"tunnel,omitempty" yaml:"tunnel,omitempty"`
 }
 
 func (c *BaseConfig) OSSpecific() *OSSpecificConfig {
@@ -70,6 +76,17 @@ func (c *BaseConfig) LogLevels() *LogLevels {
 	return &c.LogLevelsV
 }
 
+func (c *BaseConfig) LogFormat() LogFormat {
+	if c.LogFormatV == "" {
+		return LogFormatText
+	}
+	return c.LogFormatV
+}
+
+func (c *BaseConfig) LogRotation() *LogRotation {
+	return &c.LogRotationV
+}
+
 func (c *BaseConfig) Images() *Images {
 	return &c.ImagesV
 }
@@ -90,6 +107,10 @@ func (c *BaseConfig) Cluster() *Cluster {
 	return &c.ClusterV
 }
 
+func (c *BaseConfig) Tunnel() *Tunnel {
+	return &c.TunnelV
+}
+
 func ParseConfigYAML(data []byte) (Config, error) {
 	cfg := GetDefaultConfig()
 	if err := yaml.Unmarshal(data, cfg); err != nil {
@@ -103,11 +124,16 @@ func (c *BaseConfig) Merge(lc Config) {
 	c.OSSpecificConfig.Merge(lc.OSSpecific())
 	c.TimeoutsV.merge(lc.Timeouts())
 	c.LogLevelsV.merge(lc.LogLevels())
+	if lf := lc.Base().LogFormatV; lf != "" {
+		c.LogFormatV = lf
+	}
+	c.LogRotationV.merge(lc.LogRotation())
 	c.ImagesV.merge(lc.Images())
 	c.GrpcV.merge(lc.Grpc())
 	c.TelepresenceAPIV.merge(lc.TelepresenceAPI())
 	c.InterceptV.merge(lc.Intercept())
 	c.ClusterV.merge(lc.Cluster())
+	c.TunnelV.merge(lc.Tunnel())
 }
 
 func (c *BaseConfig) String() string {
@@ -177,6 +203,8 @@ type Timeouts struct {
 
 	// PrivateClusterConnect is the maximum time to wait for a connection to the cluster to be established
 	PrivateClusterConnect time.Duration `json:"clusterConnect" yaml:"clusterConnect"`
+	// PrivateConnectReady is how long "telepresence connect --wait-for-ready" waits for the routes and the DNS resolver.
+	PrivateConnectReady time.Duration `json:"connectReady" yaml:"connectReady"`
 	// PrivateConnectivityCheck timeout used when checking if cluster is already proxied on the workstation
 	PrivateConnectivityCheck time.Duration `json:"connectivityCheck" yaml:"connectivityCheck"`
 	// PrivateEndpointDial is how long to wait for a Dial to a service for which the IP is known.
@@ -185,6 +213,8 @@ type Timeouts struct {
 	PrivateHelm time.Duration `json:"helm" yaml:"helm"`
 	// PrivateIntercept is the time to wait for an intercept after the agents has been installed
 	PrivateIntercept time.Duration `json:"intercept" yaml:"intercept"`
+	// PrivateInterceptDrain is how long the traffic-agent lets intercepted connections finish when an intercept is removed.
+	PrivateInterceptDrain time.Duration `json:"interceptDrain" yaml:"interceptDrain"`
 	// PrivateRoundtripLatency is how much to add  to the EndpointDial timeout when establishing a remote connection.
 	PrivateRoundtripLatency time.Duration `json:"roundtripLatency" yaml:"roundtripLatency"`
 	// PrivateProxyDial is how long to wait for the proxy to establish an outbound connection
@@ -197,6 +227,8 @@ type Timeouts struct {
 	PrivateFtpReadWrite time.Duration `json:"ftpReadWrite" yaml:"ftpReadWrite"`
 	// PrivateFtpShutdown max time to wait for the fuseftp client to complete pending operations before forcing termination.
 	PrivateFtpShutdown time.Duration `json:"ftpShutdown" yaml:"ftpShutdown"`
+	// PrivateCredentialPlugin is how long to wait for a kubeconfig exec credential plugin to produce credentials.
+	PrivateCredentialPlugin time.Duration `json:"credentialPlugin" yaml:"credentialPlugin"`
 }
 
 type TimeoutID int
@@ -213,6 +245,9 @@ const (
 	TimeoutTrafficManagerConnect
 	TimeoutFtpReadWrite
 	TimeoutFtpShutdown
+	TimeoutCredentialPlugin
+	TimeoutInterceptDrain
+	TimeoutConnectReady
 )
 
 type timeoutContext struct {
@@ -259,6 +294,12 @@ func (t *Timeouts) Get(timeoutID TimeoutID) time.Duration {
 		timeoutVal = t.PrivateFtpReadWrite
 	case TimeoutFtpShutdown:
 		timeoutVal = t.PrivateFtpShutdown
+	case TimeoutCredentialPlugin:
+		timeoutVal = t.PrivateCredentialPlugin
+	case TimeoutInterceptDrain:
+		timeoutVal = t.PrivateInterceptDrain
+	case TimeoutConnectReady:
+		timeoutVal = t.PrivateConnectReady
 	default:
 		panic("should not happen")
 	}
@@ -319,6 +360,15 @@ func (e timeoutError) Error() string {
 	case TimeoutFtpShutdown:
 		yamlName = "ftpShutdown"
 		humanName = "FTP client shutdown grace period"
+	case TimeoutCredentialPlugin:
+		yamlName = "credentialPlugin"
+		humanName = "kubeconfig exec credential plugin"
+	case TimeoutInterceptDrain:
+		yamlName = "interceptDrain"
+		humanName = "intercept drain"
+	case TimeoutConnectReady:
+		yamlName = "connectReady"
+		humanName = "wait for the connection to become ready"
 	default:
 		panic("should not happen")
 	}
@@ -377,6 +427,12 @@ func (t *Timeouts) UnmarshalYAML(node *yaml.Node) (err error) {
 			dp = &t.PrivateFtpReadWrite
 		case "ftpShutdown":
 			dp = &t.PrivateFtpShutdown
+		case "credentialPlugin":
+			dp = &t.PrivateCredentialPlugin
+		case "interceptDrain":
+			dp = &t.PrivateInterceptDrain
+		case "connectReady":
+			dp = &t.PrivateConnectReady
 		default:
 			logrus.Warn(WithLoc(fmt.Sprintf(`unknown key "timeouts.%s"`, kv), ms[i]))
 			continue
@@ -413,6 +469,9 @@ const (
 	defaultTimeoutsTrafficManagerConnect = 60 * time.Second
 	defaultTimeoutsFtpReadWrite          = 1 * time.Minute
 	defaultTimeoutsFtpShutdown           = 2 * time.Minute
+	defaultTimeoutsCredentialPlugin      = 1 * time.Minute
+	defaultTimeoutsInterceptDrain        = 5 * time.Second
+	defaultTimeoutsConnectReady          = 30 * time.Second
 )
 
 var defaultTimeouts = Timeouts{ //nolint:gochecknoglobals // constant
@@ -427,6 +486,9 @@ var defaultTimeouts = Timeouts{ //nolint:gochecknoglobals // constant
 	PrivateTrafficManagerConnect: defaultTimeoutsTrafficManagerConnect,
 	PrivateFtpReadWrite:          defaultTimeoutsFtpReadWrite,
 	PrivateFtpShutdown:           defaultTimeoutsFtpShutdown,
+	PrivateCredentialPlugin:      defaultTimeoutsCredentialPlugin,
+	PrivateInterceptDrain:        defaultTimeoutsInterceptDrain,
+	PrivateConnectReady:          defaultTimeoutsConnectReady,
 }
 
 // IsZero controls whether this element will be included in marshalled output.
@@ -470,6 +532,15 @@ func (t Timeouts) MarshalYAML() (any, error) {
 	if t.PrivateFtpShutdown != defaultTimeoutsFtpShutdown {
 		tm["ftpShutdown"] = t.PrivateFtpShutdown.String()
 	}
+	if t.PrivateCredentialPlugin != defaultTimeoutsCredentialPlugin {
+		tm["credentialPlugin"] = t.PrivateCredentialPlugin.String()
+	}
+	if t.PrivateInterceptDrain != defaultTimeoutsInterceptDrain {
+		tm["interceptDrain"] = t.PrivateInterceptDrain.String()
+	}
+	if t.PrivateConnectReady != defaultTimeoutsConnectReady {
+		tm["connectReady"] = t.PrivateConnectReady.String()
+	}
 	return tm, nil
 }
 
@@ -508,6 +579,15 @@ func (t *Timeouts) merge(o *Timeouts) {
 	if o.PrivateFtpShutdown != defaultTimeoutsFtpShutdown {
 		t.PrivateFtpShutdown = o.PrivateFtpShutdown
 	}
+	if o.PrivateCredentialPlugin != defaultTimeoutsCredentialPlugin {
+		t.PrivateCredentialPlugin = o.PrivateCredentialPlugin
+	}
+	if o.PrivateInterceptDrain != defaultTimeoutsInterceptDrain {
+		t.PrivateInterceptDrain = o.PrivateInterceptDrain
+	}
+	if o.PrivateConnectReady != defaultTimeoutsConnectReady {
+		t.PrivateConnectReady = o.PrivateConnectReady
+	}
 }
 
 const (
@@ -570,6 +650,65 @@ func (ll *LogLevels) merge(o *LogLevels) {
 	}
 }
 
+// LogFormat is the format that the daemons use when writing to their log files.
+type LogFormat string
+
+const (
+	// LogFormatText is the default format, intended for humans.
+	LogFormatText LogFormat = "text"
+
+	// LogFormatJSON writes each log entry as a JSON object, intended for log aggregation.
+	LogFormatJSON LogFormat = "json"
+)
+
+// UnmarshalYAML parses and validates the log format.
+func (lf *LogFormat) UnmarshalYAML(node *yaml.Node) error {
+	var s string
+	if err := node.Decode(&s); err != nil {
+		return errors.New(WithLoc("logFormat must be a string", node))
+	}
+	switch f := LogFormat(s); f {
+	case LogFormatText, LogFormatJSON:
+		*lf = f
+	default:
+		return errors.New(WithLoc(fmt.Sprintf("invalid logFormat %q, must be %q or %q", s, LogFormatText, LogFormatJSON), node))
+	}
+	return nil
+}
+
+const (
+	defaultLogRotationMaxSizeMB  = 100
+	defaultLogRotationMaxBackups = 4
+)
+
+var defaultLogRotation = LogRotation{ //nolint:gochecknoglobals // constant
+	MaxSizeMB:  defaultLogRotationMaxSizeMB,
+	MaxBackups: defaultLogRotationMaxBackups,
+}
+
+// LogRotation controls the size based rotation of the log files.
+type LogRotation struct {
+	// MaxSizeMB is the size in megabytes that a log file can reach before it is rotated. Zero means no limit.
+	MaxSizeMB int `json:"maxSizeMB" yaml:"maxSizeMB"`
+
+	// MaxBackups is the number of rotated log files to retain.
+	MaxBackups int `json:"maxBackups" yaml:"maxBackups"`
+}
+
+// IsZero controls whether this element will be included in marshalled output.
+func (lr LogRotation) IsZero() bool {
+	return lr == defaultLogRotation
+}
+
+func (lr *LogRotation) merge(o *LogRotation) {
+	if o.MaxSizeMB != defaultLogRotationMaxSizeMB {
+		lr.MaxSizeMB = o.MaxSizeMB
+	}
+	if o.MaxBackups != defaultLogRotationMaxBackups {
+		lr.MaxBackups = o.MaxBackups
+	}
+}
+
 type Images struct {
 	PrivateRegistry        string `json:"registry,omitempty" yaml:"registry,omitempty"`
 	PrivateAgentImage      string `json:"agentImage,omitempty" yaml:"agentImage,omitempty"`
@@ -750,6 +889,123 @@ func (g Grpc) MarshalYAML() (any, error) {
 	return nil, nil
 }
 
+type Tunnel struct {
+	// BufferSizeV is the size of the buffer that the tunnel reads a connection into, and hence the maximum
+	// payload of one tunnel message.
+	BufferSizeV resource.Quantity `json:"bufferSize,omitempty" yaml:"bufferSize,omitempty"`
+
+	// WindowSize is the number of messages that may be queued in each direction of a tunnel stream.
+	WindowSize int `json:"windowSize,omitempty" yaml:"windowSize,omitempty"`
+
+	// PoolSize is the number of spare tunnel streams that the client keeps open to an intercepted traffic-agent.
+	// Zero disables the pool.
+	PoolSize int `json:"poolSize,omitempty" yaml:"poolSize,omitempty"`
+
+	// PoolIdleTimeout is the time that a spare tunnel stream is kept open without being used.
+	PoolIdleTimeout time.Duration `json:"poolIdleTimeout,omitempty" yaml:"poolIdleTimeout,omitempty"`
+}
+
+const defaultPoolIdleTimeout = 30 * time.Second
+
+// GetPoolIdleTimeout returns the PoolIdleTimeout, or its default when it isn't set.
+func (t *Tunnel) GetPoolIdleTimeout() time.Duration {
+	if t.PoolIdleTimeout > 0 {
+		return t.PoolIdleTimeout
+	}
+	return defaultPoolIdleTimeout
+}
+
+// BufferSize returns the configured buffer size in bytes, or zero when it isn't set. A size too large to be
+// represented is returned as math.MaxInt32, so that the tunnel clamps it to its maximum rather than ignoring it.
+func (t *Tunnel) BufferSize() int {
+	if t.BufferSizeV.IsZero() {
+		return 0
+	}
+	if bz, ok := t.BufferSizeV.AsInt64(); ok && bz <= math.MaxInt32 {
+		return int(bz)
+	}
+	return math.MaxInt32
+}
+
+func (t *Tunnel) merge(o *Tunnel) {
+	if !o.BufferSizeV.IsZero() {
+		t.BufferSizeV = o.BufferSizeV
+	}
+	if o.WindowSize != 0 {
+		t.WindowSize = o.WindowSize
+	}
+	if o.PoolSize != 0 {
+		t.PoolSize = o.PoolSize
+	}
+	if o.PoolIdleTimeout != 0 {
+		t.PoolIdleTimeout = o.PoolIdleTimeout
+	}
+}
+
+// UnmarshalYAML parses the tunnel YAML.
+func (t *Tunnel) UnmarshalYAML(node *yaml.Node) (err error) {
+	if node.Kind != yaml.MappingNode {
+		return errors.New(WithLoc("tunnel must be an object", node))
+	}
+
+	ms := node.Content
+	top := len(ms)
+	for i := 0; i < top; i += 2 {
+		kv, err := StringKey(ms[i])
+		if err != nil {
+			return err
+		}
+		v := ms[i+1]
+		switch kv {
+		case "bufferSize":
+			val, err := resource.ParseQuantity(v.Value)
+			if err != nil {
+				logrus.Warnf("unable to parse quantity %q: %v", v.Value, WithLoc(err.Error(), ms[i]))
+			} else {
+				t.BufferSizeV = val
+			}
+		case "windowSize":
+			if err := v.Decode(&t.WindowSize); err != nil {
+				logrus.Warn(WithLoc(fmt.Sprintf("unable to parse window size %q", v.Value), v))
+			}
+		case "poolSize":
+			if err := v.Decode(&t.PoolSize); err != nil {
+				logrus.Warn(WithLoc(fmt.Sprintf("unable to parse pool size %q", v.Value), v))
+			}
+		case "poolIdleTimeout":
+			if t.PoolIdleTimeout, err = time.ParseDuration(v.Value); err != nil {
+				logrus.Warn(WithLoc(fmt.Sprintf("unable to parse pool idle timeout %q", v.Value), v))
+			}
+		default:
+			logrus.Warn(WithLoc(fmt.Sprintf("unknown key %q", kv), ms[i]))
+		}
+	}
+	return nil
+}
+
+// IsZero controls whether this element will be included in marshalled output.
+func (t Tunnel) IsZero() bool {
+	return t.BufferSizeV.IsZero() && t.WindowSize == 0 && t.PoolSize == 0 && t.PoolIdleTimeout == 0
+}
+
+// MarshalYAML is not using pointer receiver here, because Tunnel is not pointer in the Config struct.
+func (t Tunnel) MarshalYAML() (any, error) {
+	m := make(map[string]any)
+	if !t.BufferSizeV.IsZero() {
+		m["bufferSize"] = t.BufferSizeV.String()
+	}
+	if t.WindowSize != 0 {
+		m["windowSize"] = t.WindowSize
+	}
+	if t.PoolSize != 0 {
+		m["poolSize"] = t.PoolSize
+	}
+	if t.PoolIdleTimeout != 0 {
+		m["poolIdleTimeout"] = t.PoolIdleTimeout.String()
+	}
+	return m, nil
+}
+
 type TelepresenceAPI struct {
 	Port int `json:"port,omitempty" yaml:"port,omitempty"`
 }
@@ -835,17 +1091,32 @@ type Cluster struct {
 	ConnectFromRootDaemon   bool     `json:"connectFromRootDaemon,omitempty" yaml:"connectFromRootDaemon,omitempty"`
 	AgentPortForward        bool     `json:"agentPortForward,omitempty" yaml:"agentPortForward,omitempty"`
 	VirtualIPSubnet         string   `json:"virtualIPSubnet,omitempty" yaml:"virtualIPSubnet,omitempty"`
+	ManagerVersionSkew      string   `json:"managerVersionSkew,omitempty" yaml:"managerVersionSkew,omitempty"`
+	ManagerServiceName      string   `json:"managerServiceName,omitempty" yaml:"managerServiceName,omitempty"`
 }
 
+// Values for Cluster.ManagerVersionSkew, which controls what happens when the client connects to a
+// traffic-manager whose version differs from the client's by more than a patch release.
+const (
+	VersionSkewWarn   = "warn"
+	VersionSkewError  = "error"
+	VersionSkewIgnore = "ignore"
+)
+
 // This is used by a different config -- the k8s_config, which needs to be able to tell if it's overridden at a cluster or environment variable level.
 // Hence, we don't default to "ambassador" but to empty, so that it can check that no default has been given.
 const defaultDefaultManagerNamespace = ""
 
+// DefaultManagerServiceName is the name of the traffic-manager service unless another name is configured.
+const DefaultManagerServiceName = "traffic-manager"
+
 var defaultCluster = Cluster{ //nolint:gochecknoglobals // constant
 	DefaultManagerNamespace: defaultDefaultManagerNamespace,
 	ConnectFromRootDaemon:   true,
 	AgentPortForward:        true,
 	VirtualIPSubnet:         defaultVirtualIPSubnet,
+	ManagerVersionSkew:      VersionSkewWarn,
+	ManagerServiceName:      DefaultManagerServiceName,
 }
 
 func (cc *Cluster) merge(o *Cluster) {
@@ -864,6 +1135,12 @@ func (cc *Cluster) merge(o *Cluster) {
 	if o.VirtualIPSubnet != defaultVirtualIPSubnet {
 		cc.VirtualIPSubnet = o.VirtualIPSubnet
 	}
+	if o.ManagerVersionSkew != VersionSkewWarn {
+		cc.ManagerVersionSkew = o.ManagerVersionSkew
+	}
+	if o.ManagerServiceName != DefaultManagerServiceName {
+		cc.ManagerServiceName = o.ManagerServiceName
+	}
 }
 
 // IsZero controls whether this element will be included in marshalled output.
@@ -872,7 +1149,9 @@ func (cc Cluster) IsZero() bool {
 		len(cc.MappedNamespaces) == 0 &&
 		cc.ConnectFromRootDaemon &&
 		cc.AgentPortForward &&
-		cc.VirtualIPSubnet == defaultVirtualIPSubnet
+		cc.VirtualIPSubnet == defaultVirtualIPSubnet &&
+		cc.ManagerVersionSkew == VersionSkewWarn &&
+		cc.ManagerServiceName == DefaultManagerServiceName
 }
 
 // MarshalYAML is not using pointer receiver here, because Cluster is not pointer in the Config struct.
@@ -893,9 +1172,41 @@ func (cc Cluster) MarshalYAML() (any, error) {
 	if cc.VirtualIPSubnet != defaultVirtualIPSubnet {
 		cm["virtualIPSubnet"] = cc.VirtualIPSubnet
 	}
+	if cc.ManagerVersionSkew != VersionSkewWarn {
+		cm["managerVersionSkew"] = cc.ManagerVersionSkew
+	}
+	if cc.ManagerServiceName != DefaultManagerServiceName {
+		cm["managerServiceName"] = cc.ManagerServiceName
+	}
 	return cm, nil
 }
 
+// UnmarshalYAML parses the cluster YAML and validates the managerVersionSkew.
+func (cc *Cluster) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return errors.New(WithLoc("cluster must be an object", node))
+	}
+	type plainCluster Cluster
+	if err := node.Decode((*plainCluster)(cc)); err != nil {
+		return err
+	}
+	ms := node.Content
+	top := len(ms)
+	for i := 0; i < top; i += 2 {
+		if kv, err := StringKey(ms[i]); err != nil || kv != "managerVersionSkew" {
+			continue
+		}
+		switch v := ms[i+1]; v.Value {
+		case VersionSkewWarn, VersionSkewError, VersionSkewIgnore:
+		default:
+			logrus.Warn(WithLoc(fmt.Sprintf("invalid managerVersionSkew %q, must be %q, %q, or %q. Using %q",
+				v.Value, VersionSkewIgnore, VersionSkewWarn, VersionSkewError, VersionSkewWarn), v))
+			cc.ManagerVersionSkew = VersionSkewWarn
+		}
+	}
+	return nil
+}
+
 var (
 	parsedFile string     //nolint:gochecknoglobals // protected by parseLock
 	parseLock  sync.Mutex //nolint:gochecknoglobals // protects parsedFile
@@ -957,6 +1268,7 @@ func GetDefaultBaseConfig() BaseConfig {
 		OSSpecificConfig: GetDefaultOSSpecificConfig(),
 		TimeoutsV:        defaultTimeouts,
 		LogLevelsV:       defaultLogLevels,
+		LogRotationV:     defaultLogRotation,
 		ImagesV:          defaultImages,
 		GrpcV:            Grpc{},
 		TelepresenceAPIV: TelepresenceAPI{},
@@ -1030,6 +1342,9 @@ type RoutingSnake struct {
 	AlsoProxy        []*iputil.Subnet `json:"also_proxy_subnets,omitempty" yaml:"also_proxy_subnets,omitempty"`
 	NeverProxy       []*iputil.Subnet `json:"never_proxy_subnets,omitempty" yaml:"never_proxy_subnets,omitempty"`
 	AllowConflicting []*iputil.Subnet `json:"allow_conflicting_subnets,omitempty" yaml:"allow_conflicting_subnets,omitempty"`
+	Protocols        []string         `json:"routed_protocols,omitempty" yaml:"routed_protocols,omitempty"`
+	DNSOnly          bool             `json:"dns_only,omitempty" yaml:"dns_only,omitempty"`
+	RouteOnly        bool             `json:"route_only,omitempty" yaml:"route_only,omitempty"`
 }
 
 type DNS struct {
@@ -1041,6 +1356,8 @@ type DNS struct {
 	Excludes        []string      `json:"excludes,omitempty" yaml:"excludes,omitempty"`
 	Mappings        DNSMappings   `json:"mappings,omitempty" yaml:"mappings,omitempty"`
 	LookupTimeout   time.Duration `json:"lookupTimeout,omitempty" yaml:"lookupTimeout,omitempty"`
+	CacheTTL        time.Duration `json:"cacheTTL,omitempty" yaml:"cacheTTL,omitempty"`
+	NegativeTTL     time.Duration `json:"negativeTTL,omitempty" yaml:"negativeTTL,omitempty"`
 }
 
 // DNSSnake is the same as DNS but with snake_case json/yaml names.
@@ -1053,6 +1370,9 @@ type DNSSnake struct {
 	Excludes        []string      `json:"excludes,omitempty" yaml:"excludes,omitempty"`
 	Mappings        DNSMappings   `json:"mappings,omitempty" yaml:"mappings,omitempty"`
 	LookupTimeout   time.Duration `json:"lookup_timeout,omitempty" yaml:"lookup_timeout,omitempty"`
+	CacheTTL        time.Duration `json:"cache_ttl,omitempty" yaml:"cache_ttl,omitempty"`
+	NegativeTTL     time.Duration `json:"negative_ttl,omitempty" yaml:"negative_ttl,omitempty"`
+	ListenAddress   string        `json:"listen_address,omitempty" yaml:"listen_address,omitempty"`
 }
 
 type SessionConfig struct {
@@ -1,12 +1,14 @@
 package client
 
 import (
+	"math"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
@@ -23,6 +25,7 @@ func TestGetConfig(t *testing.T) {
 logLevels:
   userDaemon: info
   rootDaemon: debug
+logFormat: json
 cluster:
   defaultManagerNamespace: hello
 `,
@@ -36,6 +39,8 @@ logLevels:
 timeouts:
   clusterConnect: 25
   proxyDial: 17.0
+  interceptDrain: 2s
+  connectReady: 45s
 logLevels:
   rootDaemon: trace
 images:
@@ -50,6 +55,13 @@ intercept:
   useFtp: true
 cluster:
   virtualIPSubnet: 192.169.0.0/16
+  managerVersionSkew: error
+  managerServiceName: tel-manager
+tunnel:
+  bufferSize: 256Ki
+  windowSize: 100
+  poolSize: 4
+  poolIdleTimeout: 10s
 `,
 	}
 
@@ -77,10 +89,13 @@ cluster:
 	to := cfg.Timeouts()
 	assert.Equal(t, 25*time.Second, to.PrivateClusterConnect)      // from user
 	assert.Equal(t, 17*time.Second, to.PrivateProxyDial)           // from user
+	assert.Equal(t, 2*time.Second, to.PrivateInterceptDrain)       // from user
+	assert.Equal(t, 45*time.Second, to.PrivateConnectReady)        // from user
 	assert.Equal(t, time.Duration(0), to.PrivateConnectivityCheck) // from sys2
 
 	assert.Equal(t, logrus.DebugLevel, cfg.LogLevels().UserDaemon) // from sys2
 	assert.Equal(t, logrus.TraceLevel, cfg.LogLevels().RootDaemon) // from user
+	assert.Equal(t, LogFormatJSON, cfg.LogFormat())                // from sys1
 
 	assert.Equal(t, "testregistry.io", cfg.Images().PrivateRegistry)                             // from user
 	assert.Equal(t, "ambassador-telepresence-agent-image:0.0.2", cfg.Images().PrivateAgentImage) // from user
@@ -91,6 +106,12 @@ cluster:
 	assert.True(t, cfg.Intercept().UseFtp)                                                       // from user
 	assert.Equal(t, cfg.Cluster().DefaultManagerNamespace, "hello")                              // from sys1
 	assert.Equal(t, cfg.Cluster().VirtualIPSubnet, "192.169.0.0/16")                             // from user
+	assert.Equal(t, VersionSkewError, cfg.Cluster().ManagerVersionSkew)                          // from user
+	assert.Equal(t, "tel-manager", cfg.Cluster().ManagerServiceName)                             // from user
+	assert.Equal(t, 0x40000, cfg.Tunnel().BufferSize())                                          // from user
+	assert.Equal(t, 100, cfg.Tunnel().WindowSize)                                                // from user
+	assert.Equal(t, 4, cfg.Tunnel().PoolSize)                                                    // from user
+	assert.Equal(t, 10*time.Second, cfg.Tunnel().GetPoolIdleTimeout())                           // from user
 }
 
 func Test_ConfigMarshalYAML(t *testing.T) {
@@ -107,6 +128,11 @@ func Test_ConfigMarshalYAML(t *testing.T) {
 	cfg.Intercept().AppProtocolStrategy = k8sapi.PortName
 	cfg.Intercept().DefaultPort = 9080
 	cfg.Cluster().DefaultManagerNamespace = "hello-there"
+	cfg.Cluster().ManagerServiceName = "tel-manager"
+	cfg.Tunnel().BufferSizeV, _ = resource.ParseQuantity("2Mi")
+	cfg.Tunnel().WindowSize = 20
+	cfg.Tunnel().PoolSize = 2
+	cfg.Tunnel().PoolIdleTimeout = time.Minute
 	cfgBytes, err := yaml.Marshal(cfg)
 	require.NoError(t, err)
 
@@ -126,3 +152,58 @@ func Test_ConfigMarshalYAMLDefaults(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "{}\n", string(cfgBytes))
 }
+
+func Test_ClusterUnmarshalYAML(t *testing.T) {
+	hook := logtest.NewGlobal()
+	defer hook.Reset()
+
+	tests := []struct {
+		value string
+		want  string
+		warns bool
+	}{
+		{"ignore", VersionSkewIgnore, false},
+		{"warn", VersionSkewWarn, false},
+		{"error", VersionSkewError, false},
+		{"fail", VersionSkewWarn, true},
+		{"Error", VersionSkewWarn, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			hook.Reset()
+			cfg, err := ParseConfigYAML([]byte("cluster:\n  managerVersionSkew: " + tt.value + "\n  managerServiceName: tm\n"))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cfg.Cluster().ManagerVersionSkew)
+			assert.Equal(t, "tm", cfg.Cluster().ManagerServiceName)
+			if tt.warns {
+				require.Len(t, hook.Entries, 1)
+				assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+				assert.Contains(t, hook.LastEntry().Message, "line 2: invalid managerVersionSkew")
+			} else {
+				assert.Empty(t, hook.Entries)
+			}
+		})
+	}
+}
+
+func TestTunnel_BufferSize(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int
+	}{
+		{"", 0},
+		{"256Ki", 0x40000},
+		{"2Gi", math.MaxInt32},
+		{"1Ei", math.MaxInt32},
+		{"1e30", math.MaxInt32},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			var tc Tunnel
+			if tt.value != "" {
+				tc.BufferSizeV = resource.MustParse(tt.value)
+			}
+			assert.Equal(t, tt.want, tc.BufferSize())
+		})
+	}
+}
@@ -14,6 +14,15 @@ import (
 const (
 	// APIVersion is the API version of the daemon and connector API.
 	APIVersion = 3
+
+	// AllNamespaces is the namespace that makes the user daemon list and watch workloads in all mapped
+	// namespaces that the client can access. It's not a valid namespace name, so it can't be confused
+	// with a real namespace.
+	AllNamespaces = "*"
+
+	// CredentialPluginCommand is the hidden telepresence command that runs an exec credential plugin on behalf
+	// of the user daemon, and kills it when it doesn't complete within the given timeout.
+	CredentialPluginCommand = "credential-plugin"
 )
 
 // DisplayVersion returns a printable version for `telepresence`.
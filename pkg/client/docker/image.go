@@ -12,10 +12,17 @@ import (
 	"github.com/telepresenceio/telepresence/v2/pkg/proc"
 )
 
-// BuildImage builds an image from source. Stdout is silenced during those operations. The
+// BuildImage builds an image from source. The output from the build is streamed to the given writer. The
 // image ID is returned.
-func BuildImage(ctx context.Context, context string, buildArgs []string) (string, error) {
-	args := append([]string{"build", "--quiet"}, buildArgs...)
+func BuildImage(ctx context.Context, context string, buildArgs []string, out io.Writer) (string, error) {
+	iidFile, err := os.CreateTemp("", "tel-*.iid")
+	if err != nil {
+		return "", err
+	}
+	_ = iidFile.Close()
+	defer os.Remove(iidFile.Name())
+
+	args := append([]string{"build", "--iidfile", iidFile.Name()}, buildArgs...)
 	st, err := os.Stat(context)
 	if err != nil {
 		return "", err
@@ -34,28 +41,50 @@ func BuildImage(ctx context.Context, context string, buildArgs []string) (string
 		context = dir
 		args = append(args, "--file", fn)
 	}
-	cmd := proc.StdCommand(ctx, "docker", append(args, context)...)
-	var out bytes.Buffer
-	cmd.Stdout = &out
+	cmd := proc.StdCommand(ctx, Runtime(ctx), append(args, context)...)
+	cmd.Stdout = out
+	cmd.Stderr = out
 	if err := cmd.Run(); err != nil {
 		return "", err
 	}
-	return strings.TrimSpace(out.String()), nil
+	iid, err := os.ReadFile(iidFile.Name())
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(iid)), nil
+}
+
+// RemoveImage removes the image with the given ID or tag. Only the tag is removed when other tags refer to the
+// same image. The removal will fail if the image is used by a container.
+func RemoveImage(ctx context.Context, image string) error {
+	cmd := proc.StdCommand(ctx, Runtime(ctx), "image", "rm", image)
+	cmd.Stdout = io.Discard
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w", strings.TrimSpace(stderr.String()), err)
+	}
+	return nil
 }
 
 // PullImage checks if the given image exists locally by doing docker image inspect. A docker pull is
 // performed if no local image is found. Stdout is silenced during those operations.
 func PullImage(ctx context.Context, image string) error {
-	cli, err := GetClient(ctx)
-	if err != nil {
-		return err
-	}
-	_, _, err = cli.ImageInspectWithRaw(ctx, image)
-	if err == nil {
-		// Image exists in the local cache, so don't bother pulling it.
+	rt := Runtime(ctx)
+	if rt == RuntimeDocker {
+		cli, err := GetClient(ctx)
+		if err != nil {
+			return err
+		}
+		if _, _, err = cli.ImageInspectWithRaw(ctx, image); err == nil {
+			// Image exists in the local cache, so don't bother pulling it.
+			return nil
+		}
+	} else if proc.StdCommand(ctx, rt, "image", "exists", image).Run() == nil {
+		// The docker client API isn't used for other runtimes, because their API socket is often disabled.
 		return nil
 	}
-	cmd := proc.StdCommand(ctx, "docker", "pull", image)
+	cmd := proc.StdCommand(ctx, rt, "pull", image)
 	// Docker run will put the pull logs in stderr, but docker pull will put them in stdout.
 	// We discard them here, so they don't spam the user. They'll get errors through stderr if it comes to it.
 	cmd.Stdout = io.Discard
@@ -64,8 +93,7 @@ func PullImage(ctx context.Context, image string) error {
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
-	err = cmd.Run()
-	if err != nil {
+	if err := cmd.Run(); err != nil {
 		fmt.Fprint(os.Stderr, stderr.String())
 		return err
 	}
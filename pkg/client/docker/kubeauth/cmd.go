@@ -100,7 +100,7 @@ func (as *authService) run(cmd *cobra.Command, _ []string) error {
 	g.Go("portfile-watcher", as.watchFiles)
 	g.Go("grpc-server", func(ctx context.Context) error {
 		grpcHandler := grpc.NewServer()
-		authGrpc.RegisterAuthenticatorServer(grpcHandler, config)
+		authGrpc.RegisterAuthenticatorServer(grpcHandler, config, cfg.Timeouts().Get(client.TimeoutCredentialPlugin))
 		sc := &dhttp.ServerConfig{Handler: grpcHandler}
 		return sc.Serve(ctx, grpcListener)
 	})
@@ -0,0 +1,49 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/datawire/dlib/dexec"
+)
+
+const (
+	// RuntimeDocker is the docker CLI.
+	RuntimeDocker = "docker"
+
+	// RuntimePodman is the podman CLI. Its command line arguments are compatible with those of docker.
+	RuntimePodman = "podman"
+)
+
+type runtimeKey struct{}
+
+// WithRuntime returns a context that makes the functions in this package use the given container runtime CLI.
+func WithRuntime(ctx context.Context, runtime string) context.Context {
+	return context.WithValue(ctx, runtimeKey{}, runtime)
+}
+
+// Runtime returns the container runtime CLI to use. The default is "docker".
+func Runtime(ctx context.Context) string {
+	if rt, ok := ctx.Value(runtimeKey{}).(string); ok && rt != "" {
+		return rt
+	}
+	return RuntimeDocker
+}
+
+// ResolveRuntime validates the given container runtime. An empty string means that the runtime is detected by
+// looking for a docker executable in the path, and then for a podman executable.
+func ResolveRuntime(runtime string) (string, error) {
+	switch runtime {
+	case RuntimeDocker, RuntimePodman:
+		return runtime, nil
+	case "":
+		for _, rt := range []string{RuntimeDocker, RuntimePodman} {
+			if _, err := dexec.LookPath(rt); err == nil {
+				return rt, nil
+			}
+		}
+		return RuntimeDocker, nil
+	default:
+		return "", fmt.Errorf("invalid container runtime %q, must be %q or %q", runtime, RuntimeDocker, RuntimePodman)
+	}
+}
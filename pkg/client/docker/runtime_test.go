@@ -0,0 +1,37 @@
+package docker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveRuntime(t *testing.T) {
+	rt, err := ResolveRuntime(RuntimePodman)
+	require.NoError(t, err)
+	assert.Equal(t, RuntimePodman, rt)
+
+	_, err = ResolveRuntime("containerd")
+	assert.Error(t, err)
+
+	if runtime.GOOS != "windows" {
+		// Only podman can be found in the path.
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, RuntimePodman), []byte("#!/bin/sh\n"), 0o755))
+		t.Setenv("PATH", dir)
+		rt, err = ResolveRuntime("")
+		require.NoError(t, err)
+		assert.Equal(t, RuntimePodman, rt)
+	}
+}
+
+func TestRuntime(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, RuntimeDocker, Runtime(ctx))
+	assert.Equal(t, RuntimePodman, Runtime(WithRuntime(ctx, RuntimePodman)))
+}
@@ -24,6 +24,9 @@ type Env struct {
 	// The address that the user daemon is listening to (unless it is started by the client and uses a named pipe or unix socket).
 	UserDaemonAddress string `env:"TELEPRESENCE_USER_DAEMON_ADDRESS, parser=possibly-empty-string,default="`
 	ScoutDisable      bool   `env:"SCOUT_DISABLE, parser=strconv.ParseBool, default=0"`
+
+	// Path to a file where the user daemon writes connection lifecycle events as newline-delimited JSON.
+	EventLog string `env:"TELEPRESENCE_EVENT_LOG, parser=possibly-empty-string,default="`
 }
 
 type envKey struct{}
@@ -5,8 +5,11 @@ import (
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
 	"gopkg.in/yaml.v3"
@@ -90,12 +93,21 @@ type DnsConfig struct {
 
 	// The maximum time to wait for a cluster side host lookup.
 	LookupTimeout v1.Duration `json:"lookup-timeout,omitempty"`
+
+	// The maximum time that a resolved name is kept in the DNS cache.
+	CacheTTL v1.Duration `json:"cache-ttl,omitempty"`
+
+	// The maximum time that a negative (NXDOMAIN) result is kept in the DNS cache.
+	NegativeTTL v1.Duration `json:"negative-ttl,omitempty"`
 }
 
 // The ManagerConfig is part of the KubeconfigExtension struct. It configures discovery of the traffic manager.
 type ManagerConfig struct {
 	// Namespace is the name of the namespace where the traffic manager is to be found
 	Namespace string `json:"namespace,omitempty"`
+
+	// ServiceName is the name of the traffic manager's service
+	ServiceName string `json:"serviceName,omitempty"`
 }
 
 // KubeconfigExtension is an extension read from the selected kubeconfig Cluster.
@@ -242,7 +254,56 @@ func DaemonKubeconfig(c context.Context, cr *connector.ConnectRequest) (*Kubecon
 	if err != nil {
 		return nil, err
 	}
-	return newKubeconfig(c, cr.KubeFlags, flagMap, cr.ManagerNamespace, configFlags, cr.KubeconfigData)
+	k, err := newKubeconfig(c, cr.KubeFlags, flagMap, cr.ManagerNamespace, configFlags, cr.KubeconfigData)
+	if err != nil {
+		return nil, err
+	}
+	if cr.ManagerServiceName != "" {
+		k.KubeconfigExtension.Manager.ServiceName = cr.ManagerServiceName
+	}
+	if cr.ProxyUrl != "" {
+		// Use the proxy in place of the one declared by the cluster's proxy-url.
+		var proxyURL *url.URL
+		if proxyURL, err = ParseProxyURL(cr.ProxyUrl); err != nil {
+			return nil, err
+		}
+		k.RestConfig.Proxy = http.ProxyURL(proxyURL)
+	}
+	if ep := k.RestConfig.ExecProvider; ep != nil {
+		k.RestConfig.ExecProvider = timedExecProvider(ep, GetExe(c), GetConfig(c).Timeouts().Get(TimeoutCredentialPlugin))
+	}
+	return k, nil
+}
+
+// timedExecProvider returns an exec config that runs the given exec credential plugin using the hidden
+// credential-plugin command of the given telepresence executable. client-go has no way to time out a
+// plugin, so the command kills it when it doesn't complete within the given timeout.
+func timedExecProvider(ep *api.ExecConfig, exe string, timeout time.Duration) *api.ExecConfig {
+	if timeout <= 0 {
+		return ep
+	}
+	tp := *ep
+	tp.Command = exe
+	tp.Args = append([]string{CredentialPluginCommand, "--timeout", timeout.String(), "--", ep.Command}, ep.Args...)
+	return &tp
+}
+
+// ParseProxyURL parses the given proxy URL and validates that its scheme is one that
+// is supported by the Kubernetes client, i.e. http, https, or socks5.
+func ParseProxyURL(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, errcat.User.Newf("invalid proxy URL %q: %v", s, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, errcat.User.Newf("invalid proxy URL %q: scheme must be http, https, or socks5", s)
+	}
+	if u.Host == "" {
+		return nil, errcat.User.Newf("invalid proxy URL %q: no host", s)
+	}
+	return u, nil
 }
 
 // AppendKubeFlags appends the flags in the given map to the given slice in the form of
@@ -496,6 +557,9 @@ func newKubeconfig(
 	if k.KubeconfigExtension.Manager.Namespace == "" {
 		k.KubeconfigExtension.Manager.Namespace = GetConfig(ctx).Cluster().DefaultManagerNamespace
 	}
+	if k.KubeconfigExtension.Manager.ServiceName == "" {
+		k.KubeconfigExtension.Manager.ServiceName = GetConfig(ctx).Cluster().ManagerServiceName
+	}
 	return k, nil
 }
 
@@ -536,7 +600,8 @@ func NewInClusterConfig(c context.Context, flagMap map[string]string) (*Kubeconf
 		// it may be empty, but we should avoid nil deref
 		KubeconfigExtension: KubeconfigExtension{
 			Manager: &ManagerConfig{
-				Namespace: managerNamespace,
+				Namespace:   managerNamespace,
+				ServiceName: GetConfig(c).Cluster().ManagerServiceName,
 			},
 		},
 	}, nil
@@ -559,6 +624,14 @@ func (kf *Kubeconfig) GetManagerNamespace() string {
 	return kf.KubeconfigExtension.Manager.Namespace
 }
 
+// GetManagerServiceName returns the name of the traffic-manager service.
+func (kf *Kubeconfig) GetManagerServiceName() string {
+	if sn := kf.KubeconfigExtension.Manager.ServiceName; sn != "" {
+		return sn
+	}
+	return DefaultManagerServiceName
+}
+
 func (kf *Kubeconfig) GetRestConfig() *rest.Config {
 	return kf.RestConfig
 }
@@ -606,6 +679,16 @@ func (kf *Kubeconfig) AddRemoteKubeConfigExtension(ctx context.Context, cfgYaml
 			dlog.Debugf(ctx, "Applying remote lookupTimeout: %s", dns.LookupTimeout)
 			kf.DNS.LookupTimeout.Duration = dns.LookupTimeout
 		}
+
+		if kf.DNS.CacheTTL.Duration == 0 {
+			dlog.Debugf(ctx, "Applying remote cacheTTL: %s", dns.CacheTTL)
+			kf.DNS.CacheTTL.Duration = dns.CacheTTL
+		}
+
+		if kf.DNS.NegativeTTL.Duration == 0 {
+			dlog.Debugf(ctx, "Applying remote negativeTTL: %s", dns.NegativeTTL)
+			kf.DNS.NegativeTTL.Duration = dns.NegativeTTL
+		}
 	}
 	if routing := remote.Routing; routing != nil {
 		if len(routing.AlsoProxy) > 0 {
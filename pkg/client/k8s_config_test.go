@@ -0,0 +1,62 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestParseProxyURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		wantErr bool
+	}{
+		{"socks5", "socks5://jump.example.com:1080", false},
+		{"http", "http://proxy.example.com:3128", false},
+		{"https", "https://proxy.example.com", false},
+		{"bad scheme", "ssh://jump.example.com", true},
+		{"no scheme", "jump.example.com:1080", true},
+		{"no host", "socks5://", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := ParseProxyURL(tt.url)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.url, u.String())
+		})
+	}
+}
+
+func Test_timedExecProvider(t *testing.T) {
+	ep := &api.ExecConfig{
+		Command:    "aws",
+		Args:       []string{"eks", "get-token"},
+		APIVersion: "client.authentication.k8s.io/v1beta1",
+	}
+	tests := []struct {
+		name     string
+		timeout  time.Duration
+		wantCmd  string
+		wantArgs []string
+	}{
+		{"wrapped", 30 * time.Second, "/usr/bin/telepresence", []string{CredentialPluginCommand, "--timeout", "30s", "--", "aws", "eks", "get-token"}},
+		{"no timeout", 0, "aws", []string{"eks", "get-token"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tp := timedExecProvider(ep, "/usr/bin/telepresence", tt.timeout)
+			assert.Equal(t, tt.wantCmd, tp.Command)
+			assert.Equal(t, tt.wantArgs, tp.Args)
+			assert.Equal(t, ep.APIVersion, tp.APIVersion)
+		})
+	}
+	assert.Equal(t, "aws", ep.Command, "the original exec config must not be modified")
+}
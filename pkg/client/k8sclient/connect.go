@@ -19,8 +19,12 @@ import (
 	"github.com/telepresenceio/telepresence/v2/pkg/dnet"
 )
 
-func ConnectToManager(ctx context.Context, namespace string, grpcDialer dnet.DialerFunc) (*grpc.ClientConn, manager.ManagerClient, *manager.VersionInfo2, error) {
-	grpcAddr := net.JoinHostPort("svc/traffic-manager."+namespace, "api")
+func ConnectToManager(
+	ctx context.Context,
+	namespace, serviceName string,
+	grpcDialer dnet.DialerFunc,
+) (*grpc.ClientConn, manager.ManagerClient, *manager.VersionInfo2, error) {
+	grpcAddr := net.JoinHostPort("svc/"+serviceName+"."+namespace, "api")
 	conn, err := dialClusterGRPC(ctx, grpcAddr, grpcDialer)
 	if err != nil {
 		return nil, nil, nil, err
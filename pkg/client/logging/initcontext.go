@@ -3,6 +3,7 @@ package logging
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
@@ -31,13 +32,18 @@ func InitContext(ctx context.Context, name string, strategy RotationStrategy, ca
 	logger.SetLevel(logrus.InfoLevel)
 	logger.ReportCaller = false // turned on when level >= logrus.TraceLevel
 
-	if captureStd && IsTerminal(int(os.Stdout.Fd())) {
+	toTerminal := captureStd && IsTerminal(int(os.Stdout.Fd()))
+	if toTerminal {
 		logger.Formatter = tlog.NewFormatter("15:04:05.0000")
 	} else {
 		logger.Formatter = tlog.NewFormatter("2006-01-02 15:04:05.0000")
-		maxFiles := uint16(5)
+		lr := client.GetConfig(ctx).LogRotation()
+		maxFiles := uint16(max(lr.MaxBackups, 0) + 1)
+		if lr.MaxSizeMB > 0 {
+			strategy = NewRotateOnSize(int64(lr.MaxSizeMB)*1024*1024, strategy)
+		}
 
-		// TODO: Also make this a configurable setting in config.yml
+		// The environment variable takes precedence over the config.
 		if me := os.Getenv("TELEPRESENCE_MAX_LOGFILES"); me != "" {
 			if mx, err := strconv.Atoi(me); err == nil && mx >= 0 {
 				maxFiles = uint16(mx)
@@ -74,9 +80,23 @@ func InitContext(ctx context.Context, name string, strategy RotationStrategy, ca
 	}
 	tlog.SetLogrusLevel(logger, level.String(), false)
 	ctx = tlog.WithLevelSetter(ctx, logger)
+
+	// The log format only applies to log files. A terminal is always used by humans.
+	if !toTerminal && client.GetConfig(ctx).LogFormat() == client.LogFormatJSON {
+		logger.Formatter = tlog.NewJSONFormatter(JSONTimestampFormat)
+	}
 	return ctx, nil
 }
 
+// JSONTimestampFormat is the format of the "time" field of log entries when the log format is JSON.
+const JSONTimestampFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+// jsonLogEntry contains the fields of a JSON formatted log entry that are used when summarizing a log.
+type jsonLogEntry struct {
+	Level string `json:"level"`
+	Msg   string `json:"msg"`
+}
+
 func SummarizeLog(ctx context.Context, name string) (string, error) {
 	filename := filepath.Join(filelocation.AppUserLogDir(ctx), name+".log")
 	file, err := dos.Open(ctx, filename)
@@ -93,11 +113,21 @@ func SummarizeLog(ctx context.Context, name string) (string, error) {
 	for scanner.Scan() {
 		// XXX: is there a better way to detect error lines?
 		txt := scanner.Text()
-		parts := strings.Fields(txt)
-		if len(parts) < 3 {
-			continue
+		var level string
+		if strings.HasPrefix(txt, "{") {
+			var je jsonLogEntry
+			if json.Unmarshal([]byte(txt), &je) != nil {
+				continue
+			}
+			level, txt = je.Level, je.Msg
+		} else {
+			parts := strings.Fields(txt)
+			if len(parts) < 3 {
+				continue
+			}
+			level = parts[2]
 		}
-		switch parts[2] {
+		switch level {
 		case "error":
 			errorCount++
 		case "info":
@@ -2,11 +2,13 @@ package logging
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -256,4 +258,75 @@ func TestInitContext(t *testing.T) {
 		check.NoError(err)
 		check.Equal(maxFiles, len(files))
 	})
+	t.Run("json format", func(t *testing.T) {
+		ctx, _, logFile := testSetup(t)
+		check := require.New(t)
+		client.GetConfig(ctx).Base().LogFormatV = client.LogFormatJSON
+
+		c, err := InitContext(ctx, logName, RotateNever, true)
+		loggerForTest.AddHook(&dtimeHook{})
+		check.NoError(err)
+		check.NotNil(c)
+		infoMsg := "info message"
+		dlog.Info(dlog.WithField(c, "THREAD", "/worker"), infoMsg)
+		closeLog(t)
+
+		bs, err := os.ReadFile(logFile)
+		check.NoError(err)
+		var entry map[string]any
+		check.NoError(json.Unmarshal(bs, &entry))
+		check.Equal(map[string]any{
+			"time":      dtime.Now().Format(JSONTimestampFormat),
+			"level":     "info",
+			"goroutine": "worker",
+			"msg":       infoMsg,
+		}, entry)
+
+		summary, err := SummarizeLog(filelocation.WithAppUserLogDir(ctx, filepath.Dir(logFile)), logName)
+		check.NoError(err)
+		check.Empty(summary)
+	})
+	t.Run("rotates on size", func(t *testing.T) {
+		ctx, logDir, logFile := testSetup(t)
+		check := require.New(t)
+		client.GetConfig(ctx).LogRotation().MaxSizeMB = 1
+
+		c, err := InitContext(ctx, logName, RotateNever, true)
+		loggerForTest.AddHook(&dtimeHook{})
+		check.NoError(err)
+		check.NotNil(c)
+		msg := strings.Repeat("x", 1024)
+		for i := 0; i < 1100; i++ {
+			dlog.Info(c, msg)
+		}
+		closeLog(t)
+
+		backupFile := filepath.Join(logDir, fmt.Sprintf("%s-%s.log", logName, dtime.Now().Format("20060102T150405")))
+		check.FileExists(backupFile)
+		st, err := os.Stat(backupFile)
+		check.NoError(err)
+		check.LessOrEqual(st.Size(), int64(1024*1024))
+		st, err = os.Stat(logFile)
+		check.NoError(err)
+		check.Less(st.Size(), int64(1024*1024))
+	})
+	t.Run("rotates on size more than once per second", func(t *testing.T) {
+		ctx, logDir, _ := testSetup(t)
+		check := require.New(t)
+		client.GetConfig(ctx).LogRotation().MaxSizeMB = 1
+
+		c, err := InitContext(ctx, logName, RotateNever, true)
+		loggerForTest.AddHook(&dtimeHook{})
+		check.NoError(err)
+		check.NotNil(c)
+		msg := strings.Repeat("x", 1024)
+		for i := 0; i < 3300; i++ {
+			dlog.Info(c, msg)
+		}
+		closeLog(t)
+
+		backups, err := filepath.Glob(filepath.Join(logDir, logName+"-*.log"))
+		check.NoError(err)
+		check.Len(backups, 3)
+	})
 }
@@ -63,6 +63,27 @@ func (rotateDaily) RotateNow(rf *RotatingFile, _ int) bool {
 	return dtime.Now().In(bt.Location()).Day() != rf.BirthTime().Day()
 }
 
+// A rotateOnSize rotates the file when a write would make it exceed a maximum size, and otherwise
+// delegates the decision to another strategy.
+type rotateOnSize struct {
+	maxSize int64
+	next    RotationStrategy
+}
+
+// NewRotateOnSize returns a strategy that ensures that the file is rotated if it is of non-zero size and a
+// call to Write() would make it exceed the given maxSize. The next strategy is consulted otherwise.
+func NewRotateOnSize(maxSize int64, next RotationStrategy) RotationStrategy {
+	return &rotateOnSize{maxSize: maxSize, next: next}
+}
+
+func (r *rotateOnSize) RotateNow(rf *RotatingFile, writeSize int) bool {
+	if r.next.RotateNow(rf, writeSize) {
+		return true
+	}
+	sz := rf.Size()
+	return sz > 0 && sz+int64(writeSize) > r.maxSize
+}
+
 type RotatingFile struct {
 	ctx         context.Context
 	fileMode    fs.FileMode
@@ -332,8 +353,18 @@ func (rf *RotatingFile) rotate() error {
 		fullPath := filepath.Join(rf.dirName, rf.fileName)
 		ex := filepath.Ext(rf.fileName)
 		sf := fullPath[:len(fullPath)-len(ex)]
-		ts := rf.fileTime(dtime.Now()).Format(rf.timeFormat)
+		now := rf.fileTime(dtime.Now())
+		ts := now.Format(rf.timeFormat)
 		backupName = fmt.Sprintf("%s-%s%s", sf, ts, ex)
+
+		// A size based rotation may happen more than once within the precision of the timeFormat. A fractional
+		// second keeps the backup name unique, and is accepted by time.Parse when removeOldFiles parses the name.
+		for ns := now.Nanosecond(); ; ns++ {
+			if _, err = dos.Stat(rf.ctx, backupName); err != nil {
+				break
+			}
+			backupName = fmt.Sprintf("%s-%s.%09d%s", sf, ts, ns, ex)
+		}
 	}
 	err := rf.openNew(prevInfo, backupName)
 	if err != nil {
@@ -18,12 +18,47 @@ import (
 
 type sftpMounter struct {
 	sync.Mutex
-	iceptWG *sync.WaitGroup
-	podWG   *sync.WaitGroup
+	iceptWG  *sync.WaitGroup
+	podWG    *sync.WaitGroup
+	readOnly bool
 }
 
-func NewSFTPMounter(iceptWG, podWG *sync.WaitGroup) Mounter {
-	return &sftpMounter{iceptWG: iceptWG, podWG: podWG}
+// NewSFTPMounter returns a Mounter that uses sshfs. The mount will be read-only when readOnly is true.
+func NewSFTPMounter(iceptWG, podWG *sync.WaitGroup, readOnly bool) Mounter {
+	return &sftpMounter{iceptWG: iceptWG, podWG: podWG, readOnly: readOnly}
+}
+
+// sshfsArgs returns the arguments for the sshfs command that mounts the mountPoint of the given pod at
+// clientMountPoint. An IPv6 pod is mounted using stdin/stdout, which the caller must connect to the pod.
+func (m *sftpMounter) sshfsArgs(clientMountPoint, mountPoint string, podIP net.IP, port uint16) []string {
+	args := []string{
+		"-F", "none", // don't load the user's config file
+		"-f", // foreground operation
+
+		// connection settings
+		"-C", // compression
+		"-oConnectTimeout=10",
+
+		// mount directives
+		"-o", "follow_symlinks",
+		"-o", "allow_root", // needed to make --docker-run work as docker runs as root
+	}
+	if m.readOnly {
+		args = append(args, "-o", "ro")
+	}
+	if len(podIP) == 16 {
+		// Must use stdin/stdout because sshfs is not capable of connecting with IPv6
+		return append(args,
+			"-o", "slave",
+			fmt.Sprintf("localhost:%s", mountPoint),
+			clientMountPoint, // where to mount it
+		)
+	}
+	return append(args,
+		"-o", fmt.Sprintf("directport=%d", port),
+		fmt.Sprintf("%s:%s", podIP.String(), mountPoint), // what to mount
+		clientMountPoint, // where to mount it
+	)
 }
 
 func (m *sftpMounter) Start(ctx context.Context, id, clientMountPoint, mountPoint string, podIP net.IP, port uint16) error {
@@ -47,41 +82,14 @@ func (m *sftpMounter) Start(ctx context.Context, id, clientMountPoint, mountPoin
 
 		// Retry mount in case it gets disconnected
 		err := client.Retry(ctx, "sshfs", func(ctx context.Context) error {
-			sshfsArgs := []string{
-				"-F", "none", // don't load the user's config file
-				"-f", // foreground operation
-
-				// connection settings
-				"-C", // compression
-				"-oConnectTimeout=10",
-
-				// mount directives
-				"-o", "follow_symlinks",
-				"-o", "allow_root", // needed to make --docker-run work as docker runs as root
-			}
-
-			useIPv6 := len(podIP) == 16
-			if useIPv6 {
-				// Must use stdin/stdout because sshfs is not capable of connecting with IPv6
-				sshfsArgs = append(sshfsArgs,
-					"-o", "slave",
-					fmt.Sprintf("localhost:%s", mountPoint),
-					clientMountPoint, // where to mount it
-				)
-			} else {
-				sshfsArgs = append(sshfsArgs,
-					"-o", fmt.Sprintf("directport=%d", port),
-					fmt.Sprintf("%s:%s", podIP.String(), mountPoint), // what to mount
-					clientMountPoint, // where to mount it
-				)
-			}
-
+			sshfsArgs := m.sshfsArgs(clientMountPoint, mountPoint, podIP, port)
 			exe := "sshfs"
 			if runtime.GOOS == "windows" {
 				// Use sshfs-win to launch the sshfs
 				sshfsArgs = append([]string{"cmd", "-ouid=-1", "-ogid=-1"}, sshfsArgs...)
 				exe = "sshfs-win"
 			}
+			useIPv6 := len(podIP) == 16
 			var err error
 			if useIPv6 {
 				var conn net.Conn
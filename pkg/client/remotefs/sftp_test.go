@@ -0,0 +1,26 @@
+package remotefs
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_sshfsArgs(t *testing.T) {
+	common := []string{"-F", "none", "-f", "-C", "-oConnectTimeout=10", "-o", "follow_symlinks", "-o", "allow_root"}
+	ipv4 := net.IP{10, 0, 0, 1}
+
+	m := &sftpMounter{}
+	assert.Equal(t,
+		append(common, "-o", "directport=8022", "10.0.0.1:/tel_app_exports", "/tmp/mnt"),
+		m.sshfsArgs("/tmp/mnt", "/tel_app_exports", ipv4, 8022))
+
+	m.readOnly = true
+	assert.Equal(t,
+		append(common, "-o", "ro", "-o", "directport=8022", "10.0.0.1:/tel_app_exports", "/tmp/mnt"),
+		m.sshfsArgs("/tmp/mnt", "/tel_app_exports", ipv4, 8022))
+	assert.Equal(t,
+		append(common, "-o", "ro", "-o", "slave", "localhost:/tel_app_exports", "/tmp/mnt"),
+		m.sshfsArgs("/tmp/mnt", "/tel_app_exports", net.ParseIP("fd00::1"), 8022))
+}
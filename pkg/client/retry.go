@@ -2,8 +2,11 @@ package client
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"github.com/cenkalti/backoff/v4"
+
 	"github.com/datawire/dlib/dlog"
 )
 
@@ -13,6 +16,7 @@ const (
 )
 
 // Retry will run the given function repeatedly with an increasing delay until it returns without error.
+// An error wrapped using backoff.Permanent is returned immediately, without retrying.
 //
 // The function takes 0 to 2 durations with the following meaning
 //
@@ -46,6 +50,10 @@ func Retry(c context.Context, text string, f func(context.Context) error, durati
 			// success
 			return nil
 		}
+		var pe *backoff.PermanentError
+		if errors.As(err, &pe) {
+			return pe.Err
+		}
 
 		// Logging at higher log levels should be done in the called function
 		dlog.Debugf(c, "%s waiting %s before retrying after error: %v", text, delay.String(), err)
@@ -0,0 +1,39 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetry(t *testing.T) {
+	ctx := context.Background()
+	boom := errors.New("boom")
+
+	t.Run("retries until success", func(t *testing.T) {
+		calls := 0
+		err := Retry(ctx, "test", func(context.Context) error {
+			calls++
+			if calls < 3 {
+				return boom
+			}
+			return nil
+		}, time.Millisecond)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("permanent error is not retried", func(t *testing.T) {
+		calls := 0
+		err := Retry(ctx, "test", func(context.Context) error {
+			calls++
+			return backoff.Permanent(boom)
+		}, time.Millisecond)
+		assert.Same(t, boom, err)
+		assert.Equal(t, 1, calls)
+	})
+}
@@ -37,7 +37,7 @@ const (
 
 	// sanityCheck is the query used when verifying that a DNS query reaches our DNS server. It should result
 	// in an increase of the requestCount but always yield an NXDOMAIN reply.
-	santiyCheck    = "jhfweoitnkgyeta." + tel2SubDomain
+	santiyCheck    = dnsproxy.ProbeName
 	santiyCheckDot = santiyCheck + "."
 
 	// dnsTTL is the number of seconds that a found DNS record should be allowed to live in the callers cache. We
@@ -70,8 +70,9 @@ var DefaultExcludeSuffixes = []string{
 }
 
 type nsAndDomains struct {
-	domains   []string
-	namespace string
+	domains        []string
+	namespace      string
+	namespacesOnly bool
 }
 
 // Server is a DNS server which implements the github.com/miekg/dns Handler interface.
@@ -92,6 +93,10 @@ type Server struct {
 	// routes are typically namespaces, accessible using <service-name>.<namespace-name>.
 	routes map[string]struct{}
 
+	// namespacesOnly is true when the routes are the only namespaces that names in the "svc" domain are
+	// resolved for.
+	namespacesOnly bool
+
 	// search are appended to a query to form new names that are then dispatched to the
 	// DNS resolver. The act of appending is not performed by this server, but rather
 	// by the system's DNS resolver before calling on this server.
@@ -113,9 +118,22 @@ type Server struct {
 
 	lookupTimeout time.Duration
 
+	// cacheTTL is the time to live for an entry in the local DNS cache.
+	cacheTTL time.Duration
+
+	// negativeTTL is the time to live for a negative (NXDOMAIN) entry in the local DNS cache.
+	// Negative results are not cached when it is zero.
+	negativeTTL time.Duration
+
 	localIP  net.IP
 	remoteIP net.IP
 
+	// listenAddress is the configured address of the local listener. Empty means 127.0.0.1 and a random port.
+	listenAddress string
+
+	// listenAddr is the address that the local listener is bound to.
+	listenAddr *net.UDPAddr
+
 	// clusterDomain reported by the traffic-manager
 	clusterDomain string
 
@@ -136,11 +154,29 @@ type cacheEntry struct {
 	wait         chan struct{}
 }
 
-// cacheTTL is the time to live for an entry in the local DNS cache.
-const cacheTTL = 60 * time.Second
+// defaultCacheTTL is the default time to live for an entry in the local DNS cache.
+const defaultCacheTTL = 60 * time.Second
 
-func (dv *cacheEntry) expired() bool {
-	return time.Since(dv.created) > cacheTTL
+func (dv *cacheEntry) expired(ttl time.Duration) bool {
+	return time.Since(dv.created) > ttl
+}
+
+// entryTTL returns the time to live for the given completed cache entry.
+func (s *Server) entryTTL(dv *cacheEntry) time.Duration {
+	if dv.rCode == dns.RcodeSuccess {
+		return s.cacheTTL
+	}
+	return s.negativeTTL
+}
+
+// done returns true if the lookup that produces this entry has completed.
+func (dv *cacheEntry) done() bool {
+	select {
+	case <-dv.wait:
+		return true
+	default:
+		return false
+	}
 }
 
 func (dv *cacheEntry) close() {
@@ -169,6 +205,9 @@ func NewServer(config *rpc.DNSConfig, clusterLookup Resolver) *Server {
 	if config.LookupTimeout.AsDuration() <= 0 {
 		config.LookupTimeout = durationpb.New(8 * time.Second)
 	}
+	if config.CacheTtl.AsDuration() <= 0 {
+		config.CacheTtl = durationpb.New(defaultCacheTTL)
+	}
 	s := &Server{
 		cache:           xsync.NewMapOf[cacheKey, *cacheEntry](),
 		routes:          make(map[string]struct{}),
@@ -179,6 +218,7 @@ func NewServer(config *rpc.DNSConfig, clusterLookup Resolver) *Server {
 		mappings:        mappingsMap(config.Mappings),
 		localIP:         config.LocalIp,
 		remoteIP:        config.RemoteIp,
+		listenAddress:   config.ListenAddress,
 		dropSuffixes:    []string{tel2SubDomainDot},
 		search:          []string{tel2SubDomain},
 		nsAndDomainsCh:  make(chan nsAndDomains, 5),
@@ -189,6 +229,8 @@ func NewServer(config *rpc.DNSConfig, clusterLookup Resolver) *Server {
 	if lt := config.LookupTimeout; lt != nil {
 		s.lookupTimeout = lt.AsDuration()
 	}
+	s.cacheTTL = config.CacheTtl.AsDuration()
+	s.negativeTTL = config.NegativeTtl.AsDuration()
 	return s
 }
 
@@ -220,23 +262,54 @@ var (
 )
 
 func (s *Server) shouldDoClusterLookup(query string) bool {
+	include, _, _ := s.clusterLookupRule(query)
+	return include
+}
+
+// Names of the rules returned by clusterLookupRule.
+const (
+	ruleMapping       = "mapping"
+	ruleExclude       = "exclude"
+	ruleExcludePrefix = "exclude-prefix"
+	ruleExcludeSuffix = "exclude-suffix"
+	ruleIncludeSuffix = "include-suffix"
+	ruleSearch        = "search"
+	ruleNamespace     = "namespace"
+	ruleUnmapped      = "unmapped-namespace"
+	ruleClusterDomain = "cluster-domain"
+	ruleSingleLabel   = "single-label"
+)
+
+// clusterLookupRule determines if the given query should be resolved in the cluster, and returns the kind of rule
+// that made that decision together with the name, suffix, or namespace that the rule matched.
+func (s *Server) clusterLookupRule(query string) (include bool, rule, match string) {
 	name := query[:len(query)-1] // skip last dot
 	if strings.HasPrefix(query, wpadDot) {
 		// Reject "wpad.*"
 		dlog.Debugf(s.ctx, `Cluster DNS excluded by exclude-prefix "wpad." for name %q`, name)
-		return false
+		return false, ruleExcludePrefix, wpadDot
 	}
 
 	if s.isExcluded(name) {
 		// Reject any host explicitly added to the exclude list.
 		dlog.Debugf(s.ctx, "Cluster DNS explicitly excluded for name %q", name)
-		return false
+		return false, ruleExclude, name
+	}
+
+	if s.namespacesOnly {
+		// Reject names in the svc domain of namespaces that aren't mapped.
+		if ns := s.svcNamespace(name); ns != "" {
+			if _, ok := s.routes[ns]; !ok {
+				dlog.Debugf(s.ctx, "Cluster DNS excluded by unmapped namespace %q for name %q", ns, name)
+				return false, ruleUnmapped, ns
+			}
+		}
 	}
 
 	if !strings.ContainsRune(name, '.') {
 		// Single label names are always included.
 		dlog.Debugf(s.ctx, "Cluster DNS included for single label name %q", name)
-		return true
+		return true, ruleSingleLabel, name
 	}
 
 	// Skip configured exclude-suffixes unless also matched by an include-suffix
@@ -248,11 +321,11 @@ func (s *Server) shouldDoClusterLookup(query string) bool {
 				if len(is) >= len(es) && strings.HasSuffix(name, is) {
 					dlog.Debugf(s.ctx,
 						"Cluster DNS included by include-suffix %q (overriding exclude-suffix %q) for name %q", is, es, name)
-					return true
+					return true, ruleIncludeSuffix, is
 				}
 			}
 			dlog.Debugf(s.ctx, "Cluster DNS excluded by exclude-suffix %q for name %q", es, name)
-			return false
+			return false, ruleExcludeSuffix, es
 		}
 	}
 
@@ -260,7 +333,7 @@ func (s *Server) shouldDoClusterLookup(query string) bool {
 	for _, sfx := range s.search {
 		if strings.HasSuffix(name, sfx) {
 			dlog.Debugf(s.ctx, "Cluster DNS included by search %q of name %q", sfx, name)
-			return true
+			return true, ruleSearch, sfx
 		}
 	}
 
@@ -268,14 +341,14 @@ func (s *Server) shouldDoClusterLookup(query string) bool {
 	for sfx := range s.routes {
 		if strings.HasSuffix(name, sfx) {
 			dlog.Debugf(s.ctx, "Cluster DNS included by namespace %q of name %q", sfx, name)
-			return true
+			return true, ruleNamespace, sfx
 		}
 	}
 
 	// Always include queries for the cluster domain.
 	if strings.HasSuffix(query, "."+s.clusterDomain) {
 		dlog.Debugf(s.ctx, "Cluster DNS included by cluster domain %q of name %q", s.clusterDomain, name)
-		return true
+		return true, ruleClusterDomain, s.clusterDomain
 	}
 
 	// Always include configured includeSuffixes
@@ -283,13 +356,26 @@ func (s *Server) shouldDoClusterLookup(query string) bool {
 		if strings.HasSuffix(name, sfx) {
 			dlog.Debugf(s.ctx,
 				"Cluster DNS included by include-suffix %q for name %q", sfx, name)
-			return true
+			return true, ruleIncludeSuffix, sfx
 		}
 	}
 
 	// Pass any queries for the cluster domain.
 	dlog.Debugf(s.ctx, "Cluster DNS excluded for name %q. No inclusion rule was matched", name)
-	return false
+	return false, "", ""
+}
+
+// svcNamespace returns the namespace of a name in the "svc" domain, such as <service>.<namespace>.svc or
+// <service>.<namespace>.svc.<cluster domain>, or an empty string when the name isn't in that domain.
+func (s *Server) svcNamespace(name string) string {
+	if cd := strings.TrimSuffix(s.clusterDomain, "."); cd != "" {
+		name = strings.TrimSuffix(name, "."+cd)
+	}
+	name, ok := strings.CutSuffix(name, ".svc")
+	if !ok {
+		return ""
+	}
+	return name[strings.LastIndexByte(name, '.')+1:]
 }
 
 func (s *Server) isExcluded(name string) bool {
@@ -380,6 +466,17 @@ func (s *Server) GetConfig() *rpc.DNSConfig {
 	if s.lookupTimeout != 0 {
 		c.LookupTimeout = durationpb.New(s.lookupTimeout)
 	}
+	if s.cacheTTL != 0 {
+		c.CacheTtl = durationpb.New(s.cacheTTL)
+	}
+	if s.negativeTTL != 0 {
+		c.NegativeTtl = durationpb.New(s.negativeTTL)
+	}
+	if s.listenAddr != nil {
+		c.ListenAddress = s.listenAddr.String()
+	} else {
+		c.ListenAddress = s.listenAddress
+	}
 	if len(s.mappings) > 0 {
 		ns := maps.Keys(s.mappings)
 		slices.Sort(ns)
@@ -426,10 +523,13 @@ func (s *Server) SetClusterDNS(dns *manager.DNS, remoteIP net.IP) {
 }
 
 // SetTopLevelDomainsAndSearchPath updates the DNS top level domains and the search path used by the resolver.
-func (s *Server) SetTopLevelDomainsAndSearchPath(ctx context.Context, domains []string, namespace string) {
+// When namespacesOnly is true, names in the "svc" domain are only resolved when their namespace is one of
+// the domains.
+func (s *Server) SetTopLevelDomainsAndSearchPath(ctx context.Context, domains []string, namespace string, namespacesOnly bool) {
 	das := nsAndDomains{
-		domains:   domains,
-		namespace: namespace,
+		domains:        domains,
+		namespace:      namespace,
+		namespacesOnly: namespacesOnly,
 	}
 	select {
 	case <-ctx.Done():
@@ -490,9 +590,24 @@ func (s *Server) SetMappings(mappings []*rpc.DNSMapping) {
 	}
 }
 
-func newLocalUDPListener(c context.Context) (net.PacketConn, error) {
+// newLocalUDPListener creates the listener for the local DNS server, bound to the configured listen
+// address or, if no such address is configured, to 127.0.0.1 and a random port.
+func (s *Server) newLocalUDPListener(c context.Context) (net.PacketConn, error) {
+	addr := s.listenAddress
+	if addr == "" {
+		addr = "127.0.0.1:0"
+	}
 	lc := &net.ListenConfig{}
-	return lc.ListenPacket(c, "udp", "127.0.0.1:0")
+	pc, err := lc.ListenPacket(c, "udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if la, ok := pc.LocalAddr().(*net.UDPAddr); ok {
+		s.Lock()
+		s.listenAddr = la
+		s.Unlock()
+	}
+	return pc, nil
 }
 
 func (s *Server) processSearchPaths(g *dgroup.Group, processor func(context.Context, vif.Device) error, dev vif.Device) {
@@ -503,7 +618,7 @@ func (s *Server) processSearchPaths(g *dgroup.Group, processor func(context.Cont
 			namespace: "",
 		}
 		unchanged := func(das nsAndDomains) bool {
-			return das.namespace == prevDas.namespace && slices.Equal(das.domains, prevDas.domains)
+			return das.namespace == prevDas.namespace && das.namespacesOnly == prevDas.namespacesOnly && slices.Equal(das.domains, prevDas.domains)
 		}
 
 		for {
@@ -528,6 +643,7 @@ func (s *Server) processSearchPaths(g *dgroup.Group, processor func(context.Cont
 				}
 				s.Lock()
 				s.routes = routes
+				s.namespacesOnly = das.namespacesOnly
 
 				// The connected namespace must be included as a search path for the cases
 				// where it's up to the traffic-manager to resolve. It cannot resolve a single
@@ -543,6 +659,30 @@ func (s *Server) processSearchPaths(g *dgroup.Group, processor func(context.Cont
 	})
 }
 
+// evictExpired periodically removes entries that are older than the cache TTL from the cache until
+// the given context is cancelled.
+func (s *Server) evictExpired(c context.Context) error {
+	ttl := s.cacheTTL
+	ticker := time.NewTicker(ttl)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.Done():
+			return nil
+		case <-ticker.C:
+			s.cache.Range(func(key cacheKey, dv *cacheEntry) bool {
+				if dv.done() && dv.expired(s.entryTTL(dv)) {
+					s.cache.Compute(key, func(old *cacheEntry, loaded bool) (*cacheEntry, bool) {
+						// Only delete the entry if it hasn't been replaced.
+						return old, !loaded || old == dv
+					})
+				}
+				return true
+			})
+		}
+	}
+}
+
 func (s *Server) flushDNS() {
 	s.cache.Range(func(key cacheKey, _ *cacheEntry) bool {
 		if old, ok := s.cache.LoadAndDelete(key); ok {
@@ -647,7 +787,7 @@ func (s *Server) resolveWithRecursionCheck(q *dns.Question) (dnsproxy.RRs, int,
 		if ck.qType != dns.TypeNone {
 			if ce, ok := s.cache.Load(ck); ok {
 				<-ce.wait
-				if !ce.expired() && ce.rCode == dns.RcodeSuccess && atomic.LoadInt32(&ce.currentQType) == int32(ck.qType) {
+				if !ce.expired(s.cacheTTL) && ce.rCode == dns.RcodeSuccess && atomic.LoadInt32(&ce.currentQType) == int32(ck.qType) {
 					dlog.Debugf(s.ctx, "found counterpart for %s %s", dns.TypeToString[uint16(ce.currentQType)], ce.answer)
 					err = nil
 					rCode = dns.RcodeSuccess
@@ -671,7 +811,7 @@ func (s *Server) resolveThruCache(q *dns.Question) (answer dnsproxy.RRs, rCode i
 			return nil, dns.RcodeNameError, nil
 		}
 		<-oldDv.wait
-		if !oldDv.expired() {
+		if !oldDv.expired(s.entryTTL(oldDv)) {
 			qTypes := []uint16{q.Qtype}
 			if q.Qtype != dns.TypeCNAME {
 				// Allow additional CNAME records if they are present.
@@ -689,9 +829,8 @@ func (s *Server) resolveThruCache(q *dns.Question) (answer dnsproxy.RRs, rCode i
 
 	atomic.StoreInt32(&dv.currentQType, int32(q.Qtype))
 	defer func() {
-		if rCode != dns.RcodeSuccess {
-			s.cache.Delete(key) // Don't cache unless the lookup succeeded.
-		} else {
+		switch {
+		case rCode == dns.RcodeSuccess:
 			dv.answer = answer
 			dv.rCode = rCode
 
@@ -699,6 +838,12 @@ func (s *Server) resolveThruCache(q *dns.Question) (answer dnsproxy.RRs, rCode i
 			// also be empty if no RRs were found for the given query type and that is OK.
 			// See https://datatracker.ietf.org/doc/html/rfc4074#section-3
 			answer = copyRRs(answer, []uint16{q.Qtype})
+		case err == nil && rCode == dns.RcodeNameError && s.negativeTTL > 0:
+			// Cache the negative result, so that names that don't exist aren't looked up
+			// repeatedly in the cluster.
+			dv.rCode = rCode
+		default:
+			s.cache.Delete(key) // Don't cache unless the lookup succeeded.
 		}
 		atomic.StoreInt32(&dv.currentQType, int32(dns.TypeNone))
 		dv.close()
@@ -1030,6 +1175,44 @@ func (s *Server) resolveMapping(q *dns.Question) (dnsproxy.RRs, int, error) {
 	return answer, rCode, err
 }
 
+// Resolve resolves the A and AAAA records of the given name using the same mappings, rules, and cache
+// as ServeDNS, but without consulting the fallback resolver. The response describes the rule that
+// decided whether the name is resolved in the cluster.
+func (s *Server) Resolve(name string) (*rpc.ResolveDNSResponse, error) {
+	name = dns.Fqdn(strings.ToLower(name))
+	rsp := &rpc.ResolveDNSResponse{Name: name}
+	s.RLock()
+	alias, ok := s.mappings[name]
+	s.RUnlock()
+	if ok {
+		rsp.Cluster, rsp.Rule, rsp.Match = true, ruleMapping, alias
+	} else if rsp.Cluster, rsp.Rule, rsp.Match = s.clusterLookupRule(name); !rsp.Cluster {
+		return rsp, nil
+	}
+	for _, qType := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		q := &dns.Question{Name: name, Qtype: qType, Qclass: dns.ClassINET}
+		answer, rCode, err := s.resolveMapping(q)
+		if err == errNoMapping {
+			answer, rCode, err = s.resolveWithRecursionCheck(q)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if rCode != dns.RcodeSuccess {
+			continue
+		}
+		for _, rr := range answer {
+			switch rr := rr.(type) {
+			case *dns.A:
+				rsp.Ips = append(rsp.Ips, rr.A)
+			case *dns.AAAA:
+				rsp.Ips = append(rsp.Ips, rr.AAAA)
+			}
+		}
+	}
+	return rsp, nil
+}
+
 // Run starts the DNS server(s) and waits for them to end.
 func (s *Server) Run(c context.Context, initDone chan<- struct{}, listeners []net.PacketConn, fallbackPool FallbackPool, resolve Resolver) error {
 	s.ctx = c
@@ -1048,6 +1231,7 @@ func (s *Server) Run(c context.Context, initDone chan<- struct{}, listeners []ne
 			return srv.ActivateAndServe()
 		})
 	}
+	g.Go("CacheEviction", s.evictExpired)
 	close(initDone)
 	return g.Wait()
 }
@@ -32,7 +32,7 @@ const (
 func (s *Server) Worker(c context.Context, dev vif.Device, configureDNS func(net.IP, *net.UDPAddr)) error {
 	resolverDirName := filepath.Join("/etc", "resolver")
 
-	listener, err := newLocalUDPListener(c)
+	listener, err := s.newLocalUDPListener(c)
 	if err != nil {
 		return err
 	}
@@ -166,7 +166,7 @@ func (s *Server) runOverridingServer(c context.Context, dev vif.Device) error {
 }
 
 func (s *Server) dnsListeners(c context.Context) ([]net.PacketConn, error) {
-	listener, err := newLocalUDPListener(c)
+	listener, err := s.newLocalUDPListener(c)
 	if err != nil {
 		return nil, err
 	}
@@ -195,6 +195,15 @@ func (s *Server) dnsListeners(c context.Context) ([]net.PacketConn, error) {
 	if dockerGatewayIP == nil || dockerGatewayIP.Equal(localAddr.IP) {
 		return listeners, nil
 	}
+	if s.listenAddress != "" {
+		// The port must not change, so the bridge can only be used if that port is free there too.
+		ls, err := net.ListenPacket("udp", (&net.UDPAddr{IP: dockerGatewayIP, Port: localAddr.Port}).String())
+		if err != nil {
+			dlog.Infof(c, "not listening on docker bridge: %v", err)
+			return listeners, nil
+		}
+		return append(listeners, ls), nil
+	}
 
 	// Check that the dockerGatewayIP is registered as an interface on this machine. When running WSL2 on
 	// a Windows box, the gateway is managed by Windows and never visible to the Linux host and hence
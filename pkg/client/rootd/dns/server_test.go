@@ -1,6 +1,8 @@
 package dns
 
 import (
+	"context"
+	"net"
 	"testing"
 	"time"
 
@@ -10,6 +12,7 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	rpc "github.com/telepresenceio/telepresence/rpc/v2/daemon"
+	"github.com/telepresenceio/telepresence/v2/pkg/dnsproxy"
 )
 
 type suiteServer struct {
@@ -20,7 +23,9 @@ type suiteServer struct {
 
 func (s *suiteServer) SetupSuite() {
 	s.server = &Server{
-		cache: xsync.NewMapOf[cacheKey, *cacheEntry](),
+		cache:         xsync.NewMapOf[cacheKey, *cacheEntry](),
+		cacheTTL:      defaultCacheTTL,
+		clusterDomain: defaultClusterDomain,
 	}
 }
 
@@ -127,6 +132,287 @@ func (s *suiteServer) TestIsExcluded() {
 	assert.False(s.T(), s.server.isExcluded("something-else"))
 }
 
+func (s *suiteServer) TestResolve() {
+	// given
+	s.server.ctx = context.Background()
+	s.server.excludes = nil
+	s.server.excludeSuffixes = []string{".com"}
+	s.server.includeSuffixes = []string{".example.com"}
+	s.server.routes = map[string]struct{}{"blue": {}}
+	s.server.mappings = map[string]string{"db.": "10.1.1.1"}
+	s.server.resolve = func(_ context.Context, q *dns.Question) (dnsproxy.RRs, int, error) {
+		if q.Qtype != dns.TypeA {
+			return nil, dns.RcodeSuccess, nil
+		}
+		return dnsproxy.RRs{&dns.A{Hdr: dnsproxy.NewHeader(q.Name, q.Qtype), A: net.IP{10, 0, 0, 1}}}, dns.RcodeSuccess, nil
+	}
+
+	// when & then
+	rsp, err := s.server.Resolve("Echo.Blue")
+	s.Require().NoError(err)
+	s.Equal("echo.blue.", rsp.Name)
+	s.True(rsp.Cluster)
+	s.Equal(ruleNamespace, rsp.Rule)
+	s.Equal("blue", rsp.Match)
+	s.Equal([][]byte{net.IP{10, 0, 0, 1}}, rsp.Ips)
+
+	rsp, err = s.server.Resolve("api.example.com")
+	s.Require().NoError(err)
+	s.True(rsp.Cluster)
+	s.Equal(ruleIncludeSuffix, rsp.Rule)
+	s.Equal(".example.com", rsp.Match)
+
+	rsp, err = s.server.Resolve("google.com")
+	s.Require().NoError(err)
+	s.False(rsp.Cluster)
+	s.Equal(ruleExcludeSuffix, rsp.Rule)
+	s.Empty(rsp.Ips)
+
+	rsp, err = s.server.Resolve("db")
+	s.Require().NoError(err)
+	s.True(rsp.Cluster)
+	s.Equal(ruleMapping, rsp.Rule)
+	s.Equal([][]byte{net.IP{10, 1, 1, 1}.To4()}, rsp.Ips)
+}
+
+func (s *suiteServer) TestResolveIPv6() {
+	// given
+	s.server.ctx = context.Background()
+	s.server.excludes = nil
+	s.server.excludeSuffixes = nil
+	s.server.includeSuffixes = nil
+	s.server.routes = map[string]struct{}{"blue": {}}
+	s.server.mappings = map[string]string{"db6.": "fd00::10"}
+	svcIP := net.ParseIP("fd00:10:96::a")
+	s.server.resolve = func(_ context.Context, q *dns.Question) (dnsproxy.RRs, int, error) {
+		if q.Qtype != dns.TypeAAAA {
+			return nil, dns.RcodeSuccess, nil
+		}
+		return dnsproxy.RRs{&dns.AAAA{Hdr: dnsproxy.NewHeader(q.Name, q.Qtype), AAAA: svcIP}}, dns.RcodeSuccess, nil
+	}
+
+	// when & then
+	rsp, err := s.server.Resolve("echo6.blue")
+	s.Require().NoError(err)
+	s.True(rsp.Cluster)
+	s.Equal([][]byte{svcIP}, rsp.Ips)
+
+	rsp, err = s.server.Resolve("db6")
+	s.Require().NoError(err)
+	s.True(rsp.Cluster)
+	s.Equal(ruleMapping, rsp.Rule)
+	s.Equal([][]byte{net.ParseIP("fd00::10")}, rsp.Ips)
+}
+
+func (s *suiteServer) TestResolveNamespacesOnly() {
+	// given
+	s.server.ctx = context.Background()
+	s.server.excludes = nil
+	s.server.excludeSuffixes = nil
+	s.server.includeSuffixes = nil
+	s.server.mappings = nil
+	s.server.clusterDomain = "cluster.local."
+	s.server.routes = map[string]struct{}{"blue": {}, "svc": {}}
+	s.server.namespacesOnly = true
+	defer func() { s.server.namespacesOnly = false }()
+	s.server.resolve = func(_ context.Context, q *dns.Question) (dnsproxy.RRs, int, error) {
+		return nil, dns.RcodeSuccess, nil
+	}
+
+	// when & then
+	for _, name := range []string{"echo.blue.svc", "echo.blue.svc.cluster.local", "echo.blue"} {
+		rsp, err := s.server.Resolve(name)
+		s.Require().NoError(err)
+		s.True(rsp.Cluster, name)
+	}
+	for _, name := range []string{"echo.green.svc", "echo.green.svc.cluster.local", "pod.echo.green.svc.cluster.local"} {
+		rsp, err := s.server.Resolve(name)
+		s.Require().NoError(err)
+		s.False(rsp.Cluster, name)
+		s.Equal(ruleUnmapped, rsp.Rule)
+		s.Equal("green", rsp.Match)
+	}
+	rsp, err := s.server.Resolve("echo.green")
+	s.Require().NoError(err)
+	s.False(rsp.Cluster)
+
+	// Names of unmapped namespaces are resolved when all namespaces are mapped.
+	s.server.namespacesOnly = false
+	rsp, err = s.server.Resolve("echo.green.svc.cluster.local")
+	s.Require().NoError(err)
+	s.True(rsp.Cluster)
+}
+
+func (s *suiteServer) TestCacheTTL() {
+	// given
+	const ttl = 100 * time.Millisecond
+	s.server.ctx = context.Background()
+	s.server.cacheTTL = ttl
+	defer func() { s.server.cacheTTL = defaultCacheTTL }()
+	lookups := 0
+	s.server.resolve = func(_ context.Context, q *dns.Question) (dnsproxy.RRs, int, error) {
+		lookups++
+		return dnsproxy.RRs{&dns.A{Hdr: dnsproxy.NewHeader(q.Name, q.Qtype), A: net.IP{10, 0, 0, byte(lookups)}}}, dns.RcodeSuccess, nil
+	}
+	q := &dns.Question{Name: "ttl-test.blue.", Qtype: dns.TypeA, Qclass: dns.ClassINET}
+
+	// when & then
+	answer, _, err := s.server.resolveThruCache(q)
+	s.Require().NoError(err)
+	s.Equal(1, lookups)
+	s.Equal(net.IP{10, 0, 0, 1}, answer[0].(*dns.A).A)
+
+	answer, _, err = s.server.resolveThruCache(q)
+	s.Require().NoError(err)
+	s.Equal(1, lookups, "name was resolved from the cache")
+	s.Equal(net.IP{10, 0, 0, 1}, answer[0].(*dns.A).A)
+
+	time.Sleep(ttl + 10*time.Millisecond)
+	answer, _, err = s.server.resolveThruCache(q)
+	s.Require().NoError(err)
+	s.Equal(2, lookups, "name was re-resolved after the TTL elapsed")
+	s.Equal(net.IP{10, 0, 0, 2}, answer[0].(*dns.A).A)
+}
+
+func (s *suiteServer) TestNegativeTTL() {
+	// given
+	const ttl = 100 * time.Millisecond
+	s.server.ctx = context.Background()
+	s.server.negativeTTL = ttl
+	defer func() { s.server.negativeTTL = 0 }()
+	lookups := 0
+	s.server.resolve = func(_ context.Context, q *dns.Question) (dnsproxy.RRs, int, error) {
+		lookups++
+		if lookups == 1 {
+			return nil, dns.RcodeNameError, nil
+		}
+		return dnsproxy.RRs{&dns.A{Hdr: dnsproxy.NewHeader(q.Name, q.Qtype), A: net.IP{10, 0, 0, 1}}}, dns.RcodeSuccess, nil
+	}
+	q := &dns.Question{Name: "negative-test.blue.", Qtype: dns.TypeA, Qclass: dns.ClassINET}
+
+	// when & then
+	_, rCode, err := s.server.resolveThruCache(q)
+	s.Require().NoError(err)
+	s.Equal(dns.RcodeNameError, rCode)
+	s.Equal(1, lookups)
+
+	_, rCode, err = s.server.resolveThruCache(q)
+	s.Require().NoError(err)
+	s.Equal(dns.RcodeNameError, rCode)
+	s.Equal(1, lookups, "negative result was resolved from the cache")
+
+	time.Sleep(ttl + 10*time.Millisecond)
+	answer, rCode, err := s.server.resolveThruCache(q)
+	s.Require().NoError(err)
+	s.Equal(dns.RcodeSuccess, rCode, "name is no longer negative after the negative TTL elapsed")
+	s.Equal(2, lookups)
+	s.Len(answer, 1)
+}
+
+func (s *suiteServer) TestNegativeTTLDisabled() {
+	// given
+	s.server.ctx = context.Background()
+	lookups := 0
+	s.server.resolve = func(_ context.Context, q *dns.Question) (dnsproxy.RRs, int, error) {
+		lookups++
+		return nil, dns.RcodeNameError, nil
+	}
+	q := &dns.Question{Name: "negative-disabled-test.blue.", Qtype: dns.TypeA, Qclass: dns.ClassINET}
+
+	// when
+	_, _, _ = s.server.resolveThruCache(q)
+	_, _, _ = s.server.resolveThruCache(q)
+
+	// then
+	s.Equal(2, lookups, "negative results are not cached")
+}
+
+func (s *suiteServer) TestEvictExpired() {
+	// given
+	const ttl = 50 * time.Millisecond
+	s.server.cacheTTL = ttl
+	defer func() { s.server.cacheTTL = defaultCacheTTL }()
+	key := cacheKey{name: "evict-test.", qType: dns.TypeA}
+	entry := &cacheEntry{wait: make(chan struct{}), created: time.Now()}
+	entry.close()
+	s.server.cache.Store(key, entry)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = s.server.evictExpired(ctx) }()
+
+	// then
+	s.Eventually(func() bool {
+		_, ok := s.server.cache.Load(key)
+		return !ok
+	}, time.Second, 10*time.Millisecond, "expired entry was evicted")
+}
+
+func (s *suiteServer) TestExcludeBeatsIncludeSuffix() {
+	// given
+	s.server.ctx = context.Background()
+	s.server.excludes = []string{"metadata.google.internal"}
+	s.server.excludeSuffixes = nil
+	s.server.includeSuffixes = []string{".internal"}
+	defer func() {
+		s.server.excludes = nil
+		s.server.includeSuffixes = nil
+	}()
+
+	// when & then
+	include, rule, match := s.server.clusterLookupRule("metadata.google.internal.")
+	s.False(include)
+	s.Equal(ruleExclude, rule)
+	s.Equal("metadata.google.internal", match)
+
+	include, rule, match = s.server.clusterLookupRule("db.internal.")
+	s.True(include)
+	s.Equal(ruleIncludeSuffix, rule)
+	s.Equal(".internal", match)
+}
+
+func (s *suiteServer) TestNewLocalUDPListener() {
+	// given
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	s.Require().NoError(err)
+	addr := pc.LocalAddr().String()
+	s.Require().NoError(pc.Close())
+	s.server.listenAddress = addr
+	defer func() {
+		s.server.listenAddress = ""
+		s.server.listenAddr = nil
+	}()
+
+	// when
+	l, err := s.server.newLocalUDPListener(context.Background())
+	s.Require().NoError(err)
+	defer l.Close()
+
+	// then
+	s.Equal(addr, l.LocalAddr().String())
+	s.Equal(addr, s.server.GetConfig().ListenAddress)
+	s.Error(dnsproxy.CheckListenAddress(addr), "address is in use")
+}
+
+func (s *suiteServer) TestProbe() {
+	// given
+	s.server.ctx = context.Background()
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	s.Require().NoError(err)
+	started := make(chan struct{})
+	srv := &dns.Server{PacketConn: pc, Handler: s.server, NotifyStartedFunc: func() { close(started) }}
+	go func() { _ = srv.ActivateAndServe() }()
+	defer func() { _ = srv.Shutdown() }()
+	<-started
+
+	silent, err := net.ListenPacket("udp", "127.0.0.1:0")
+	s.Require().NoError(err)
+	defer silent.Close()
+
+	// when & then
+	s.NoError(dnsproxy.Probe(context.Background(), pc.LocalAddr().String(), time.Second))
+	s.Error(dnsproxy.Probe(context.Background(), silent.LocalAddr().String(), 100*time.Millisecond))
+}
+
 func TestServerTestSuite(t *testing.T) {
 	suite.Run(t, new(suiteServer))
 }
@@ -21,7 +21,7 @@ const (
 )
 
 func (s *Server) Worker(c context.Context, dev vif.Device, configureDNS func(net.IP, *net.UDPAddr)) error {
-	listener, err := newLocalUDPListener(c)
+	listener, err := s.newLocalUDPListener(c)
 	if err != nil {
 		return err
 	}
@@ -84,7 +84,7 @@ func (rd *InProcSession) GetNetworkConfig(context.Context, *empty.Empty, ...grpc
 }
 
 func (rd *InProcSession) SetDNSTopLevelDomains(ctx context.Context, in *rpc.Domains, _ ...grpc.CallOption) (*empty.Empty, error) {
-	rd.SetTopLevelDomains(ctx, in.Domains)
+	rd.SetTopLevelDomains(ctx, in.Domains, in.NamespacesOnly)
 	return &empty.Empty{}, nil
 }
 
@@ -98,6 +98,10 @@ func (rd *InProcSession) SetDNSMappings(ctx context.Context, in *rpc.SetDNSMappi
 	return &empty.Empty{}, nil
 }
 
+func (rd *InProcSession) ResolveDNS(ctx context.Context, in *rpc.ResolveDNSRequest, _ ...grpc.CallOption) (*rpc.ResolveDNSResponse, error) {
+	return rd.Session.ResolveDNS(ctx, in.Name)
+}
+
 func (rd *InProcSession) SetLogLevel(context.Context, *manager.LogLevelRequest, ...grpc.CallOption) (*empty.Empty, error) {
 	// No loglevel when session runs in the same process as the user daemon.
 	return &empty.Empty{}, nil
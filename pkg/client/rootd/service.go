@@ -30,10 +30,12 @@ import (
 	"github.com/telepresenceio/telepresence/v2/pkg/client/socket"
 	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
 	"github.com/telepresenceio/telepresence/v2/pkg/filelocation"
+	"github.com/telepresenceio/telepresence/v2/pkg/ipproto"
 	"github.com/telepresenceio/telepresence/v2/pkg/log"
 	"github.com/telepresenceio/telepresence/v2/pkg/pprof"
 	"github.com/telepresenceio/telepresence/v2/pkg/proc"
 	"github.com/telepresenceio/telepresence/v2/pkg/tracing"
+	"github.com/telepresenceio/telepresence/v2/pkg/tunnel"
 	"github.com/telepresenceio/telepresence/v2/pkg/vif"
 )
 
@@ -145,6 +147,9 @@ func (s *Service) Status(_ context.Context, _ *emptypb.Empty) (*rpc.DaemonStatus
 		nc := s.session.getNetworkConfig()
 		r.Subnets = nc.Subnets
 		r.OutboundConfig = nc.OutboundInfo
+		for _, proto := range vif.RoutedProtocols {
+			r.RoutedProtocols = append(r.RoutedProtocols, ipproto.String(proto))
+		}
 	}
 	return r, nil
 }
@@ -167,7 +172,7 @@ func (s *Service) Quit(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, e
 
 func (s *Service) SetDNSTopLevelDomains(ctx context.Context, domains *rpc.Domains) (*emptypb.Empty, error) {
 	err := s.WithSession(func(ctx context.Context, session *Session) error {
-		session.SetTopLevelDomains(ctx, domains.Domains)
+		session.SetTopLevelDomains(ctx, domains.Domains, domains.NamespacesOnly)
 		return nil
 	})
 	return &emptypb.Empty{}, err
@@ -189,6 +194,14 @@ func (s *Service) SetDNSMappings(ctx context.Context, req *rpc.SetDNSMappingsReq
 	return &emptypb.Empty{}, err
 }
 
+func (s *Service) ResolveDNS(ctx context.Context, req *rpc.ResolveDNSRequest) (rsp *rpc.ResolveDNSResponse, err error) {
+	err = s.WithSession(func(c context.Context, session *Session) error {
+		rsp, err = session.ResolveDNS(c, req.Name)
+		return err
+	})
+	return rsp, err
+}
+
 func (s *Service) Connect(ctx context.Context, info *rpc.OutboundInfo) (*rpc.DaemonStatus, error) {
 	dlog.Debug(ctx, "Received gRPC Connect")
 	select {
@@ -369,6 +382,8 @@ func (s *Service) startSession(ctx context.Context, oi *rpc.OutboundInfo, wg *sy
 	if err := s.session.applyConfig(ctx); err != nil {
 		dlog.Warnf(ctx, "failed to apply config from traffic-manager: %v", err)
 	}
+	tc := client.GetConfig(ctx).Tunnel()
+	s.sessionContext = tunnel.WithTuning(s.sessionContext, tunnel.Tuning{BufferSize: tc.BufferSize(), WindowSize: tc.WindowSize})
 
 	reply.status.OutboundConfig = s.session.getNetworkConfig().OutboundInfo
 	dlog.Debugf(ctx, "Returning session from new session %v", reply.status.OutboundConfig.Session)
@@ -171,6 +171,14 @@ type Session struct {
 	// Whether services should be proxied by the TUN-device
 	proxyClusterSvcs bool
 
+	// dnsOnly is set when the session only provides DNS resolution of cluster names. Neither
+	// cluster subnets nor also-proxy subnets are routed to the TUN-device in this mode.
+	dnsOnly bool
+
+	// routeOnly is set when the session only routes subnets to the TUN-device. The DNS server is
+	// never attached to the system's DNS configuration in this mode.
+	routeOnly bool
+
 	// dnsServerSubnet is normally never set. It is only used when neither proxyClusterPods nor the
 	// proxyClusterSvcs are set. In this situation, the VIF would be left without a primary subnet, so
 	// it will instead route very small subnet with 30 bit mask, large enough to hold:
@@ -230,7 +238,7 @@ func createK8sConfig(ctx context.Context, kubeFlags map[string]string, kubeData
 // connectToManager connects to the traffic-manager and asserts that its version is compatible.
 func connectToManager(
 	ctx context.Context,
-	namespace string,
+	namespace, serviceName string,
 	kubeFlags map[string]string,
 	kubeData []byte,
 ) (
@@ -270,7 +278,7 @@ func connectToManager(
 		return ctx, nil, nil, mgrVer, err
 	}
 
-	conn, mc, ver, err := k8sclient.ConnectToManager(tc, namespace, pfDialer.Dial)
+	conn, mc, ver, err := k8sclient.ConnectToManager(tc, namespace, serviceName, pfDialer.Dial)
 	if err != nil {
 		return ctx, nil, nil, mgrVer, err
 	}
@@ -334,7 +342,7 @@ func connectToUserDaemon(c context.Context) (*grpc.ClientConn, connector.Manager
 func NewSession(c context.Context, mi *rpc.OutboundInfo) (context.Context, *Session, error) {
 	dlog.Info(c, "-- Starting new session")
 
-	c, conn, mc, ver, err := connectToManager(c, mi.ManagerNamespace, mi.KubeFlags, mi.KubeconfigData)
+	c, conn, mc, ver, err := connectToManager(c, mi.ManagerNamespace, managerServiceName(mi), mi.KubeFlags, mi.KubeconfigData)
 	if mc == nil || err != nil {
 		return c, nil, err
 	}
@@ -348,6 +356,14 @@ func NewSession(c context.Context, mi *rpc.OutboundInfo) (context.Context, *Sess
 	return c, s, nil
 }
 
+// managerServiceName returns the name of the traffic-manager service. Older user daemons don't send it.
+func managerServiceName(mi *rpc.OutboundInfo) string {
+	if sn := mi.ManagerServiceName; sn != "" {
+		return sn
+	}
+	return client.DefaultManagerServiceName
+}
+
 func nope() bool { return false }
 
 func newSession(c context.Context, mi *rpc.OutboundInfo, mc connector.ManagerProxyClient, ver semver.Version, isPodDaemon bool) (*Session, error) {
@@ -377,12 +393,25 @@ func newSession(c context.Context, mi *rpc.OutboundInfo, mc connector.ManagerPro
 		config:             cfg,
 		done:               make(chan struct{}),
 		podDaemon:          isPodDaemon,
+		dnsOnly:            mi.DnsOnly,
+		routeOnly:          mi.RouteOnly,
 	}
 	s.alsoProxySubnets, err = validateSubnets("also-proxy", mi.AlsoProxySubnets, s.alsoProxyVia)
 	if err != nil {
 		return nil, err
 	}
 	dlog.Infof(c, "also-proxy subnets %v", s.alsoProxySubnets)
+	if s.dnsOnly && (len(s.alsoProxySubnets) > 0 || len(s.subnetViaWorkloads) > 0) {
+		// Subnets declared in the kubeconfig extension are ignored, because nothing is routed in this mode.
+		dlog.Info(c, "DNS-only mode, ignoring also-proxy and subnet-via-workload subnets")
+		s.alsoProxySubnets = nil
+		s.subnetViaWorkloads = nil
+	}
+	if s.routeOnly && len(s.subnetViaWorkloads) > 0 {
+		// Subnet-via-workload relies on the DNS server to translate IPs into virtual IPs.
+		dlog.Info(c, "Routing-only mode, ignoring subnet-via-workload subnets")
+		s.subnetViaWorkloads = nil
+	}
 
 	s.neverProxySubnets, err = validateSubnets("never-proxy", mi.NeverProxySubnets, nope)
 	if err != nil {
@@ -396,8 +425,13 @@ func newSession(c context.Context, mi *rpc.OutboundInfo, mc connector.ManagerPro
 	}
 	dlog.Infof(c, "allow-conflicting subnets %v", s.allowConflictingSubnets)
 
+	if la := mi.Dns.GetListenAddress(); la != "" {
+		if err = dnsproxy.CheckListenAddress(la); err != nil {
+			return nil, err
+		}
+	}
 	s.dnsServer = dns.NewServer(mi.Dns, s.clusterLookup)
-	s.SetTopLevelDomains(c, nil)
+	s.SetTopLevelDomains(c, nil, false)
 	return s, nil
 }
 
@@ -476,8 +510,10 @@ func (s *Session) nextVirtualIP(workload string, destinationIP net.IP) (net.IP,
 
 func (s *Session) getNetworkConfig() *rpc.NetworkConfig {
 	info := rpc.OutboundInfo{
-		Session: s.session,
-		Dns:     s.dnsServer.GetConfig(),
+		Session:   s.session,
+		Dns:       s.dnsServer.GetConfig(),
+		DnsOnly:   s.dnsOnly,
+		RouteOnly: s.routeOnly,
 	}
 	nc := &rpc.NetworkConfig{
 		OutboundInfo: &info,
@@ -553,7 +589,8 @@ func (s *Session) shouldProxySubnet(ctx context.Context, name string, sn *net.IP
 	return true
 }
 
-// networkReady returns a channel that is close when both the VIF and DNS are ready.
+// networkReady returns a channel that is close when both the VIF and DNS are ready. Only the
+// VIF is awaited in routing-only mode, because the DNS server is never configured.
 func (s *Session) networkReady(ctx context.Context) <-chan error {
 	rdy := make(chan error, 2)
 	go func() {
@@ -563,7 +600,7 @@ func (s *Session) networkReady(ctx context.Context) <-chan error {
 		case err, ok := <-s.vifReady:
 			if ok {
 				rdy <- err
-			} else {
+			} else if !s.routeOnly {
 				select {
 				case <-ctx.Done():
 				case <-s.dnsServer.Ready():
@@ -669,8 +706,14 @@ func (s *Session) onFirstClusterInfo(ctx context.Context, mgrInfo *manager.Clust
 	if s.podDaemon {
 		return nil
 	}
-	s.proxyClusterPods = s.checkPodConnectivity(ctx, mgrInfo)
-	s.proxyClusterSvcs = s.checkSvcConnectivity(ctx, mgrInfo)
+	if s.dnsOnly {
+		dlog.Info(ctx, "DNS-only mode, cluster subnets will not be routed")
+		s.proxyClusterPods = false
+		s.proxyClusterSvcs = false
+	} else {
+		s.proxyClusterPods = s.checkPodConnectivity(ctx, mgrInfo)
+		s.proxyClusterSvcs = s.checkSvcConnectivity(ctx, mgrInfo)
+	}
 	if ctx.Err() != nil {
 		return ctx.Err()
 	}
@@ -744,7 +787,7 @@ func (s *Session) onClusterInfo(ctx context.Context, mgrInfo *manager.ClusterInf
 			break
 		}
 	}
-	if runtime.GOOS != "darwin" && !dnsRouted {
+	if runtime.GOOS != "darwin" && !dnsRouted && !s.routeOnly {
 		// We'll need to synthesize a subnet where we can attach the DNS service when the VIF isn't configured
 		// from cluster subnets. But not on darwin systems, because there the DNS is controlled by /etc/resolver
 		// entries appointing the DNS service directly via localhost:<port>.
@@ -1086,6 +1129,12 @@ func (s *Session) Start(c context.Context, g *dgroup.Group) error {
 		cancelDNSLock.Lock()
 		ctx, cancelDNS = context.WithCancel(ctx)
 		cancelDNSLock.Unlock()
+		if s.routeOnly {
+			dlog.Info(ctx, "Routing-only mode, the system's DNS configuration is left untouched")
+			s.dnsServer.Stop()
+			<-ctx.Done()
+			return nil
+		}
 		var dev vif.Device
 		if s.tunVif != nil {
 			dev = s.tunVif.Device
@@ -1230,8 +1279,12 @@ func (s *Session) waitForProxyViaWorkloads(ctx context.Context) error {
 	return nil
 }
 
-func (s *Session) SetTopLevelDomains(ctx context.Context, topLevelDomains []string) {
-	s.dnsServer.SetTopLevelDomainsAndSearchPath(ctx, topLevelDomains, s.namespace)
+func (s *Session) SetTopLevelDomains(ctx context.Context, topLevelDomains []string, namespacesOnly bool) {
+	if s.routeOnly {
+		// The DNS server never runs in routing-only mode, so nothing would consume the update.
+		return
+	}
+	s.dnsServer.SetTopLevelDomainsAndSearchPath(ctx, topLevelDomains, s.namespace, namespacesOnly)
 }
 
 func (s *Session) SetExcludes(ctx context.Context, excludes []string) {
@@ -1242,6 +1295,10 @@ func (s *Session) SetMappings(ctx context.Context, mappings []*rpc.DNSMapping) {
 	s.dnsServer.SetMappings(mappings)
 }
 
+func (s *Session) ResolveDNS(ctx context.Context, name string) (*rpc.ResolveDNSResponse, error) {
+	return s.dnsServer.Resolve(name)
+}
+
 func (s *Session) applyConfig(ctx context.Context) error {
 	cfg, err := client.LoadConfig(ctx)
 	if err != nil {
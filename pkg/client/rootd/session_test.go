@@ -0,0 +1,69 @@
+package rootd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/client/rootd/dns"
+)
+
+func newReadySession(routeOnly bool) *Session {
+	s := &Session{
+		vifReady:  make(chan error, 2),
+		dnsServer: dns.NewServer(nil, nil),
+		routeOnly: routeOnly,
+	}
+	// This is what onClusterInfo does once the VIF has been configured.
+	close(s.vifReady)
+	return s
+}
+
+func TestSession_networkReady(t *testing.T) {
+	t.Run("route-only does not wait for DNS", func(t *testing.T) {
+		s := newReadySession(true)
+		select {
+		case err, ok := <-s.networkReady(context.Background()):
+			assert.False(t, ok, "unexpected error %v", err)
+		case <-time.After(time.Second):
+			require.Fail(t, "route-only session never became ready")
+		}
+	})
+
+	t.Run("default mode waits for DNS", func(t *testing.T) {
+		s := newReadySession(false)
+		rdy := s.networkReady(context.Background())
+		select {
+		case <-rdy:
+			require.Fail(t, "session became ready before the DNS server")
+		case <-time.After(100 * time.Millisecond):
+		}
+		s.dnsServer.Stop()
+		select {
+		case err, ok := <-rdy:
+			assert.False(t, ok, "unexpected error %v", err)
+		case <-time.After(time.Second):
+			require.Fail(t, "session never became ready")
+		}
+	})
+}
+
+func TestSession_SetTopLevelDomains(t *testing.T) {
+	// The DNS server buffers a few updates, so send well beyond that.
+	s := newReadySession(true)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			s.SetTopLevelDomains(context.Background(), []string{"default"}, false)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		require.Fail(t, "route-only session blocked on top level domain updates")
+	}
+}
@@ -0,0 +1,150 @@
+package scout
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/datawire/dlib/dlog"
+)
+
+// eventLogBufferSize is the number of events that can be queued for writing before new events are dropped.
+const eventLogBufferSize = 256
+
+// eventLog is a Reporter that writes each reported event as a line of JSON to a local file. All calls are
+// also passed on to the Reporter that it wraps, if any.
+type eventLog struct {
+	wrapped Reporter
+	mode    string
+	path    string
+	events  chan []byte
+	dropped atomic.Int64
+}
+
+// Event is the JSON representation of an event written to the event log.
+type Event struct {
+	Time    time.Time      `json:"time"`
+	Mode    string         `json:"mode"`
+	Action  string         `json:"action"`
+	Entries map[string]any `json:"entries,omitempty"`
+}
+
+// WithEventLog returns a context with a Reporter that writes newline-delimited JSON events to the file
+// at the given path, in addition to passing them on to the Reporter already present in the context. The
+// writes are buffered, and events are dropped rather than blocking the caller when the buffer is full.
+func WithEventLog(ctx context.Context, mode, path string) context.Context {
+	return WithReporter(ctx, newEventLog(getReporter(ctx), mode, path))
+}
+
+func newEventLog(wrapped Reporter, mode, path string) *eventLog {
+	return &eventLog{
+		wrapped: wrapped,
+		mode:    mode,
+		path:    path,
+		events:  make(chan []byte, eventLogBufferSize),
+	}
+}
+
+func (l *eventLog) Close() {
+	if l.wrapped != nil {
+		l.wrapped.Close()
+	}
+}
+
+func (l *eventLog) InstallID() string {
+	if l.wrapped != nil {
+		return l.wrapped.InstallID()
+	}
+	return ""
+}
+
+func (l *eventLog) Report(ctx context.Context, action string, entries ...Entry) {
+	if l.wrapped != nil {
+		l.wrapped.Report(ctx, action, entries...)
+	}
+	ev := Event{
+		Time:   time.Now(),
+		Mode:   l.mode,
+		Action: action,
+	}
+	if len(entries) > 0 {
+		ev.Entries = make(map[string]any, len(entries))
+		for _, e := range entries {
+			ev.Entries[e.Key] = e.Value
+		}
+	}
+	data, err := json.Marshal(&ev)
+	if err != nil {
+		dlog.Errorf(ctx, "unable to marshal event %q: %v", action, err)
+		return
+	}
+	select {
+	case l.events <- append(data, '\n'):
+	default:
+		l.dropped.Add(1)
+	}
+}
+
+// Run writes the queued events to the event log until the context is cancelled.
+func (l *eventLog) Run(ctx context.Context) error {
+	if l.wrapped == nil {
+		l.write(ctx)
+		return nil
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- l.wrapped.Run(ctx)
+	}()
+	l.write(ctx)
+	return <-errCh
+}
+
+func (l *eventLog) SetMetadatum(ctx context.Context, key string, value any) {
+	if l.wrapped != nil {
+		l.wrapped.SetMetadatum(ctx, key, value)
+	}
+}
+
+func (l *eventLog) Start(ctx context.Context) {
+	if l.wrapped != nil {
+		l.wrapped.Start(ctx)
+	}
+	go l.write(ctx)
+}
+
+// write writes queued events to the event log file until the context is cancelled. Events that are
+// still queued at that point are written before the file is closed.
+func (l *eventLog) write(ctx context.Context) {
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		dlog.Errorf(ctx, "unable to open event log: %v", err)
+		return
+	}
+	defer f.Close()
+
+	writeEvent := func(data []byte) {
+		if dropped := l.dropped.Swap(0); dropped > 0 {
+			dlog.Warnf(ctx, "%d events were dropped from the event log", dropped)
+		}
+		if _, err := f.Write(data); err != nil {
+			dlog.Errorf(ctx, "unable to write to event log: %v", err)
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			for {
+				select {
+				case data := <-l.events:
+					writeEvent(data)
+				default:
+					return
+				}
+			}
+		case data := <-l.events:
+			writeEvent(data)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package scout
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datawire/dlib/dlog"
+)
+
+func readEvents(t *testing.T, path string) []Event {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	var evs []Event
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var ev Event
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &ev))
+		evs = append(evs, ev)
+	}
+	require.NoError(t, sc.Err())
+	return evs
+}
+
+func TestEventLog(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	path := filepath.Join(t.TempDir(), "events.json")
+	ctx = WithEventLog(ctx, "connector", path)
+
+	Report(ctx, "connect", Entry{Key: "mapped_namespaces", Value: 2})
+	Report(ctx, "disconnect")
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	require.NoError(t, Run(ctx))
+
+	evs := readEvents(t, path)
+	require.Len(t, evs, 2)
+	assert.Equal(t, "connector", evs[0].Mode)
+	assert.Equal(t, "connect", evs[0].Action)
+	assert.Equal(t, map[string]any{"mapped_namespaces": float64(2)}, evs[0].Entries)
+	assert.Equal(t, "disconnect", evs[1].Action)
+	assert.Nil(t, evs[1].Entries)
+}
+
+func TestEventLogDropsWhenFull(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	path := filepath.Join(t.TempDir(), "events.json")
+	l := newEventLog(nil, "connector", path)
+
+	// Nothing is writing, so the buffer fills up and the rest is dropped without blocking.
+	for i := 0; i < eventLogBufferSize+10; i++ {
+		l.Report(ctx, "connect")
+	}
+	assert.Equal(t, int64(10), l.dropped.Load())
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	require.NoError(t, l.Run(ctx))
+	assert.Len(t, readEvents(t, path), eventLogBufferSize)
+}
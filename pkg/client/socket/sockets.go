@@ -7,12 +7,16 @@ import (
 	"io/fs"
 	"net"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/proc"
 )
 
 // UserDaemonPath is the path used when communicating to the user daemon process.
@@ -56,6 +60,9 @@ func Dial(ctx context.Context, socketName string, waitForSocket bool, opts ...gr
 			return nil, errNotExist(socketName)
 		}
 	}
+	if removeIfStale(socketName) {
+		return nil, fmt.Errorf("%w; stale socket removed", errNotExist(socketName))
+	}
 
 	b := backoff.ExponentialBackOff{
 		InitialInterval:     50 * time.Millisecond,
@@ -86,7 +93,7 @@ func Dial(ctx context.Context, socketName string, waitForSocket bool, opts ...gr
 			// and dial again.
 			conn.Close()
 			conn = nil
-			if rmErr := os.Remove(socketName); rmErr != nil {
+			if rmErr := RemovePath(socketName); rmErr != nil {
 				err = fmt.Errorf("%w; remove of unresponsive socket failed: %v", err, rmErr)
 			} else {
 				err = fmt.Errorf("%w; socket unresponsive and removed", errNotExist(socketName))
@@ -127,14 +134,68 @@ func waitUntilReady(ctx context.Context, cc *grpc.ClientConn) error {
 	}
 }
 
-// Listen returns a listener for the given socket and returns the resulting connection.
+// Listen returns a listener for the given socket and returns the resulting connection. The PID of the
+// current process is written to the PidPath of the socket, so that a process that has locked up can be killed.
 func Listen(ctx context.Context, processName, socketName string) (net.Listener, error) {
-	return listen(ctx, processName, socketName)
+	removeIfStale(socketName)
+	listener, err := listen(ctx, processName, socketName)
+	if err != nil {
+		return nil, err
+	}
+	if err = os.WriteFile(PidPath(socketName), []byte(strconv.Itoa(os.Getpid())), 0o644); err != nil {
+		_ = listener.Close()
+		_ = RemovePath(socketName)
+		return nil, fmt.Errorf("unable to write pid file for %s: %w", processName, err)
+	}
+	return listener, nil
 }
 
 // Remove removes any representation of the socket from the filesystem.
 func Remove(listener net.Listener) error {
-	return os.Remove(listener.Addr().String())
+	return RemovePath(listener.Addr().String())
+}
+
+// RemovePath removes the given socket and its pid file from the filesystem. A pid file that doesn't
+// exist is not considered an error.
+func RemovePath(socketName string) error {
+	if err := os.Remove(PidPath(socketName)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.Remove(socketName)
+}
+
+// removeIfStale removes the given socket and its pid file when the process that created them is no longer
+// alive, e.g. because it crashed. It returns true if the socket was removed.
+func removeIfStale(socketName string) bool {
+	pid, err := ReadPid(socketName)
+	if err != nil {
+		return false
+	}
+	if alive, err := proc.IsAlive(pid); err != nil || alive {
+		return false
+	}
+	if err = RemovePath(socketName); err != nil && !os.IsNotExist(err) {
+		return false
+	}
+	return true
+}
+
+// PidPath returns the path of the file that contains the PID of the process that listens to the given socket.
+func PidPath(socketName string) string {
+	return socketName + ".pid"
+}
+
+// ReadPid returns the PID of the process that listens to the given socket.
+func ReadPid(socketName string) (int, error) {
+	data, err := os.ReadFile(PidPath(socketName))
+	if err != nil {
+		return 0, err
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("invalid pid file for socket %s: %w", socketName, err)
+	}
+	return pid, nil
 }
 
 // Exists returns true if a socket is found with the given name, false otherwise.
@@ -5,7 +5,9 @@ import (
 	"io/fs"
 	"net"
 	"os"
+	"os/exec" //nolint:depguard // Only used to obtain the PID of a terminated process.
 	"path/filepath"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -58,6 +60,7 @@ func TestDialSocket(t *testing.T) {
 			return
 		}
 		defer listener.Close()
+		assert.NoError(t, os.WriteFile(socket.PidPath(sockname), []byte(strconv.Itoa(os.Getpid())), 0o600))
 
 		ctx := dlog.NewTestContext(t, false)
 		conn, err := socket.Dial(ctx, sockname, true)
@@ -67,6 +70,8 @@ func TestDialSocket(t *testing.T) {
 		assert.ErrorIs(t, err, fs.ErrNotExist)
 		assert.Contains(t, err.Error(), "dial unix "+sockname)
 		assert.Contains(t, err.Error(), "this usually means that the process has locked up")
+		_, err = os.Stat(socket.PidPath(sockname))
+		assert.ErrorIs(t, err, fs.ErrNotExist)
 	})
 	t.Run("NotExist", func(t *testing.T) {
 		ctx := dlog.NewTestContext(t, false)
@@ -80,3 +85,44 @@ func TestDialSocket(t *testing.T) {
 		assert.Contains(t, err.Error(), "this usually means that the process is not running")
 	})
 }
+
+func TestListenWritesPid(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	sockname := filepath.Join(t.TempDir(), "pid.sock")
+	listener, err := socket.Listen(ctx, "test", sockname)
+	if !assert.NoError(t, err) {
+		return
+	}
+	pid, err := socket.ReadPid(sockname)
+	assert.NoError(t, err)
+	assert.Equal(t, os.Getpid(), pid)
+
+	assert.NoError(t, socket.Remove(listener))
+	_ = listener.Close()
+	_, err = os.Stat(socket.PidPath(sockname))
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestDialRemovesStaleSocket(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	sockname := filepath.Join(t.TempDir(), "stale.sock")
+	listener, err := net.Listen("unix", sockname)
+	if !assert.NoError(t, err) {
+		return
+	}
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	_ = listener.Close()
+
+	// Use the PID of a process that has terminated.
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	if !assert.NoError(t, cmd.Run()) {
+		return
+	}
+	assert.NoError(t, os.WriteFile(socket.PidPath(sockname), []byte(strconv.Itoa(cmd.Process.Pid)), 0o600))
+
+	_, err = socket.Dial(ctx, sockname, false)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	exists, err := socket.Exists(sockname)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
@@ -16,6 +16,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 	empty "google.golang.org/protobuf/types/known/emptypb"
 
@@ -135,6 +136,7 @@ func (s *service) Connect(ctx context.Context, cr *rpc.ConnectRequest) (result *
 
 func (s *service) Disconnect(ctx context.Context, ex *empty.Empty) (*empty.Empty, error) {
 	s.LogCall(ctx, "Disconnect", func(ctx context.Context) {
+		scout.Report(ctx, "disconnect")
 		s.cancelSession()
 		_ = s.withRootDaemon(ctx, func(ctx context.Context, rd daemon.DaemonClient) error {
 			_, err := rd.Disconnect(ctx, ex)
@@ -161,6 +163,30 @@ func (s *service) Status(ctx context.Context, ex *empty.Empty) (result *rpc.Conn
 	return
 }
 
+func (s *service) WatchStatus(ex *empty.Empty, stream rpc.Connector_WatchStatusServer) error {
+	ctx := stream.Context()
+	var last *rpc.ConnectInfo
+	for {
+		// Obtain the channel before the status is read, so that no change goes unnoticed.
+		changed := s.statusChanged()
+		st, err := s.Status(ctx, ex)
+		if err != nil {
+			return err
+		}
+		if !proto.Equal(st, last) {
+			if err = stream.Send(st); err != nil {
+				return err
+			}
+			last = st
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changed:
+		}
+	}
+}
+
 // isMultiPortIntercept checks if the intercept is one of several active intercepts on the same workload.
 // If it is, then the first returned value will be true and the second will indicate if those intercepts are
 // on different services. Otherwise, this function returns false, false.
@@ -545,9 +571,9 @@ func (s *service) RootDaemonVersion(ctx context.Context, empty *empty.Empty) (vi
 	return vi, err
 }
 
-func (s *service) AgentImageFQN(ctx context.Context, empty *emptypb.Empty) (fqn *manager.AgentImageFQN, err error) {
+func (s *service) AgentImageFQN(ctx context.Context, _ *emptypb.Empty) (fqn *manager.AgentImageFQN, err error) {
 	err = s.WithSession(ctx, "AgentImageFQN", func(ctx context.Context, session userd.Session) error {
-		fqn, err = session.ManagerClient().GetAgentImageFQN(ctx, empty)
+		fqn, err = session.AgentImageFQN(ctx)
 		return err
 	})
 	return fqn, err
@@ -612,6 +638,14 @@ func (s *service) SetDNSMappings(ctx context.Context, req *daemon.SetDNSMappings
 	return &empty.Empty{}, err
 }
 
+func (s *service) ResolveDNS(ctx context.Context, req *daemon.ResolveDNSRequest) (rsp *daemon.ResolveDNSResponse, err error) {
+	err = s.WithSession(ctx, "ResolveDNS", func(ctx context.Context, session userd.Session) error {
+		rsp, err = session.RootDaemon().ResolveDNS(ctx, req)
+		return err
+	})
+	return rsp, err
+}
+
 func (s *service) withRootDaemon(ctx context.Context, f func(ctx context.Context, daemonClient daemon.DaemonClient) error) error {
 	if s.rootSessionInProc {
 		return status.Error(codes.Unavailable, "root daemon is embedded")
@@ -77,6 +77,10 @@ type service struct {
 	sessionQuitting int32 // atomic boolean. True if non-zero.
 	sessionLock     sync.RWMutex
 
+	// statusCh is closed, and then replaced, when the status reported by WatchStatus changes.
+	statusCh   chan struct{}
+	statusLock sync.Mutex
+
 	// These are used to communicate between the various goroutines.
 	connectRequest  chan userd.ConnectRequest // server-grpc.connect() -> connectWorker
 	connectResponse chan *rpc.ConnectInfo     // connectWorker -> server-grpc.connect()
@@ -141,6 +145,25 @@ func (s *service) SetSelf(self userd.Service) {
 	s.self = self
 }
 
+func (s *service) StatusChanged() {
+	s.statusLock.Lock()
+	if s.statusCh != nil {
+		close(s.statusCh)
+		s.statusCh = nil
+	}
+	s.statusLock.Unlock()
+}
+
+// statusChanged returns a channel that is closed on the next call to StatusChanged.
+func (s *service) statusChanged() <-chan struct{} {
+	s.statusLock.Lock()
+	defer s.statusLock.Unlock()
+	if s.statusCh == nil {
+		s.statusCh = make(chan struct{})
+	}
+	return s.statusCh
+}
+
 func (s *service) FuseFTPMgr() remotefs.FuseFTPManager {
 	return s.fuseFtpMgr
 }
@@ -293,6 +316,7 @@ func (s *service) startSession(ctx context.Context, cr userd.ConnectRequest, wg
 		cancel()
 		<-session.Done()
 	}
+	s.StatusChanged()
 
 	// Run the session asynchronously. We must be able to respond to connect (with UpdateStatus) while
 	// the session is running. The s.sessionCancel is called from Disconnect
@@ -307,6 +331,7 @@ func (s *service) startSession(ctx context.Context, cr userd.ConnectRequest, wg
 				dlog.Warn(ctx, err)
 			}
 			s.sessionLock.Unlock()
+			s.StatusChanged()
 			wg.Done()
 		}()
 		if err := session.RunSession(s.sessionContext); err != nil {
@@ -378,6 +403,7 @@ func (s *service) cancelSession() {
 	s.sessionCancel = nil
 	atomic.StoreInt32(&s.sessionQuitting, 0)
 	s.sessionLock.Unlock()
+	s.StatusChanged()
 }
 
 // run is the main function when executing as the connector.
@@ -446,6 +472,9 @@ func run(cmd *cobra.Command, _ []string) error {
 	// prefer to let the OS close it when we exit.
 
 	c = scout.NewReporter(c, "connector")
+	if env := client.GetEnv(c); env != nil && env.EventLog != "" {
+		c = scout.WithEventLog(c, "connector", env.EventLog)
+	}
 	g := dgroup.NewGroup(c, dgroup.GroupConfig{
 		SoftShutdownTimeout:  2 * time.Second,
 		EnableSignalHandling: true,
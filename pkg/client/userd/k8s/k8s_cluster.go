@@ -50,6 +50,9 @@ type Cluster struct {
 
 	// Namespace listener. Notified when the currentNamespaces changes
 	namespaceListeners []userd.NamespaceListener
+
+	// namespacesRestricted is true when the client isn't permitted to watch namespaces.
+	namespacesRestricted bool
 }
 
 func (kc *Cluster) ActualNamespace(namespace string) string {
@@ -161,6 +164,12 @@ func NewCluster(c context.Context, kubeFlags *client.Kubeconfig, namespaces []st
 	if len(namespaces) == 0 {
 		if k8sclient.CanWatchNamespaces(c) {
 			ret.StartNamespaceWatcher(c)
+		} else {
+			dlog.Info(c, "Not permitted to watch namespaces. Using the namespace of the kubeconfig context")
+			ret.nsLock.Lock()
+			ret.namespacesRestricted = true
+			ret.nsLock.Unlock()
+			ret.refreshNamespaces(c)
 		}
 	} else {
 		ret.SetMappedNamespaces(c, namespaces)
@@ -190,34 +199,77 @@ func ConnectCluster(c context.Context, cr *rpc.ConnectRequest, config *client.Ku
 	return cluster, nil
 }
 
-// determineTrafficManagerNamespace finds the namespace for the traffic-manager. It is determined by the following steps:
+// determineTrafficManagerNamespace finds the namespace for the traffic-manager using DiscoverTrafficManagerNamespace
+// and the currently accessible namespaces.
+//
+// The result is stored in the Kubeconfig extension of the cluster, so the search is performed once per session.
+func (kc *Cluster) determineTrafficManagerNamespace(c context.Context) (string, error) {
+	return DiscoverTrafficManagerNamespace(c, kc.GetManagerServiceName(), kc.Namespace, kc.GetCurrentNamespaces(true))
+}
+
+// DiscoverTrafficManagerNamespace finds the namespace for the traffic-manager. It is determined by the following steps:
 //
-//  1. If a treffic-manager service is found in one of the currently accessible namespaces, return it.
+//  1. If a traffic-manager service is found in exactly one of the given namespaces, return it.
+//     If it's found in more than one, return an error listing them.
 //  2. If the client has access to the default manager namespace, then return it.
-//  3. If the client has access to the default namespace, then return it.
+//  3. If the client has access to the namespace of the kubeconfig context, then return it.
 //  4. Return an error stating that it isn't possible to determine the namespace.
-func (kc *Cluster) determineTrafficManagerNamespace(c context.Context) (string, error) {
+//
+// The given context must provide a k8sapi.K8sInterface.
+func DiscoverTrafficManagerNamespace(c context.Context, serviceName, contextNamespace string, namespaces []string) (string, error) {
 	// Search for the traffic-manager in mapped namespaces
-	nss := kc.GetCurrentNamespaces(true)
-	for _, ns := range nss {
-		if _, err := k8sapi.GetService(c, "traffic-manager", ns); err == nil {
-			return ns, nil
+	var found []string
+	for _, ns := range namespaces {
+		if _, err := k8sapi.GetService(c, serviceName, ns); err == nil {
+			found = append(found, ns)
 		}
 	}
+	switch len(found) {
+	case 0:
+	case 1:
+		dlog.Infof(c, "Discovered traffic-manager in namespace %s", found[0])
+		return found[0], nil
+	default:
+		return "", errcat.User.Newf("found traffic-managers in namespaces %s, please use --manager-namespace to choose one",
+			strings.Join(found, ", "))
+	}
 
 	// No existing manager was found.
-	if canGetDefaultTrafficManagerService(c) {
+	if canGetDefaultTrafficManagerService(c, serviceName) {
 		return defaultManagerNamespace, nil
 	}
 
 	// No existing traffic-manager found. Assume that it should be installed
 	// in the default namespace if it is accessible
-	if canAccessNS(c, kc.Namespace) {
-		return kc.Namespace, nil
+	if canAccessNS(c, contextNamespace) {
+		return contextNamespace, nil
 	}
 	return "", errcat.User.New("unable to determine the traffic-manager namespace")
 }
 
+// NamespacesRestricted returns true if the client isn't permitted to watch namespaces, and therefore only uses
+// the mapped namespaces, or the namespace of the kubeconfig context.
+func (kc *Cluster) NamespacesRestricted() bool {
+	kc.nsLock.Lock()
+	defer kc.nsLock.Unlock()
+	return kc.namespacesRestricted
+}
+
+// GetInaccessibleNamespaces returns the names of the mapped namespaces where the client isn't
+// permitted to watch workloads and services.
+func (kc *Cluster) GetInaccessibleNamespaces() []string {
+	kc.nsLock.Lock()
+	var nss []string
+	for ns, ok := range kc.currentMappedNamespaces {
+		if !ok {
+			nss = append(nss, ns)
+		}
+	}
+	kc.nsLock.Unlock()
+	sort.Strings(nss)
+	return nss
+}
+
 // GetCurrentNamespaces returns the names of the namespaces that this client
 // is mapping. If the forClientAccess is true, then the namespaces are restricted
 // to those where an intercept can take place, i.e. the namespaces where this
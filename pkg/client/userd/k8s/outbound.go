@@ -8,6 +8,7 @@ import (
 
 	auth "k8s.io/api/authorization/v1"
 	core "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/watch"
 
@@ -28,7 +29,22 @@ func (kc *Cluster) StartNamespaceWatcher(ctx context.Context) {
 		for ctx.Err() == nil {
 			w, err := api.Namespaces().Watch(ctx, meta.ListOptions{})
 			if err != nil {
-				dlog.Errorf(ctx, "unable to create service watcher: %v", err)
+				if k8sErrors.IsForbidden(err) {
+					// Fall back to the mapped namespaces, or the namespace of the kubeconfig context.
+					dlog.Infof(ctx, "not permitted to watch namespaces: %v", err)
+					kc.nsLock.Lock()
+					kc.namespaceWatcherSnapshot = nil
+					kc.namespacesRestricted = true
+					kc.nsLock.Unlock()
+					kc.refreshNamespaces(ctx)
+				} else {
+					dlog.Errorf(ctx, "unable to create namespace watcher: %v", err)
+				}
+				select {
+				case <-nsSynced:
+				default:
+					close(nsSynced)
+				}
 				return
 			}
 			kc.namespacesEventHandler(ctx, w.ResultChan(), nsSynced)
@@ -83,11 +99,11 @@ func (kc *Cluster) namespacesEventHandler(ctx context.Context, evCh <-chan watch
 
 // canGetDefaultTrafficManagerService answers the question if this client has the RBAC permissions
 // necessary to get the traffic-manager in the default namespace.
-func canGetDefaultTrafficManagerService(ctx context.Context) bool {
+func canGetDefaultTrafficManagerService(ctx context.Context, serviceName string) bool {
 	ok, err := k8sclient.CanI(ctx, &auth.ResourceAttributes{
 		Verb:      "get",
 		Resource:  "services",
-		Name:      "traffic-manager",
+		Name:      serviceName,
 		Namespace: defaultManagerNamespace,
 	})
 	return err == nil && ok
@@ -100,7 +116,12 @@ func canAccessNS(ctx context.Context, namespace string) bool {
 	review := auth.SelfSubjectRulesReview{Spec: auth.SelfSubjectRulesReviewSpec{Namespace: namespace}}
 	rr, err := authHandler.Create(ctx, &review, meta.CreateOptions{})
 	if err != nil {
-		dlog.Errorf(ctx, `unable to do "can-i --list" on namespace %s`, namespace)
+		if k8sErrors.IsForbidden(err) {
+			dlog.Infof(ctx, `not permitted to do "can-i --list" on namespace %s`, namespace)
+		} else {
+			dlog.Errorf(ctx, `unable to do "can-i --list" on namespace %s: %v`, namespace, err)
+		}
+		return false
 	}
 	if rr.Status.Incomplete {
 		// Incomplete is most commonly encountered when an authorizer, such as an external authorizer, doesn't support rules evaluation.
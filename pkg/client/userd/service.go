@@ -42,6 +42,9 @@ type Service interface {
 	ReadConnectResponse(context.Context) (*rpc.ConnectInfo, error)
 
 	ManageSessions(c context.Context) error
+
+	// StatusChanged tells the WatchStatus subscribers that the connection status, or its intercepts, have changed.
+	StatusChanged()
 }
 
 type NewServiceFunc func(context.Context, *dgroup.Group, client.Config, *grpc.Server) (Service, error)
@@ -68,6 +68,10 @@ type Session interface {
 	ManagerVersion() semver.Version
 	NewRemainRequest() *manager.RemainRequest
 
+	// AgentImageFQN returns the fully qualified name of the traffic-agent image that the traffic-manager
+	// injects. The result is cached for the duration of the session.
+	AgentImageFQN(context.Context) (*manager.AgentImageFQN, error)
+
 	Status(context.Context) *rpc.ConnectInfo
 	UpdateStatus(context.Context, ConnectRequest) *rpc.ConnectInfo
 
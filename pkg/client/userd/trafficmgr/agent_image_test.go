@@ -0,0 +1,52 @@
+package trafficmgr
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	empty "google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+)
+
+type agentImageManager struct {
+	manager.ManagerClient
+	calls int
+	err   error
+}
+
+func (m *agentImageManager) GetAgentImageFQN(context.Context, *empty.Empty, ...grpc.CallOption) (*manager.AgentImageFQN, error) {
+	m.calls++
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &manager.AgentImageFQN{FQN: "ghcr.io/telepresenceio/tel2:2.21.0"}, nil
+}
+
+func TestSession_AgentImageFQN(t *testing.T) {
+	ctx := context.Background()
+	mc := &agentImageManager{err: errors.New("unavailable")}
+	s := &session{managerClient: mc}
+	s.self = s
+
+	// Errors are not cached
+	_, err := s.AgentImageFQN(ctx)
+	require.Error(t, err)
+	mc.err = nil
+
+	for i := 0; i < 3; i++ {
+		fqn, err := s.AgentImageFQN(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "ghcr.io/telepresenceio/tel2:2.21.0", fqn.FQN)
+	}
+	assert.Equal(t, 2, mc.calls)
+
+	s.invalidateAgentImage()
+	_, err = s.AgentImageFQN(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, mc.calls)
+}
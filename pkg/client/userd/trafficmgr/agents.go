@@ -2,7 +2,9 @@ package trafficmgr
 
 import (
 	"context"
+	"fmt"
 
+	batch "k8s.io/api/batch/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/yaml"
@@ -39,3 +41,47 @@ func (s *session) getCurrentSidecarsInNamespace(ctx context.Context, ns string)
 
 	return sidecars
 }
+
+// unsupportedAgentWorkload returns an error if the given name denotes a CronJob, or a Job, in the given namespace.
+// The traffic-manager never injects traffic-agents into pods owned by such workloads, so there's no agent to
+// uninstall. A nil error is returned when no such workload is found.
+func unsupportedAgentWorkload(ctx context.Context, name, namespace string) error {
+	bi := k8sapi.GetK8sInterface(ctx).BatchV1()
+	if _, err := bi.CronJobs(namespace).Get(ctx, name, meta.GetOptions{}); err == nil {
+		return unsupportedKindError("CronJob", name, namespace)
+	}
+	job, err := bi.Jobs(namespace).Get(ctx, name, meta.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	if cj := owningCronJob(ctx, job); cj != nil {
+		return fmt.Errorf("%w (the Job is owned by CronJob %s)", unsupportedKindError("Job", name, namespace), cj.Name)
+	}
+	return unsupportedKindError("Job", name, namespace)
+}
+
+// owningCronJob follows the controller reference of the given Job and returns the CronJob that it refers to, or
+// nil if the Job isn't controlled by a CronJob, or if that CronJob no longer exists.
+func owningCronJob(ctx context.Context, job *batch.Job) *batch.CronJob {
+	or := meta.GetControllerOf(job)
+	if or == nil || or.Kind != "CronJob" {
+		return nil
+	}
+	cj, err := k8sapi.GetK8sInterface(ctx).BatchV1().CronJobs(job.Namespace).Get(ctx, or.Name, meta.GetOptions{})
+	if err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			dlog.Errorf(ctx, "unable to get CronJob %s.%s: %v", or.Name, job.Namespace, err)
+		}
+		return nil
+	}
+	if cj.UID != or.UID {
+		// The CronJob that created the Job has been replaced by another one with the same name.
+		return nil
+	}
+	return cj
+}
+
+func unsupportedKindError(kind, name, namespace string) error {
+	return fmt.Errorf("%s %s.%s has no traffic-agent, because agents are only injected into the pods of "+
+		"Deployments, ReplicaSets, StatefulSets, and Argo Rollouts", kind, name, namespace)
+}
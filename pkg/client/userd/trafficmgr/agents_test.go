@@ -0,0 +1,93 @@
+package trafficmgr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batch "k8s.io/api/batch/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/datawire/k8sapi/pkg/k8sapi"
+)
+
+func Test_unsupportedAgentWorkload(t *testing.T) {
+	cronJob := func(name string, uid types.UID) *batch.CronJob {
+		return &batch.CronJob{ObjectMeta: meta.ObjectMeta{Name: name, Namespace: "default", UID: uid}}
+	}
+	job := func(name string, owner *batch.CronJob) *batch.Job {
+		j := &batch.Job{ObjectMeta: meta.ObjectMeta{Name: name, Namespace: "default"}}
+		if owner != nil {
+			j.OwnerReferences = []meta.OwnerReference{
+				*meta.NewControllerRef(owner, batch.SchemeGroupVersion.WithKind("CronJob")),
+			}
+		}
+		return j
+	}
+	nightly := cronJob("nightly", "uid-1")
+
+	tests := []struct {
+		name     string
+		objects  []runtime.Object
+		workload string
+		wantErr  string
+		owned    bool
+	}{
+		{
+			name:     "not found",
+			workload: "nightly",
+		},
+		{
+			name:     "cron job",
+			objects:  []runtime.Object{nightly},
+			workload: "nightly",
+			wantErr:  "CronJob nightly.default has no traffic-agent",
+		},
+		{
+			name:     "job",
+			objects:  []runtime.Object{job("nightly-1", nil)},
+			workload: "nightly-1",
+			wantErr:  "Job nightly-1.default has no traffic-agent",
+		},
+		{
+			name:     "job owned by cron job",
+			objects:  []runtime.Object{nightly, job("nightly-1", nightly)},
+			workload: "nightly-1",
+			wantErr:  "Job nightly-1.default has no traffic-agent",
+			owned:    true,
+		},
+		{
+			name:     "job owned by deleted cron job",
+			objects:  []runtime.Object{job("nightly-1", nightly)},
+			workload: "nightly-1",
+			wantErr:  "Job nightly-1.default has no traffic-agent",
+		},
+		{
+			name:     "job owned by replaced cron job",
+			objects:  []runtime.Object{cronJob("nightly", "uid-2"), job("nightly-1", nightly)},
+			workload: "nightly-1",
+			wantErr:  "Job nightly-1.default has no traffic-agent",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := k8sapi.WithK8sInterface(context.Background(), fake.NewSimpleClientset(tt.objects...))
+			err := unsupportedAgentWorkload(ctx, tt.workload, "default")
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			if tt.owned {
+				assert.Contains(t, err.Error(), "(the Job is owned by CronJob nightly)")
+			} else {
+				assert.NotContains(t, err.Error(), "owned by")
+			}
+		})
+	}
+}
@@ -13,7 +13,7 @@ import (
 )
 
 func (s *session) GetConfig(ctx context.Context) (*client.SessionConfig, error) {
-	nc, err := s.rootDaemon.GetNetworkConfig(ctx, &empty.Empty{})
+	nc, err := s.RootDaemon().GetNetworkConfig(ctx, &empty.Empty{})
 	if err != nil {
 		return nil, err
 	}
@@ -35,6 +35,8 @@ func (s *session) GetConfig(ctx context.Context) (*client.SessionConfig, error)
 			IncludeSuffixes: dns.IncludeSuffixes,
 			ExcludeSuffixes: dns.ExcludeSuffixes,
 			LookupTimeout:   dns.LookupTimeout.AsDuration(),
+			CacheTTL:        dns.CacheTtl.AsDuration(),
+			NegativeTTL:     dns.NegativeTtl.AsDuration(),
 		},
 		Routing: client.Routing{
 			Subnets:          subnets(nc.Subnets),
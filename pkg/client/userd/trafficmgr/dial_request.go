@@ -12,9 +12,10 @@ func (s *session) dialRequestWatcher(ctx context.Context) error {
 
 func (s *session) _dialRequestWatcher(ctx context.Context) error {
 	// Deal with dial requests from the manager
-	dialerStream, err := s.managerClient.WatchDial(ctx, s.sessionInfo)
+	si := s.SessionInfo()
+	dialerStream, err := s.managerClient.WatchDial(ctx, si)
 	if err != nil {
 		return err
 	}
-	return tunnel.DialWaitLoop(ctx, tunnel.ManagerProvider(s.managerClient), dialerStream, s.sessionInfo.SessionId)
+	return tunnel.DialWaitLoop(ctx, tunnel.ManagerProvider(s.managerClient), dialerStream, si.SessionId)
 }
@@ -2,10 +2,12 @@ package trafficmgr
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
+	"net"
 	"net/http"
+	"net/netip"
 	"os"
 	"strconv"
 	"strings"
@@ -15,15 +17,19 @@ import (
 	grpcCodes "google.golang.org/grpc/codes"
 	grpcStatus "google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/durationpb"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/datawire/dlib/dgroup"
 	"github.com/datawire/dlib/dlog"
+	"github.com/datawire/k8sapi/pkg/k8sapi"
 	"github.com/telepresenceio/telepresence/rpc/v2/common"
 	rpc "github.com/telepresenceio/telepresence/rpc/v2/connector"
 	"github.com/telepresenceio/telepresence/rpc/v2/daemon"
 	"github.com/telepresenceio/telepresence/rpc/v2/manager"
 	"github.com/telepresenceio/telepresence/v2/pkg/agentconfig"
 	"github.com/telepresenceio/telepresence/v2/pkg/client"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/api"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/docker"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/remotefs"
 	"github.com/telepresenceio/telepresence/v2/pkg/client/userd"
@@ -68,6 +74,15 @@ type intercept struct {
 
 	// Use bridged ftp/sftp mount through this local port
 	localMountPort int32
+
+	// Mount the remote file system read-only
+	mountReadOnly bool
+
+	// Maps remote paths to local directories when each path is mounted separately.
+	mountPoints map[string]string
+
+	// The mounters used for the mountPoints, keyed by remote path.
+	mounters map[string]remotefs.Mounter
 }
 
 // interceptResult is what gets written to the awaitIntercept's waitCh channel when the
@@ -89,6 +104,12 @@ type awaitIntercept struct {
 	// the mount to take place in a host
 	mountPort int32
 
+	// mountReadOnly is true when the remote file system should be mounted read-only.
+	mountReadOnly bool
+
+	// mountPoints maps remote paths to local directories when each path should be mounted separately.
+	mountPoints map[string]string
+
 	waitCh chan<- interceptResult
 }
 
@@ -139,6 +160,58 @@ func (ic *intercept) localPorts() []string {
 	return ps
 }
 
+// toPodForwards returns the forwards described by the given local ports. Malformed entries are
+// ignored here. They are reported when the forward is started.
+func toPodForwards(localPorts []string) []agentconfig.ToPodForward {
+	tps := make([]agentconfig.ToPodForward, 0, len(localPorts))
+	for _, lp := range localPorts {
+		if tp, err := agentconfig.NewToPodForward(lp); err == nil {
+			tps = append(tps, tp)
+		}
+	}
+	return tps
+}
+
+// targetForward returns the local address of the intercept handler as a ToPodForward, so that it
+// can be checked for conflicts with the forwards. The boolean is false when the target host isn't an IP.
+func targetForward(spec *manager.InterceptSpec) (agentconfig.ToPodForward, bool) {
+	addr, err := netip.ParseAddr(spec.TargetHost)
+	if err != nil {
+		return agentconfig.ToPodForward{}, false
+	}
+	port := uint16(spec.TargetPort)
+	return agentconfig.ToPodForward{
+		PortAndProto: agentconfig.PortAndProto{Port: port, Proto: core.ProtocolTCP},
+		BindAddr:     addr,
+		LocalPort:    port,
+	}, true
+}
+
+// localAddrConflict returns the local address that is used both by the given intercept and the
+// intercept described by the given spec, or an empty string when no such address exists.
+func (ic *intercept) localAddrConflict(spec *manager.InterceptSpec) string {
+	if ic.Spec.TargetPort == spec.TargetPort && ic.Spec.TargetHost == spec.TargetHost {
+		return net.JoinHostPort(spec.TargetHost, strconv.Itoa(int(spec.TargetPort)))
+	}
+	tps := toPodForwards(spec.LocalPorts)
+	if tf, ok := targetForward(spec); ok {
+		tps = append(tps, tf)
+	}
+	its := toPodForwards(ic.localPorts())
+	if tf, ok := targetForward(ic.Spec); ok {
+		its = append(its, tf)
+	}
+	for i := range tps {
+		tp := &tps[i]
+		for j := range its {
+			if tp.Conflicts(&its[j]) {
+				return tp.LocalAddrString()
+			}
+		}
+	}
+	return ""
+}
+
 func (ic *intercept) shouldForward() bool {
 	return len(ic.localPorts()) > 0
 }
@@ -160,17 +233,17 @@ func (ic *intercept) startForwards(ctx context.Context, wg *sync.WaitGroup) {
 
 func (ic *intercept) workerPortForward(ctx context.Context, port string, wg *sync.WaitGroup) {
 	defer wg.Done()
-	pp, err := agentconfig.NewPortAndProto(port)
+	tp, err := agentconfig.NewToPodForward(port)
 	if err != nil {
 		dlog.Errorf(ctx, "malformed extra port %q: %v", port, err)
 		return
 	}
-	addr, err := pp.Addr()
+	addr, err := tp.Addr()
 	if err != nil {
 		dlog.Errorf(ctx, "unable to resolve extra port %q: %v", port, err)
 		return
 	}
-	f := forwarder.NewInterceptor(addr, ic.PodIp, pp.Port)
+	f := forwarder.NewInterceptor(addr, ic.PodIp, tp.Port)
 	err = f.Serve(ctx, nil)
 	if err != nil && ctx.Err() == nil {
 		dlog.Errorf(ctx, "port-forwarder failed with %v", err)
@@ -262,6 +335,29 @@ func (lpf *podIntercepts) getOrCreateMountsDone(ic *intercept) <-chan struct{} {
 	return md
 }
 
+// retain makes the port forwards and mounts of the intercept with the given ID part of the current snapshot,
+// so that cancelUnwanted doesn't cancel them.
+func (lpf *podIntercepts) retain(id string) {
+	for fk := range lpf.alivePods {
+		if fk.Id == id {
+			lpf.snapshot[fk] = struct{}{}
+		}
+	}
+}
+
+// cancelIntercept cancels the port forwards and mounts of the intercept with the given ID, and waits for
+// them to end.
+func (lpf *podIntercepts) cancelIntercept(ctx context.Context, id string) {
+	for fk, lp := range lpf.alivePods {
+		if fk.Id == id {
+			dlog.Infof(ctx, "Terminating mounts and port-forwards for %+v", fk)
+			lp.cancelPod()
+			delete(lpf.alivePods, fk)
+			lp.wg.Wait()
+		}
+	}
+}
+
 // cancelUnwanted cancels all port forwards that hasn't been started since initSnapshot.
 func (lpf *podIntercepts) cancelUnwanted(ctx context.Context) {
 	for fk, lp := range lpf.alivePods {
@@ -287,26 +383,45 @@ func (s *session) watchInterceptsHandler(ctx context.Context) error {
 	//     their exit statuses is just a memory leak
 	//  3. because we want a per-worker cancel, we'd have to implement our own Context
 	//     management on top anyway, so dgroup wouldn't actually save us any complexity.
-	return runWithRetry(ctx, s.watchInterceptsLoop)
+	//
+	// The podIntercepts outlive each stream, so that port forwards and volume mounts survive while
+	// a dropped stream is re-established.
+	podIcepts := newPodIntercepts()
+	defer func() {
+		// Handle as if we had an empty snapshot. This will ensure that port forwards and volume mounts are cancelled correctly.
+		s.handleInterceptSnapshot(ctx, podIcepts, nil)
+	}()
+	return runWithRetry(ctx, func(ctx context.Context) error {
+		return s.watchInterceptsLoop(ctx, podIcepts)
+	})
 }
 
-func (s *session) watchInterceptsLoop(ctx context.Context) error {
-	stream, err := s.managerClient.WatchIntercepts(ctx, s.SessionInfo())
+func (s *session) watchInterceptsLoop(ctx context.Context, podIcepts *podIntercepts) error {
+	// The stream has its own context, so that it can be restarted when the heartbeat fails.
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	s.currentInterceptsLock.Lock()
+	s.cancelInterceptStream = cancel
+	s.currentInterceptsLock.Unlock()
+
+	stream, err := s.managerClient.WatchIntercepts(streamCtx, s.SessionInfo())
 	if err != nil {
 		return fmt.Errorf("manager.WatchIntercepts dial: %w", err)
 	}
-	podIcepts := newPodIntercepts()
 	for ctx.Err() == nil {
 		snapshot, err := stream.Recv()
 		if err != nil {
-			// Handle as if we had an empty snapshot. This will ensure that port forwards and volume mounts are cancelled correctly.
-			s.handleInterceptSnapshot(ctx, podIcepts, nil)
-			if ctx.Err() != nil || errors.Is(err, io.EOF) {
-				// Normal termination
+			if streamCtx.Err() != nil {
+				// Normal termination, or a restart
 				return nil
 			}
+			s.interceptStreamDropped(ctx)
+			if grpcStatus.Code(err) == grpcCodes.NotFound {
+				s.signalSessionLost()
+			}
 			return fmt.Errorf("manager.WatchIntercepts recv: %w", err)
 		}
+		s.interceptStreamRecovered(ctx, snapshot.Intercepts)
 		s.handleInterceptSnapshot(ctx, podIcepts, snapshot.Intercepts)
 	}
 	return nil
@@ -314,6 +429,7 @@ func (s *session) watchInterceptsLoop(ctx context.Context) error {
 
 func (s *session) handleInterceptSnapshot(ctx context.Context, podIcepts *podIntercepts, intercepts []*manager.InterceptInfo) {
 	s.setCurrentIntercepts(ctx, intercepts)
+	userd.GetService(ctx).StatusChanged()
 	podIcepts.initSnapshot()
 
 	for _, ii := range intercepts {
@@ -327,7 +443,19 @@ func (s *session) handleInterceptSnapshot(ctx context.Context, podIcepts *podInt
 		if aw != nil {
 			delete(s.interceptWaiters, ii.Spec.Name)
 		}
+
+		var old *intercept
+		if ii.Disposition == manager.InterceptDispositionType_ACTIVE {
+			old = s.takeRecreated(ii)
+		}
 		s.currentInterceptsLock.Unlock()
+		if old != nil {
+			// The lost intercept has been recreated, so its mounts and port-forwards are replaced. They
+			// must end before the ones of the recreated intercept start on the same mount point and ports.
+			old.cancel()
+			podIcepts.cancelIntercept(ctx, old.Id)
+			old.wg.Wait()
+		}
 
 		var err error
 		if ii.Disposition == manager.InterceptDispositionType_ACTIVE {
@@ -336,7 +464,7 @@ func (s *session) handleInterceptSnapshot(ctx context.Context, podIcepts *podInt
 				err = errcat.User.Newf("active intercepts in both namespace %s and %s", ns, s.Namespace)
 			}
 		} else {
-			err = fmt.Errorf("intercept in error state %v: %v", ii.Disposition, ii.Message)
+			err = errors.New(api.DispositionMessage(ii))
 		}
 
 		// Notify waiters for active intercepts
@@ -368,8 +496,13 @@ func (s *session) handleInterceptSnapshot(ctx context.Context, podIcepts *podInt
 			ic.FtpPort = 0
 			ic.SftpPort = 0
 		}
-		podIcepts.start(ctx, ic, s.rootDaemon)
+		podIcepts.start(ctx, ic, s.RootDaemon())
+	}
+	s.currentInterceptsLock.Lock()
+	for _, ic := range s.recreating {
+		podIcepts.retain(ic.Id)
 	}
+	s.currentInterceptsLock.Unlock()
 	podIcepts.cancelUnwanted(ctx)
 }
 
@@ -413,6 +546,8 @@ func (s *session) setCurrentIntercepts(ctx context.Context, iis []*manager.Inter
 			if aw, ok := s.interceptWaiters[ii.Spec.Name]; ok {
 				ic.ClientMountPoint = aw.mountPoint
 				ic.localMountPort = aw.mountPort
+				ic.mountReadOnly = aw.mountReadOnly
+				ic.mountPoints = aw.mountPoints
 			}
 		}
 		intercepts[ii.Id] = ic
@@ -426,9 +561,12 @@ func (s *session) setCurrentIntercepts(ctx context.Context, iis []*manager.Inter
 	sb.WriteByte(']')
 	dlog.Debugf(ctx, "setCurrentIntercepts(%s)", sb.String())
 
-	// Cancel those that no longer exists
+	// Cancel those that no longer exists, unless they are lost intercepts that are being recreated
 	for id, ic := range s.currentIntercepts {
-		if _, ok := intercepts[id]; !ok {
+		if _, ok := intercepts[id]; !ok && s.recreating[ic.Spec.Name] != ic {
+			if ea := ic.ExpiresAt; ea != nil && !ea.AsTime().After(time.Now()) {
+				dlog.Warnf(ctx, "Intercept %s was removed by the traffic-manager because it reached its maximum lifetime", ic.Spec.Name)
+			}
 			dlog.Debugf(ctx, "Cancelling context for intercept %s", ic.Spec.Name)
 			ic.cancel()
 		}
@@ -477,13 +615,14 @@ func (s *session) ensureNoInterceptConflict(ir *rpc.CreateInterceptRequest) *rpc
 	defer s.currentInterceptsLock.Unlock()
 	spec := ir.Spec
 	for _, iCept := range s.currentIntercepts {
+		addrInUse := iCept.localAddrConflict(spec)
 		switch {
 		case iCept.Spec.Name == spec.Name:
 			return InterceptError(common.InterceptError_ALREADY_EXISTS, errcat.User.New(spec.Name))
-		case iCept.Spec.TargetPort == spec.TargetPort && iCept.Spec.TargetHost == spec.TargetHost:
+		case addrInUse != "":
 			return &rpc.InterceptResult{
 				Error:         common.InterceptError_LOCAL_TARGET_IN_USE,
-				ErrorText:     spec.Name,
+				ErrorText:     addrInUse,
 				ErrorCategory: int32(errcat.User),
 				InterceptInfo: iCept.InterceptInfo,
 			}
@@ -499,6 +638,18 @@ func (s *session) ensureNoInterceptConflict(ir *rpc.CreateInterceptRequest) *rpc
 	return nil
 }
 
+// readOnlyMountError returns an error result if the given request asks for a read-only mount that the daemon
+// can't provide. Only sshfs can mount read-only, so the FTP mounter can't be used. The check is made here rather
+// than when the mount is started, because the daemon's configuration may include settings from the
+// traffic-manager that the CLI doesn't know about.
+func readOnlyMountError(ir *rpc.CreateInterceptRequest, useFtp bool) *rpc.InterceptResult {
+	if ir.MountReadOnly && useFtp && ir.LocalMountPort == 0 {
+		return InterceptError(common.InterceptError_INTERNAL,
+			errcat.User.New("only SFTP can be used with --mount-ro. Client is configured to perform remote mounts using FTP"))
+	}
+	return nil
+}
+
 // CanIntercept checks if it is possible to create an intercept for the given request. The intercept can proceed
 // only if the returned rpc.InterceptResult is nil. The returned runtime.Object is either nil, indicating a local
 // intercept, or the workload for the intercept.
@@ -511,10 +662,38 @@ func (s *session) CanIntercept(c context.Context, ir *rpc.CreateInterceptRequest
 		return nil, InterceptError(common.InterceptError_NAMESPACE_AMBIGUITY, errcat.User.Newf("%s,%s", s.Namespace, spec.Namespace))
 	}
 
+	if spec.Agent == "" && ir.WorkloadSelector != "" {
+		wls, err := s.workloadsForSelector(c, ir.WorkloadSelector, spec.Namespace)
+		if err != nil {
+			return nil, InterceptError(common.InterceptError_INTERNAL, err)
+		}
+		switch len(wls) {
+		case 0:
+			return nil, InterceptError(common.InterceptError_NO_ACCEPTABLE_WORKLOAD,
+				errcat.User.Newf("selector %q", ir.WorkloadSelector))
+		case 1:
+			spec.Agent = wls[0].GetName()
+			spec.WorkloadKind = wls[0].GetKind()
+		default:
+			matches := make([]*manager.AgentInfo, len(wls))
+			for i, wl := range wls {
+				matches[i] = &manager.AgentInfo{Name: wl.GetName(), Namespace: wl.GetNamespace()}
+			}
+			data, err := json.Marshal(matches)
+			if err != nil {
+				return nil, InterceptError(common.InterceptError_INTERNAL, err)
+			}
+			return nil, InterceptError(common.InterceptError_AMBIGUOUS_MATCH, errcat.User.New(string(data)))
+		}
+	}
+
 	self := s.self
 	if er := s.ensureNoInterceptConflict(ir); er != nil {
 		return nil, er
 	}
+	if er := readOnlyMountError(ir, client.GetConfig(c).Intercept().UseFtp); er != nil {
+		return nil, er
+	}
 	if spec.Agent == "" {
 		return nil, nil
 	}
@@ -522,22 +701,58 @@ func (s *session) CanIntercept(c context.Context, ir *rpc.CreateInterceptRequest
 	mgrIr := &manager.CreateInterceptRequest{
 		Session:       s.SessionInfo(),
 		InterceptSpec: spec,
+		AgentImage:    ir.AgentImage,
 	}
 	if er := self.InterceptProlog(c, mgrIr); er != nil {
 		return nil, er
 	}
 	pi, err := s.managerClient.PrepareIntercept(c, mgrIr)
 	if err != nil {
+		if wait := ir.Wait.AsDuration(); wait > 0 && grpcStatus.Code(err) == grpcCodes.DeadlineExceeded {
+			err = errcat.User.Newf("the traffic-agent of %s.%s did not become ready within --wait %s", spec.Agent, spec.Namespace, wait)
+		}
 		return nil, InterceptError(common.InterceptError_TRAFFIC_MANAGER_ERROR, err)
 	}
 	if pi.Error != "" {
 		return nil, InterceptError(common.InterceptError_TRAFFIC_MANAGER_ERROR, errcat.Category(pi.ErrorCategory).New(pi.Error))
 	}
+	if spec.TargetPort == 0 {
+		// The port was given as a name only, so the local port is the container port that the name resolved to.
+		spec.TargetPort = pi.ContainerPort
+		if er := s.ensureNoInterceptConflict(ir); er != nil {
+			return nil, er
+		}
+	}
 
 	iInfo := &interceptInfo{preparedIntercept: pi}
 	return iInfo, nil
 }
 
+// workloadsForSelector returns the workloads in the given namespace whose pod template labels
+// match the given label selector.
+func (s *session) workloadsForSelector(c context.Context, selector, namespace string) ([]k8sapi.Workload, error) {
+	var wls []k8sapi.Workload
+	s.wlWatcher.eachWorkload(c, s.GetManagerNamespace(), []string{namespace}, func(wl k8sapi.Workload) {
+		wls = append(wls, wl)
+	})
+	return selectWorkloads(selector, wls)
+}
+
+// selectWorkloads returns the workloads whose pod template labels match the given label selector.
+func selectWorkloads(selector string, wls []k8sapi.Workload) ([]k8sapi.Workload, error) {
+	sel, err := labels.Parse(selector)
+	if err != nil {
+		return nil, errcat.User.Newf("invalid selector %q: %v", selector, err)
+	}
+	var matches []k8sapi.Workload
+	for _, wl := range wls {
+		if sel.Matches(labels.Set(wl.GetPodTemplate().Labels)) {
+			matches = append(matches, wl)
+		}
+	}
+	return matches, nil
+}
+
 func (s *session) NewCreateInterceptRequest(spec *manager.InterceptSpec) *manager.CreateInterceptRequest {
 	return &manager.CreateInterceptRequest{
 		Session:       s.self.SessionInfo(),
@@ -547,6 +762,14 @@ func (s *session) NewCreateInterceptRequest(spec *manager.InterceptSpec) *manage
 
 // AddIntercept adds one intercept.
 func (s *session) AddIntercept(c context.Context, ir *rpc.CreateInterceptRequest) *rpc.InterceptResult {
+	// The --wait covers the arrival and readiness of the traffic-agent, which the traffic-manager waits for
+	// when the intercept is prepared, and the activation of the intercept.
+	wait := ir.Wait.AsDuration()
+	if wait > 0 {
+		var cancel context.CancelFunc
+		c, cancel = context.WithTimeout(c, wait)
+		defer cancel()
+	}
 	self := s.self
 	iInfo, result := self.CanIntercept(c, ir)
 	if result != nil {
@@ -591,17 +814,23 @@ func (s *session) AddIntercept(c context.Context, ir *rpc.CreateInterceptRequest
 	tos := client.GetConfig(c).Timeouts()
 	spec.RoundtripLatency = int64(tos.Get(client.TimeoutRoundtripLatency)) * 2 // Account for extra hop
 	spec.DialTimeout = int64(tos.Get(client.TimeoutEndpointDial))
-	c, cancel := tos.TimeoutContext(c, client.TimeoutIntercept)
-	defer cancel()
+	spec.DrainTimeout = int64(tos.Get(client.TimeoutInterceptDrain))
+	if wait == 0 {
+		var cancel context.CancelFunc
+		c, cancel = tos.TimeoutContext(c, client.TimeoutIntercept)
+		defer cancel()
+	}
 
 	// The agent is in place and the traffic-manager has acknowledged the creation of the intercept. It
 	// should become active within a few seconds.
 	waitCh := make(chan interceptResult, 2) // Need a buffer because reply can come before we're reading the channel,
 	s.currentInterceptsLock.Lock()
 	s.interceptWaiters[spec.Name] = &awaitIntercept{
-		mountPoint: ir.MountPoint,
-		mountPort:  ir.LocalMountPort,
-		waitCh:     waitCh,
+		mountPoint:    ir.MountPoint,
+		mountPort:     ir.LocalMountPort,
+		mountReadOnly: ir.MountReadOnly,
+		mountPoints:   ir.MountPoints,
+		waitCh:        waitCh,
 	}
 	s.currentInterceptsLock.Unlock()
 	defer func() {
@@ -641,10 +870,15 @@ func (s *session) AddIntercept(c context.Context, ir *rpc.CreateInterceptRequest
 	for {
 		select {
 		case <-c.Done():
-			return InterceptError(common.InterceptError_FAILED_TO_ESTABLISH, client.CheckTimeout(c, c.Err()))
+			return InterceptError(common.InterceptError_FAILED_TO_ESTABLISH, waitTimeoutError(c, ii, wait))
 		case wr := <-waitCh:
 			if wr.err != nil {
-				return InterceptError(common.InterceptError_FAILED_TO_ESTABLISH, wr.err)
+				er := InterceptError(common.InterceptError_FAILED_TO_ESTABLISH, wr.err)
+				if wr.intercept != nil {
+					// Let the caller see the disposition and message of the failed intercept.
+					er.InterceptInfo = wr.intercept.InterceptInfo
+				}
+				return er
 			}
 			ic := wr.intercept
 			ii = ic.InterceptInfo
@@ -666,6 +900,21 @@ func (s *session) AddIntercept(c context.Context, ir *rpc.CreateInterceptRequest
 	}
 }
 
+// waitTimeoutError returns the error to use when the given context is done before the intercept became active.
+// The error includes the last known state of the intercept.
+func waitTimeoutError(c context.Context, ii *manager.InterceptInfo, wait time.Duration) error {
+	err := c.Err()
+	if wait > 0 && errors.Is(err, context.DeadlineExceeded) {
+		err = errcat.User.Newf("the intercept did not become active within --wait %s", wait)
+	} else {
+		err = client.CheckTimeout(c, err)
+	}
+	if ii != nil && ii.Disposition != manager.InterceptDispositionType_ACTIVE {
+		err = fmt.Errorf("%w. Last known state: %s", err, api.DispositionMessage(ii))
+	}
+	return err
+}
+
 func (s *session) InterceptProlog(context.Context, *manager.CreateInterceptRequest) *rpc.InterceptResult {
 	return nil
 }
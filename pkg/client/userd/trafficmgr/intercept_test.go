@@ -0,0 +1,85 @@
+package trafficmgr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/datawire/k8sapi/pkg/k8sapi"
+	rpc "github.com/telepresenceio/telepresence/rpc/v2/connector"
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+)
+
+func Test_selectWorkloads(t *testing.T) {
+	deployment := func(name string, lbs map[string]string) k8sapi.Workload {
+		return k8sapi.Deployment(&apps.Deployment{
+			ObjectMeta: meta.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "ignored"}},
+			Spec: apps.DeploymentSpec{
+				Template: core.PodTemplateSpec{ObjectMeta: meta.ObjectMeta{Labels: lbs}},
+			},
+		})
+	}
+	wls := []k8sapi.Workload{
+		deployment("checkout", map[string]string{"app": "checkout", "tier": "backend"}),
+		deployment("cart", map[string]string{"app": "cart", "tier": "backend"}),
+		deployment("web", map[string]string{"app": "web", "tier": "frontend"}),
+	}
+	names := func(wls []k8sapi.Workload) []string {
+		ns := make([]string, len(wls))
+		for i, wl := range wls {
+			ns[i] = wl.GetName()
+		}
+		return ns
+	}
+	tests := []struct {
+		selector string
+		want     []string
+	}{
+		{"app=checkout", []string{"checkout"}},
+		{"tier=backend", []string{"checkout", "cart"}},
+		{"tier=backend,app!=cart", []string{"checkout"}},
+		{"app in (cart, web)", []string{"cart", "web"}},
+		{"app=ignored", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			got, err := selectWorkloads(tt.selector, wls)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, names(got))
+		})
+	}
+
+	_, err := selectWorkloads("app=(", wls)
+	require.Error(t, err)
+	assert.Equal(t, errcat.User, errcat.GetCategory(err))
+}
+
+func Test_readOnlyMountError(t *testing.T) {
+	tests := []struct {
+		name     string
+		ir       *rpc.CreateInterceptRequest
+		useFtp   bool
+		rejected bool
+	}{
+		{"sftp", &rpc.CreateInterceptRequest{MountReadOnly: true}, false, false},
+		{"ftp", &rpc.CreateInterceptRequest{MountReadOnly: true}, true, true},
+		{"ftp, not read-only", &rpc.CreateInterceptRequest{}, true, false},
+		{"ftp, bridged", &rpc.CreateInterceptRequest{MountReadOnly: true, LocalMountPort: 8022}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			er := readOnlyMountError(tt.ir, tt.useFtp)
+			if !tt.rejected {
+				assert.Nil(t, er)
+				return
+			}
+			require.NotNil(t, er)
+			assert.Equal(t, int32(errcat.User), er.ErrorCategory)
+			assert.Contains(t, er.ErrorText, "--mount-ro")
+		})
+	}
+}
@@ -2,6 +2,7 @@ package trafficmgr
 
 import (
 	"context"
+	"path"
 	"sync"
 
 	"github.com/datawire/dlib/dlog"
@@ -13,7 +14,7 @@ import (
 )
 
 func (ic *intercept) shouldMount() bool {
-	return (ic.FtpPort > 0 || ic.SftpPort > 0) && (ic.localMountPort > 0 || ic.ClientMountPoint != "")
+	return (ic.FtpPort > 0 || ic.SftpPort > 0) && (ic.localMountPort > 0 || ic.ClientMountPoint != "" || len(ic.mountPoints) > 0)
 }
 
 // startMount starts the mount for the given podInterceptKey.
@@ -32,6 +33,10 @@ func (ic *intercept) startMount(ctx context.Context, iceptWG, podWG *sync.WaitGr
 			dlog.Errorf(ctx, "Client is configured to perform remote mounts using FTP, but only SFTP can be used with --local-mount-port")
 			return
 		}
+		if ic.mountReadOnly {
+			dlog.Errorf(ctx, "Client is configured to perform remote mounts using FTP, but only SFTP can be used with --mount-ro")
+			return
+		}
 		// The FTP mounter survives multiple starts for the same intercept. It just resets the address
 		mountCtx = ic.ctx
 		if fuseftp = userd.GetService(ctx).FuseFTPMgr().GetFuseFTPClient(ctx); fuseftp == nil {
@@ -47,20 +52,44 @@ func (ic *intercept) startMount(ctx context.Context, iceptWG, podWG *sync.WaitGr
 		port = ic.SftpPort
 	}
 
-	m := ic.Mounter
-	if m == nil {
+	newMounter := func() remotefs.Mounter {
 		switch {
 		case ic.localMountPort != 0:
 			session := userd.GetSession(ctx)
-			m = remotefs.NewBridgeMounter(session.SessionInfo().SessionId, session.ManagerClient(), uint16(ic.localMountPort))
+			return remotefs.NewBridgeMounter(session.SessionInfo().SessionId, session.ManagerClient(), uint16(ic.localMountPort))
 		case useFtp:
-			m = remotefs.NewFTPMounter(fuseftp, iceptWG)
+			return remotefs.NewFTPMounter(fuseftp, iceptWG)
 		default:
-			m = remotefs.NewSFTPMounter(iceptWG, podWG)
+			return remotefs.NewSFTPMounter(iceptWG, podWG, ic.mountReadOnly)
+		}
+	}
+
+	podIP := iputil.Parse(ic.PodIp)
+	if len(ic.mountPoints) > 0 {
+		// Each remote path is mounted separately on its own local directory.
+		if ic.mounters == nil {
+			ic.mounters = make(map[string]remotefs.Mounter, len(ic.mountPoints))
+		}
+		for remote, local := range ic.mountPoints {
+			m, ok := ic.mounters[remote]
+			if !ok {
+				m = newMounter()
+				ic.mounters[remote] = m
+			}
+			err := m.Start(mountCtx, ic.Id, local, path.Join(ic.MountPoint, remote), podIP, uint16(port))
+			if err != nil && ctx.Err() == nil {
+				dlog.Error(ctx, err)
+			}
 		}
+		return
+	}
+
+	m := ic.Mounter
+	if m == nil {
+		m = newMounter()
 		ic.Mounter = m
 	}
-	err := m.Start(mountCtx, ic.Id, ic.ClientMountPoint, ic.MountPoint, iputil.Parse(ic.PodIp), uint16(port))
+	err := m.Start(mountCtx, ic.Id, ic.ClientMountPoint, ic.MountPoint, podIP, uint16(port))
 	if err != nil && ctx.Err() == nil {
 		dlog.Error(ctx, err)
 	}
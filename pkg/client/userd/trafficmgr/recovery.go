@@ -0,0 +1,230 @@
+package trafficmgr
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	empty "google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/datawire/dlib/dlog"
+	rootdRpc "github.com/telepresenceio/telepresence/rpc/v2/daemon"
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
+	"github.com/telepresenceio/telepresence/v2/pkg/client/userd"
+)
+
+// interceptStreamDropped is called when the intercept stream from the traffic-manager is lost. The session
+// enters the reconnecting state and remembers the active intercepts so that they can be recreated once the
+// stream is re-established. Port forwards and mounts are left intact in the meantime.
+func (s *session) interceptStreamDropped(ctx context.Context) {
+	if !s.reconnecting.CompareAndSwap(false, true) {
+		return
+	}
+	s.currentInterceptsLock.Lock()
+	var lost []*intercept
+	for _, ic := range s.currentIntercepts {
+		if ic.Disposition == manager.InterceptDispositionType_ACTIVE {
+			lost = append(lost, ic)
+		}
+	}
+	s.lostIntercepts = lost
+	s.currentInterceptsLock.Unlock()
+	dlog.Warnf(ctx, "Lost the intercept stream from the traffic-manager with %d active intercepts, reconnecting", len(lost))
+	userd.GetService(ctx).StatusChanged()
+}
+
+// restartInterceptStream is called when the traffic-manager doesn't respond to the heartbeat. The current
+// intercept stream is considered dropped, and is cancelled so that it is dialed again.
+func (s *session) restartInterceptStream(ctx context.Context) {
+	s.interceptStreamDropped(ctx)
+	s.currentInterceptsLock.Lock()
+	if s.cancelInterceptStream != nil {
+		s.cancelInterceptStream()
+	}
+	s.currentInterceptsLock.Unlock()
+}
+
+// signalSessionLost tells the remain loop that the traffic-manager no longer knows the session.
+func (s *session) signalSessionLost() {
+	select {
+	case s.sessionLost <- struct{}{}:
+	default:
+	}
+}
+
+// arriveAgain is called when the traffic-manager no longer knows the session, e.g. because it was restarted. The
+// client arrives again using a new session, and the root daemon is reconnected using that session. The active
+// intercepts are then recreated by the intercept stream of the new session, while their mounts and port-forwards
+// are kept.
+func (s *session) arriveAgain(ctx context.Context) error {
+	s.restartInterceptStream(ctx)
+	tCtx, cancel := client.GetConfig(ctx).Timeouts().TimeoutContext(ctx, client.TimeoutTrafficManagerAPI)
+	defer cancel()
+	si, err := s.managerClient.ArriveAsClient(tCtx, &manager.ClientInfo{
+		Name:      s.clientID,
+		Namespace: s.Namespace,
+		InstallId: s.installID,
+		Product:   "telepresence",
+		Version:   client.Version(),
+	})
+	if err != nil {
+		return client.CheckTimeout(tCtx, fmt.Errorf("manager.ArriveAsClient: %w", err))
+	}
+	if err = SaveSessionInfoToUserCache(ctx, s.daemonID, si); err != nil {
+		return err
+	}
+	dlog.Infof(ctx, "The traffic-manager lost session %s, arrived again as %s", s.SessionInfo().SessionId, si.SessionId)
+	s.sessionLock.Lock()
+	s.sessionInfo = si
+	s.outboundInfo = proto.Clone(s.outboundInfo).(*rootdRpc.OutboundInfo)
+	s.outboundInfo.Session = si
+	oi := s.outboundInfo
+	s.sessionLock.Unlock()
+
+	// The intercept stream might have been dialed again using the old session while it was replaced.
+	s.restartInterceptStream(ctx)
+
+	if userd.GetService(ctx).RootSessionInProcess() {
+		// The in-process root session can't change its session, so it's replaced.
+		_, _ = s.RootDaemon().Disconnect(ctx, &empty.Empty{})
+		rd, err := s.connectRootDaemon(ctx, oi, s.isPodDaemon)
+		if err != nil {
+			return err
+		}
+		s.sessionLock.Lock()
+		s.rootDaemon = rd
+		s.sessionLock.Unlock()
+		return nil
+	}
+	rd := s.RootDaemon()
+	if err = connectRootSession(ctx, rd, oi); err != nil {
+		return err
+	}
+	return waitForRootNetwork(ctx, rd)
+}
+
+// interceptStreamRecovered is called with the first snapshot of every intercept stream. It ends the reconnecting
+// state and recreates the intercepts that were active when the previous stream was dropped, but that are
+// absent from the given snapshot.
+func (s *session) interceptStreamRecovered(ctx context.Context, iis []*manager.InterceptInfo) {
+	if !s.reconnecting.CompareAndSwap(true, false) {
+		return
+	}
+	// The traffic-manager may have been upgraded while the stream was down.
+	s.invalidateAgentImage()
+	s.currentInterceptsLock.Lock()
+	lost, expired := interceptsToRecreate(s.lostIntercepts, iis, time.Now())
+	s.lostIntercepts = nil
+	for _, ic := range lost {
+		s.recreating[ic.Spec.Name] = ic
+	}
+	s.currentInterceptsLock.Unlock()
+	for _, ic := range expired {
+		dlog.Warnf(ctx, "Intercept %s reached its maximum lifetime while reconnecting and will not be recreated", ic.Spec.Name)
+	}
+	dlog.Infof(ctx, "Intercept stream from the traffic-manager re-established, recreating %d intercepts", len(lost))
+	for _, ic := range lost {
+		go s.recreateIntercept(ctx, ic)
+	}
+}
+
+// interceptsToRecreate returns the lost intercepts that are not found, by name, in the given snapshot. Intercepts
+// that have been removed while reconnecting are excluded. So are intercepts that have passed their expiry time,
+// because recreating them would give them a new one. Those are returned separately.
+func interceptsToRecreate(lost []*intercept, iis []*manager.InterceptInfo, now time.Time) (recreate, expired []*intercept) {
+	found := make(map[string]struct{}, len(iis))
+	for _, ii := range iis {
+		found[ii.Spec.Name] = struct{}{}
+	}
+	for _, ic := range lost {
+		if ic.ctx != nil && ic.ctx.Err() != nil {
+			// Removed by the user
+			continue
+		}
+		if _, ok := found[ic.Spec.Name]; ok {
+			continue
+		}
+		if ea := ic.ExpiresAt; ea != nil && ea.AsTime().Before(now) {
+			expired = append(expired, ic)
+			continue
+		}
+		recreate = append(recreate, ic)
+	}
+	return recreate, expired
+}
+
+// takeRecreated returns the lost intercept that the given intercept is a recreation of, and forgets it, or nil
+// if there is none. Must be called with the currentInterceptsLock held.
+func (s *session) takeRecreated(ii *manager.InterceptInfo) *intercept {
+	old, ok := s.recreating[ii.Spec.Name]
+	if !ok {
+		return nil
+	}
+	delete(s.recreating, ii.Spec.Name)
+	return old
+}
+
+// recreateIntercept asks the traffic-manager to recreate a lost intercept using its original spec, and waits for
+// it to become active. The client side mount settings and the intercept handler are retained. The mounts and
+// port-forwards of the lost intercept are kept until the recreated intercept is active.
+func (s *session) recreateIntercept(ctx context.Context, ic *intercept) {
+	spec := proto.Clone(ic.Spec).(*manager.InterceptSpec)
+	name := spec.Name
+	ctx, cancel := client.GetConfig(ctx).Timeouts().TimeoutContext(ctx, client.TimeoutIntercept)
+	defer cancel()
+
+	waitCh := make(chan interceptResult, 2)
+	s.currentInterceptsLock.Lock()
+	s.interceptWaiters[name] = &awaitIntercept{
+		mountPoint:    ic.ClientMountPoint,
+		mountPort:     ic.localMountPort,
+		mountReadOnly: ic.mountReadOnly,
+		mountPoints:   ic.mountPoints,
+		waitCh:        waitCh,
+	}
+	s.currentInterceptsLock.Unlock()
+	defer func() {
+		s.currentInterceptsLock.Lock()
+		if _, ok := s.interceptWaiters[name]; ok {
+			delete(s.interceptWaiters, name)
+			close(waitCh)
+		}
+		if s.recreating[name] == ic {
+			// The recreation failed, so the mounts and port-forwards of the lost intercept must end.
+			delete(s.recreating, name)
+			ic.cancel()
+		}
+		s.currentInterceptsLock.Unlock()
+	}()
+
+	dlog.Infof(ctx, "Recreating intercept %s", name)
+	if _, err := s.self.ManagerClient().CreateIntercept(ctx, s.self.NewCreateInterceptRequest(spec)); err != nil {
+		dlog.Errorf(ctx, "unable to recreate intercept %s: %v", name, err)
+		return
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			dlog.Errorf(ctx, "recreated intercept %s did not become active: %v", name, client.CheckTimeout(ctx, ctx.Err()))
+			return
+		case wr := <-waitCh:
+			if wr.err != nil {
+				dlog.Errorf(ctx, "recreated intercept %s failed: %v", name, wr.err)
+				return
+			}
+			if wr.intercept.Disposition != manager.InterceptDispositionType_ACTIVE {
+				continue
+			}
+			if ic.pid != 0 || ic.containerName != "" {
+				s.currentInterceptsLock.Lock()
+				wr.intercept.pid = ic.pid
+				wr.intercept.containerName = ic.containerName
+				s.currentInterceptsLock.Unlock()
+			}
+			dlog.Infof(ctx, "Intercept %s recreated", name)
+			return
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package trafficmgr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+)
+
+func Test_interceptsToRecreate(t *testing.T) {
+	newIntercept := func(name string) *intercept {
+		ic := &intercept{InterceptInfo: &manager.InterceptInfo{Spec: &manager.InterceptSpec{Name: name}}}
+		ic.ctx, ic.cancel = context.WithCancel(context.Background())
+		return ic
+	}
+	names := func(ics []*intercept) []string {
+		ns := make([]string, len(ics))
+		for i, ic := range ics {
+			ns[i] = ic.Spec.Name
+		}
+		return ns
+	}
+	a, b, c, d := newIntercept("a"), newIntercept("b"), newIntercept("c"), newIntercept("d")
+	defer a.cancel()
+	defer b.cancel()
+	defer d.cancel()
+
+	// c was removed by the user while reconnecting
+	c.cancel()
+
+	// b expires in an hour and d expired while reconnecting
+	now := time.Now()
+	b.ExpiresAt = timestamppb.New(now.Add(time.Hour))
+	d.ExpiresAt = timestamppb.New(now.Add(-time.Second))
+
+	// a is still known by the traffic-manager
+	snapshot := []*manager.InterceptInfo{{Spec: &manager.InterceptSpec{Name: "a"}}}
+	recreate, expired := interceptsToRecreate([]*intercept{a, b, c, d}, snapshot, now)
+	assert.Equal(t, []string{"b"}, names(recreate))
+	assert.Equal(t, []string{"d"}, names(expired))
+
+	recreate, expired = interceptsToRecreate([]*intercept{a, b, c, d}, nil, now)
+	assert.Equal(t, []string{"a", "b"}, names(recreate))
+	assert.Equal(t, []string{"d"}, names(expired))
+
+	recreate, expired = interceptsToRecreate(nil, snapshot, now)
+	assert.Empty(t, recreate)
+	assert.Empty(t, expired)
+}
+
+func Test_podIntercepts_retain(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	lpf := newPodIntercepts()
+	cancelled := make(map[podInterceptKey]bool)
+	add := func(id, podIP string) podInterceptKey {
+		fk := podInterceptKey{Id: id, PodIP: podIP}
+		lpf.alivePods[fk] = &podIntercept{cancelPod: func() { cancelled[fk] = true }}
+		return fk
+	}
+	lost := add("old-session:a", "10.0.0.1")
+	gone := add("old-session:b", "10.0.0.2")
+
+	// A snapshot from a new session contains neither of them, but a is being recreated.
+	lpf.initSnapshot()
+	lpf.retain(lost.Id)
+	lpf.cancelUnwanted(ctx)
+	assert.False(t, cancelled[lost])
+	assert.Contains(t, lpf.alivePods, lost)
+	assert.True(t, cancelled[gone])
+	assert.NotContains(t, lpf.alivePods, gone)
+
+	// The recreated intercept is active.
+	lpf.cancelIntercept(ctx, lost.Id)
+	assert.True(t, cancelled[lost])
+	assert.Empty(t, lpf.alivePods)
+}
+
+func Test_setCurrentIntercepts_recreating(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	newIntercept := func(name string) *intercept {
+		ic := &intercept{InterceptInfo: &manager.InterceptInfo{Id: "old-session:" + name, Spec: &manager.InterceptSpec{Name: name}}}
+		ic.ctx, ic.cancel = context.WithCancel(ctx)
+		return ic
+	}
+	a, b := newIntercept("a"), newIntercept("b")
+	s := &session{
+		currentIntercepts: map[string]*intercept{a.Id: a, b.Id: b},
+		recreating:        map[string]*intercept{"a": a},
+	}
+
+	// The first snapshot from a new session contains neither of them.
+	s.setCurrentIntercepts(ctx, nil)
+	assert.NoError(t, a.ctx.Err(), "a is being recreated, so it's kept")
+	assert.Error(t, b.ctx.Err())
+	assert.Empty(t, s.currentIntercepts)
+
+	// a becomes active in the new session.
+	s.currentInterceptsLock.Lock()
+	old := s.takeRecreated(&manager.InterceptInfo{Id: "new-session:a", Spec: &manager.InterceptSpec{Name: "a"}})
+	s.currentInterceptsLock.Unlock()
+	assert.Same(t, a, old)
+	assert.Empty(t, s.recreating)
+	assert.Nil(t, s.takeRecreated(&manager.InterceptInfo{Id: "new-session:b", Spec: &manager.InterceptSpec{Name: "b"}}))
+}
@@ -16,9 +16,11 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/blang/semver/v4"
+	"github.com/cenkalti/backoff/v4"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -62,6 +64,7 @@ import (
 	"github.com/telepresenceio/telepresence/v2/pkg/matcher"
 	"github.com/telepresenceio/telepresence/v2/pkg/proc"
 	"github.com/telepresenceio/telepresence/v2/pkg/restapi"
+	"github.com/telepresenceio/telepresence/v2/pkg/tunnel"
 )
 
 type apiServer struct {
@@ -76,9 +79,17 @@ type apiMatcher struct {
 
 type session struct {
 	*k8s.Cluster
+
+	// sessionLock protects rootDaemon and sessionInfo, which are replaced when the client arrives again
+	// after the traffic-manager has lost its session.
+	sessionLock sync.RWMutex
+
 	rootDaemon         rootdRpc.DaemonClient
 	subnetViaWorkloads []*rootdRpc.SubnetViaWorkload
 
+	// outboundInfo is the info that the root daemon was connected with.
+	outboundInfo *rootdRpc.OutboundInfo
+
 	// local information
 	installID string // telepresence's install ID
 	clientID  string // "laptop-username@laptop-hostname"
@@ -98,6 +109,13 @@ type session struct {
 	// version reported by the manager
 	managerVersion semver.Version
 
+	// agentImageLock protects agentImageFQN
+	agentImageLock sync.Mutex
+
+	// agentImageFQN is the traffic-agent image reported by the manager, or nil if it hasn't been
+	// retrieved since the session started or the cache was last invalidated.
+	agentImageFQN *manager.AgentImageFQN
+
 	// The identifier for this daemon
 	daemonID *daemon.Identifier
 
@@ -128,6 +146,25 @@ type session struct {
 
 	ingressInfo []*manager.IngressInfo
 
+	// reconnecting is true while a dropped intercept stream from the traffic-manager is being re-established.
+	reconnecting atomic.Bool
+
+	// lostIntercepts are the intercepts that were active when the intercept stream was dropped. They are
+	// recreated unless the traffic-manager still knows about them once the stream is re-established. Guarded
+	// by currentInterceptsLock.
+	lostIntercepts []*intercept
+
+	// recreating are the lost intercepts that are being recreated, keyed by name. Their mounts and
+	// port-forwards are kept until the recreated intercept is active. Guarded by currentInterceptsLock.
+	recreating map[string]*intercept
+
+	// sessionLost is signalled when the traffic-manager no longer knows the session, so that the
+	// remain loop arrives again without waiting for its next tick.
+	sessionLost chan struct{}
+
+	// cancelInterceptStream cancels the current intercept stream. Guarded by currentInterceptsLock.
+	cancelInterceptStream context.CancelFunc
+
 	isPodDaemon bool
 
 	sessionConfig client.Config
@@ -253,7 +290,8 @@ func NewSession(
 			konfig, err := patcher.CreateExternalKubeConfig(ctx, config.ClientConfig, cluster.Context, func([]string) (string, string, error) {
 				s := userd.GetService(ctx)
 				if _, ok := s.Server().GetServiceInfo()[authenticator.Authenticator_ServiceDesc.ServiceName]; !ok {
-					authGrpc.RegisterAuthenticatorServer(s.Server(), config.ClientConfig)
+					authGrpc.RegisterAuthenticatorServer(s.Server(), config.ClientConfig,
+						client.GetConfig(ctx).Timeouts().Get(client.TimeoutCredentialPlugin))
 				}
 				return client.GetExe(ctx), s.ListenerAddress(ctx), nil
 			}, nil)
@@ -264,6 +302,7 @@ func NewSession(
 		}
 	}
 
+	tmgr.outboundInfo = oi
 	tmgr.rootDaemon, err = tmgr.connectRootDaemon(ctx, oi, cr.IsPodDaemon)
 	if err != nil {
 		tmgr.managerConn.Close()
@@ -277,6 +316,8 @@ func NewSession(
 	dlog.Debug(ctx, "Finished connecting to traffic manager")
 
 	tmgr.AddNamespaceListener(ctx, tmgr.updateDaemonNamespaces)
+	tc := client.GetConfig(ctx).Tunnel()
+	ctx = tunnel.WithTuning(ctx, tunnel.Tuning{BufferSize: tc.BufferSize(), WindowSize: tc.WindowSize})
 	return ctx, tmgr, tmgr.status(ctx, true)
 }
 
@@ -303,6 +344,8 @@ func (s *session) RunSession(c context.Context) error {
 }
 
 func (s *session) RootDaemon() rootdRpc.DaemonClient {
+	s.sessionLock.RLock()
+	defer s.sessionLock.RUnlock()
 	return s.rootDaemon
 }
 
@@ -322,10 +365,48 @@ func (s *session) ManagerVersion() semver.Version {
 	return s.managerVersion
 }
 
+func (s *session) AgentImageFQN(ctx context.Context) (*manager.AgentImageFQN, error) {
+	s.agentImageLock.Lock()
+	defer s.agentImageLock.Unlock()
+	if s.agentImageFQN == nil {
+		fqn, err := s.self.ManagerClient().GetAgentImageFQN(ctx, &empty.Empty{})
+		if err != nil {
+			return nil, err
+		}
+		s.agentImageFQN = fqn
+	}
+	return s.agentImageFQN, nil
+}
+
+// invalidateAgentImage ensures that the next call to AgentImageFQN asks the traffic-manager.
+func (s *session) invalidateAgentImage() {
+	s.agentImageLock.Lock()
+	s.agentImageFQN = nil
+	s.agentImageLock.Unlock()
+}
+
 func (s *session) getSessionConfig() client.Config {
 	return s.sessionConfig
 }
 
+// retryableManagerError returns true if the given error, returned when connecting to the traffic-manager, is
+// likely to be caused by a traffic-manager that is still rolling out, so that it's meaningful to try again.
+func retryableManagerError(err error) bool {
+	if errcat.GetCategory(err) == errcat.User {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	if st, ok := status.FromError(err); ok {
+		switch st.Code() {
+		case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+			return true
+		}
+	}
+	return false
+}
+
 // connectMgr returns a session for the given cluster that is connected to the traffic-manager.
 func connectMgr(
 	ctx context.Context,
@@ -338,7 +419,7 @@ func connectMgr(
 	ctx, cancel := tos.TimeoutContext(ctx, client.TimeoutTrafficManagerConnect)
 	defer cancel()
 
-	err := CheckTrafficManagerService(ctx, cluster.GetManagerNamespace())
+	err := CheckTrafficManagerService(ctx, cluster.GetManagerNamespace(), cluster.GetManagerServiceName())
 	if err != nil {
 		return nil, err
 	}
@@ -348,9 +429,23 @@ func connectMgr(
 	if err != nil {
 		return nil, err
 	}
-	conn, mClient, vi, err := k8sclient.ConnectToManager(ctx, cluster.GetManagerNamespace(), pfDialer.Dial)
+	// A traffic-manager that was just installed or upgraded might still be rolling out, so keep trying until
+	// the traffic-manager connect timeout expires.
+	var conn *grpc.ClientConn
+	var mClient manager.ManagerClient
+	var vi *manager.VersionInfo2
+	err = client.Retry(ctx, "connect to traffic-manager", func(ctx context.Context) (err error) {
+		conn, mClient, vi, err = k8sclient.ConnectToManager(ctx, cluster.GetManagerNamespace(), cluster.GetManagerServiceName(), pfDialer.Dial)
+		if err != nil {
+			if !retryableManagerError(err) {
+				return backoff.Permanent(err)
+			}
+			dlog.Infof(ctx, "waiting for the traffic-manager in namespace %s to become ready: %v", cluster.GetManagerNamespace(), err)
+		}
+		return err
+	}, time.Second, 5*time.Second)
 	if err != nil {
-		return nil, err
+		return nil, client.CheckTimeout(ctx, err)
 	}
 	managerVersion, err := semver.Parse(strings.TrimPrefix(vi.Version, "v"))
 	if err != nil {
@@ -440,9 +535,16 @@ func connectMgr(
 		return nil, fmt.Errorf("failed to parse extra allow conflicting subnets: %w", err)
 	}
 
-	cluster.AlsoProxy = append(cluster.AlsoProxy, extraAlsoProxy...)
-	cluster.NeverProxy = append(cluster.NeverProxy, extraNeverProxy...)
-	cluster.AllowConflictingSubnets = append(cluster.AllowConflictingSubnets, extraAllow...)
+	// Subnets given on the command line override the ones declared in the kubeconfig extension
+	if len(extraAlsoProxy) > 0 {
+		cluster.AlsoProxy = extraAlsoProxy
+	}
+	if len(extraNeverProxy) > 0 {
+		cluster.NeverProxy = extraNeverProxy
+	}
+	if len(extraAllow) > 0 {
+		cluster.AllowConflictingSubnets = extraAllow
+	}
 
 	knownWorkloadKinds, err := mClient.GetKnownWorkloadKinds(ctx, si)
 	if err != nil {
@@ -469,6 +571,8 @@ func connectMgr(
 		managerVersion:     managerVersion,
 		sessionInfo:        si,
 		interceptWaiters:   make(map[string]*awaitIntercept),
+		recreating:         make(map[string]*intercept),
+		sessionLost:        make(chan struct{}, 1),
 		wlWatcher:          newWASWatcher(knownWorkloadKinds),
 		isPodDaemon:        cr.IsPodDaemon,
 		done:               make(chan struct{}),
@@ -488,9 +592,12 @@ func (s *session) Remain(ctx context.Context) error {
 	defer cancel()
 	_, err := self.ManagerClient().Remain(ctx, self.NewRemainRequest())
 	if err != nil {
-		if status.Code(err) == codes.NotFound || status.Code(err) == codes.Unavailable {
-			// The session has expired. We need to cancel the owner session and reconnect.
+		switch status.Code(err) {
+		case codes.NotFound:
+			// The session has expired. We need to arrive again using a new session.
 			return ErrSessionExpired
+		case codes.Unavailable, codes.DeadlineExceeded:
+			return fmt.Errorf("%w: %v", ErrManagerUnreachable, client.CheckTimeout(ctx, err))
 		}
 		dlog.Errorf(ctx, "error calling Remain: %v", client.CheckTimeout(ctx, err))
 	}
@@ -515,11 +622,11 @@ func parseCIDR(cidr []string) ([]*iputil.Subnet, error) {
 	return result, nil
 }
 
-func CheckTrafficManagerService(ctx context.Context, namespace string) error {
-	dlog.Debug(ctx, "checking that traffic-manager exists")
+func CheckTrafficManagerService(ctx context.Context, namespace, serviceName string) error {
+	dlog.Debugf(ctx, "checking that service %s exists", serviceName)
 	coreV1 := k8sapi.GetK8sInterface(ctx).CoreV1()
-	if _, err := coreV1.Services(namespace).Get(ctx, "traffic-manager", meta.GetOptions{}); err != nil {
-		msg := fmt.Sprintf("unable to get service traffic-manager in %s: %v", namespace, err)
+	if _, err := coreV1.Services(namespace).Get(ctx, serviceName, meta.GetOptions{}); err != nil {
+		msg := fmt.Sprintf("unable to get service %s in %s: %v", serviceName, namespace, err)
 		se := &k8serrors.StatusError{}
 		if errors.As(err, &se) {
 			if se.Status().Code == http.StatusNotFound {
@@ -563,14 +670,16 @@ func (s *session) updateDaemonNamespaces(c context.Context) {
 	}
 	dlog.Debugf(c, "posting top-level domains %v to root daemon", domains)
 
-	if _, err := s.rootDaemon.SetDNSTopLevelDomains(c, &rootdRpc.Domains{Domains: domains}); err != nil {
+	// Names in the svc domain of other namespaces are only resolved when no namespaces are mapped.
+	namespacesOnly := len(s.MappedNamespaces) > 0
+	if _, err := s.RootDaemon().SetDNSTopLevelDomains(c, &rootdRpc.Domains{Domains: domains, NamespacesOnly: namespacesOnly}); err != nil {
 		dlog.Errorf(c, "error posting domains %v to root daemon: %v", domains, err)
 	}
 	dlog.Debug(c, "domains posted successfully")
 }
 
 func (s *session) Epilog(ctx context.Context) {
-	_, _ = s.rootDaemon.Disconnect(ctx, &empty.Empty{})
+	_, _ = s.RootDaemon().Disconnect(ctx, &empty.Empty{})
 	_ = s.pfDialer.Close()
 	dlog.Info(ctx, "-- Session ended")
 	close(s.done)
@@ -602,6 +711,8 @@ func (s *session) Done() <-chan struct{} {
 }
 
 func (s *session) SessionInfo() *manager.SessionInfo {
+	s.sessionLock.RLock()
+	defer s.sessionLock.RUnlock()
 	return s.sessionInfo
 }
 
@@ -614,6 +725,7 @@ func (s *session) ApplyConfig(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	s.invalidateAgentImage()
 	if len(s.MappedNamespaces) == 0 {
 		mns := client.GetConfig(ctx).Cluster().MappedNamespaces
 		if len(mns) > 0 {
@@ -659,10 +771,11 @@ func (s *session) getInfosForWorkloads(
 		}
 
 		var ok bool
-		if wlInfo.InterceptInfos, ok = iMap[name]; !ok && filter <= rpc.ListRequest_INTERCEPTS {
+		key := name + "." + wlInfo.Namespace
+		if wlInfo.InterceptInfos, ok = iMap[key]; !ok && filter <= rpc.ListRequest_INTERCEPTS {
 			return
 		}
-		if wlInfo.Sidecar, ok = sMap[name]; !ok && filter <= rpc.ListRequest_INSTALLED_AGENTS {
+		if wlInfo.Sidecar, ok = sMap[key]; !ok && filter <= rpc.ListRequest_INSTALLED_AGENTS {
 			return
 		}
 		wiMap[workload.GetUID()] = wlInfo
@@ -673,7 +786,12 @@ func (s *session) getInfosForWorkloads(
 		wiz[i] = wi
 		i++
 	}
-	sort.Slice(wiz, func(i, j int) bool { return wiz[i].Name < wiz[j].Name })
+	sort.Slice(wiz, func(i, j int) bool {
+		if wiz[i].Namespace != wiz[j].Namespace {
+			return wiz[i].Namespace < wiz[j].Namespace
+		}
+		return wiz[i].Name < wiz[j].Name
+	})
 	return wiz
 }
 
@@ -695,7 +813,8 @@ func (s *session) waitForSync(ctx context.Context) {
 
 func (s *session) WatchWorkloads(c context.Context, wr *rpc.WatchWorkloadsRequest, stream userd.WatchWorkloadsStream) error {
 	s.waitForSync(c)
-	s.ensureWatchers(c, wr.Namespaces)
+	nss, _ := expandNamespaces(wr.Namespaces, s.GetCurrentNamespaces)
+	s.ensureWatchers(c, nss)
 	sCtx, sCancel := context.WithCancel(c)
 	// We need to make sure the subscription ends when we leave this method, since this is the one consuming the snapshotAvailable channel.
 	// Otherwise, the goroutine that writes to the channel will leak.
@@ -753,12 +872,28 @@ func (s *session) ensureWatchers(ctx context.Context,
 	wg.Wait()
 }
 
+// expandNamespaces replaces a request for all namespaces with the mapped namespaces that the client can
+// access. The mapped namespaces that it cannot access are returned separately.
+func expandNamespaces(namespaces []string, mapped func(forClientAccess bool) []string) (accessible, inaccessible []string) {
+	if !slices.Contains(namespaces, client.AllNamespaces) {
+		return namespaces, nil
+	}
+	accessible = mapped(true)
+	for _, ns := range mapped(false) {
+		if !slices.Contains(accessible, ns) {
+			inaccessible = append(inaccessible, ns)
+		}
+	}
+	return accessible, inaccessible
+}
+
 func (s *session) workloadInfoSnapshot(
 	ctx context.Context,
 	namespaces []string,
 	filter rpc.ListRequest_Filter,
 ) (*rpc.WorkloadInfoSnapshot, error) {
 	is := s.getCurrentIntercepts()
+	namespaces, inaccessible := expandNamespaces(namespaces, s.GetCurrentNamespaces)
 	s.ensureWatchers(ctx, namespaces)
 
 	var nss []string
@@ -776,15 +911,17 @@ func (s *session) workloadInfoSnapshot(
 	}
 	if len(nss) == 0 {
 		// none of the namespaces are currently mapped
-		return &rpc.WorkloadInfoSnapshot{}, nil
+		return &rpc.WorkloadInfoSnapshot{InaccessibleNamespaces: inaccessible}, nil
 	}
 
+	// The maps are keyed by <name>.<namespace> because workloads in different namespaces may share a name.
 	iMap := make(map[string][]*manager.InterceptInfo, len(is))
 nextIs:
 	for _, i := range is {
 		for _, ns := range nss {
 			if i.Spec.Namespace == ns {
-				iMap[i.Spec.Agent] = append(iMap[i.Spec.Agent], i.InterceptInfo)
+				k := i.Spec.Agent + "." + ns
+				iMap[k] = append(iMap[k], i.InterceptInfo)
 				continue nextIs
 			}
 		}
@@ -797,16 +934,19 @@ nextIs:
 			if err != nil {
 				continue
 			}
-			sMap[k] = &rpc.WorkloadInfo_Sidecar{Json: data}
+			sMap[k+"."+ns] = &rpc.WorkloadInfo_Sidecar{Json: data}
 		}
 	}
 
 	workloadInfos := s.getInfosForWorkloads(ctx, nss, iMap, sMap, filter)
-	return &rpc.WorkloadInfoSnapshot{Workloads: workloadInfos}, nil
+	return &rpc.WorkloadInfoSnapshot{Workloads: workloadInfos, InaccessibleNamespaces: inaccessible}, nil
 }
 
 var ErrSessionExpired = errors.New("session expired")
 
+// ErrManagerUnreachable is returned by Remain when the traffic-manager doesn't respond.
+var ErrManagerUnreachable = errors.New("traffic-manager unreachable")
+
 func (s *session) remainLoop(c context.Context) error {
 	ticker := time.NewTicker(5 * time.Second)
 	defer func() {
@@ -829,11 +969,27 @@ func (s *session) remainLoop(c context.Context) error {
 		select {
 		case <-c.Done():
 			return nil
+		case <-s.sessionLost:
 		case <-ticker.C:
-			if err := s.self.Remain(c); err != nil {
+			err := s.self.Remain(c)
+			switch {
+			case err == nil:
+				continue
+			case errors.Is(err, ErrManagerUnreachable):
+				// The Remain calls double as a heartbeat for the intercept stream, which might otherwise
+				// hang on a connection that is silently gone.
+				dlog.Warn(c, err)
+				s.restartInterceptStream(c)
+				continue
+			case !errors.Is(err, ErrSessionExpired):
 				return err
 			}
 		}
+		if err := s.arriveAgain(c); err != nil {
+			dlog.Errorf(c, "unable to arrive again at the traffic-manager: %v", err)
+			// Let the daemon start a new session.
+			return ErrSessionExpired
+		}
 	}
 }
 
@@ -912,6 +1068,7 @@ func (s *session) status(c context.Context, initial bool) *rpc.ConnectInfo {
 		},
 		ManagerNamespace:   cfg.GetManagerNamespace(),
 		SubnetViaWorkloads: s.subnetViaWorkloads,
+		Reconnecting:       s.reconnecting.Load(),
 		Version: &common.VersionInfo{
 			ApiVersion: client.APIVersion,
 			Version:    client.Version(),
@@ -925,8 +1082,10 @@ func (s *session) status(c context.Context, initial bool) *rpc.ConnectInfo {
 	if len(s.MappedNamespaces) > 0 || len(s.sessionConfig.Cluster().MappedNamespaces) > 0 {
 		ret.MappedNamespaces = s.GetCurrentNamespaces(true)
 	}
+	ret.InaccessibleNamespaces = s.GetInaccessibleNamespaces()
+	ret.NamespacesRestricted = s.NamespacesRestricted()
 	var err error
-	ret.DaemonStatus, err = s.rootDaemon.Status(c, &empty.Empty{})
+	ret.DaemonStatus, err = s.RootDaemon().Status(c, &empty.Empty{})
 	if err != nil {
 		return connectError(rpc.ConnectInfo_DAEMON_FAILED, err)
 	}
@@ -972,10 +1131,15 @@ func (s *session) Uninstall(ctx context.Context, ur *rpc.UninstallRequest) (*com
 			return errcat.ToResult(errcat.User.Newf("namespace %s is not mapped", ur.Namespace)), nil
 		}
 		cm, err := loadAgentConfigMap(namespace)
-		if err != nil || cm == nil {
+		if err != nil {
 			return errcat.ToResult(err), nil
 		}
+		if cm == nil {
+			// No agents exist in the namespace, but the names must still be checked for unsupported kinds.
+			cm = &core.ConfigMap{}
+		}
 		changed := false
+		var unsupported []error
 		ics := s.getCurrentIntercepts()
 		for _, an := range ur.Agents {
 			for _, ic := range ics {
@@ -987,10 +1151,17 @@ func (s *session) Uninstall(ctx context.Context, ur *rpc.UninstallRequest) (*com
 			if _, ok := cm.Data[an]; ok {
 				delete(cm.Data, an)
 				changed = true
+			} else if err := unsupportedAgentWorkload(ctx, an, namespace); err != nil {
+				unsupported = append(unsupported, err)
 			}
 		}
 		if changed {
-			return errcat.ToResult(updateAgentConfigMap(namespace, cm)), nil
+			if err = updateAgentConfigMap(namespace, cm); err != nil {
+				return errcat.ToResult(err), nil
+			}
+		}
+		if len(unsupported) > 0 {
+			return errcat.ToResult(errcat.User.New(errors.Join(unsupported...))), nil
 		}
 		return errcat.ToResult(nil), nil
 	}
@@ -1077,14 +1248,17 @@ func (s *session) getOutboundInfo(ctx context.Context, cr *rpc.ConnectRequest) *
 		neverProxy = append(neverProxy, iputil.IPNetToRPC((*net.IPNet)(np)))
 	}
 	info := &rootdRpc.OutboundInfo{
-		Session:            s.sessionInfo,
+		Session:            s.SessionInfo(),
 		NeverProxySubnets:  neverProxy,
 		HomeDir:            homedir.HomeDir(),
 		Namespace:          s.Namespace,
 		ManagerNamespace:   s.GetManagerNamespace(),
+		ManagerServiceName: s.GetManagerServiceName(),
 		SubnetViaWorkloads: s.subnetViaWorkloads,
 		KubeFlags:          cr.KubeFlags,
 		KubeconfigData:     cr.KubeconfigData,
+		DnsOnly:            cr.DnsOnly,
+		RouteOnly:          cr.RouteOnly,
 	}
 
 	if s.DNS != nil {
@@ -1094,6 +1268,8 @@ func (s *session) getOutboundInfo(ctx context.Context, cr *rpc.ConnectRequest) *
 			Excludes:        s.DNS.Excludes,
 			Mappings:        s.DNS.Mappings.ToRPC(),
 			LookupTimeout:   durationpb.New(s.DNS.LookupTimeout.Duration),
+			CacheTtl:        durationpb.New(s.DNS.CacheTTL.Duration),
+			NegativeTtl:     durationpb.New(s.DNS.NegativeTTL.Duration),
 		}
 		if len(s.DNS.LocalIP) > 0 {
 			info.Dns.LocalIp = s.DNS.LocalIP.IP()
@@ -1102,6 +1278,12 @@ func (s *session) getOutboundInfo(ctx context.Context, cr *rpc.ConnectRequest) *
 			info.Dns.RemoteIp = s.DNS.RemoteIP.IP()
 		}
 	}
+	if cr.DnsResolverAddress != "" {
+		if info.Dns == nil {
+			info.Dns = &rootdRpc.DNSConfig{}
+		}
+		info.Dns.ListenAddress = cr.DnsResolverAddress
+	}
 
 	if len(s.AlsoProxy) > 0 {
 		info.AlsoProxySubnets = make([]*manager.IPNet, len(s.AlsoProxy))
@@ -1146,47 +1328,61 @@ func (s *session) connectRootDaemon(ctx context.Context, oi *rootdRpc.OutboundIn
 			}
 		}()
 		rd = rootdRpc.NewDaemonClient(conn)
+		if err = connectRootSession(ctx, rd, oi); err != nil {
+			return nil, err
+		}
+	}
+	if err = waitForRootNetwork(ctx, rd); err != nil {
+		return nil, err
+	}
+	return rd, nil
+}
 
-		for attempt := 1; ; attempt++ {
-			var rootStatus *rootdRpc.DaemonStatus
-			tCtx, tCancel := context.WithTimeout(ctx, 15*time.Second)
-			rootStatus, err = rd.Connect(tCtx, oi)
-			tCancel()
-			if err != nil {
-				return nil, fmt.Errorf("failed to connect to root daemon: %w", err)
-			}
-			oc := rootStatus.OutboundConfig
-			if oc == nil || oc.Session == nil {
-				// This is an internal error. Something is wrong with the root daemon.
-				return nil, errors.New("root daemon's OutboundConfig has no Session")
-			}
-			if oc.Session.SessionId == oi.Session.SessionId {
-				break
-			}
+// connectRootSession makes the root daemon run a session for the session in the given OutboundInfo. A root
+// daemon session that was started for another session is ended first.
+func connectRootSession(ctx context.Context, rd rootdRpc.DaemonClient, oi *rootdRpc.OutboundInfo) error {
+	for attempt := 1; ; attempt++ {
+		tCtx, tCancel := context.WithTimeout(ctx, 15*time.Second)
+		rootStatus, err := rd.Connect(tCtx, oi)
+		tCancel()
+		if err != nil {
+			return fmt.Errorf("failed to connect to root daemon: %w", err)
+		}
+		oc := rootStatus.OutboundConfig
+		if oc == nil || oc.Session == nil {
+			// This is an internal error. Something is wrong with the root daemon.
+			return errors.New("root daemon's OutboundConfig has no Session")
+		}
+		if oc.Session.SessionId == oi.Session.SessionId {
+			return nil
+		}
 
-			// Root daemon was running an old session. This indicates that this daemon somehow
-			// crashed without disconnecting. So let's do that now, and then reconnect...
-			if attempt == 2 {
-				// ...or not, since we've already done it.
-				return nil, errors.New("unable to reconnect to root daemon")
-			}
-			if _, err = rd.Disconnect(ctx, &empty.Empty{}); err != nil {
-				return nil, fmt.Errorf("failed to disconnect from the root daemon: %w", err)
-			}
+		// Root daemon was running an old session. This indicates that this daemon somehow
+		// crashed without disconnecting, or that it arrived again using a new session. So
+		// let's disconnect now, and then reconnect...
+		if attempt == 2 {
+			// ...or not, since we've already done it.
+			return errors.New("unable to reconnect to root daemon")
+		}
+		if _, err = rd.Disconnect(ctx, &empty.Empty{}); err != nil {
+			return fmt.Errorf("failed to disconnect from the root daemon: %w", err)
 		}
 	}
+}
 
+// waitForRootNetwork waits for the root daemon to set up its network.
+func waitForRootNetwork(ctx context.Context, rd rootdRpc.DaemonClient) error {
 	// The root daemon needs time to set up the TUN-device and DNS, which involves interacting
 	// with the cluster-side traffic-manager. We know that the traffic-manager is up and
 	// responding at this point, so it shouldn't take too long.
 	ctx, cancel := client.GetConfig(ctx).Timeouts().TimeoutContext(ctx, client.TimeoutTrafficManagerAPI)
 	defer cancel()
-	if _, err = rd.WaitForNetwork(ctx, &empty.Empty{}); err != nil {
+	if _, err := rd.WaitForNetwork(ctx, &empty.Empty{}); err != nil {
 		if se, ok := status.FromError(err); ok {
 			err = se.Err()
 		}
-		return nil, fmt.Errorf("failed to connect to root daemon: %v", err)
+		return fmt.Errorf("failed to connect to root daemon: %v", err)
 	}
 	dlog.Debug(ctx, "Connected to root daemon")
-	return rd, nil
+	return nil
 }
@@ -0,0 +1,79 @@
+package trafficmgr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/client"
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+)
+
+func Test_expandNamespaces(t *testing.T) {
+	mapped := func(forClientAccess bool) []string {
+		if forClientAccess {
+			return []string{"default", "team-a"}
+		}
+		return []string{"default", "kube-system", "team-a"}
+	}
+	tests := []struct {
+		name             string
+		namespaces       []string
+		wantAccessible   []string
+		wantInaccessible []string
+	}{
+		{
+			"all namespaces",
+			[]string{client.AllNamespaces},
+			[]string{"default", "team-a"},
+			[]string{"kube-system"},
+		},
+		{
+			"explicit list",
+			[]string{"team-a", "kube-system"},
+			[]string{"team-a", "kube-system"},
+			nil,
+		},
+		{
+			"empty list",
+			nil,
+			nil,
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accessible, inaccessible := expandNamespaces(tt.namespaces, mapped)
+			assert.Equal(t, tt.wantAccessible, accessible)
+			assert.Equal(t, tt.wantInaccessible, inaccessible)
+		})
+	}
+}
+
+func Test_retryableManagerError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"unavailable", status.Error(codes.Unavailable, "connection refused"), true},
+		{"wrapped unavailable", fmt.Errorf("dial manager: %w", status.Error(codes.Unavailable, "no pods")), true},
+		{"deadline exceeded", status.Error(codes.DeadlineExceeded, "timeout"), true},
+		{"resource exhausted", status.Error(codes.ResourceExhausted, "too many streams"), true},
+		{"context deadline", fmt.Errorf("dial manager: %w", context.DeadlineExceeded), true},
+		{"permission denied", status.Error(codes.PermissionDenied, "forbidden"), false},
+		{"unimplemented", status.Error(codes.Unimplemented, "unknown method"), false},
+		{"user error", errcat.User.New(status.Error(codes.Unavailable, "bad config")), false},
+		{"plain error", errors.New("boom"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, retryableManagerError(tt.err))
+		})
+	}
+}
@@ -127,7 +127,7 @@ func (c *traceCollector) rootdTraces(ctx context.Context, tCh chan<- []byte) err
 
 func (c *traceCollector) trafficManagerTraces(ctx context.Context, sess *session, tCh chan<- []byte, remotePort string) error {
 	span := trace.SpanFromContext(ctx)
-	host := "svc/traffic-manager." + sess.GetManagerNamespace()
+	host := "svc/" + sess.GetManagerServiceName() + "." + sess.GetManagerNamespace()
 	grpcAddr := dnet.K8sPFScheme + ":///" + net.JoinHostPort(host, remotePort)
 	span.SetAttributes(attribute.String("traffic-manager.host", host), attribute.String("traffic-manager.port", remotePort))
 
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -13,9 +14,12 @@ import (
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/net/nettest"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
 
 	"github.com/datawire/dlib/dcontext"
 	"github.com/datawire/dlib/dexec"
@@ -174,3 +178,64 @@ func TestKubectlPortForward(t *testing.T) {
 	// t.Run("Client", func(t *testing.T) { nettest.TestConn(t, makePipe) })
 	t.Run("Server", func(t *testing.T) { nettest.TestConn(t, flipMakePipe(makePipe)) })
 }
+
+// TestK8sPortForwardDialer_proxyURL verifies that the port-forward dialer, which is used when dialing the
+// traffic-manager, honors the proxy-url of the cluster in the kubeconfig.
+func TestK8sPortForwardDialer_proxyURL(t *testing.T) {
+	ctx, cancel := context.WithTimeout(dlog.NewTestContext(t, false), 10*time.Second)
+	defer cancel()
+
+	connects := make(chan string, 10)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodConnect {
+			connects <- r.Host
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer proxy.Close()
+
+	kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: air-gapped
+  cluster:
+    server: https://kubernetes.invalid:6443
+    insecure-skip-tls-verify: true
+    proxy-url: %s
+contexts:
+- name: air-gapped
+  context:
+    cluster: air-gapped
+    user: air-gapped
+current-context: air-gapped
+users:
+- name: air-gapped
+  user:
+    token: not-used
+`, proxy.URL)
+
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig([]byte(kubeconfig))
+	require.NoError(t, err)
+	ki, err := kubernetes.NewForConfig(restConfig)
+	require.NoError(t, err)
+	pf, err := dnet.NewK8sPortForwardDialer(ctx, restConfig, ki)
+	require.NoError(t, err)
+	defer pf.Close()
+
+	dialCtx, dialCancel := context.WithCancel(ctx)
+	defer dialCancel()
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := pf.DialPod(dialCtx, "traffic-manager", "ambassador", 8081)
+		errCh <- err
+	}()
+
+	select {
+	case host := <-connects:
+		assert.Equal(t, "kubernetes.invalid:6443", host)
+	case <-ctx.Done():
+		t.Fatal("dial did not go through the proxy")
+	}
+	dialCancel()
+	assert.Error(t, <-errCh)
+}
@@ -0,0 +1,45 @@
+package dnsproxy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/errcat"
+)
+
+// ProbeName is a name that the client's DNS server always resolves to localhost. Clients use it to verify
+// that lookups made using the system's resolver reach the DNS server.
+const ProbeName = "jhfweoitnkgyeta.tel2-search"
+
+// CheckListenAddress checks that the given address is a valid IP and port, and that it is possible to listen to it.
+func CheckListenAddress(addr string) error {
+	if _, err := netip.ParseAddrPort(addr); err != nil {
+		return errcat.User.Newf("invalid DNS resolver address: %v", err)
+	}
+	pc, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return errcat.User.Newf("unable to use DNS resolver address %s: %v", addr, err)
+	}
+	return pc.Close()
+}
+
+// Probe sends a query for the ProbeName to the DNS server that listens to the given address and returns an
+// error unless the server answers it.
+func Probe(ctx context.Context, addr string, timeout time.Duration) error {
+	q := new(dns.Msg)
+	q.SetQuestion(ProbeName+".", dns.TypeA)
+	dc := &dns.Client{Net: "udp", Timeout: timeout}
+	r, _, err := dc.ExchangeContext(ctx, q, addr)
+	if err != nil {
+		return err
+	}
+	if r.Rcode != dns.RcodeSuccess || len(r.Answer) == 0 {
+		return fmt.Errorf("unexpected reply %s", dns.RcodeToString[r.Rcode])
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package dnsproxy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckListenAddress(t *testing.T) {
+	assert.NoError(t, CheckListenAddress("127.0.0.1:0"))
+	assert.Error(t, CheckListenAddress("127.0.0.1"))
+	assert.Error(t, CheckListenAddress("localhost:53"))
+}
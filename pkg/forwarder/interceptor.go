@@ -6,6 +6,7 @@ import (
 	"io"
 	"net"
 	"sync"
+	"time"
 
 	"github.com/datawire/dlib/dlog"
 	"github.com/telepresenceio/telepresence/rpc/v2/manager"
@@ -37,6 +38,11 @@ type interceptor struct {
 	targetPort     uint16
 	streamProvider tunnel.ClientStreamProvider
 
+	// canDrain is true when the connections of a removed intercept can continue while new
+	// connections are routed to the new target. It's false when all traffic shares one
+	// connection, as is the case with UDP.
+	canDrain bool
+
 	intercept *manager.InterceptInfo
 }
 
@@ -115,8 +121,17 @@ func (f *interceptor) SetIntercepting(intercept *manager.InterceptInfo) {
 		}
 	}
 
-	// Drop existing connections
-	f.tCancel()
+	// Drop existing connections, or let them drain when the old intercept asks for it.
+	var drain time.Duration
+	if f.canDrain && f.intercept != nil {
+		drain = time.Duration(f.intercept.Spec.DrainTimeout)
+	}
+	if drain > 0 {
+		dlog.Debugf(f.lCtx, "Draining connections of intercept %s for %s", iceptInfo(f.intercept), drain)
+		time.AfterFunc(drain, f.tCancel)
+	} else {
+		f.tCancel()
+	}
 
 	// Set up new target and lifetime
 	f.tCtx, f.tCancel = context.WithCancel(f.lCtx)
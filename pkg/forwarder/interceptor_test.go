@@ -0,0 +1,85 @@
+package forwarder
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+)
+
+func testIntercept(id string, drain time.Duration) *manager.InterceptInfo {
+	return &manager.InterceptInfo{
+		Id: id,
+		Spec: &manager.InterceptSpec{
+			Name:         id,
+			Client:       "user@host",
+			TargetPort:   8080,
+			DrainTimeout: int64(drain),
+		},
+	}
+}
+
+func targetContext(f *interceptor) context.Context {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.tCtx
+}
+
+func TestInterceptor_SetIntercepting(t *testing.T) {
+	ctx := dlog.NewTestContext(t, false)
+	newForwarder := func(t *testing.T, addr net.Addr) *interceptor {
+		f := NewInterceptor(addr, "appHost", 5000)
+		ctx, cancel := context.WithCancel(ctx)
+		initCh := make(chan net.Addr)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_ = f.Serve(ctx, initCh)
+		}()
+		<-initCh
+		t.Cleanup(func() {
+			cancel()
+			<-done
+		})
+		switch f := f.(type) {
+		case *tcp:
+			return &f.interceptor
+		case *udp:
+			return &f.interceptor
+		}
+		t.Fatalf("unexpected type %T", f)
+		return nil
+	}
+
+	t.Run("drain", func(t *testing.T) {
+		f := newForwarder(t, &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		f.SetIntercepting(testIntercept("a", 200*time.Millisecond))
+		iCtx := targetContext(f)
+		f.SetIntercepting(nil)
+		assert.NoError(t, targetContext(f).Err())
+		require.NoError(t, iCtx.Err(), "intercepted connections must survive the removal")
+		assert.Eventually(t, func() bool { return iCtx.Err() != nil }, 2*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("no drain", func(t *testing.T) {
+		f := newForwarder(t, &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		f.SetIntercepting(testIntercept("a", 0))
+		iCtx := targetContext(f)
+		f.SetIntercepting(nil)
+		assert.Error(t, iCtx.Err())
+	})
+
+	t.Run("udp never drains", func(t *testing.T) {
+		f := newForwarder(t, &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		f.SetIntercepting(testIntercept("a", time.Minute))
+		iCtx := targetContext(f)
+		f.SetIntercepting(nil)
+		assert.Error(t, iCtx.Err())
+	})
+}
@@ -2,6 +2,7 @@ package forwarder
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"net"
@@ -28,6 +29,7 @@ func newTCP(listen net.Addr, targetHost string, targetPort uint16) Interceptor {
 			listenAddr: listen,
 			targetHost: targetHost,
 			targetPort: targetPort,
+			canDrain:   true,
 		},
 	}
 }
@@ -169,6 +171,12 @@ func (f *tcp) interceptConn(ctx context.Context, conn net.Conn, iCept *manager.I
 	}
 
 	spec := iCept.Spec
+	var tlsConfig *tls.Config
+	if spec.TargetTls {
+		if tlsConfig, err = targetTLSConfig(spec); err != nil {
+			return err
+		}
+	}
 	destIp := iputil.Parse(spec.TargetHost)
 	clientSession := iCept.ClientSession.SessionId
 	id := tunnel.NewConnID(ipproto.Parse(addr.Network()), srcIp, destIp, srcPort, uint16(spec.TargetPort))
@@ -186,9 +194,19 @@ func (f *tcp) interceptConn(ctx context.Context, conn net.Conn, iCept *manager.I
 	ingressBytes := tunnel.NewCounterProbe("FromClientBytes")
 	egressBytes := tunnel.NewCounterProbe("ToClientBytes")
 
+	if tlsConfig != nil {
+		conn = originateTLS(ctx, conn, tlsConfig, spec.TargetHost)
+	}
+
 	// Ingress and egress swap places here, because this endpoint reflects a connection
 	// where the stream is attached to a connection *to* the client, not *from* the client.
-	d := tunnel.NewConnEndpoint(s, conn, cancel, egressBytes, ingressBytes)
+	// The shaping is from the endpoint's point of view, so its egress is the traffic that
+	// is forwarded to the client.
+	d := tunnel.NewShapedConnEndpoint(s, conn, cancel, tunnel.Shaping{
+		IngressThrottle: tunnel.NewThrottle(spec.ThrottleRate),
+		EgressThrottle:  tunnel.NewThrottle(spec.ThrottleRate),
+		EgressDelay:     time.Duration(spec.Delay),
+	}, egressBytes, ingressBytes)
 	d.Start(ctx)
 	<-d.Done()
 
@@ -196,6 +214,7 @@ func (f *tcp) interceptConn(ctx context.Context, conn net.Conn, iCept *manager.I
 		ClientSessionId: clientSession,
 		IngressBytes:    ingressBytes.GetValue(),
 		EgressBytes:     egressBytes.GetValue(),
+		InterceptId:     iCept.Id,
 	})
 	return nil
 }
@@ -0,0 +1,70 @@
+package forwarder
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"net/netip"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+)
+
+// originateTLS returns a connection that carries the TLS encrypted form of the traffic read from and written
+// to the given plaintext connection. The TLS handshake is performed with whatever peer is connected to the
+// returned connection, which is the intercept target on the workstation. Both connections are closed when
+// either side ends.
+func originateTLS(ctx context.Context, conn net.Conn, config *tls.Config, targetHost string) net.Conn {
+	tlsSide, peerSide := net.Pipe()
+	tc := tls.Client(tlsSide, config)
+	go func() {
+		defer conn.Close()
+		defer tc.Close()
+		if err := tc.HandshakeContext(ctx); err != nil {
+			dlog.Errorf(ctx, "TLS handshake with intercept target %s failed: %v", targetHost, err)
+			return
+		}
+		done := make(chan struct{}, 2)
+		cp := func(dst io.Writer, src io.Reader) {
+			_, _ = io.Copy(dst, src)
+			done <- struct{}{}
+		}
+		go cp(tc, conn)
+		go cp(conn, tc)
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+	}()
+	return peerSide
+}
+
+// targetTLSConfig returns the client configuration used when originating TLS to the intercept target. Unless
+// --target-tls-insecure is used, the certificate is verified using the certificate authorities that were read
+// from the workstation using --target-tls-ca, or when there are none, the ones that the traffic-agent trusts.
+func targetTLSConfig(spec *manager.InterceptSpec) (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName:         tlsServerName(spec.TargetHost),
+		InsecureSkipVerify: spec.TargetTlsInsecure, //nolint:gosec // explicitly requested using --target-tls-insecure
+	}
+	if spec.TargetTlsCa != "" && !spec.TargetTlsInsecure {
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM([]byte(spec.TargetTlsCa)) {
+			return nil, errors.New("the --target-tls-ca of the intercept contains no valid certificates")
+		}
+	}
+	return config, nil
+}
+
+// tlsServerName returns the name used for SNI and for verification of the target's certificate. Certificates
+// for local development servers are normally issued for "localhost", so that name is used when the target host
+// is a loopback address.
+func tlsServerName(targetHost string) string {
+	if ip, err := netip.ParseAddr(targetHost); err == nil && ip.IsLoopback() {
+		return "localhost"
+	}
+	return targetHost
+}
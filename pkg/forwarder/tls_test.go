@@ -0,0 +1,192 @@
+package forwarder
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+)
+
+// localhostCert returns a self-signed certificate for "localhost", like the ones that local development servers
+// typically use.
+func localhostCert(t *testing.T) (tls.Certificate, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, cert
+}
+
+// tlsTarget is an HTTPS server that records the server names that its clients send using SNI.
+type tlsTarget struct {
+	*httptest.Server
+	cert        *x509.Certificate
+	mu          sync.Mutex
+	serverNames []string
+}
+
+func startTLSTarget(t *testing.T) *tlsTarget {
+	tlsCert, cert := localhostCert(t)
+	tt := &tlsTarget{cert: cert}
+	tt.Server = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "hello")
+	}))
+	tt.TLS = &tls.Config{
+		Certificates: []tls.Certificate{tlsCert},
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			tt.mu.Lock()
+			tt.serverNames = append(tt.serverNames, hello.ServerName)
+			tt.mu.Unlock()
+			return nil, nil
+		},
+	}
+	tt.StartTLS()
+	t.Cleanup(tt.Close)
+	return tt
+}
+
+// get sends a plaintext HTTP request through originateTLS to the target and returns the response body.
+func (tt *tlsTarget) get(t *testing.T, config *tls.Config) (string, error) {
+	ctx := dlog.NewTestContext(t, false)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	targetConn, err := net.Dial("tcp", tt.Listener.Addr().String())
+	require.NoError(t, err)
+	defer targetConn.Close()
+
+	clientSide, agentSide := net.Pipe()
+	defer clientSide.Close()
+	peer := originateTLS(ctx, agentSide, config, "127.0.0.1")
+	go func() {
+		_, _ = io.Copy(targetConn, peer)
+		_ = targetConn.Close()
+	}()
+	go func() {
+		_, _ = io.Copy(peer, targetConn)
+		_ = peer.Close()
+	}()
+
+	req, err := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+	require.NoError(t, err)
+	require.NoError(t, clientSide.SetDeadline(time.Now().Add(5*time.Second)))
+	go func() {
+		_ = req.Write(clientSide)
+	}()
+	rsp, err := http.ReadResponse(bufio.NewReader(clientSide), req)
+	if err != nil {
+		return "", err
+	}
+	defer rsp.Body.Close()
+	body, err := io.ReadAll(rsp.Body)
+	return string(body), err
+}
+
+func Test_originateTLS(t *testing.T) {
+	spec := &manager.InterceptSpec{TargetHost: "127.0.0.1", TargetTls: true}
+	config := func(t *testing.T, spec *manager.InterceptSpec) *tls.Config {
+		cfg, err := targetTLSConfig(spec)
+		require.NoError(t, err)
+		return cfg
+	}
+
+	t.Run("verified using --target-tls-ca", func(t *testing.T) {
+		tt := startTLSTarget(t)
+		withCA := &manager.InterceptSpec{
+			TargetHost:  "127.0.0.1",
+			TargetTls:   true,
+			TargetTlsCa: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tt.cert.Raw})),
+		}
+		body, err := tt.get(t, config(t, withCA))
+		require.NoError(t, err)
+		assert.Equal(t, "hello", body)
+	})
+
+	t.Run("untrusted", func(t *testing.T) {
+		tt := startTLSTarget(t)
+		_, err := tt.get(t, config(t, spec))
+		assert.Error(t, err)
+	})
+
+	t.Run("untrusted by --target-tls-ca", func(t *testing.T) {
+		tt := startTLSTarget(t)
+		_, other := localhostCert(t)
+		withCA := &manager.InterceptSpec{
+			TargetHost:  "127.0.0.1",
+			TargetTls:   true,
+			TargetTlsCa: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: other.Raw})),
+		}
+		_, err := tt.get(t, config(t, withCA))
+		assert.Error(t, err)
+	})
+
+	t.Run("insecure", func(t *testing.T) {
+		tt := startTLSTarget(t)
+		insecure := &manager.InterceptSpec{TargetHost: "127.0.0.1", TargetTls: true, TargetTlsInsecure: true}
+		body, err := tt.get(t, config(t, insecure))
+		require.NoError(t, err)
+		assert.Equal(t, "hello", body)
+	})
+
+	t.Run("SNI", func(t *testing.T) {
+		tt := startTLSTarget(t)
+		_, _ = tt.get(t, config(t, spec))
+		tt.mu.Lock()
+		defer tt.mu.Unlock()
+		assert.Equal(t, []string{"localhost"}, tt.serverNames)
+	})
+
+	t.Run("invalid --target-tls-ca", func(t *testing.T) {
+		_, err := targetTLSConfig(&manager.InterceptSpec{TargetHost: "127.0.0.1", TargetTls: true, TargetTlsCa: "garbage"})
+		assert.Error(t, err)
+	})
+}
+
+func Test_tlsServerName(t *testing.T) {
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"127.0.0.1", "localhost"},
+		{"::1", "localhost"},
+		{"192.168.1.10", "192.168.1.10"},
+		{"my-laptop.example.com", "my-laptop.example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			assert.Equal(t, tt.want, tlsServerName(tt.host))
+		})
+	}
+}
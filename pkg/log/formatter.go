@@ -2,6 +2,7 @@ package log
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
@@ -81,3 +82,52 @@ func (f *Formatter) Format(entry *logrus.Entry) ([]byte, error) {
 
 	return b.Bytes(), nil
 }
+
+// JSONFormatter formats log messages for Telepresence as JSON objects, one per line.
+type JSONFormatter struct {
+	timestampFormat string
+}
+
+func NewJSONFormatter(timestampFormat string) *JSONFormatter {
+	return &JSONFormatter{timestampFormat: timestampFormat}
+}
+
+// Format implements logrus.Formatter.
+func (f *JSONFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	data := make(map[string]any, len(entry.Data)+5)
+	for key, val := range entry.Data {
+		switch v := val.(type) {
+		case error:
+			// errors are structs without exported fields, so they must be converted explicitly.
+			val = v.Error()
+		case fmt.Stringer:
+			val = v.String()
+		}
+		if key == "THREAD" {
+			key = "goroutine"
+			if s, ok := val.(string); ok {
+				val = strings.TrimPrefix(s, "/")
+			}
+		}
+		data[key] = val
+	}
+	data["time"] = entry.Time.Format(f.timestampFormat)
+	data["level"] = entry.Level.String()
+	data["msg"] = entry.Message
+	if entry.HasCaller() && strings.HasPrefix(entry.Caller.File, thisModule+"/") {
+		data["caller"] = fmt.Sprintf("%s:%d", strings.TrimPrefix(entry.Caller.File, thisModule+"/"), entry.Caller.Line)
+	}
+
+	var b *bytes.Buffer
+	if entry.Buffer != nil {
+		b = entry.Buffer
+	} else {
+		b = &bytes.Buffer{}
+	}
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(data); err != nil {
+		return nil, fmt.Errorf("failed to marshal log entry to JSON: %w", err)
+	}
+	return b.Bytes(), nil
+}
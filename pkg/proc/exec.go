@@ -115,3 +115,8 @@ func IsAdmin() bool {
 func Terminate(p *os.Process) error {
 	return terminate(p)
 }
+
+// IsAlive returns true if a process with the given pid exists.
+func IsAlive(pid int) (bool, error) {
+	return isAlive(pid)
+}
@@ -5,6 +5,7 @@ package proc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec" //nolint:depguard // We want no logging and no soft-context signal handling
@@ -56,6 +57,20 @@ func startInBackgroundAsRoot(_ context.Context, args ...string) error {
 	}, args...)...).Run()
 }
 
+func isAlive(pid int) (bool, error) {
+	// Signal 0 performs the error checking without sending a signal. EPERM means that the process
+	// exists but is owned by another user.
+	err := unix.Kill(pid, 0)
+	switch {
+	case err == nil, errors.Is(err, unix.EPERM):
+		return true, nil
+	case errors.Is(err, unix.ESRCH):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
 func terminate(p *os.Process) error {
 	// SIGTERM makes it through a PTY, SIGINT doesn't. Not sure why that is.
 	// thallgren
@@ -169,6 +169,10 @@ func terminateChildrenOf(ctx context.Context, pid uint32, sig os.Signal, pes []*
 	return nil
 }
 
+func isAlive(pid int) (bool, error) {
+	return processIsAlive(uint32(pid))
+}
+
 // processIsAlive checks if the given pid exists in the current process snapshot.
 func processIsAlive(pid uint32) (bool, error) {
 	found := false
@@ -0,0 +1,35 @@
+package proc
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+)
+
+// PortOwner is a local process that listens to a TCP port.
+type PortOwner struct {
+	PID     int
+	Command string
+}
+
+func (p *PortOwner) String() string {
+	if p.Command == "" {
+		return fmt.Sprintf("process %d", p.PID)
+	}
+	return fmt.Sprintf("process %d (%s)", p.PID, p.Command)
+}
+
+// TCPPortOwner returns the local process that listens to the given TCP port. The returned error wraps
+// fs.ErrPermission when a listener exists but the process that owns it cannot be inspected, and
+// fs.ErrNotExist when no owner was found.
+func TCPPortOwner(ctx context.Context, port uint16) (*PortOwner, error) {
+	return tcpPortOwner(ctx, port)
+}
+
+func errNoPortOwner(port uint16) error {
+	return fmt.Errorf("no process found that listens to TCP port %d: %w", port, fs.ErrNotExist)
+}
+
+func errPortOwnerDenied(port uint16) error {
+	return fmt.Errorf("unable to inspect the process that listens to TCP port %d: %w", port, fs.ErrPermission)
+}
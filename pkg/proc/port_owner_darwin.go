@@ -0,0 +1,56 @@
+//go:build darwin
+
+package proc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"strconv"
+
+	"github.com/datawire/dlib/dexec"
+)
+
+func tcpPortOwner(ctx context.Context, port uint16) (*PortOwner, error) {
+	// lsof exits with status 1 when it finds nothing, which includes the case where the
+	// owning process belongs to another user and cannot be inspected.
+	cmd := dexec.CommandContext(ctx, "lsof", "-nP", "-iTCP:"+strconv.Itoa(int(port)), "-sTCP:LISTEN", "-Fpc")
+	cmd.DisableLogging = true
+	out, _ := cmd.Output()
+	if po := parseLsof(out); po != nil {
+		return po, nil
+	}
+	if os.Geteuid() != 0 {
+		return nil, errPortOwnerDenied(port)
+	}
+	return nil, errNoPortOwner(port)
+}
+
+// parseLsof returns the first process found in output from lsof that uses the -Fpc field format.
+func parseLsof(out []byte) *PortOwner {
+	var po *PortOwner
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		line := sc.Text()
+		if len(line) < 2 {
+			continue
+		}
+		switch line[0] {
+		case 'p':
+			if po != nil {
+				return po
+			}
+			pid, err := strconv.Atoi(line[1:])
+			if err != nil {
+				return nil
+			}
+			po = &PortOwner{PID: pid}
+		case 'c':
+			if po != nil {
+				po.Command = line[1:]
+			}
+		}
+	}
+	return po
+}
@@ -0,0 +1,104 @@
+//go:build linux
+
+package proc
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// tcpListen is the value of the "st" column in /proc/net/tcp for a socket in the LISTEN state.
+const tcpListen = "0A"
+
+func tcpPortOwner(_ context.Context, port uint16) (*PortOwner, error) {
+	inodes := make(map[string]struct{})
+	for _, tf := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		f, err := os.Open(tf)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		err = listenInodes(f, port, inodes)
+		_ = f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(inodes) == 0 {
+		return nil, errNoPortOwner(port)
+	}
+
+	procs, err := filepath.Glob("/proc/[0-9]*")
+	if err != nil {
+		return nil, err
+	}
+	denied := false
+	for _, pd := range procs {
+		fds, err := os.ReadDir(filepath.Join(pd, "fd"))
+		if err != nil {
+			// The process may have terminated, or belong to another user.
+			if errors.Is(err, fs.ErrPermission) {
+				denied = true
+			}
+			continue
+		}
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(pd, "fd", fd.Name()))
+			if err != nil || !strings.HasPrefix(link, "socket:[") {
+				continue
+			}
+			if _, ok := inodes[strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")]; ok {
+				pid, _ := strconv.Atoi(filepath.Base(pd))
+				po := &PortOwner{PID: pid}
+				if comm, err := os.ReadFile(filepath.Join(pd, "comm")); err == nil {
+					po.Command = strings.TrimSpace(string(comm))
+				}
+				return po, nil
+			}
+		}
+	}
+	if denied {
+		return nil, errPortOwnerDenied(port)
+	}
+	return nil, errNoPortOwner(port)
+}
+
+// listenInodes reads a /proc/net/tcp formatted table and adds the inodes of the sockets that listen to the
+// given port to the given set.
+func listenInodes(r io.Reader, port uint16, inodes map[string]struct{}) error {
+	sc := bufio.NewScanner(r)
+	first := true
+	for sc.Scan() {
+		if first {
+			// Skip the header line
+			first = false
+			continue
+		}
+		// Fields are: sl local_address rem_address st tx_queue:rx_queue tr:tm->when retrnsmt uid timeout inode ...
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 10 || fields[3] != tcpListen {
+			continue
+		}
+		i := strings.LastIndexByte(fields[1], ':')
+		if i < 0 {
+			continue
+		}
+		lp, err := strconv.ParseUint(fields[1][i+1:], 16, 16)
+		if err != nil || uint16(lp) != port {
+			continue
+		}
+		if fields[9] != "0" {
+			inodes[fields[9]] = struct{}{}
+		}
+	}
+	return sc.Err()
+}
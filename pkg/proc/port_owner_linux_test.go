@@ -0,0 +1,41 @@
+package proc
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_listenInodes(t *testing.T) {
+	table := `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 0100007F:2329 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 123456 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:2329 0100007F:C350 01 00000000:00000000 00:00000000 00000000  1000        0 123457 1 0000000000000000 20 4 30 10 -1
+   2: 00000000:0016 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 2345 1 0000000000000000 100 0 0 10 0
+`
+	inodes := make(map[string]struct{})
+	require.NoError(t, listenInodes(strings.NewReader(table), 9001, inodes))
+	assert.Equal(t, map[string]struct{}{"123456": {}}, inodes)
+}
+
+func TestTCPPortOwner(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	port := uint16(l.Addr().(*net.TCPAddr).Port)
+
+	po, err := TCPPortOwner(context.Background(), port)
+	require.NoError(t, err)
+	assert.Equal(t, os.Getpid(), po.PID)
+	assert.NotEmpty(t, po.Command)
+
+	require.NoError(t, l.Close())
+	_, err = TCPPortOwner(context.Background(), port)
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+}
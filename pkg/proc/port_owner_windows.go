@@ -0,0 +1,62 @@
+//go:build windows
+
+package proc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strconv"
+	"strings"
+
+	"github.com/datawire/dlib/dexec"
+)
+
+func tcpPortOwner(ctx context.Context, port uint16) (*PortOwner, error) {
+	cmd := dexec.CommandContext(ctx, "netstat", "-ano", "-p", "TCP")
+	cmd.DisableLogging = true
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	pid := parseNetstat(out, port)
+	if pid == 0 {
+		cmd = dexec.CommandContext(ctx, "netstat", "-ano", "-p", "TCPv6")
+		cmd.DisableLogging = true
+		if out, err = cmd.Output(); err != nil {
+			return nil, err
+		}
+		if pid = parseNetstat(out, port); pid == 0 {
+			return nil, errNoPortOwner(port)
+		}
+	}
+	po := &PortOwner{PID: pid}
+	cmd = dexec.CommandContext(ctx, "tasklist", "/FI", "PID eq "+strconv.Itoa(pid), "/FO", "CSV", "/NH")
+	cmd.DisableLogging = true
+	if out, err = cmd.Output(); err == nil {
+		// The first field of the CSV output is the image name. Nothing is found if access is denied.
+		if rec, err := csv.NewReader(bytes.NewReader(out)).Read(); err == nil && len(rec) > 1 {
+			po.Command = rec[0]
+		}
+	}
+	return po, nil
+}
+
+// parseNetstat returns the PID of the process that listens to the given port in output from "netstat -ano",
+// or zero if no such process is found.
+func parseNetstat(out []byte, port uint16) int {
+	suffix := ":" + strconv.Itoa(int(port))
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		// Fields are: Proto Local-Address Foreign-Address State PID
+		fields := strings.Fields(sc.Text())
+		if len(fields) != 5 || fields[0] != "TCP" || fields[3] != "LISTENING" || !strings.HasSuffix(fields[1], suffix) {
+			continue
+		}
+		if pid, err := strconv.Atoi(fields[4]); err == nil {
+			return pid
+		}
+	}
+	return 0
+}
@@ -49,6 +49,24 @@ func DefaultRoute(ctx context.Context) (*Route, error) {
 	return nil, errors.New("unable to find a default route")
 }
 
+// DefaultRouteFor returns the default route for the IP family (IPv4 or IPv6) of the given IP.
+func DefaultRouteFor(ctx context.Context, ip net.IP) (*Route, error) {
+	rt, err := GetRoutingTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ipv4 := ip.To4() != nil
+	for _, r := range rt {
+		if r.Default && (r.RoutedNet.IP.To4() != nil) == ipv4 {
+			return r, nil
+		}
+	}
+	if ipv4 {
+		return nil, errors.New("unable to find an IPv4 default route")
+	}
+	return nil, errors.New("unable to find an IPv6 default route")
+}
+
 type rtError string
 
 func (r rtError) Error() string {
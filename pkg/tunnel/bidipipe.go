@@ -69,7 +69,7 @@ func (p *bidiPipe) doPipe(
 	readBytesProbe, writeBytesProbe *CounterProbe,
 ) {
 	defer wg.Done()
-	wrCh := make(chan Message, 50)
+	wrCh := make(chan Message, GetTuning(ctx).WindowSize)
 	defer close(wrCh)
 	wg.Add(1)
 	WriteLoop(ctx, b, wrCh, wg, writeBytesProbe)
@@ -16,3 +16,19 @@ func GetPool(ctx context.Context) *Pool {
 	}
 	return pool
 }
+
+type tuningKey struct{}
+
+// WithTuning returns a context with the given Tuning.
+func WithTuning(ctx context.Context, tuning Tuning) context.Context {
+	return context.WithValue(ctx, tuningKey{}, tuning.Normalized())
+}
+
+// GetTuning returns the Tuning of the given context, or the default tuning when the context has none.
+func GetTuning(ctx context.Context) Tuning {
+	tuning, ok := ctx.Value(tuningKey{}).(Tuning)
+	if !ok {
+		return Tuning{}.Normalized()
+	}
+	return tuning
+}
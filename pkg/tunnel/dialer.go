@@ -54,6 +54,8 @@ type dialer struct {
 
 	ingressBytesProbe *CounterProbe
 	egressBytesProbe  *CounterProbe
+
+	shaping Shaping
 }
 
 // NewDialer creates a new handler that dispatches messages in both directions between the given gRPC stream
@@ -90,6 +92,33 @@ func NewConnEndpointTTL(
 	ttl time.Duration,
 	ingressBytesProbe, egressBytesProbe *CounterProbe,
 ) Endpoint {
+	return newDialer(stream, conn, cancel, ttl, Shaping{}, ingressBytesProbe, egressBytesProbe)
+}
+
+// NewShapedConnEndpoint is like NewConnEndpoint, but shapes the traffic that passes through the endpoint as
+// described by the given Shaping.
+func NewShapedConnEndpoint(
+	stream Stream,
+	conn net.Conn,
+	cancel context.CancelFunc,
+	shaping Shaping,
+	ingressBytesProbe, egressBytesProbe *CounterProbe,
+) Endpoint {
+	ttl := tcpConnTTL
+	if stream.ID().Protocol() == ipproto.UDP {
+		ttl = udpConnTTL
+	}
+	return newDialer(stream, conn, cancel, ttl, shaping, ingressBytesProbe, egressBytesProbe)
+}
+
+func newDialer(
+	stream Stream,
+	conn net.Conn,
+	cancel context.CancelFunc,
+	ttl time.Duration,
+	shaping Shaping,
+	ingressBytesProbe, egressBytesProbe *CounterProbe,
+) *dialer {
 	state := notConnected
 	if conn != nil {
 		state = connecting
@@ -104,6 +133,8 @@ func NewConnEndpointTTL(
 
 		ingressBytesProbe: ingressBytesProbe,
 		egressBytesProbe:  egressBytesProbe,
+
+		shaping: shaping,
 	}
 }
 
@@ -190,7 +221,12 @@ func (h *dialer) connToStreamLoop(ctx context.Context, wg *sync.WaitGroup) {
 	endLevel := dlog.LogLevelTrace
 	id := h.stream.ID()
 
-	outgoing := make(chan Message, 50)
+	tuning := GetTuning(ctx)
+	toStream := make(chan Message, tuning.WindowSize)
+	outgoing := chan<- Message(toStream)
+	if h.shaping.EgressDelay > 0 {
+		outgoing = delayLine(ctx, h.shaping.EgressDelay, toStream)
+	}
 	defer func() {
 		if !h.ResetIdle() {
 			// Hard close of peer. We don't want any more data
@@ -205,14 +241,18 @@ func (h *dialer) connToStreamLoop(ctx context.Context, wg *sync.WaitGroup) {
 	}()
 
 	wg.Add(1)
-	WriteLoop(ctx, h.stream, outgoing, wg, h.egressBytesProbe)
+	WriteLoop(ctx, h.stream, toStream, wg, h.egressBytesProbe)
 
-	buf := make([]byte, 0x100000)
+	buf := make([]byte, tuning.BufferSize)
 	dlog.Tracef(ctx, "   CONN %s conn-to-stream loop started", id)
 	for {
-		n, err := h.conn.Read(buf)
+		n, err := h.conn.Read(buf[:h.shaping.EgressThrottle.ChunkSize(len(buf))])
 		if n > 0 {
 			dlog.Tracef(ctx, "<- CONN %s, len %d", id, n)
+			if werr := h.shaping.EgressThrottle.Wait(ctx, n); werr != nil {
+				endReason = werr.Error()
+				return
+			}
 			select {
 			case <-ctx.Done():
 				endReason = ctx.Err().Error()
@@ -254,7 +294,7 @@ func (h *dialer) streamToConnLoop(ctx context.Context, wg *sync.WaitGroup) {
 	defer func() {
 		wg.Done()
 	}()
-	readLoop(ctx, h, h.ingressBytesProbe)
+	readLoop(ctx, h, h.ingressBytesProbe, h.shaping.IngressThrottle)
 }
 
 func handleControl(ctx context.Context, h streamReader, cm Message) {
@@ -276,7 +316,7 @@ func handleControl(ctx context.Context, h streamReader, cm Message) {
 	}
 }
 
-func readLoop(ctx context.Context, h streamReader, trafficProbe *CounterProbe) {
+func readLoop(ctx context.Context, h streamReader, trafficProbe *CounterProbe, throttle *Throttle) {
 	var endReason string
 	endLevel := dlog.LogLevelTrace
 	id := h.getStream().ID()
@@ -316,7 +356,12 @@ func readLoop(ctx context.Context, h streamReader, trafficProbe *CounterProbe) {
 			payload := dg.Payload()
 			pn := len(payload)
 			for n := 0; n < pn; {
-				wn, err := h.reply(payload[n:])
+				chunk := payload[n : n+throttle.ChunkSize(pn-n)]
+				if err := throttle.Wait(ctx, len(chunk)); err != nil {
+					endReason = err.Error()
+					return
+				}
+				wn, err := h.reply(chunk)
 				if err != nil {
 					endReason = fmt.Sprintf("a write error occurred: %v", err)
 					endLevel = dlog.LogLevelError
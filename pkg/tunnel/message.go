@@ -57,6 +57,10 @@ const (
 
 	KeepAlive
 	Session
+
+	// bind is sent on a spare stream to bind it to a connection. It carries the roundtrip latency,
+	// the dial timeout, and the ID of the connection.
+	bind
 )
 
 func (c MessageCode) String() string {
@@ -77,6 +81,8 @@ func (c MessageCode) String() string {
 		return "KEEP_ALIVE"
 	case Session:
 		return "SESSION"
+	case bind:
+		return "BIND"
 	default:
 		return fmt.Sprintf("** unknown control code: %d **", c)
 	}
@@ -145,6 +151,24 @@ func StreamInfoMessage(id ConnID, sessionID string, callDelay, dialTimeout time.
 	return msg(b.Bytes())
 }
 
+func bindMessage(id ConnID, callDelay, dialTimeout time.Duration) Message {
+	b := bytes.Buffer{}
+	b.WriteByte(byte(bind))
+
+	buf := make([]byte, 8)
+	n := binary.PutUvarint(buf, uint64(callDelay))
+	b.Write(buf[:n])
+
+	n = binary.PutUvarint(buf, uint64(dialTimeout))
+	b.Write(buf[:n])
+
+	idb := []byte(id)
+	n = binary.PutUvarint(buf, uint64(len(idb)))
+	b.Write(buf[:n])
+	b.Write(idb)
+	return msg(b.Bytes())
+}
+
 func StreamOKMessage() Message {
 	m := makeMessage(streamOK, 4)
 	n := binary.PutUvarint(m.Payload(), uint64(Version))
@@ -214,3 +238,32 @@ func setConnectInfo(m Message, s *stream) error {
 	s.sessionID = string(pl[:v])
 	return nil
 }
+
+var errMalformedBind = errors.New("malformed Bind message")
+
+// setBindInfo assigns the connection info that the given bind Message represents to the given boundStream.
+func setBindInfo(m Message, s *boundStream) error {
+	pl := m.Payload()
+
+	v, n := binary.Uvarint(pl)
+	if n <= 0 {
+		return errMalformedBind
+	}
+	s.roundtripLatency = time.Duration(v)
+	pl = pl[n:]
+
+	v, n = binary.Uvarint(pl)
+	if n <= 0 {
+		return errMalformedBind
+	}
+	s.dialTimeout = time.Duration(v)
+	pl = pl[n:]
+
+	v, n = binary.Uvarint(pl)
+	if n <= 0 || v > uint64(len(pl)-n) {
+		return errMalformedBind
+	}
+	pl = pl[n:]
+	s.id = ConnID(pl[:v])
+	return nil
+}
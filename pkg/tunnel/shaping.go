@@ -0,0 +1,70 @@
+package tunnel
+
+import (
+	"context"
+	"time"
+)
+
+// maxDelayed is the maximum number of messages that a delay line holds back. A sender that has more messages
+// in flight is blocked until the oldest one has been passed on.
+const maxDelayed = 50
+
+// Shaping controls how the traffic that passes through a connection endpoint is shaped. Ingress is the data that
+// is written to the connection, and egress is the data that is read from it. The zero value doesn't shape anything.
+type Shaping struct {
+	// IngressThrottle limits the rate of the ingress data. Nil means unlimited.
+	IngressThrottle *Throttle
+
+	// EgressThrottle limits the rate of the egress data. Nil means unlimited.
+	EgressThrottle *Throttle
+
+	// EgressDelay is the time that the egress data is held back before it's sent to the stream.
+	EgressDelay time.Duration
+}
+
+// delayLine returns a channel that passes each message sent to it on to the out channel once the given delay has
+// elapsed since the message was sent. The order of the messages is retained. The out channel is closed when the
+// returned channel has been closed and all messages have been passed on, or at once when the context is done.
+func delayLine(ctx context.Context, delay time.Duration, out chan<- Message) chan<- Message {
+	in := make(chan Message, maxDelayed)
+	go func() {
+		defer close(out)
+		type delayed struct {
+			due time.Time
+			msg Message
+		}
+		var queue []delayed
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		recv := (<-chan Message)(in)
+		for recv != nil || len(queue) > 0 {
+			var due <-chan time.Time
+			if len(queue) > 0 {
+				timer.Reset(time.Until(queue[0].due))
+				due = timer.C
+			}
+			r := recv
+			if len(queue) >= maxDelayed {
+				r = nil
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case m, ok := <-r:
+				if !ok {
+					recv = nil
+					continue
+				}
+				queue = append(queue, delayed{due: time.Now().Add(delay), msg: m})
+			case <-due:
+				select {
+				case <-ctx.Done():
+					return
+				case out <- queue[0].msg:
+				}
+				queue = queue[1:]
+			}
+		}
+	}()
+	return in
+}
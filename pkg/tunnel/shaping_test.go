@@ -0,0 +1,52 @@
+package tunnel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_delayLine(t *testing.T) {
+	ctx := context.Background()
+	const delay = 100 * time.Millisecond
+
+	t.Run("delays and keeps order", func(t *testing.T) {
+		out := make(chan Message, 10)
+		in := delayLine(ctx, delay, out)
+		start := time.Now()
+		in <- NewMessage(Normal, []byte("a"))
+		time.Sleep(delay / 2)
+		in <- NewMessage(Normal, []byte("b"))
+		in <- NewMessage(Disconnect, nil)
+		close(in)
+
+		m := <-out
+		assert.Equal(t, "a", string(m.Payload()))
+		assert.GreaterOrEqual(t, time.Since(start), delay)
+		m = <-out
+		assert.Equal(t, "b", string(m.Payload()))
+		assert.GreaterOrEqual(t, time.Since(start), delay+delay/2)
+		m = <-out
+		assert.Equal(t, Disconnect, m.Code())
+		_, ok := <-out
+		assert.False(t, ok)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		cc, cancel := context.WithCancel(ctx)
+		out := make(chan Message, 10)
+		in := delayLine(cc, time.Hour, out)
+		in <- NewMessage(Normal, []byte("a"))
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+		select {
+		case _, ok := <-out:
+			require.False(t, ok)
+		case <-time.After(time.Second):
+			t.Fatal("the delay line didn't end when the context was cancelled")
+		}
+	})
+}
@@ -0,0 +1,185 @@
+package tunnel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/telepresence/v2/pkg/ipproto"
+)
+
+// spareVersion is the first stream version that supports spare streams.
+const spareVersion = 3
+
+// spareConnID is the ID of a stream that isn't yet bound to a connection. It can never be the ID of a real
+// connection, because port zero cannot be dialed.
+var spareConnID = NewConnID(ipproto.TCP, net.IPv4zero, net.IPv4zero, 0, 0) //nolint:gochecknoglobals // constant
+
+// ErrSpareUnsupported is returned by NewSpareClientStream when the peer doesn't support spare streams.
+var ErrSpareUnsupported = errors.New("peer doesn't support spare streams")
+
+// NewSpareClientStream creates a client stream that isn't bound to a connection. The peer binds the stream to a
+// connection when it needs one, which saves the roundtrips needed to request and establish a new stream. The
+// given idleTimeout is the time that the peer keeps the stream before it closes it unbound.
+//
+// A peer that doesn't support spare streams will attempt to dial the spare ID, and fail at once. Such peers are
+// detected by their version, and ErrSpareUnsupported is returned.
+func NewSpareClientStream(ctx context.Context, grpcStream GRPCClientStream, sessionID string, idleTimeout time.Duration) (Stream, error) {
+	s, err := NewClientStream(ctx, grpcStream, spareConnID, sessionID, 0, idleTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if s.PeerVersion() < spareVersion {
+		_ = s.CloseSend(ctx)
+		return nil, ErrSpareUnsupported
+	}
+	return s, nil
+}
+
+// IsSpare returns true if the given stream was created using NewSpareClientStream. The DialTimeout of such a
+// stream is the time that it may be kept unbound.
+func IsSpare(s Stream) bool {
+	return s.ID() == spareConnID && s.PeerVersion() >= spareVersion
+}
+
+// Bind binds the given spare stream to the connection with the given ID, and returns a Stream that represents
+// that connection. The peer of the spare stream obtains the same Stream using AwaitBind.
+func Bind(ctx context.Context, s Stream, id ConnID, roundtripLatency, dialTimeout time.Duration) (Stream, error) {
+	if err := s.Send(ctx, bindMessage(id, roundtripLatency, dialTimeout)); err != nil {
+		return nil, err
+	}
+	return &boundStream{Stream: s, id: id, roundtripLatency: roundtripLatency, dialTimeout: dialTimeout}, nil
+}
+
+// AwaitBind waits until the peer binds the given spare stream to a connection, and returns a Stream that
+// represents that connection. An error is returned if the stream ends before it is bound.
+func AwaitBind(ctx context.Context, s Stream) (Stream, error) {
+	m, err := s.Receive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if m.Code() != bind {
+		return nil, fmt.Errorf("initial message on spare stream was %s, not BIND", m.Code())
+	}
+	bs := &boundStream{Stream: s}
+	if err = setBindInfo(m, bs); err != nil {
+		return nil, err
+	}
+	return bs, nil
+}
+
+// boundStream is a spare stream that has been bound to a connection.
+type boundStream struct {
+	Stream
+	id               ConnID
+	roundtripLatency time.Duration
+	dialTimeout      time.Duration
+}
+
+func (s *boundStream) ID() ConnID {
+	return s.id
+}
+
+func (s *boundStream) RoundtripLatency() time.Duration {
+	return s.roundtripLatency
+}
+
+func (s *boundStream) DialTimeout() time.Duration {
+	return s.dialTimeout
+}
+
+// SparePool keeps up to a given number of spare streams open to a peer, and dials the connections that the peer
+// binds them to. A spare that isn't bound within the idle timeout is closed by the peer, and isn't replaced until
+// Refill is called, so the pool is only kept warm while there is traffic.
+type SparePool struct {
+	provider    Provider
+	sessionID   string
+	size        int32
+	idleTimeout time.Duration
+	spares      atomic.Int32
+	unsupported atomic.Bool
+	refillCh    chan struct{}
+}
+
+// NewSparePool creates a SparePool that opens spare streams using the given provider.
+func NewSparePool(provider Provider, sessionID string, size int, idleTimeout time.Duration) *SparePool {
+	return &SparePool{
+		provider:    provider,
+		sessionID:   sessionID,
+		size:        int32(size),
+		idleTimeout: idleTimeout,
+		refillCh:    make(chan struct{}, 1),
+	}
+}
+
+// Refill requests that the pool is filled up with new spare streams.
+func (p *SparePool) Refill() {
+	select {
+	case p.refillCh <- struct{}{}:
+	default:
+	}
+}
+
+// Run fills the pool, and then fills it up again each time Refill is called, until the context is cancelled or
+// the peer turns out not to support spare streams. Cancelling the context closes all spare streams.
+func (p *SparePool) Run(ctx context.Context) {
+	p.Refill()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.refillCh:
+		}
+		if p.unsupported.Load() {
+			dlog.Debug(ctx, "spare streams are disabled, because the peer doesn't support them")
+			return
+		}
+		for p.spares.Load() < p.size {
+			p.spares.Add(1)
+			go p.spare(ctx)
+		}
+	}
+}
+
+func (p *SparePool) spare(ctx context.Context) {
+	bound := false
+	defer func() {
+		if !bound {
+			p.spares.Add(-1)
+		}
+	}()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	mt, err := p.provider.Tunnel(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			dlog.Errorf(ctx, "!! spare stream, call to Tunnel failed: %v", err)
+		}
+		return
+	}
+	s, err := NewSpareClientStream(ctx, mt, p.sessionID, p.idleTimeout)
+	if err != nil {
+		if errors.Is(err, ErrSpareUnsupported) {
+			p.unsupported.Store(true)
+		} else if ctx.Err() == nil {
+			dlog.Error(ctx, err)
+		}
+		return
+	}
+	bs, err := AwaitBind(ctx, s)
+	if err != nil {
+		// The peer closed the spare because it was idle, or the pool was cancelled.
+		dlog.Tracef(ctx, "   %s %s, spare stream closed: %v", s.Tag(), s.ID(), err)
+		return
+	}
+	bound = true
+	p.spares.Add(-1)
+	p.Refill()
+	d := NewDialer(bs, cancel, nil, nil)
+	d.Start(ctx)
+	<-d.Done()
+}
@@ -0,0 +1,235 @@
+package tunnel
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/telepresenceio/telepresence/rpc/v2/manager"
+	"github.com/telepresenceio/telepresence/v2/pkg/ipproto"
+	"github.com/telepresenceio/telepresence/v2/pkg/iputil"
+)
+
+func TestSpareStream_Bind(t *testing.T) {
+	ctx, cancel := testContext(t, time.Second)
+	defer cancel()
+
+	tunnel := newBidi(10, ctx.Done())
+	id := NewConnID(ipproto.TCP, iputil.Parse("127.0.0.1"), iputil.Parse("192.168.0.1"), 1001, 8080)
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		client, err := NewSpareClientStream(ctx, tunnel.clientSide(), "session", 10*time.Second)
+		require.NoError(t, err)
+		bound, err := AwaitBind(ctx, client)
+		require.NoError(t, err)
+		assert.Equal(t, id, bound.ID())
+		assert.Equal(t, 2*time.Millisecond, bound.RoundtripLatency())
+		assert.Equal(t, 3*time.Second, bound.DialTimeout())
+		assert.Equal(t, "session", bound.SessionID())
+		m, err := bound.Receive(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(m.Payload()))
+	}()
+
+	go func() {
+		defer wg.Done()
+		server, err := NewServerStream(ctx, tunnel.serverSide())
+		require.NoError(t, err)
+		require.True(t, IsSpare(server))
+		assert.Equal(t, 10*time.Second, server.DialTimeout())
+		bound, err := Bind(ctx, server, id, 2*time.Millisecond, 3*time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, id, bound.ID())
+		assert.False(t, IsSpare(bound))
+		require.NoError(t, bound.Send(ctx, NewMessage(Normal, []byte("hello"))))
+	}()
+	wg.Wait()
+}
+
+func TestSpareStream_Unsupported(t *testing.T) {
+	ctx, cancel := testContext(t, time.Second)
+	defer cancel()
+
+	tunnel := newBidi(10, ctx.Done())
+	go func() {
+		// Respond like a peer that uses stream version 2.
+		ss := tunnel.serverSide()
+		_, _ = ss.Recv()
+		ok := makeMessage(streamOK, 1)
+		ok[1] = 2
+		_ = ss.Send(ok.TunnelMessage())
+	}()
+	_, err := NewSpareClientStream(ctx, tunnel.clientSide(), "session", time.Second)
+	assert.ErrorIs(t, err, ErrSpareUnsupported)
+}
+
+// latencyLink is a bidirectional gRPC stream where each message arrives a fixed latency after it was sent.
+// Unlike the bidi, the latency doesn't accumulate when several messages are in flight.
+type latencyLink struct {
+	latency time.Duration
+	done    <-chan struct{}
+	cToS    chan timedTunnelMessage
+	sToC    chan timedTunnelMessage
+	once    sync.Once
+}
+
+type timedTunnelMessage struct {
+	due time.Time
+	msg *manager.TunnelMessage
+}
+
+func newLatencyLink(latency time.Duration, done <-chan struct{}) *latencyLink {
+	return &latencyLink{
+		latency: latency,
+		done:    done,
+		cToS:    make(chan timedTunnelMessage, 100),
+		sToC:    make(chan timedTunnelMessage, 100),
+	}
+}
+
+func (l *latencyLink) recv(ch <-chan timedTunnelMessage) (*manager.TunnelMessage, error) {
+	select {
+	case <-l.done:
+		return nil, context.Canceled
+	case tm, ok := <-ch:
+		if !ok {
+			return nil, io.EOF
+		}
+		time.Sleep(time.Until(tm.due))
+		return tm.msg, nil
+	}
+}
+
+func (l *latencyLink) send(ch chan<- timedTunnelMessage, msg *manager.TunnelMessage) error {
+	select {
+	case <-l.done:
+		return context.Canceled
+	case ch <- timedTunnelMessage{due: time.Now().Add(l.latency), msg: msg}:
+		return nil
+	}
+}
+
+type latencyClientSide struct{ *latencyLink }
+
+func (c latencyClientSide) Recv() (*manager.TunnelMessage, error) { return c.recv(c.sToC) }
+
+func (c latencyClientSide) Send(msg *manager.TunnelMessage) error { return c.send(c.cToS, msg) }
+
+func (c latencyClientSide) CloseSend() error {
+	c.once.Do(func() { close(c.cToS) })
+	return nil
+}
+
+type latencyServerSide struct{ *latencyLink }
+
+func (c latencyServerSide) Recv() (*manager.TunnelMessage, error) { return c.recv(c.cToS) }
+
+func (c latencyServerSide) Send(msg *manager.TunnelMessage) error { return c.send(c.sToC, msg) }
+
+// BenchmarkSpareStream measures the latency of a request that the traffic-agent receives on an intercepted
+// connection, from the time the agent accepts the connection until the request reaches the local service. The
+// agent and the client are connected by a link with a one-way latency of 5ms.
+//
+// Without a pool, the agent sends a DialRequest to the client and must then wait for the client to open a new
+// stream before it can send the request, so the request needs three one-way trips. With a pool, the agent binds
+// a spare stream and sends the request at once, so it needs one.
+func BenchmarkSpareStream(b *testing.B) {
+	const latency = 5 * time.Millisecond
+	request := []byte("GET / HTTP/1.1\r\nHost: example.com\r\n\r\n")
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(b, err)
+	defer l.Close()
+	received := make(chan struct{})
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				buf := make([]byte, len(request))
+				if _, err := io.ReadFull(conn, buf); err == nil {
+					received <- struct{}{}
+				}
+			}()
+		}
+	}()
+	svc := l.Addr().(*net.TCPAddr)
+	id := NewConnID(ipproto.TCP, iputil.Parse("10.0.0.1"), svc.IP, 34567, uint16(svc.Port))
+
+	// sendRequest lets the agent send the request on a connection bound to the given stream, and waits until
+	// the local service has received it.
+	sendRequest := func(ctx context.Context, cancel context.CancelFunc, s Stream) {
+		app, conn := net.Pipe()
+		defer app.Close()
+		NewConnEndpoint(s, conn, cancel, nil, nil).Start(ctx)
+		if _, err := app.Write(request); err != nil {
+			b.Fatal(err)
+		}
+		<-received
+	}
+
+	b.Run("without pool", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ctx, cancel := context.WithCancel(context.Background())
+			link := newLatencyLink(latency, ctx.Done())
+			go func() {
+				// The DialRequest reaches the client.
+				time.Sleep(latency)
+				cs, err := NewClientStream(ctx, latencyClientSide{link}, id, "session", 0, time.Second)
+				if err != nil {
+					return
+				}
+				NewDialer(cs, cancel, nil, nil).Start(ctx)
+			}()
+			ss, err := NewServerStream(ctx, latencyServerSide{link})
+			if err != nil {
+				b.Fatal(err)
+			}
+			sendRequest(ctx, cancel, ss)
+			cancel()
+		}
+	})
+
+	b.Run("with pool", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			ctx, cancel := context.WithCancel(context.Background())
+			link := newLatencyLink(latency, ctx.Done())
+			go func() {
+				cs, err := NewSpareClientStream(ctx, latencyClientSide{link}, "session", time.Minute)
+				if err != nil {
+					return
+				}
+				bs, err := AwaitBind(ctx, cs)
+				if err != nil {
+					return
+				}
+				NewDialer(bs, cancel, nil, nil).Start(ctx)
+			}()
+			ss, err := NewServerStream(ctx, latencyServerSide{link})
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.StartTimer()
+
+			bs, err := Bind(ctx, ss, id, 0, time.Second)
+			if err != nil {
+				b.Fatal(err)
+			}
+			sendRequest(ctx, cancel, bs)
+			cancel()
+		}
+	})
+}
@@ -21,7 +21,8 @@ import (
 //
 //	0 which didn't report versions and didn't do synchronization
 //	1 used MuxTunnel instead of one tunnel per connection.
-const Version = uint16(2)
+//	2 doesn't support spare streams.
+const Version = uint16(3)
 
 // Endpoint is an endpoint for a Stream such as a Dialer or a bidirectional pipe.
 type Endpoint interface {
@@ -75,7 +76,7 @@ type StreamCreator func(context.Context, ConnID) (Stream, error)
 // ReadLoop reads from the Stream and dispatches messages and error to the give channels. There
 // will be max one error since the error also terminates the loop.
 func ReadLoop(ctx context.Context, s Stream, p *CounterProbe) (<-chan Message, <-chan error) {
-	msgCh := make(chan Message, 50)
+	msgCh := make(chan Message, GetTuning(ctx).WindowSize)
 	errCh := make(chan error, 1) // Max one message will be sent on this channel
 	dlog.Tracef(ctx, "   %s %s, ReadLoop starting", s.Tag(), s.ID())
 	go func() {
@@ -0,0 +1,109 @@
+package tunnel
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// throttleInterval is the time slice used when shaping traffic. A throttled loop never passes on more data in
+// one go than the rate permits during one interval, so a shorter interval gives smoother traffic at the cost of
+// more and smaller messages.
+const throttleInterval = 100 * time.Millisecond
+
+// Throttle limits the rate of the data that passes through a tunnel loop. The data is passed on in chunks, and
+// each chunk is delayed until the time that the previous chunks would need at the given rate has elapsed. Time
+// during which no data passes is not saved up, so the traffic never bursts above the rate.
+//
+// All methods are safe to call on a nil Throttle, which doesn't limit anything.
+type Throttle struct {
+	mu    sync.Mutex
+	rate  int64 // bytes per second
+	chunk int
+	next  time.Time
+}
+
+// NewThrottle returns a Throttle that limits the rate to the given number of bytes per second, or nil when the
+// rate is zero or negative.
+func NewThrottle(bytesPerSecond int64) *Throttle {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+	chunk := bytesPerSecond * int64(throttleInterval) / int64(time.Second)
+	if chunk < 1 {
+		chunk = 1
+	}
+	return &Throttle{rate: bytesPerSecond, chunk: int(chunk)}
+}
+
+// ChunkSize returns the number of bytes, up to the given maximum, that may be passed on in one go.
+func (t *Throttle) ChunkSize(maxSize int) int {
+	if t == nil || maxSize <= t.chunk {
+		return maxSize
+	}
+	return t.chunk
+}
+
+// Wait blocks until the given number of bytes may be passed on, or until the context is done.
+func (t *Throttle) Wait(ctx context.Context, n int) error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	now := time.Now()
+	if t.next.Before(now) {
+		t.next = now
+	}
+	delay := t.next.Sub(now)
+	t.next = t.next.Add(time.Duration(int64(n) * int64(time.Second) / t.rate))
+	t.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+	tm := time.NewTimer(delay)
+	defer tm.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-tm.C:
+		return nil
+	}
+}
+
+var rateUnits = map[string]float64{
+	"bit":  1.0 / 8,
+	"kbit": 1e3 / 8,
+	"mbit": 1e6 / 8,
+	"gbit": 1e9 / 8,
+	"b":    1,
+	"kb":   1e3,
+	"mb":   1e6,
+	"gb":   1e9,
+}
+
+// ParseRate parses a bandwidth such as "1mbit" or "500kB" and returns it in bytes per second. The units are
+// case-insensitive and use decimal prefixes. The bit units are bit, kbit, mbit, and gbit, and the byte units
+// are B, kB, MB, and GB.
+func ParseRate(s string) (int64, error) {
+	ls := strings.ToLower(strings.TrimSpace(s))
+	i := strings.IndexFunc(ls, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if i <= 0 {
+		return 0, fmt.Errorf("invalid rate %q: must be a number followed by one of bit, kbit, mbit, gbit, B, kB, MB, or GB", s)
+	}
+	m, ok := rateUnits[ls[i:]]
+	if !ok {
+		return 0, fmt.Errorf("invalid rate %q: unknown unit %q", s, ls[i:])
+	}
+	v, err := strconv.ParseFloat(ls[:i], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid rate %q: %w", s, err)
+	}
+	bps := int64(v * m)
+	if bps < 1 {
+		return 0, fmt.Errorf("invalid rate %q: must be at least 1 byte per second", s)
+	}
+	return bps, nil
+}
@@ -0,0 +1,93 @@
+package tunnel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		rate    string
+		want    int64
+		wantErr bool
+	}{
+		{rate: "1mbit", want: 125000},
+		{rate: "1Mbit", want: 125000},
+		{rate: "500kbit", want: 62500},
+		{rate: "8bit", want: 1},
+		{rate: "2gbit", want: 250000000},
+		{rate: "1.5MB", want: 1500000},
+		{rate: "500kB", want: 500000},
+		{rate: "100B", want: 100},
+		{rate: " 1GB ", want: 1000000000},
+		{rate: "1", wantErr: true},
+		{rate: "mbit", wantErr: true},
+		{rate: "1mbps", wantErr: true},
+		{rate: "1.2.3mbit", wantErr: true},
+		{rate: "1bit", wantErr: true},
+		{rate: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rate, func(t *testing.T) {
+			got, err := ParseRate(tt.rate)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("nil", func(t *testing.T) {
+		var th *Throttle
+		assert.Nil(t, NewThrottle(0))
+		assert.Equal(t, 0x10000, th.ChunkSize(0x10000))
+		assert.NoError(t, th.Wait(ctx, 0x10000))
+	})
+
+	t.Run("chunk size", func(t *testing.T) {
+		th := NewThrottle(10000)
+		assert.Equal(t, 1000, th.ChunkSize(0x10000))
+		assert.Equal(t, 100, th.ChunkSize(100))
+		assert.Equal(t, 1, NewThrottle(5).ChunkSize(100))
+	})
+
+	t.Run("rate", func(t *testing.T) {
+		// 10 chunks of 1000 bytes at 20000 bytes per second. The first chunk passes at once.
+		th := NewThrottle(20000)
+		start := time.Now()
+		for i := 0; i < 10; i++ {
+			require.NoError(t, th.Wait(ctx, th.ChunkSize(0x10000)))
+		}
+		elapsed := time.Since(start)
+		assert.GreaterOrEqual(t, elapsed, 450*time.Millisecond)
+		assert.Less(t, elapsed, 2*time.Second)
+	})
+
+	t.Run("idle time is not saved up", func(t *testing.T) {
+		th := NewThrottle(20000)
+		require.NoError(t, th.Wait(ctx, 1000))
+		time.Sleep(200 * time.Millisecond)
+		start := time.Now()
+		require.NoError(t, th.Wait(ctx, 1000))
+		require.NoError(t, th.Wait(ctx, 1000))
+		assert.GreaterOrEqual(t, time.Since(start), 45*time.Millisecond)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		th := NewThrottle(1)
+		require.NoError(t, th.Wait(ctx, 100))
+		cc, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, th.Wait(cc, 1), context.DeadlineExceeded)
+	})
+}
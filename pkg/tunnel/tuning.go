@@ -0,0 +1,53 @@
+package tunnel
+
+const (
+	// DefaultBufferSize is the default size of the buffer that a connection endpoint reads into. It's also the
+	// maximum size of the payload of one message.
+	DefaultBufferSize = 0x100000
+
+	// DefaultWindowSize is the default number of messages that are queued in each direction of a stream.
+	DefaultWindowSize = 50
+
+	// MinBufferSize is the smallest permitted buffer size.
+	MinBufferSize = 0x1000
+
+	// MaxBufferSize is the largest permitted buffer size. A message must stay below the 4MiB that the
+	// traffic-manager and the traffic-agent accept by default, including the overhead of the gRPC envelope.
+	MaxBufferSize = 0x300000
+
+	// MaxWindowSize is the largest permitted window size.
+	MaxWindowSize = 1000
+)
+
+// Tuning controls the buffering of the tunnel. Larger values increase throughput on links with high latency at
+// the cost of memory. Each connection uses one buffer, and up to twice the window size of messages of at most the
+// buffer size may be queued for it.
+type Tuning struct {
+	// BufferSize is the number of bytes that a connection endpoint reads in one go, and hence the maximum size of
+	// the payload of one message. Zero means DefaultBufferSize.
+	BufferSize int
+
+	// WindowSize is the number of messages that may be queued in each direction of a stream while waiting to be
+	// sent or written. Zero means DefaultWindowSize.
+	WindowSize int
+}
+
+// Normalized returns the tuning with its zero values replaced by defaults, and its other values brought within
+// the permitted range.
+func (t Tuning) Normalized() Tuning {
+	switch {
+	case t.BufferSize == 0:
+		t.BufferSize = DefaultBufferSize
+	case t.BufferSize < MinBufferSize:
+		t.BufferSize = MinBufferSize
+	case t.BufferSize > MaxBufferSize:
+		t.BufferSize = MaxBufferSize
+	}
+	switch {
+	case t.WindowSize <= 0:
+		t.WindowSize = DefaultWindowSize
+	case t.WindowSize > MaxWindowSize:
+		t.WindowSize = MaxWindowSize
+	}
+	return t
+}
@@ -0,0 +1,155 @@
+package tunnel
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/telepresenceio/telepresence/v2/pkg/ipproto"
+	"github.com/telepresenceio/telepresence/v2/pkg/iputil"
+)
+
+func TestTuning_Normalized(t *testing.T) {
+	assert.Equal(t, Tuning{BufferSize: DefaultBufferSize, WindowSize: DefaultWindowSize}, Tuning{}.Normalized())
+	assert.Equal(t, Tuning{BufferSize: MinBufferSize, WindowSize: DefaultWindowSize}, Tuning{BufferSize: 10, WindowSize: -1}.Normalized())
+	assert.Equal(t, Tuning{BufferSize: MaxBufferSize, WindowSize: MaxWindowSize}, Tuning{BufferSize: 0x1000000, WindowSize: 5000}.Normalized())
+	assert.Equal(t, Tuning{BufferSize: 0x10000, WindowSize: 10}, Tuning{BufferSize: 0x10000, WindowSize: 10}.Normalized())
+
+	assert.Equal(t, Tuning{}.Normalized(), GetTuning(context.Background()))
+	assert.Equal(t, Tuning{BufferSize: MaxBufferSize, WindowSize: 1}, GetTuning(WithTuning(context.Background(), Tuning{BufferSize: 0x1000000, WindowSize: 1})))
+}
+
+type timedMessage struct {
+	due time.Time
+	msg Message
+}
+
+// linkStream is a Stream that simulates a network link with a fixed latency and flow control. A sender may have
+// a limited number of messages in flight. The credit for a message is returned to the sender when the receiver
+// has received it, and that takes another latency, so a receiver that doesn't keep up stalls the sender.
+type linkStream struct {
+	channelStream
+	latency  time.Duration
+	sendCh   chan<- timedMessage
+	recvCh   <-chan timedMessage
+	sendCred chan struct{}
+	recvCred chan struct{}
+}
+
+func newLinkPipe(id ConnID, latency time.Duration, inFlight int) (Stream, Stream) {
+	aToB := make(chan timedMessage, inFlight)
+	bToA := make(chan timedMessage, inFlight)
+	aCred := make(chan struct{}, inFlight)
+	bCred := make(chan struct{}, inFlight)
+	for i := 0; i < inFlight; i++ {
+		aCred <- struct{}{}
+		bCred <- struct{}{}
+	}
+	return &linkStream{
+		channelStream: channelStream{id: id, tag: "A"},
+		latency:       latency,
+		sendCh:        aToB,
+		recvCh:        bToA,
+		sendCred:      aCred,
+		recvCred:      bCred,
+	}, &linkStream{
+		channelStream: channelStream{id: id, tag: "B"},
+		latency:       latency,
+		sendCh:        bToA,
+		recvCh:        aToB,
+		sendCred:      bCred,
+		recvCred:      aCred,
+	}
+}
+
+func (s *linkStream) Receive(ctx context.Context) (Message, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case tm, ok := <-s.recvCh:
+		if !ok {
+			return nil, io.EOF
+		}
+		time.Sleep(time.Until(tm.due))
+		time.AfterFunc(s.latency, func() { s.recvCred <- struct{}{} })
+		return tm.msg, nil
+	}
+}
+
+func (s *linkStream) Send(ctx context.Context, m Message) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.sendCred:
+	}
+	s.sendCh <- timedMessage{due: time.Now().Add(s.latency), msg: m}
+	return nil
+}
+
+func (s *linkStream) CloseSend(context.Context) error {
+	close(s.sendCh)
+	return nil
+}
+
+// BenchmarkTuning transfers 5MB between two connection endpoints using different tunings. The endpoints are
+// connected by a link with a latency of 10ms that permits 8 messages in flight, and the receiving application
+// pauses after each 512KiB that it reads. The reported throughput shows the effect of the tuning. A larger buffer
+// size means fewer and larger messages, so more data is in flight. A larger window size lets the receiving end
+// keep accepting data while the application pauses.
+func BenchmarkTuning(b *testing.B) {
+	const size = 5 * 1000 * 1000
+	data := make([]byte, size)
+	id := NewConnID(ipproto.TCP, iputil.Parse("127.0.0.1"), iputil.Parse("192.168.0.1"), 1001, 8080)
+
+	for _, tuning := range []Tuning{
+		{BufferSize: 0x4000, WindowSize: 1},
+		{BufferSize: 0x4000, WindowSize: DefaultWindowSize},
+		{BufferSize: 0x10000, WindowSize: 1},
+		{BufferSize: 0x10000, WindowSize: DefaultWindowSize},
+		{BufferSize: DefaultBufferSize, WindowSize: 1},
+		{BufferSize: DefaultBufferSize, WindowSize: DefaultWindowSize},
+	} {
+		b.Run(fmt.Sprintf("buffer=%dKiB,window=%d", tuning.BufferSize/1024, tuning.WindowSize), func(b *testing.B) {
+			b.SetBytes(size)
+			for i := 0; i < b.N; i++ {
+				ctx, cancel := context.WithCancel(WithTuning(context.Background(), tuning))
+				sa, sb := newLinkPipe(id, 10*time.Millisecond, 8)
+				srcApp, srcConn := net.Pipe()
+				dstApp, dstConn := net.Pipe()
+				NewConnEndpoint(sa, srcConn, cancel, nil, nil).Start(ctx)
+				NewConnEndpoint(sb, dstConn, cancel, nil, nil).Start(ctx)
+				go func() {
+					_, _ = srcApp.Write(data)
+				}()
+				n, err := io.CopyN(io.Discard, &pausingReader{Reader: dstApp}, size)
+				cancel()
+				_ = srcApp.Close()
+				_ = dstApp.Close()
+				if err != nil || n != size {
+					b.Fatalf("transferred %d bytes: %v", n, err)
+				}
+			}
+		})
+	}
+}
+
+// pausingReader simulates an application that stops reading for a while after each 512KiB that it has read.
+type pausingReader struct {
+	io.Reader
+	count int
+}
+
+func (r *pausingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.count += n
+	if r.count >= 0x80000 {
+		r.count -= 0x80000
+		time.Sleep(30 * time.Millisecond)
+	}
+	return n, err
+}
@@ -119,11 +119,15 @@ func (rt *Router) UpdateRoutes(ctx context.Context, pleaseProxy, dontProxy, dont
 	}
 
 	var staticNets []*net.IPNet
-	var pr *routing.Route
+
+	// The primary routes are the routes of the first subnet added for each IP family. They are used
+	// when adding static routes for subnets that are too small to be added to the device.
+	primaryRoutes := make(map[bool]*routing.Route, 2)
 	for _, sn := range added {
 		var err error
 		ones, bits := sn.Mask.Size()
-		if bits == 32 && ones > 30 {
+		if ones > bits-2 {
+			// A /31 or /32 (or /127 or /128) doesn't leave room for an address on the device.
 			staticNets = append(staticNets, sn)
 			continue
 		}
@@ -133,27 +137,44 @@ func (rt *Router) UpdateRoutes(ctx context.Context, pleaseProxy, dontProxy, dont
 			continue
 		}
 
-		if pr == nil {
-			if pr, err = routing.GetRoute(ctx, sn); err == nil {
+		ipv4 := isIPv4(sn)
+		if primaryRoutes[ipv4] == nil {
+			var pr *routing.Route
+			if pr, err = routing.GetRoute(ctx, sn); err != nil {
 				dlog.Errorf(ctx, "failed to retrieve route for subnet %s: %v", sn, err)
+			} else {
+				primaryRoutes[ipv4] = pr
 			}
 		}
 	}
-	if len(staticNets) > 0 && pr == nil {
-		return fmt.Errorf("unable to route subnets %v, because there's no subnet with a mask smaller than 31 bits", staticNets)
+	for _, sn := range staticNets {
+		if primaryRoutes[isIPv4(sn)] == nil {
+			return fmt.Errorf("unable to route subnet %s, because there's no subnet of the same IP family with a mask smaller than %d bits",
+				sn, len(sn.Mask)*8-1)
+		}
 	}
-	return rt.addStaticOverrides(ctx, dontProxy, dontProxyOverrides, staticNets, pr)
+	return rt.addStaticOverrides(ctx, dontProxy, dontProxyOverrides, staticNets, primaryRoutes)
+}
+
+// isIPv4 returns true if the given subnet is an IPv4 subnet.
+func isIPv4(sn *net.IPNet) bool {
+	return sn.IP.To4() != nil
 }
 
-func (rt *Router) addStaticOverrides(ctx context.Context, neverProxy, neverProxyOverrides, staticNets []*net.IPNet, primaryRoute *routing.Route) (err error) {
+func (rt *Router) addStaticOverrides(ctx context.Context, neverProxy, neverProxyOverrides, staticNets []*net.IPNet, primaryRoutes map[bool]*routing.Route) (err error) {
 	desired := make([]*routing.Route, 0, len(neverProxy)+len(neverProxyOverrides))
-	dr, err := routing.DefaultRoute(ctx)
-	if err != nil {
-		return err
-	}
+	defaultRoutes := make(map[bool]*routing.Route, 2)
 	for _, sn := range neverProxy {
 		// All subnets in neverProxy have been verified as being routed by the TUN-device, so we
-		// route them to the default route instead.
+		// route them to the default route of their IP family instead.
+		ipv4 := isIPv4(sn)
+		dr, ok := defaultRoutes[ipv4]
+		if !ok {
+			if dr, err = routing.DefaultRouteFor(ctx, sn.IP); err != nil {
+				return err
+			}
+			defaultRoutes[ipv4] = dr
+		}
 		desired = append(desired, &routing.Route{
 			LocalIP:   dr.LocalIP,
 			RoutedNet: sn,
@@ -179,6 +200,7 @@ func (rt *Router) addStaticOverrides(ctx context.Context, neverProxy, neverProxy
 	}
 
 	for _, sn := range staticNets {
+		primaryRoute := primaryRoutes[isIPv4(sn)]
 		desired = append(desired, &routing.Route{
 			LocalIP:   primaryRoute.LocalIP,
 			RoutedNet: sn,
@@ -39,6 +39,27 @@ func getCidr(byte3, byte4 byte, mask int) *net.IPNet {
 	}
 }
 
+func getCidr6(group4 uint16, last byte, mask int) *net.IPNet {
+	// 2001:2::/48 is reserved for benchmarking.
+	ip := net.IP{0x20, 0x01, 0x00, 0x02, 0, 0, byte(group4 >> 8), byte(group4), 0, 0, 0, 0, 0, 0, 0, last}
+	return &net.IPNet{
+		IP:   ip,
+		Mask: net.CIDRMask(mask, 128),
+	}
+}
+
+// requireIPv6 skips the test unless the host has IPv6 enabled.
+func (s *RoutingSuite) requireIPv6() {
+	addrs, err := net.InterfaceAddrs()
+	s.Require().NoError(err)
+	for _, addr := range addrs {
+		if ipn, ok := addr.(*net.IPNet); ok && ipn.IP.To4() == nil {
+			return
+		}
+	}
+	s.T().Skip("IPv6 is not enabled, skipping test")
+}
+
 func (s *RoutingSuite) SetupSuite() {
 	// Compile the router binary
 	if runtime.GOOS == "windows" {
@@ -262,6 +283,58 @@ func (s *RoutingSuite) Test_GetRoute() {
 	s.Require().Equal(cidr.IP, route.LocalIP)
 }
 
+func (s *RoutingSuite) Test_IPv6RouteIsAdded() {
+	s.requireIPv6()
+	ctx := context.Background()
+	cidr := getCidr6(2, 0, 64)
+
+	device, routerCancel, err := s.runRouter(ctx, cidr.String())
+	s.Require().NoError(err)
+	defer routerCancel()
+
+	route, err := routing.GetRoute(ctx, getCidr6(2, 1, 128))
+	s.Require().NoError(err)
+	s.Require().Equal(device, route.Interface.Name)
+}
+
+func (s *RoutingSuite) Test_IPv6StaticRoute() {
+	s.requireIPv6()
+	ctx := context.Background()
+	cidr := getCidr6(2, 0, 64)
+	single := getCidr6(3, 1, 128)
+
+	// The /128 is too small to be added to the device, so it's added as a static route
+	// using the route of the /64.
+	device, routerCancel, err := s.runRouter(ctx, cidr.String(), single.String())
+	s.Require().NoError(err)
+	defer routerCancel()
+
+	route, err := routing.GetRoute(ctx, single)
+	s.Require().NoError(err)
+	s.Require().Equal(device, route.Interface.Name)
+}
+
+func (s *RoutingSuite) Test_IPv6RouteIsBlackListed() {
+	s.requireIPv6()
+	ctx := context.Background()
+	if _, err := routing.DefaultRouteFor(ctx, net.IPv6loopback); err != nil {
+		s.T().Skip("no IPv6 default route, skipping test")
+	}
+	cidrYes := getCidr6(2, 0, 64)
+	cidrNo := getCidr6(2, 4, 128)
+	oldRoute, err := routing.GetRoute(ctx, cidrNo)
+	s.Require().NoError(err)
+
+	device, routerCancel, err := s.runRouter(ctx, cidrYes.String(), "!"+cidrNo.String())
+	s.Require().NoError(err)
+	defer routerCancel()
+
+	route, err := routing.GetRoute(ctx, cidrNo)
+	s.Require().NoError(err)
+	s.Require().Equal(oldRoute.Interface.Name, route.Interface.Name, "Expected route %s got %s", oldRoute, route)
+	s.Require().NotEqual(device, route.Interface.Name)
+}
+
 func (s *RoutingSuite) printRoutingTable(ctx context.Context) { //nolint:unused // Useful for debugging
 	var err error
 	// Print out the routing table for debugging
@@ -22,10 +22,15 @@ import (
 	"gvisor.dev/gvisor/pkg/waiter"
 
 	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/telepresence/v2/pkg/ipproto"
 	"github.com/telepresenceio/telepresence/v2/pkg/iputil"
 	"github.com/telepresenceio/telepresence/v2/pkg/tunnel"
 )
 
+// RoutedProtocols are the IP protocols that the stack routes to the cluster. Packets using other
+// transport protocols, such as SCTP, are dropped.
+var RoutedProtocols = []int{ipproto.TCP, ipproto.UDP} //nolint:gochecknoglobals // constant
+
 func NewStack(ctx context.Context, dev stack.LinkEndpoint, streamCreator tunnel.StreamCreator) (*stack.Stack, error) {
 	s := stack.New(stack.Options{
 		NetworkProtocols: []stack.NetworkProtocolFactory{
@@ -32,6 +32,7 @@ const (
 	Connector_Disconnect_FullMethodName              = "/telepresence.connector.Connector/Disconnect"
 	Connector_GetClusterSubnets_FullMethodName       = "/telepresence.connector.Connector/GetClusterSubnets"
 	Connector_Status_FullMethodName                  = "/telepresence.connector.Connector/Status"
+	Connector_WatchStatus_FullMethodName             = "/telepresence.connector.Connector/WatchStatus"
 	Connector_CanIntercept_FullMethodName            = "/telepresence.connector.Connector/CanIntercept"
 	Connector_CreateIntercept_FullMethodName         = "/telepresence.connector.Connector/CreateIntercept"
 	Connector_RemoveIntercept_FullMethodName         = "/telepresence.connector.Connector/RemoveIntercept"
@@ -51,6 +52,7 @@ const (
 	Connector_GetConfig_FullMethodName               = "/telepresence.connector.Connector/GetConfig"
 	Connector_SetDNSExcludes_FullMethodName          = "/telepresence.connector.Connector/SetDNSExcludes"
 	Connector_SetDNSMappings_FullMethodName          = "/telepresence.connector.Connector/SetDNSMappings"
+	Connector_ResolveDNS_FullMethodName              = "/telepresence.connector.Connector/ResolveDNS"
 )
 
 // ConnectorClient is the client API for Connector service.
@@ -84,6 +86,10 @@ type ConnectorClient interface {
 	// Status returns the status of the current connection or DISCONNECTED
 	// if no connection has been established.
 	Status(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ConnectInfo, error)
+	// WatchStatus streams the status of the current connection. The current
+	// status is sent immediately, and a new status is sent each time a
+	// connection is established or closed, or when the intercepts change.
+	WatchStatus(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (Connector_WatchStatusClient, error)
 	// Queries the connector whether it is possible to create the given intercept.
 	CanIntercept(ctx context.Context, in *CreateInterceptRequest, opts ...grpc.CallOption) (*InterceptResult, error)
 	// Adds an intercept to a workload.  Requires having already called
@@ -129,6 +135,8 @@ type ConnectorClient interface {
 	SetDNSExcludes(ctx context.Context, in *daemon.SetDNSExcludesRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	// SetDNSMappings sets the Mappings field of DNSConfig.
 	SetDNSMappings(ctx context.Context, in *daemon.SetDNSMappingsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// ResolveDNS resolves a name using the rules and the resolver of the root daemon's DNS server.
+	ResolveDNS(ctx context.Context, in *daemon.ResolveDNSRequest, opts ...grpc.CallOption) (*daemon.ResolveDNSResponse, error)
 }
 
 type connectorClient struct {
@@ -229,6 +237,39 @@ func (c *connectorClient) Status(ctx context.Context, in *emptypb.Empty, opts ..
 	return out, nil
 }
 
+func (c *connectorClient) WatchStatus(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (Connector_WatchStatusClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Connector_ServiceDesc.Streams[0], Connector_WatchStatus_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &connectorWatchStatusClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Connector_WatchStatusClient interface {
+	Recv() (*ConnectInfo, error)
+	grpc.ClientStream
+}
+
+type connectorWatchStatusClient struct {
+	grpc.ClientStream
+}
+
+func (x *connectorWatchStatusClient) Recv() (*ConnectInfo, error) {
+	m := new(ConnectInfo)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *connectorClient) CanIntercept(ctx context.Context, in *CreateInterceptRequest, opts ...grpc.CallOption) (*InterceptResult, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(InterceptResult)
@@ -291,7 +332,7 @@ func (c *connectorClient) List(ctx context.Context, in *ListRequest, opts ...grp
 
 func (c *connectorClient) WatchWorkloads(ctx context.Context, in *WatchWorkloadsRequest, opts ...grpc.CallOption) (Connector_WatchWorkloadsClient, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &Connector_ServiceDesc.Streams[0], Connector_WatchWorkloads_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &Connector_ServiceDesc.Streams[1], Connector_WatchWorkloads_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -442,6 +483,16 @@ func (c *connectorClient) SetDNSMappings(ctx context.Context, in *daemon.SetDNSM
 	return out, nil
 }
 
+func (c *connectorClient) ResolveDNS(ctx context.Context, in *daemon.ResolveDNSRequest, opts ...grpc.CallOption) (*daemon.ResolveDNSResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(daemon.ResolveDNSResponse)
+	err := c.cc.Invoke(ctx, Connector_ResolveDNS_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ConnectorServer is the server API for Connector service.
 // All implementations must embed UnimplementedConnectorServer
 // for forward compatibility
@@ -473,6 +524,10 @@ type ConnectorServer interface {
 	// Status returns the status of the current connection or DISCONNECTED
 	// if no connection has been established.
 	Status(context.Context, *emptypb.Empty) (*ConnectInfo, error)
+	// WatchStatus streams the status of the current connection. The current
+	// status is sent immediately, and a new status is sent each time a
+	// connection is established or closed, or when the intercepts change.
+	WatchStatus(*emptypb.Empty, Connector_WatchStatusServer) error
 	// Queries the connector whether it is possible to create the given intercept.
 	CanIntercept(context.Context, *CreateInterceptRequest) (*InterceptResult, error)
 	// Adds an intercept to a workload.  Requires having already called
@@ -518,6 +573,8 @@ type ConnectorServer interface {
 	SetDNSExcludes(context.Context, *daemon.SetDNSExcludesRequest) (*emptypb.Empty, error)
 	// SetDNSMappings sets the Mappings field of DNSConfig.
 	SetDNSMappings(context.Context, *daemon.SetDNSMappingsRequest) (*emptypb.Empty, error)
+	// ResolveDNS resolves a name using the rules and the resolver of the root daemon's DNS server.
+	ResolveDNS(context.Context, *daemon.ResolveDNSRequest) (*daemon.ResolveDNSResponse, error)
 	mustEmbedUnimplementedConnectorServer()
 }
 
@@ -552,6 +609,9 @@ func (UnimplementedConnectorServer) GetClusterSubnets(context.Context, *emptypb.
 func (UnimplementedConnectorServer) Status(context.Context, *emptypb.Empty) (*ConnectInfo, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Status not implemented")
 }
+func (UnimplementedConnectorServer) WatchStatus(*emptypb.Empty, Connector_WatchStatusServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchStatus not implemented")
+}
 func (UnimplementedConnectorServer) CanIntercept(context.Context, *CreateInterceptRequest) (*InterceptResult, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CanIntercept not implemented")
 }
@@ -609,6 +669,9 @@ func (UnimplementedConnectorServer) SetDNSExcludes(context.Context, *daemon.SetD
 func (UnimplementedConnectorServer) SetDNSMappings(context.Context, *daemon.SetDNSMappingsRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetDNSMappings not implemented")
 }
+func (UnimplementedConnectorServer) ResolveDNS(context.Context, *daemon.ResolveDNSRequest) (*daemon.ResolveDNSResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveDNS not implemented")
+}
 func (UnimplementedConnectorServer) mustEmbedUnimplementedConnectorServer() {}
 
 // UnsafeConnectorServer may be embedded to opt out of forward compatibility for this service.
@@ -784,6 +847,27 @@ func _Connector_Status_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Connector_WatchStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(emptypb.Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ConnectorServer).WatchStatus(m, &connectorWatchStatusServer{ServerStream: stream})
+}
+
+type Connector_WatchStatusServer interface {
+	Send(*ConnectInfo) error
+	grpc.ServerStream
+}
+
+type connectorWatchStatusServer struct {
+	grpc.ServerStream
+}
+
+func (x *connectorWatchStatusServer) Send(m *ConnectInfo) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Connector_CanIntercept_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CreateInterceptRequest)
 	if err := dec(in); err != nil {
@@ -1129,6 +1213,24 @@ func _Connector_SetDNSMappings_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Connector_ResolveDNS_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(daemon.ResolveDNSRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConnectorServer).ResolveDNS(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Connector_ResolveDNS_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConnectorServer).ResolveDNS(ctx, req.(*daemon.ResolveDNSRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Connector_ServiceDesc is the grpc.ServiceDesc for Connector service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1244,8 +1346,17 @@ var Connector_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SetDNSMappings",
 			Handler:    _Connector_SetDNSMappings_Handler,
 		},
+		{
+			MethodName: "ResolveDNS",
+			Handler:    _Connector_ResolveDNS_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchStatus",
+			Handler:       _Connector_WatchStatus_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "WatchWorkloads",
 			Handler:       _Connector_WatchWorkloads_Handler,
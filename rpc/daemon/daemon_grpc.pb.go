@@ -31,6 +31,7 @@ const (
 	Daemon_SetDNSTopLevelDomains_FullMethodName = "/telepresence.daemon.Daemon/SetDNSTopLevelDomains"
 	Daemon_SetDNSExcludes_FullMethodName        = "/telepresence.daemon.Daemon/SetDNSExcludes"
 	Daemon_SetDNSMappings_FullMethodName        = "/telepresence.daemon.Daemon/SetDNSMappings"
+	Daemon_ResolveDNS_FullMethodName            = "/telepresence.daemon.Daemon/ResolveDNS"
 	Daemon_SetLogLevel_FullMethodName           = "/telepresence.daemon.Daemon/SetLogLevel"
 	Daemon_WaitForNetwork_FullMethodName        = "/telepresence.daemon.Daemon/WaitForNetwork"
 	Daemon_WaitForAgentIP_FullMethodName        = "/telepresence.daemon.Daemon/WaitForAgentIP"
@@ -61,6 +62,8 @@ type DaemonClient interface {
 	SetDNSExcludes(ctx context.Context, in *SetDNSExcludesRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	// SetDNSMappings sets the Mappings field of DNSConfig.
 	SetDNSMappings(ctx context.Context, in *SetDNSMappingsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// ResolveDNS resolves a name using the rules and the resolver of the DNS server.
+	ResolveDNS(ctx context.Context, in *ResolveDNSRequest, opts ...grpc.CallOption) (*ResolveDNSResponse, error)
 	// SetLogLevel will temporarily set the log-level for the daemon for a duration that is determined b the request.
 	SetLogLevel(ctx context.Context, in *manager.LogLevelRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	// WaitForNetwork waits for the network of the currently connected session to become ready.
@@ -167,6 +170,16 @@ func (c *daemonClient) SetDNSMappings(ctx context.Context, in *SetDNSMappingsReq
 	return out, nil
 }
 
+func (c *daemonClient) ResolveDNS(ctx context.Context, in *ResolveDNSRequest, opts ...grpc.CallOption) (*ResolveDNSResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResolveDNSResponse)
+	err := c.cc.Invoke(ctx, Daemon_ResolveDNS_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *daemonClient) SetLogLevel(ctx context.Context, in *manager.LogLevelRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(emptypb.Empty)
@@ -222,6 +235,8 @@ type DaemonServer interface {
 	SetDNSExcludes(context.Context, *SetDNSExcludesRequest) (*emptypb.Empty, error)
 	// SetDNSMappings sets the Mappings field of DNSConfig.
 	SetDNSMappings(context.Context, *SetDNSMappingsRequest) (*emptypb.Empty, error)
+	// ResolveDNS resolves a name using the rules and the resolver of the DNS server.
+	ResolveDNS(context.Context, *ResolveDNSRequest) (*ResolveDNSResponse, error)
 	// SetLogLevel will temporarily set the log-level for the daemon for a duration that is determined b the request.
 	SetLogLevel(context.Context, *manager.LogLevelRequest) (*emptypb.Empty, error)
 	// WaitForNetwork waits for the network of the currently connected session to become ready.
@@ -262,6 +277,9 @@ func (UnimplementedDaemonServer) SetDNSExcludes(context.Context, *SetDNSExcludes
 func (UnimplementedDaemonServer) SetDNSMappings(context.Context, *SetDNSMappingsRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetDNSMappings not implemented")
 }
+func (UnimplementedDaemonServer) ResolveDNS(context.Context, *ResolveDNSRequest) (*ResolveDNSResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveDNS not implemented")
+}
 func (UnimplementedDaemonServer) SetLogLevel(context.Context, *manager.LogLevelRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetLogLevel not implemented")
 }
@@ -446,6 +464,24 @@ func _Daemon_SetDNSMappings_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Daemon_ResolveDNS_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveDNSRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServer).ResolveDNS(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Daemon_ResolveDNS_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServer).ResolveDNS(ctx, req.(*ResolveDNSRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Daemon_SetLogLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(manager.LogLevelRequest)
 	if err := dec(in); err != nil {
@@ -543,6 +579,10 @@ var Daemon_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SetDNSMappings",
 			Handler:    _Daemon_SetDNSMappings_Handler,
 		},
+		{
+			MethodName: "ResolveDNS",
+			Handler:    _Daemon_ResolveDNS_Handler,
+		},
 		{
 			MethodName: "SetLogLevel",
 			Handler:    _Daemon_SetLogLevel_Handler,